@@ -0,0 +1,51 @@
+package main
+
+import (
+	"log"
+	"net/http"
+
+	"scm-api/internal/audit"
+	"scm-api/internal/models"
+	"scm-api/internal/store"
+
+	"github.com/gin-gonic/gin"
+)
+
+// =================================================================
+// HANDLER UNTUK JEJAK AUDIT
+// =================================================================
+
+// getAuditHandler memakai parameter daftar bersama (lihat bacaKueri) dengan filter
+// entitas, entitas_id, aktor, dan aksi
+func (s *server) getAuditHandler(c *gin.Context) {
+	q, ok := bacaKueri(c, store.DaftarAudit)
+	if !ok {
+		return
+	}
+	switch aksi := q.Filter["aksi"]; aksi {
+	case "", models.AuditBuat, models.AuditUbah, models.AuditHapus, models.AuditPulihkan:
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Aksi audit tidak dikenal: " + aksi})
+		return
+	}
+	daftar, total, err := s.audit.ListAudit(c.Request.Context(), q)
+	if err != nil {
+		log.Printf("Error mengambil audit: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil data audit"})
+		return
+	}
+	kirimHalaman(c, daftar, total, q)
+}
+
+// catatAudit mencatat perubahan satu entitas oleh pengguna yang sedang login.
+// Dipanggil setelah perubahan berhasil disimpan, jadi kegagalan di sini hanya
+// dicatat di log dan tidak menggagalkan permintaan.
+func (s *server) catatAudit(c *gin.Context, entitas string, id int64, aksi string, sebelum, sesudah any) {
+	e, err := audit.Entri(aktor(c), entitas, id, aksi, sebelum, sesudah)
+	if err == nil {
+		err = s.audit.CatatAudit(c.Request.Context(), &e)
+	}
+	if err != nil {
+		log.Printf("Error mencatat audit %s %d (%s): %v", entitas, id, aksi, err)
+	}
+}
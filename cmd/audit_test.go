@@ -0,0 +1,57 @@
+package main
+
+import (
+	"net/http"
+	"testing"
+
+	"scm-api/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestJejakAudit(t *testing.T) {
+	p := pengujiBaru(t)
+	p.dataDasar()
+
+	p.harus(http.StatusCreated, "pembelian", "POST", "/api/supplier", gin.H{"nama_supplier": "Cahaya Abadi", "kontak": "0811"})
+	p.harus(http.StatusOK, "pembelian", "PUT", "/api/supplier/2", gin.H{"nama_supplier": "Cahaya Abadi Jaya", "kontak": "0811"})
+	p.harus(http.StatusOK, "manajer", "DELETE", "/api/supplier/2", nil)
+	// Permintaan yang gagal tidak tercatat
+	p.harus(http.StatusConflict, "manajer", "DELETE", "/api/supplier/2", nil)
+
+	var daftar models.Halaman[models.AuditLog]
+	p.decode(p.harus(http.StatusOK, "admin", "GET", "/api/audit?entitas=supplier&entitas_id=2&sort=audit_id&dir=asc", nil), &daftar)
+	if len(daftar.Data) != 3 {
+		t.Fatalf("audit supplier 2 = %+v, ingin 3 entri", daftar.Data)
+	}
+	for i, ingin := range []struct{ aksi, aktor string }{
+		{models.AuditBuat, "pembelian"},
+		{models.AuditUbah, "pembelian"},
+		{models.AuditHapus, "manajer"},
+	} {
+		if e := daftar.Data[i]; e.Aksi != ingin.aksi || e.Aktor != ingin.aktor || e.Entitas != "supplier" || e.EntitasID != 2 {
+			t.Errorf("entri ke-%d = %s oleh %s, ingin %s oleh %s", i+1, e.Aksi, e.Aktor, ingin.aksi, ingin.aktor)
+		}
+	}
+	if buat := daftar.Data[0]; string(buat.Sebelum) != "null" || string(buat.Sesudah) == "null" {
+		t.Errorf("entri buat sebelum %s sesudah %s, ingin hanya sesudah", buat.Sebelum, buat.Sesudah)
+	}
+	var beda map[string]struct {
+		Sebelum any `json:"sebelum"`
+		Sesudah any `json:"sesudah"`
+	}
+	p.decode(daftar.Data[1].Perubahan, &beda)
+	if len(beda) != 1 || beda["nama_supplier"].Sebelum != "Cahaya Abadi" || beda["nama_supplier"].Sesudah != "Cahaya Abadi Jaya" {
+		t.Errorf("perubahan entri ubah = %+v, ingin hanya nama_supplier", beda)
+	}
+	if hapus := daftar.Data[2]; string(hapus.Sesudah) != "null" || string(hapus.Sebelum) == "null" {
+		t.Errorf("entri hapus sebelum %s sesudah %s, ingin hanya sebelum", hapus.Sebelum, hapus.Sesudah)
+	}
+
+	p.decode(p.harus(http.StatusOK, "manajer", "GET", "/api/audit?aktor=manajer&aksi=hapus", nil), &daftar)
+	if daftar.Meta.Total != 1 {
+		t.Errorf("audit hapus oleh manajer = %d entri, ingin 1", daftar.Meta.Total)
+	}
+	p.harus(http.StatusBadRequest, "admin", "GET", "/api/audit?aksi=hancurkan", nil)
+	p.harus(http.StatusForbidden, "pembelian", "GET", "/api/audit", nil)
+}
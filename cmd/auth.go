@@ -0,0 +1,211 @@
+package main
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+
+	"scm-api/internal/auth"
+	"scm-api/internal/models"
+	"scm-api/internal/store"
+
+	"github.com/gin-gonic/gin"
+)
+
+// =================================================================
+// HANDLER UNTUK LOGIN DAN TOKEN
+// =================================================================
+
+// kunciClaims adalah kunci gin.Context tempat wajibLogin menyimpan isi access token
+const kunciClaims = "auth.claims"
+
+type loginRequest struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+type refreshRequest struct {
+	RefreshToken string `json:"refresh_token"`
+	// Semua mencabut seluruh token refresh milik pengguna (hanya untuk logout)
+	Semua bool `json:"semua"`
+}
+
+func (s *server) loginHandler(c *gin.Context) {
+	var req loginRequest
+	if err := c.ShouldBindJSON(&req); err != nil || strings.TrimSpace(req.Username) == "" || req.Password == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Username dan password wajib diisi"})
+		return
+	}
+
+	p, err := s.pengguna.GetPenggunaByUsername(c.Request.Context(), strings.TrimSpace(req.Username))
+	if err != nil && !errors.Is(err, store.ErrNotFound) {
+		log.Printf("Error mengambil pengguna %s: %v", req.Username, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memproses login"})
+		return
+	}
+	// Pesan yang sama untuk username tidak dikenal, password salah, dan akun nonaktif
+	// agar tidak bisa dipakai menebak username
+	if !auth.CocokPassword(p.PasswordHash, req.Password) || !p.Aktif {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Username atau password salah"})
+		return
+	}
+	s.terbitkanToken(c, p)
+}
+
+func (s *server) refreshTokenHandler(c *gin.Context) {
+	var req refreshRequest
+	if err := c.ShouldBindJSON(&req); err != nil || req.RefreshToken == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "refresh_token wajib diisi"})
+		return
+	}
+	penggunaID, ok := s.pakaiRefreshToken(c, req.RefreshToken)
+	if !ok {
+		return
+	}
+	p, err := s.pengguna.GetPengguna(c.Request.Context(), penggunaID)
+	if err != nil {
+		log.Printf("Error mengambil pengguna %d: %v", penggunaID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memperbarui token"})
+		return
+	}
+	if !p.Aktif {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Akun pengguna sudah dinonaktifkan"})
+		return
+	}
+	s.terbitkanToken(c, p)
+}
+
+func (s *server) logoutHandler(c *gin.Context) {
+	var req refreshRequest
+	if err := c.ShouldBindJSON(&req); err != nil || req.RefreshToken == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "refresh_token wajib diisi"})
+		return
+	}
+	penggunaID, ok := s.pakaiRefreshToken(c, req.RefreshToken)
+	if !ok {
+		return
+	}
+	if req.Semua {
+		if err := s.pengguna.CabutSemuaRefreshToken(c.Request.Context(), penggunaID); err != nil {
+			log.Printf("Error mencabut token pengguna %d: %v", penggunaID, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal logout dari semua perangkat"})
+			return
+		}
+	}
+	// Access token yang sudah terbit ikut dicabut; perangkat lain cukup memakai token refreshnya
+	if err := s.pengguna.NaikkanVersiToken(c.Request.Context(), penggunaID); err != nil {
+		log.Printf("Error mencabut access token pengguna %d: %v", penggunaID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal logout"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Berhasil logout"})
+}
+
+func (s *server) getPenggunaSayaHandler(c *gin.Context) {
+	claims := c.MustGet(kunciClaims).(auth.Claims)
+	p, err := s.pengguna.GetPengguna(c.Request.Context(), claims.PenggunaID)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Pengguna tidak ditemukan"})
+			return
+		}
+		log.Printf("Error mengambil pengguna %d: %v", claims.PenggunaID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Terjadi kesalahan internal"})
+		return
+	}
+	daftarRole, ok := s.roleSaya(c)
+	if !ok {
+		return
+	}
+	izin, err := s.role.IzinPengguna(c.Request.Context(), claims.PenggunaID)
+	if err != nil {
+		log.Printf("Error mengambil izin pengguna %d: %v", claims.PenggunaID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Terjadi kesalahan internal"})
+		return
+	}
+	gudang, err := s.pengguna.GudangPengguna(c.Request.Context(), claims.PenggunaID)
+	if err != nil {
+		log.Printf("Error mengambil gudang pengguna %d: %v", claims.PenggunaID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Terjadi kesalahan internal"})
+		return
+	}
+	hasil := models.PenggunaDenganRole{Pengguna: p, Role: make([]string, 0, len(daftarRole)), Gudang: gudang, Izin: izin}
+	for _, r := range daftarRole {
+		hasil.Role = append(hasil.Role, r.Nama)
+	}
+	c.JSON(http.StatusOK, hasil)
+}
+
+// pakaiRefreshToken mencabut token refresh dan mengembalikan pemiliknya.
+// Jika token tidak berlaku, respons 401 sudah dikirim dan ok bernilai false.
+func (s *server) pakaiRefreshToken(c *gin.Context, token string) (int64, bool) {
+	penggunaID, err := s.pengguna.PakaiRefreshToken(c.Request.Context(), auth.HashRefreshToken(token))
+	if err == nil {
+		return penggunaID, true
+	}
+	if errors.Is(err, store.ErrNotFound) {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Token refresh tidak valid atau sudah kedaluwarsa"})
+		return 0, false
+	}
+	log.Printf("Error memakai token refresh: %v", err)
+	c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memproses token refresh"})
+	return 0, false
+}
+
+// terbitkanToken membuat access token dan token refresh baru untuk pengguna lalu mengirimkannya
+func (s *server) terbitkanToken(c *gin.Context, p models.Pengguna) {
+	access, err := s.tokens.BuatAccessToken(p.PenggunaID, p.Username, p.VersiToken)
+	if err != nil {
+		log.Printf("Error membuat access token: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal membuat token"})
+		return
+	}
+	refresh, hash, err := auth.BuatRefreshToken()
+	if err == nil {
+		err = s.pengguna.SimpanRefreshToken(c.Request.Context(), p.PenggunaID, hash, s.tokens.RefreshTTL)
+	}
+	if err != nil {
+		log.Printf("Error menyimpan token refresh: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal membuat token"})
+		return
+	}
+	c.JSON(http.StatusOK, models.TokenResponse{
+		AccessToken:  access,
+		TokenType:    "Bearer",
+		ExpiresIn:    int64(s.tokens.AccessTTL.Seconds()),
+		RefreshToken: refresh,
+		Pengguna:     p,
+	})
+}
+
+// wajibLogin adalah middleware yang menolak permintaan tanpa access token yang valid
+// di header "Authorization: Bearer <token>". Token juga ditolak jika penggunanya sudah
+// dinonaktifkan atau versi tokennya tertinggal karena logout.
+func (s *server) wajibLogin(c *gin.Context) {
+	token, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+	if !ok || strings.TrimSpace(token) == "" {
+		c.Header("WWW-Authenticate", `Bearer realm="scm-api"`)
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Token akses wajib disertakan"})
+		return
+	}
+	claims, err := s.tokens.VerifikasiAccessToken(strings.TrimSpace(token))
+	if err != nil {
+		c.Header("WWW-Authenticate", `Bearer realm="scm-api", error="invalid_token"`)
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Token akses tidak valid atau sudah kedaluwarsa"})
+		return
+	}
+	p, err := s.pengguna.GetPengguna(c.Request.Context(), claims.PenggunaID)
+	if err != nil && !errors.Is(err, store.ErrNotFound) {
+		log.Printf("Error mengambil pengguna %d: %v", claims.PenggunaID, err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Gagal memeriksa token akses"})
+		return
+	}
+	if err != nil || !p.Aktif || p.VersiToken != claims.Versi {
+		c.Header("WWW-Authenticate", `Bearer realm="scm-api", error="invalid_token"`)
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Token akses sudah dicabut"})
+		return
+	}
+	c.Set(kunciClaims, claims)
+	c.Next()
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"scm-api/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// masuk login sebagai username, menyimpan access token-nya untuk kirim, dan mengembalikan respons login
+func (p *penguji) masuk(username string) models.TokenResponse {
+	p.t.Helper()
+	var tr models.TokenResponse
+	p.decode(p.harus(http.StatusOK, "", "POST", "/api/auth/login", gin.H{"username": username, "password": "rahasia123"}), &tr)
+	p.token[username] = tr.AccessToken
+	return tr
+}
+
+func TestLogin(t *testing.T) {
+	p := pengujiBaru(t)
+	p.harus(http.StatusUnauthorized, "", "POST", "/api/auth/login", gin.H{"username": "admin", "password": "salah12345"})
+	p.harus(http.StatusUnauthorized, "", "POST", "/api/auth/login", gin.H{"username": "tidak-ada", "password": "rahasia123"})
+	p.harus(http.StatusBadRequest, "", "POST", "/api/auth/login", gin.H{"username": "admin"})
+	p.harus(http.StatusUnauthorized, "", "GET", "/api/auth/saya", nil)
+
+	p.token["palsu"] = p.token["admin"] + "x"
+	p.harus(http.StatusUnauthorized, "palsu", "GET", "/api/auth/saya", nil)
+
+	// Access token yang sudah lewat masa berlakunya ditolak
+	p.srv.tokens.AccessTTL = -time.Second
+	p.masuk("gudang")
+	p.harus(http.StatusUnauthorized, "gudang", "GET", "/api/auth/saya", nil)
+}
+
+func TestRefreshTokenSekaliPakai(t *testing.T) {
+	p := pengujiBaru(t)
+	tr := p.masuk("pembelian")
+
+	var baru models.TokenResponse
+	p.decode(p.harus(http.StatusOK, "", "POST", "/api/auth/refresh", gin.H{"refresh_token": tr.RefreshToken}), &baru)
+	if baru.RefreshToken == "" || baru.RefreshToken == tr.RefreshToken {
+		t.Fatalf("refresh tidak menerbitkan token refresh baru: %+v", baru)
+	}
+	p.harus(http.StatusUnauthorized, "", "POST", "/api/auth/refresh", gin.H{"refresh_token": tr.RefreshToken})
+	p.harus(http.StatusUnauthorized, "", "POST", "/api/auth/refresh", gin.H{"refresh_token": "bukan-token"})
+	p.harus(http.StatusBadRequest, "", "POST", "/api/auth/refresh", gin.H{})
+}
+
+func TestLogoutMencabutToken(t *testing.T) {
+	p := pengujiBaru(t)
+	perangkat1 := p.masuk("pembelian")
+	perangkat2 := p.masuk("pembelian")
+
+	p.harus(http.StatusOK, "", "POST", "/api/auth/logout", gin.H{"refresh_token": perangkat2.RefreshToken})
+	// Access token yang sudah terbit ikut dicabut, tetapi perangkat lain masih bisa refresh
+	p.harus(http.StatusUnauthorized, "pembelian", "GET", "/api/auth/saya", nil)
+	p.harus(http.StatusUnauthorized, "", "POST", "/api/auth/refresh", gin.H{"refresh_token": perangkat2.RefreshToken})
+	var tr models.TokenResponse
+	p.decode(p.harus(http.StatusOK, "", "POST", "/api/auth/refresh", gin.H{"refresh_token": perangkat1.RefreshToken}), &tr)
+	p.token["pembelian"] = tr.AccessToken
+	p.harus(http.StatusOK, "pembelian", "GET", "/api/auth/saya", nil)
+
+	// Logout semua mencabut token refresh di setiap perangkat
+	perangkat3 := p.masuk("pembelian")
+	p.harus(http.StatusOK, "", "POST", "/api/auth/logout", gin.H{"refresh_token": perangkat3.RefreshToken, "semua": true})
+	p.harus(http.StatusUnauthorized, "", "POST", "/api/auth/refresh", gin.H{"refresh_token": tr.RefreshToken})
+	p.harus(http.StatusUnauthorized, "pembelian", "GET", "/api/auth/saya", nil)
+}
+
+func TestPenggunaNonaktif(t *testing.T) {
+	p := pengujiBaru(t)
+	tr := p.masuk("gudang")
+	p.harus(http.StatusOK, "gudang", "GET", "/api/auth/saya", nil)
+
+	if err := p.store.SetAktifPengguna(context.Background(), tr.Pengguna.PenggunaID, false); err != nil {
+		t.Fatalf("SetAktifPengguna: %v", err)
+	}
+	p.harus(http.StatusUnauthorized, "gudang", "GET", "/api/auth/saya", nil)
+	p.harus(http.StatusUnauthorized, "", "POST", "/api/auth/refresh", gin.H{"refresh_token": tr.RefreshToken})
+	p.harus(http.StatusUnauthorized, "", "POST", "/api/auth/login", gin.H{"username": "gudang", "password": "rahasia123"})
+
+	// Setelah diaktifkan kembali pengguna harus login ulang
+	if err := p.store.SetAktifPengguna(context.Background(), tr.Pengguna.PenggunaID, true); err != nil {
+		t.Fatalf("SetAktifPengguna: %v", err)
+	}
+	p.masuk("gudang")
+	p.harus(http.StatusOK, "gudang", "GET", "/api/auth/saya", nil)
+}
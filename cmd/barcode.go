@@ -0,0 +1,254 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+
+	"scm-api/internal/barcode"
+	"scm-api/internal/models"
+	"scm-api/internal/store"
+
+	"github.com/gin-gonic/gin"
+)
+
+// =================================================================
+// HANDLER UNTUK BARCODE PRODUK
+// =================================================================
+
+// Skala label bawaan: lebar satu modul dalam piksel (PNG) atau satuan tampilan (SVG)
+const skalaLabelBawaan = 3
+
+func (s *server) getBarcodeProdukHandler(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+	if _, err := s.produk.GetProduk(c.Request.Context(), id); err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Produk tidak ditemukan"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Terjadi kesalahan internal"})
+		return
+	}
+	daftar, err := s.barcode.ListBarcodeProduk(c.Request.Context(), id)
+	if err != nil {
+		log.Printf("Error mengambil barcode produk %d: %v", id, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil barcode"})
+		return
+	}
+	c.JSON(http.StatusOK, daftar)
+}
+
+// createBarcodeProdukHandler mendaftarkan barcode pabrik ({"kode": "..."}) atau
+// membuat barcode internal ({"internal": true}) untuk produk
+func (s *server) createBarcodeProdukHandler(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+	var req struct {
+		Kode     string `json:"kode"`
+		Internal bool   `json:"internal"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Data JSON tidak valid: " + err.Error()})
+		return
+	}
+
+	fe := make(fieldErrors)
+	b := models.ProdukBarcode{ProdukID: id, Kode: req.Kode, Internal: req.Internal}
+	switch {
+	case req.Internal && req.Kode != "":
+		fe.add("kode", "kosongkan jika internal bernilai true")
+	case !req.Internal && req.Kode == "":
+		fe.add("kode", "wajib diisi, atau kirim internal: true untuk membuat barcode internal")
+	case !req.Internal:
+		gtin, err := barcode.Normalisasi(req.Kode)
+		if err != nil {
+			fe.add("kode", err.Error())
+		}
+		b.GTIN = gtin
+	}
+	if fe.respond(c) {
+		return
+	}
+
+	ctx := c.Request.Context()
+	p, err := s.produk.GetProduk(ctx, id)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Produk tidak ditemukan"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Terjadi kesalahan internal"})
+		return
+	}
+	if p.DeletedAt.Valid {
+		c.JSON(http.StatusConflict, gin.H{"error": "Produk sudah dihapus; pulihkan dulu sebelum mengubahnya"})
+		return
+	}
+
+	if req.Internal {
+		daftar, err := s.barcode.ListBarcodeProduk(ctx, id)
+		if err != nil {
+			log.Printf("Error mengambil barcode produk %d: %v", id, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menyimpan barcode"})
+			return
+		}
+		for _, lama := range daftar {
+			if lama.Internal {
+				c.JSON(http.StatusConflict, gin.H{"error": "Produk sudah memiliki barcode internal " + lama.Kode})
+				return
+			}
+		}
+		// nomor internal diambil dari produk_id agar setiap produk mendapat kode yang tetap
+		if b.Kode, err = barcode.Internal(s.awalanBarcodeInternal, id); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		b.GTIN, _ = barcode.Normalisasi(b.Kode)
+	}
+
+	if err := s.barcode.CreateBarcode(ctx, &b); err != nil {
+		if errors.Is(err, store.ErrDuplikat) {
+			pesan := "Barcode sudah dipakai produk lain"
+			if pemilik, err := s.barcode.CariBarcode(ctx, b.GTIN); err == nil {
+				pesan = fmt.Sprintf("Barcode sudah dipakai produk %d", pemilik.ProdukID)
+			}
+			c.JSON(http.StatusConflict, gin.H{"error": pesan})
+			return
+		}
+		log.Printf("Error menyimpan barcode produk %d: %v", id, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menyimpan barcode"})
+		return
+	}
+	s.catatAudit(c, "barcode", b.BarcodeID, models.AuditBuat, nil, b)
+	c.JSON(http.StatusCreated, b)
+}
+
+func (s *server) deleteBarcodeProdukHandler(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+	b, ok := s.barcodeProduk(c, id)
+	if !ok {
+		return
+	}
+	if err := s.barcode.DeleteBarcode(c.Request.Context(), id, b.GTIN); err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Barcode tidak terdaftar di produk ini"})
+			return
+		}
+		log.Printf("Error menghapus barcode produk %d: %v", id, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menghapus barcode"})
+		return
+	}
+	s.catatAudit(c, "barcode", b.BarcodeID, models.AuditHapus, b, nil)
+	c.JSON(http.StatusOK, gin.H{"message": "Barcode berhasil dihapus"})
+}
+
+// lookupBarcodeHandler mencari produk dari hasil pindaian barcode. Kode boleh
+// ditulis sebagai EAN-8, UPC-A, EAN-13, atau GTIN-14.
+func (s *server) lookupBarcodeHandler(c *gin.Context) {
+	gtin, err := barcode.Normalisasi(c.Param("kode"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	ctx := c.Request.Context()
+	b, err := s.barcode.CariBarcode(ctx, gtin)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Barcode tidak terdaftar"})
+			return
+		}
+		log.Printf("Error mencari barcode %s: %v", gtin, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Terjadi kesalahan internal"})
+		return
+	}
+	p, err := s.produk.GetProduk(ctx, b.ProdukID)
+	if err != nil {
+		log.Printf("Error mengambil produk %d untuk barcode %s: %v", b.ProdukID, gtin, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Terjadi kesalahan internal"})
+		return
+	}
+	if p.DeletedAt.Valid {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Produk dengan barcode ini sudah dihapus"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"barcode": b, "produk": p})
+}
+
+// labelBarcodeHandler menggambar label EAN-13 untuk dicetak, dalam format png (bawaan) atau svg
+func (s *server) labelBarcodeHandler(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+	fe := make(fieldErrors)
+	format := c.DefaultQuery("format", "png")
+	if format != "png" && format != "svg" {
+		fe.add("format", "harus png atau svg")
+	}
+	skala := skalaLabelBawaan
+	if v := c.Query("skala"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > barcode.SkalaMaks {
+			fe.add("skala", fmt.Sprintf("harus antara 1 dan %d", barcode.SkalaMaks))
+		}
+		skala = n
+	}
+	if fe.respond(c) {
+		return
+	}
+	b, ok := s.barcodeProduk(c, id)
+	if !ok {
+		return
+	}
+	ean, err := barcode.EAN13(b.GTIN)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var isi []byte
+	tipe := "image/png"
+	if format == "svg" {
+		isi, err = barcode.SVG(ean, skala)
+		tipe = "image/svg+xml"
+	} else {
+		isi, err = barcode.PNG(ean, skala)
+	}
+	if err != nil {
+		log.Printf("Error menggambar label barcode %s: %v", ean, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal membuat label barcode"})
+		return
+	}
+	c.Header("Content-Disposition", fmt.Sprintf(`inline; filename="%s.%s"`, ean, format))
+	c.Data(http.StatusOK, tipe, isi)
+}
+
+// barcodeProduk mengambil barcode dari parameter :kode dan memastikan barcode itu milik produk id
+func (s *server) barcodeProduk(c *gin.Context, id int64) (models.ProdukBarcode, bool) {
+	gtin, err := barcode.Normalisasi(c.Param("kode"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return models.ProdukBarcode{}, false
+	}
+	b, err := s.barcode.CariBarcode(c.Request.Context(), gtin)
+	if err != nil && !errors.Is(err, store.ErrNotFound) {
+		log.Printf("Error mencari barcode %s: %v", gtin, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Terjadi kesalahan internal"})
+		return b, false
+	}
+	if err != nil || b.ProdukID != id {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Barcode tidak terdaftar di produk ini"})
+		return b, false
+	}
+	return b, true
+}
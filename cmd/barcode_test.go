@@ -0,0 +1,51 @@
+package main
+
+import (
+	"bytes"
+	"net/http"
+	"testing"
+
+	"scm-api/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestBarcodeProduk(t *testing.T) {
+	p := pengujiBaru(t)
+	p.dataDasar()
+
+	var b models.ProdukBarcode
+	p.decode(p.harus(http.StatusCreated, "admin", "POST", "/api/produk/1/barcode", gin.H{"kode": "036000291452"}), &b)
+	if b.Kode != "036000291452" || b.GTIN != "00036000291452" {
+		t.Errorf("barcode = %+v, ingin kode UPC-A dengan GTIN 00036000291452", b)
+	}
+	// UPC-A dan EAN-13 dengan nol di depan adalah barang yang sama
+	var hasil struct {
+		Produk models.Produk `json:"produk"`
+	}
+	p.decode(p.harus(http.StatusOK, "admin", "GET", "/api/produk/barcode/0036000291452", nil), &hasil)
+	if hasil.Produk.ProdukID != 1 {
+		t.Errorf("lookup EAN-13 menemukan produk %d, ingin 1", hasil.Produk.ProdukID)
+	}
+	p.harus(http.StatusConflict, "admin", "POST", "/api/produk/2/barcode", gin.H{"kode": "0036000291452"})
+	p.harus(http.StatusBadRequest, "admin", "POST", "/api/produk/2/barcode", gin.H{"kode": "4006381333932"})
+	p.harus(http.StatusBadRequest, "admin", "GET", "/api/produk/barcode/4006381333932", nil)
+	p.harus(http.StatusNotFound, "admin", "GET", "/api/produk/barcode/4006381333931", nil)
+
+	// Nomor internal diambil dari produk_id dengan awalan bawaan 20
+	p.decode(p.harus(http.StatusCreated, "admin", "POST", "/api/produk/2/barcode", gin.H{"internal": true}), &b)
+	if b.Kode != "2000000000022" || !b.Internal {
+		t.Errorf("barcode internal = %+v, ingin 2000000000022", b)
+	}
+	p.harus(http.StatusConflict, "admin", "POST", "/api/produk/2/barcode", gin.H{"internal": true})
+
+	label := p.harus(http.StatusOK, "admin", "GET", "/api/produk/2/barcode/2000000000022/label?format=svg", nil)
+	if !bytes.HasPrefix(label, []byte("<svg")) {
+		t.Errorf("label = %.40s, ingin SVG", label)
+	}
+	p.harus(http.StatusNotFound, "admin", "GET", "/api/produk/1/barcode/2000000000022/label", nil)
+	p.harus(http.StatusBadRequest, "admin", "GET", "/api/produk/2/barcode/2000000000022/label?skala=11", nil)
+
+	p.harus(http.StatusOK, "admin", "DELETE", "/api/produk/1/barcode/036000291452", nil)
+	p.harus(http.StatusNotFound, "admin", "GET", "/api/produk/barcode/036000291452", nil)
+}
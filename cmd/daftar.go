@@ -0,0 +1,128 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"scm-api/internal/models"
+	"scm-api/internal/store"
+
+	"github.com/gin-gonic/gin"
+)
+
+// =================================================================
+// PARAMETER BERSAMA UNTUK ENDPOINT DAFTAR
+// =================================================================
+
+// Jumlah baris per halaman jika limit tidak diisi, dan batas atasnya
+const (
+	batasBawaan = 50
+	batasMaks   = 200
+)
+
+// bacaKueri membaca parameter daftar dari query string:
+//
+//	page, limit            halaman (mulai dari 1) dan jumlah baris per halaman
+//	sort, dir              field pengurutan dari d.Urut dan arahnya (asc atau desc)
+//	<field>                filter nilai sama persis untuk setiap field di d.Filter
+//	dari, sampai           rentang tanggal YYYY-MM-DD (inklusif) jika d.Tanggal diisi
+//	include_deleted        ikut menampilkan data yang sudah dihapus jika d.BisaDihapus
+//
+// Kesalahannya dikirim sekaligus per parameter seperti validasi body. Jika ada,
+// respons 400 sudah dikirim dan ok bernilai false.
+func bacaKueri(c *gin.Context, d store.Daftar) (store.Kueri, bool) {
+	q := store.Kueri{Halaman: 1, Batas: batasBawaan, Urut: d.UrutBawaan, Turun: d.TurunBawaan, Filter: make(map[string]string)}
+	fe := make(fieldErrors)
+
+	if v := c.Query("page"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			fe.add("page", "harus bilangan bulat mulai dari 1")
+		}
+		q.Halaman = n
+	}
+	if v := c.Query("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > batasMaks {
+			fe.add("limit", fmt.Sprintf("harus antara 1 dan %d", batasMaks))
+		}
+		q.Batas = n
+	}
+	if v := c.Query("sort"); v != "" {
+		if !slices.Contains(d.Urut, v) {
+			fe.add("sort", "harus salah satu dari "+strings.Join(d.Urut, ", "))
+		}
+		q.Urut = v
+	}
+	switch c.Query("dir") {
+	case "":
+	case "asc":
+		q.Turun = false
+	case "desc":
+		q.Turun = true
+	default:
+		fe.add("dir", "harus asc atau desc")
+	}
+
+	for _, field := range d.Filter {
+		v, ada := c.GetQuery(field)
+		if !ada {
+			continue
+		}
+		if strings.HasSuffix(field, "_id") {
+			if id, err := strconv.ParseInt(v, 10, 64); err != nil || id < 1 {
+				fe.add(field, "harus ID yang valid")
+			}
+		}
+		q.Filter[field] = v
+	}
+
+	if d.Tanggal != "" {
+		for nama, t := range map[string]*time.Time{"dari": &q.Dari, "sampai": &q.Sampai} {
+			v := c.Query(nama)
+			if v == "" {
+				continue
+			}
+			var err error
+			if *t, err = time.Parse("2006-01-02", v); err != nil {
+				fe.add(nama, "harus berformat YYYY-MM-DD")
+			}
+		}
+		if !q.Dari.IsZero() && !q.Sampai.IsZero() && q.Sampai.Before(q.Dari) {
+			fe.add("sampai", "tidak boleh sebelum dari")
+		}
+		if !q.Sampai.IsZero() {
+			// sampai bersifat inklusif, jadi batas atasnya awal hari berikutnya
+			q.Sampai = q.Sampai.AddDate(0, 0, 1)
+		}
+	}
+	if d.BisaDihapus {
+		if v := c.Query("include_deleted"); v != "" {
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				fe.add("include_deleted", "harus bernilai true atau false")
+			}
+			q.TermasukDihapus = b
+		}
+	}
+	if fe.respond(c) {
+		return q, false
+	}
+	return q, true
+}
+
+// kirimHalaman mengirim satu halaman hasil daftar dalam amplop {"data": [...], "meta": {...}}
+func kirimHalaman[T any](c *gin.Context, data []T, total int, q store.Kueri) {
+	meta := models.MetaHalaman{Page: q.Halaman, Limit: q.Batas, Total: total, Sort: q.Urut, Dir: "asc"}
+	if q.Turun {
+		meta.Dir = "desc"
+	}
+	if q.Batas > 0 {
+		meta.TotalPages = (total + q.Batas - 1) / q.Batas
+	}
+	c.JSON(http.StatusOK, models.Halaman[T]{Data: data, Meta: meta})
+}
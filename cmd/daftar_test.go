@@ -0,0 +1,54 @@
+package main
+
+import (
+	"net/http"
+	"testing"
+
+	"scm-api/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestParameterDaftar(t *testing.T) {
+	p := pengujiBaru(t)
+	p.dataDasar()
+	for _, pr := range []gin.H{
+		{"sku": "BRG-3", "nama_produk": "Barang Tiga", "satuan": "kg", "harga_jual": 500, "kategori": "Curah"},
+		{"sku": "BRG-4", "nama_produk": "Barang Empat", "satuan": "kg", "harga_jual": 4500, "kategori": "Curah"},
+		{"sku": "BRG-5", "nama_produk": "Barang Lima", "satuan": "pcs", "harga_jual": 3500},
+	} {
+		p.harus(http.StatusCreated, "admin", "POST", "/api/produk", pr)
+	}
+
+	var daftar models.Halaman[models.Produk]
+	p.decode(p.harus(http.StatusOK, "admin", "GET", "/api/produk?limit=2&page=2&sort=harga_jual&dir=desc", nil), &daftar)
+	ingin := models.MetaHalaman{Page: 2, Limit: 2, Total: 5, TotalPages: 3, Sort: "harga_jual", Dir: "desc"}
+	if daftar.Meta != ingin {
+		t.Errorf("meta = %+v, ingin %+v", daftar.Meta, ingin)
+	}
+	// Urutan harga 4500, 3500, 2500, 1500, 500: halaman kedua berisi BRG-2 dan BRG-1
+	if len(daftar.Data) != 2 || daftar.Data[0].SKU != "BRG-2" || daftar.Data[1].SKU != "BRG-1" {
+		t.Errorf("halaman kedua = %+v, ingin BRG-2 lalu BRG-1", daftar.Data)
+	}
+
+	p.decode(p.harus(http.StatusOK, "admin", "GET", "/api/produk?kategori=Curah&satuan=kg&sort=sku", nil), &daftar)
+	if daftar.Meta.Total != 2 || daftar.Data[0].SKU != "BRG-3" || daftar.Meta.Dir != "asc" {
+		t.Errorf("filter kategori = %+v %+v, ingin BRG-3 dan BRG-4", daftar.Meta, daftar.Data)
+	}
+	p.decode(p.harus(http.StatusOK, "admin", "GET", "/api/produk?page=9", nil), &daftar)
+	if daftar.Meta.Total != 5 || len(daftar.Data) != 0 {
+		t.Errorf("halaman di luar jangkauan = %+v, ingin data kosong dengan total 5", daftar)
+	}
+
+	// Semua parameter yang salah dilaporkan sekaligus
+	var galat struct {
+		Fields map[string]string `json:"fields"`
+	}
+	p.decode(p.harus(http.StatusBadRequest, "admin", "GET", "/api/produk?page=0&limit=500&sort=deskripsi&dir=naik&supplier_id=x&include_deleted=mungkin", nil), &galat)
+	for _, field := range []string{"page", "limit", "sort", "dir", "supplier_id", "include_deleted"} {
+		if galat.Fields[field] == "" {
+			t.Errorf("kesalahan %s tidak dilaporkan: %v", field, galat.Fields)
+		}
+	}
+	p.harus(http.StatusBadRequest, "admin", "GET", "/api/pembelian?dari=2024-06-02&sampai=2024-06-01", nil)
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"log"
+	"net/http"
+
+	"scm-api/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// =================================================================
+// HANDLER UNTUK MODUL DASHBOARD
+// =================================================================
+
+func (s *server) getDashboardStatsHandler(c *gin.Context) {
+	ctx := c.Request.Context()
+	var stats models.DashboardStats
+	var err error
+
+	// Hitung jumlah produk
+	if stats.JumlahProduk, err = s.produk.CountProduk(ctx); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menghitung produk"})
+		return
+	}
+
+	// Hitung jumlah supplier
+	if stats.JumlahSupplier, err = s.supplier.CountSupplier(ctx); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menghitung supplier"})
+		return
+	}
+
+	// Hitung jumlah pembelian
+	if stats.JumlahPembelian, err = s.pembelian.CountPembelian(ctx); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menghitung pembelian"})
+		return
+	}
+
+	// Hitung jumlah gudang
+	if stats.JumlahGudang, err = s.gudang.CountGudang(ctx); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menghitung gudang"})
+		return
+	}
+
+	// Jika semua berhasil, kirim data statistik sebagai respons JSON
+	c.JSON(http.StatusOK, stats)
+}
+
+// HANDLER UNTUK DATA GRAFIK STOK
+// ===============================
+func (s *server) getStokChartHandler(c *gin.Context) {
+	chartData, err := s.stok.StokPerProduk(c.Request.Context())
+	if err != nil {
+		log.Printf("Error mengambil data grafik stok: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil data stok untuk grafik"})
+		return
+	}
+	c.JSON(http.StatusOK, chartData)
+}
+
+// HANDLER UNTUK 5 PEMBELIAN TERAKHIR
+// ===================================
+func (s *server) getPembelianTerakhirHandler(c *gin.Context) {
+	daftarPembelian, err := s.pembelian.RecentPembelian(c.Request.Context(), 5)
+	if err != nil {
+		log.Printf("Error mengambil pembelian terakhir: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil data pembelian"})
+		return
+	}
+	c.JSON(http.StatusOK, daftarPembelian)
+}
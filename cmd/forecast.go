@@ -0,0 +1,157 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"time"
+
+	"scm-api/internal/forecast"
+	"scm-api/internal/models"
+	"scm-api/internal/store"
+
+	"github.com/gin-gonic/gin"
+)
+
+// =================================================================
+// HANDLER UNTUK PERAMALAN PERMINTAAN
+// =================================================================
+
+// Bawaan dan batas peramalan, dalam hari
+const (
+	hariRiwayatBawaan = 56
+	hariRiwayatMaks   = 365
+	horizonBawaan     = 14
+	horizonMaks       = 90
+)
+
+// titikDeret adalah pemakaian atau ramalan satu hari
+type titikDeret struct {
+	Tanggal string  `json:"tanggal"`
+	Jumlah  float64 `json:"jumlah"`
+}
+
+// getForecastHandler meramalkan permintaan harian satu produk dari mutasi keluar
+// hari-hari sebelumnya. Tanpa gudang_id, penjualan semua gudang pengguna dijumlahkan;
+// transfer antar gudang tidak dihitung agar barang yang dipindah lalu dijual tidak
+// terhitung dua kali.
+// Query: produk_id (wajib), gudang_id, hari (panjang riwayat, bawaan 56),
+// horizon (bawaan 14), metode (bawaan otomatis).
+func (s *server) getForecastHandler(c *gin.Context) {
+	produkID, ok := queryID(c, "produk_id")
+	if !ok {
+		return
+	}
+	gudangID, ok := queryID(c, "gudang_id")
+	if !ok {
+		return
+	}
+	hari, horizon, metode := hariRiwayatBawaan, horizonBawaan, c.DefaultQuery("metode", forecast.MetodeOtomatis)
+	fe := make(fieldErrors)
+	queryAngka(c, fe, "hari", &hari)
+	queryAngka(c, fe, "horizon", &horizon)
+	if produkID == 0 {
+		fe.add("produk_id", "wajib diisi")
+	}
+	if hari < forecast.JendelaBawaan || hari > hariRiwayatMaks {
+		fe.add("hari", fmt.Sprintf("harus antara %d dan %d", forecast.JendelaBawaan, hariRiwayatMaks))
+	}
+	if horizon < 1 || horizon > horizonMaks {
+		fe.add("horizon", fmt.Sprintf("harus antara 1 dan %d", horizonMaks))
+	}
+	if !forecast.Valid(metode) {
+		fe.add("metode", "harus salah satu dari rata_bergerak, eksponensial, musiman, otomatis")
+	}
+	if fe.respond(c) {
+		return
+	}
+	l, ok := s.gudangSaya(c)
+	if !ok || (gudangID != 0 && !s.bolehGudang(c, gudangID)) {
+		return
+	}
+
+	ctx := c.Request.Context()
+	p, err := s.produk.GetProduk(ctx, produkID)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Produk tidak ditemukan"})
+			return
+		}
+		log.Printf("Error mengambil produk %d untuk peramalan: %v", produkID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal meramalkan permintaan"})
+		return
+	}
+
+	// Riwayat berakhir kemarin agar hari ini yang belum selesai tidak menurunkan ramalan
+	hariIni, _ := time.Parse("2006-01-02", time.Now().Format("2006-01-02"))
+	awal, akhir := hariIni.AddDate(0, 0, -hari), hariIni.AddDate(0, 0, -1)
+	tipe := models.TipeMutasiPermintaan
+	if gudangID != 0 {
+		tipe = models.TipeMutasiPemakaian
+	}
+	riwayat, err := s.replenishment.PemakaianHarian(ctx, awal.Format("2006-01-02"), akhir.Format("2006-01-02"), produkID, gudangID, tipe)
+	if err != nil {
+		log.Printf("Error mengambil pemakaian produk %d: %v", produkID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal meramalkan permintaan"})
+		return
+	}
+	perTanggal := make(map[string]float64)
+	for _, r := range riwayat {
+		if l.boleh(r.GudangID) {
+			perTanggal[r.Tanggal] += float64(r.Jumlah)
+		}
+	}
+	deret := forecast.Deret(awal, hari, perTanggal)
+
+	// Akurasi semua metode tetap dikirim agar dashboard bisa membandingkannya
+	terbaik, akurasi := forecast.Pilih(deret, awal, forecast.Opsi{})
+	if metode == forecast.MetodeOtomatis {
+		metode = terbaik
+	}
+	for i := range akurasi {
+		akurasi[i].MAE = bulat2(akurasi[i].MAE)
+		akurasi[i].RMSE = bulat2(akurasi[i].RMSE)
+		if akurasi[i].MAPE != nil {
+			mape := bulat2(*akurasi[i].MAPE)
+			akurasi[i].MAPE = &mape
+		}
+	}
+
+	titikRiwayat := make([]titikDeret, len(deret))
+	for i, v := range deret {
+		titikRiwayat[i] = titikDeret{awal.AddDate(0, 0, i).Format("2006-01-02"), v}
+	}
+	var total float64
+	ramalan := forecast.Ramal(metode, deret, awal, horizon, forecast.Opsi{})
+	titikRamalan := make([]titikDeret, len(ramalan))
+	for i, v := range ramalan {
+		total += v
+		titikRamalan[i] = titikDeret{hariIni.AddDate(0, 0, i).Format("2006-01-02"), bulat2(v)}
+	}
+
+	var gudang *int64
+	if gudangID != 0 {
+		gudang = &gudangID
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"produk_id":        p.ProdukID,
+		"nama_produk":      p.NamaProduk,
+		"satuan":           p.Satuan,
+		"gudang_id":        gudang,
+		"dari":             awal.Format("2006-01-02"),
+		"sampai":           akhir.Format("2006-01-02"),
+		"metode":           metode,
+		"riwayat":          titikRiwayat,
+		"ramalan":          titikRamalan,
+		"total_ramalan":    bulat2(total),
+		"rata_rata_harian": bulat2(total / float64(horizon)),
+		"akurasi":          akurasi,
+	})
+}
+
+// bulat2 membulatkan ke dua angka desimal untuk respons
+func bulat2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
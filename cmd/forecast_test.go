@@ -0,0 +1,55 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"scm-api/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestForecastTidakMenghitungTransferDuaKali(t *testing.T) {
+	p := pengujiBaru(t)
+	p.dataDasar()
+
+	// Riwayat peramalan berakhir kemarin, jadi semua mutasi dicatat kemarin
+	kemarin := time.Now().AddDate(0, 0, -1)
+	p.store.AturWaktu(func() time.Time { return kemarin })
+	p.harus(http.StatusOK, "admin", "POST", "/api/stok/adjust", gin.H{"produk_id": 1, "gudang_id": 1, "jumlah": 5})
+	var tr models.Transfer
+	p.decode(p.harus(http.StatusCreated, "admin", "POST", "/api/transfer", gin.H{
+		"gudang_asal_id": 1, "gudang_tujuan_id": 2, "details": []gin.H{{"produk_id": 1, "jumlah": 3}},
+	}), &tr)
+	p.harus(http.StatusOK, "admin", "PUT", fmt.Sprintf("/api/transfer/%d/kirim", tr.TransferID), nil)
+	p.harus(http.StatusOK, "admin", "PUT", fmt.Sprintf("/api/transfer/%d/terima", tr.TransferID), nil)
+	p.harus(http.StatusCreated, "admin", "POST", "/api/stok/penjualan", gin.H{"produk_id": 1, "gudang_id": 2, "jumlah": 3})
+	p.store.AturWaktu(time.Now)
+
+	for _, tc := range []struct {
+		kueri string
+		ingin float64
+	}{
+		{"", 3},             // hanya penjualan di gudang 2
+		{"&gudang_id=1", 3}, // transfer keluar adalah pemakaian gudang 1
+		{"&gudang_id=2", 3},
+	} {
+		var respons struct {
+			Riwayat []titikDeret `json:"riwayat"`
+		}
+		p.decode(p.harus(http.StatusOK, "admin", "GET", "/api/forecast?produk_id=1&hari=7"+tc.kueri, nil), &respons)
+		var total float64
+		for _, r := range respons.Riwayat {
+			total += r.Jumlah
+		}
+		if total != tc.ingin {
+			t.Errorf("forecast%s: total permintaan %v, ingin %v", tc.kueri, total, tc.ingin)
+		}
+	}
+
+	p.harus(http.StatusBadRequest, "admin", "GET", "/api/forecast?produk_id=1&hari=6", nil)
+	p.harus(http.StatusBadRequest, "admin", "GET", "/api/forecast?produk_id=1&hari=366", nil)
+	p.harus(http.StatusOK, "admin", "GET", "/api/forecast?produk_id=1&hari=365", nil)
+}
@@ -0,0 +1,228 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"scm-api/internal/gambar"
+	"scm-api/internal/models"
+	"scm-api/internal/penyimpanan"
+	"scm-api/internal/store"
+
+	"github.com/gin-gonic/gin"
+)
+
+// =================================================================
+// HANDLER UNTUK GAMBAR PRODUK DAN BERKAS MEDIA
+// =================================================================
+
+// uploadGambarProdukHandler menerima satu gambar di field multipart "gambar",
+// menyimpannya beserta thumbnail, lalu mengganti gambar lama produk
+func (s *server) uploadGambarProdukHandler(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+	ctx := c.Request.Context()
+	sebelum, ok := s.produkUntukDiubah(c, id)
+	if !ok {
+		return
+	}
+
+	// sisakan ruang untuk header multipart di luar isi berkas
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, int64(s.ukuranGambarMaks)+64<<10)
+	fh, err := c.FormFile("gambar")
+	if err != nil {
+		var terlaluBesar *http.MaxBytesError
+		if errors.As(err, &terlaluBesar) {
+			s.tolakUkuranGambar(c)
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Kirim berkas gambar sebagai multipart/form-data di field gambar"})
+		return
+	}
+	if fh.Size > int64(s.ukuranGambarMaks) {
+		s.tolakUkuranGambar(c)
+		return
+	}
+	f, err := fh.Open()
+	if err != nil {
+		log.Printf("Error membuka berkas unggahan: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal membaca berkas"})
+		return
+	}
+	isi, err := io.ReadAll(f)
+	f.Close()
+	if err != nil {
+		log.Printf("Error membaca berkas unggahan: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal membaca berkas"})
+		return
+	}
+
+	hasil, err := gambar.Proses(isi, s.sisiThumbnail)
+	switch {
+	case errors.Is(err, gambar.ErrFormat):
+		c.JSON(http.StatusUnsupportedMediaType, gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, gambar.ErrDimensi):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	case err != nil:
+		log.Printf("Error memproses gambar produk %d: %v", id, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memproses gambar"})
+		return
+	}
+
+	// nama acak membuat URL baru setiap kali gambar diganti, sehingga cache lama tidak terpakai
+	acak := make([]byte, 12)
+	if _, err := rand.Read(acak); err != nil {
+		log.Printf("Error membuat nama berkas: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menyimpan gambar"})
+		return
+	}
+	dasar := fmt.Sprintf("produk/%d/%s", id, hex.EncodeToString(acak))
+	kunciAsli, kunciThumb := dasar+"."+hasil.Ekstensi, dasar+"-thumb.jpg"
+
+	if err := s.media.Simpan(ctx, kunciAsli, isi, hasil.Tipe); err != nil {
+		log.Printf("Error menyimpan gambar produk %d: %v", id, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menyimpan gambar"})
+		return
+	}
+	if err := s.media.Simpan(ctx, kunciThumb, hasil.Thumbnail, "image/jpeg"); err != nil {
+		log.Printf("Error menyimpan thumbnail produk %d: %v", id, err)
+		s.hapusMedia(ctx, s.urlMedia(kunciAsli))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menyimpan gambar"})
+		return
+	}
+	urlAsli := sql.NullString{String: s.urlMedia(kunciAsli), Valid: true}
+	urlThumb := sql.NullString{String: s.urlMedia(kunciThumb), Valid: true}
+	if err := s.produk.SetGambarProduk(ctx, id, urlAsli, urlThumb); err != nil {
+		log.Printf("Error menyimpan URL gambar produk %d: %v", id, err)
+		s.hapusMedia(ctx, urlAsli.String, urlThumb.String)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menyimpan gambar"})
+		return
+	}
+	s.hapusMedia(ctx, sebelum.GambarProduk.String, sebelum.GambarThumbnail.String)
+	s.selesaiUbahGambar(c, id, sebelum)
+}
+
+// hapusGambarProdukHandler mengosongkan gambar produk dan menghapus berkasnya
+func (s *server) hapusGambarProdukHandler(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+	sebelum, ok := s.produkUntukDiubah(c, id)
+	if !ok {
+		return
+	}
+	if !sebelum.GambarProduk.Valid {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Produk belum memiliki gambar"})
+		return
+	}
+	if err := s.produk.SetGambarProduk(c.Request.Context(), id, sql.NullString{}, sql.NullString{}); err != nil {
+		log.Printf("Error menghapus gambar produk %d: %v", id, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menghapus gambar"})
+		return
+	}
+	s.hapusMedia(c.Request.Context(), sebelum.GambarProduk.String, sebelum.GambarThumbnail.String)
+	s.selesaiUbahGambar(c, id, sebelum)
+}
+
+// produkUntukDiubah mengambil produk yang gambar atau satuannya akan diubah. Produk
+// yang sudah dihapus ditolak seperti pada updateProdukHandler.
+func (s *server) produkUntukDiubah(c *gin.Context, id int64) (models.Produk, bool) {
+	p, err := s.produk.GetProduk(c.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Produk tidak ditemukan"})
+			return p, false
+		}
+		log.Printf("Error mengambil produk %d: %v", id, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Terjadi kesalahan internal"})
+		return p, false
+	}
+	if p.DeletedAt.Valid {
+		c.JSON(http.StatusConflict, gin.H{"error": "Produk sudah dihapus; pulihkan dulu sebelum mengubahnya"})
+		return p, false
+	}
+	return p, true
+}
+
+// selesaiUbahGambar mencatat audit dan mengirim produk terbaru setelah gambarnya berubah
+func (s *server) selesaiUbahGambar(c *gin.Context, id int64, sebelum models.Produk) {
+	p, err := s.produk.GetProduk(c.Request.Context(), id)
+	if err != nil {
+		log.Printf("Error mengambil produk %d: %v", id, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Terjadi kesalahan internal"})
+		return
+	}
+	s.indeksProduk.Kosongkan()
+	s.catatAudit(c, "produk", id, models.AuditUbah, sebelum, p)
+	c.JSON(http.StatusOK, p)
+}
+
+func (s *server) tolakUkuranGambar(c *gin.Context) {
+	c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": fmt.Sprintf("Ukuran gambar maksimal %d byte", s.ukuranGambarMaks)})
+}
+
+// urlMedia menyusun URL publik untuk kunci berkas
+func (s *server) urlMedia(kunci string) string {
+	return s.urlDasarMedia + "/" + kunci
+}
+
+// hapusMedia menghapus berkas milik URL yang dibuat oleh urlMedia. URL lain
+// (misalnya gambar lama yang diisi manual atau base_url yang sudah diganti) dilewati.
+// Kegagalan hanya dicatat di log karena data produk sudah tersimpan.
+func (s *server) hapusMedia(ctx context.Context, urls ...string) {
+	for _, u := range urls {
+		kunci, ok := strings.CutPrefix(u, s.urlDasarMedia+"/")
+		if !ok || !penyimpanan.KunciValid(kunci) {
+			continue
+		}
+		if err := s.media.Hapus(ctx, kunci); err != nil {
+			log.Printf("Error menghapus berkas media %s: %v", kunci, err)
+		}
+	}
+}
+
+// mediaHandler menyajikan berkas media tanpa login agar bisa dipakai langsung di
+// tag <img>. Nama berkas berisi bagian acak dan tidak pernah ditimpa, jadi boleh
+// di-cache selamanya.
+func (s *server) mediaHandler(c *gin.Context) {
+	kunci := strings.TrimPrefix(c.Param("kunci"), "/")
+	if !penyimpanan.KunciValid(kunci) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Berkas tidak ditemukan"})
+		return
+	}
+	b, err := s.media.Buka(c.Request.Context(), kunci)
+	if err != nil {
+		if errors.Is(err, penyimpanan.ErrTidakAda) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Berkas tidak ditemukan"})
+			return
+		}
+		log.Printf("Error membuka berkas media %s: %v", kunci, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal membaca berkas"})
+		return
+	}
+	defer b.Close()
+	c.Header("Content-Type", b.Tipe)
+	c.Header("Cache-Control", "public, max-age=31536000, immutable")
+	c.Header("X-Content-Type-Options", "nosniff")
+	if b.Ukuran >= 0 {
+		c.Header("Content-Length", strconv.FormatInt(b.Ukuran, 10))
+	}
+	c.Status(http.StatusOK)
+	if _, err := io.Copy(c.Writer, b); err != nil {
+		log.Printf("Error mengirim berkas media %s: %v", kunci, err)
+	}
+}
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"log"
+	"net/http"
+
+	"scm-api/internal/models"
+	"scm-api/internal/store"
+
+	"github.com/gin-gonic/gin"
+)
+
+// =================================================================
+// HANDLER UNTUK MODUL GUDANG
+// =================================================================
+
+func (s *server) getGudangHandler(c *gin.Context) {
+	q, ok := bacaKueri(c, store.DaftarGudang)
+	if !ok {
+		return
+	}
+	daftarGudang, total, err := s.gudang.ListGudang(c.Request.Context(), q)
+	if err != nil {
+		log.Printf("Error mengambil gudang: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil data gudang"})
+		return
+	}
+	kirimHalaman(c, daftarGudang, total, q)
+}
+
+func (s *server) getGudangByIdHandler(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+	g, err := s.gudang.GetGudang(c.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Gudang tidak ditemukan"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Terjadi kesalahan internal"})
+		return
+	}
+	c.JSON(http.StatusOK, g)
+}
+
+func (s *server) createGudangHandler(c *gin.Context) {
+	var g models.Gudang
+	if err := c.ShouldBindJSON(&g); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Data JSON tidak valid"})
+		return
+	}
+	g.DeletedAt = sql.NullString{}
+	if err := s.gudang.CreateGudang(c.Request.Context(), &g); err != nil {
+		log.Printf("Error menyimpan gudang: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menyimpan gudang"})
+		return
+	}
+	s.catatAudit(c, "gudang", g.GudangID, models.AuditBuat, nil, g)
+	c.JSON(http.StatusCreated, g)
+}
+
+func (s *server) updateGudangHandler(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+	var g models.Gudang
+	if err := c.ShouldBindJSON(&g); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Data JSON tidak valid"})
+		return
+	}
+	sebelum, err := s.gudang.GetGudang(c.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Gudang tidak ditemukan"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengupdate gudang"})
+		return
+	}
+	if sebelum.DeletedAt.Valid {
+		c.JSON(http.StatusConflict, gin.H{"error": "Gudang sudah diarsipkan; pulihkan dulu sebelum mengubahnya"})
+		return
+	}
+	g.GudangID = id
+	g.DeletedAt = sebelum.DeletedAt
+	if err := s.gudang.UpdateGudang(c.Request.Context(), g); err != nil {
+		log.Printf("Error mengupdate gudang: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengupdate gudang"})
+		return
+	}
+	s.catatAudit(c, "gudang", id, models.AuditUbah, sebelum, g)
+	c.JSON(http.StatusOK, gin.H{"message": "Gudang berhasil diupdate"})
+}
+
+func (s *server) deleteGudangHandler(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+	sebelum, err := s.gudang.GetGudang(c.Request.Context(), id)
+	if err == nil && sebelum.DeletedAt.Valid {
+		c.JSON(http.StatusConflict, gin.H{"error": "Gudang sudah diarsipkan"})
+		return
+	}
+	if err == nil {
+		err = s.gudang.DeleteGudang(c.Request.Context(), id)
+	}
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Gudang tidak ditemukan"})
+			return
+		}
+		if errors.Is(err, store.ErrStokTersisa) {
+			c.JSON(http.StatusConflict, gin.H{"error": "Gudang tidak bisa diarsipkan: " + err.Error()})
+			return
+		}
+		log.Printf("Error mengarsipkan gudang: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengarsipkan gudang"})
+		return
+	}
+	s.catatAudit(c, "gudang", id, models.AuditHapus, sebelum, nil)
+	c.JSON(http.StatusOK, gin.H{"message": "Gudang berhasil diarsipkan"})
+}
+
+// pulihkanGudangHandler mengaktifkan kembali gudang yang diarsipkan
+func (s *server) pulihkanGudangHandler(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+	sebelum, err := s.gudang.GetGudang(c.Request.Context(), id)
+	if err == nil && !sebelum.DeletedAt.Valid {
+		c.JSON(http.StatusConflict, gin.H{"error": "Gudang tidak sedang diarsipkan"})
+		return
+	}
+	if err == nil {
+		err = s.gudang.PulihkanGudang(c.Request.Context(), id)
+	}
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Gudang tidak ditemukan"})
+			return
+		}
+		log.Printf("Error memulihkan gudang %d: %v", id, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memulihkan gudang"})
+		return
+	}
+	g := sebelum
+	g.DeletedAt = sql.NullString{}
+	s.catatAudit(c, "gudang", id, models.AuditPulihkan, sebelum, g)
+	c.JSON(http.StatusOK, g)
+}
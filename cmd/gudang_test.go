@@ -0,0 +1,43 @@
+package main
+
+import (
+	"net/http"
+	"testing"
+
+	"scm-api/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestArsipGudang(t *testing.T) {
+	p := pengujiBaru(t)
+	p.dataDasar()
+
+	// Gudang yang masih menyimpan stok tidak bisa diarsipkan
+	p.harus(http.StatusOK, "admin", "POST", "/api/stok/adjust", gin.H{"produk_id": 1, "gudang_id": 2, "jumlah": 5})
+	p.harus(http.StatusConflict, "admin", "DELETE", "/api/gudang/2", nil)
+	p.harus(http.StatusOK, "admin", "POST", "/api/stok/adjust", gin.H{"produk_id": 1, "gudang_id": 2, "jumlah": 0})
+	p.harus(http.StatusOK, "admin", "DELETE", "/api/gudang/2", nil)
+	p.harus(http.StatusConflict, "admin", "DELETE", "/api/gudang/2", nil)
+
+	var daftar models.Halaman[models.Gudang]
+	p.decode(p.harus(http.StatusOK, "admin", "GET", "/api/gudang", nil), &daftar)
+	if daftar.Meta.Total != 1 || daftar.Data[0].GudangID != 1 {
+		t.Errorf("daftar gudang = %+v, ingin hanya gudang 1", daftar.Data)
+	}
+
+	// Gudang arsip ditolak sebagai tujuan transaksi baru
+	p.harus(http.StatusBadRequest, "admin", "POST", "/api/stok/adjust", gin.H{"produk_id": 1, "gudang_id": 2, "jumlah": 1})
+	p.harus(http.StatusBadRequest, "admin", "POST", "/api/pembelian", gin.H{
+		"supplier_id": 1, "tanggal_pesan": "2024-05-01", "gudang_tujuan_id": 2,
+		"details": []gin.H{{"produk_id": 1, "jumlah": 1, "harga_beli_satuan": 1000}},
+	})
+	p.harus(http.StatusOK, "admin", "POST", "/api/stok/adjust", gin.H{"produk_id": 1, "gudang_id": 1, "jumlah": 3})
+	p.harus(http.StatusBadRequest, "admin", "POST", "/api/transfer", gin.H{
+		"gudang_asal_id": 1, "gudang_tujuan_id": 2, "details": []gin.H{{"produk_id": 1, "jumlah": 1}},
+	})
+
+	p.harus(http.StatusOK, "admin", "PUT", "/api/gudang/2/pulihkan", nil)
+	p.harus(http.StatusConflict, "admin", "PUT", "/api/gudang/2/pulihkan", nil)
+	p.harus(http.StatusOK, "admin", "POST", "/api/stok/adjust", gin.H{"produk_id": 1, "gudang_id": 2, "jumlah": 1})
+}
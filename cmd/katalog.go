@@ -0,0 +1,337 @@
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"scm-api/internal/models"
+	"scm-api/internal/store"
+
+	"github.com/gin-gonic/gin"
+)
+
+// =================================================================
+// HANDLER UNTUK KATALOG HARGA SUPPLIER
+// =================================================================
+
+// Batas isian katalog
+const (
+	panjangSKUSupplierMaks = 64
+	leadTimeMaks           = 365
+)
+
+// katalogRequest adalah body JSON untuk membuat dan mengganti baris katalog.
+// Satuan kosong berarti satuan dasar produk; moq kosong berarti 1.
+type katalogRequest struct {
+	ProdukID      int64   `json:"produk_id"`
+	SKUSupplier   *string `json:"sku_supplier"`
+	Satuan        string  `json:"satuan"`
+	HargaBeli     float64 `json:"harga_beli"`
+	MOQ           *int    `json:"moq"`
+	LeadTimeHari  int     `json:"lead_time_hari"`
+	BerlakuDari   *string `json:"berlaku_dari"`
+	BerlakuSampai *string `json:"berlaku_sampai"`
+}
+
+func (s *server) getKatalogSupplierHandler(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+	q, ok := bacaKueri(c, store.DaftarKatalog)
+	if !ok {
+		return
+	}
+	if _, ok := s.supplierUntukKatalog(c, id, false); !ok {
+		return
+	}
+	q.Filter["supplier_id"] = strconv.FormatInt(id, 10)
+	daftar, total, err := s.katalog.ListKatalog(c.Request.Context(), q)
+	if err != nil {
+		log.Printf("Error mengambil katalog supplier %d: %v", id, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil katalog supplier"})
+		return
+	}
+	kirimHalaman(c, daftar, total, q)
+}
+
+func (s *server) getKatalogByIdHandler(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+	k, ok := s.katalogSupplier(c, id)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, k)
+}
+
+func (s *server) createKatalogHandler(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+	var req katalogRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Data JSON tidak valid: " + err.Error()})
+		return
+	}
+	if _, ok := s.supplierUntukKatalog(c, id, true); !ok {
+		return
+	}
+	k, ok := s.katalogDariRequest(c, id, 0, req)
+	if !ok {
+		return
+	}
+	if err := s.katalog.CreateKatalog(c.Request.Context(), &k); err != nil {
+		log.Printf("Error menyimpan katalog supplier %d: %v", id, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menyimpan katalog"})
+		return
+	}
+	s.catatAudit(c, "katalog", k.SupplierProdukID, models.AuditBuat, nil, k)
+	c.JSON(http.StatusCreated, k)
+}
+
+func (s *server) updateKatalogHandler(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+	var req katalogRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Data JSON tidak valid: " + err.Error()})
+		return
+	}
+	if _, ok := s.supplierUntukKatalog(c, id, true); !ok {
+		return
+	}
+	sebelum, ok := s.katalogSupplier(c, id)
+	if !ok {
+		return
+	}
+	k, ok := s.katalogDariRequest(c, id, sebelum.SupplierProdukID, req)
+	if !ok {
+		return
+	}
+	k.SupplierProdukID = sebelum.SupplierProdukID
+	ctx := c.Request.Context()
+	if err := s.katalog.UpdateKatalog(ctx, k); err != nil {
+		log.Printf("Error mengubah katalog %d: %v", k.SupplierProdukID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengubah katalog"})
+		return
+	}
+	sesudah, err := s.katalog.GetKatalog(ctx, k.SupplierProdukID)
+	if err != nil {
+		log.Printf("Error mengambil katalog %d: %v", k.SupplierProdukID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Terjadi kesalahan internal"})
+		return
+	}
+	s.catatAudit(c, "katalog", k.SupplierProdukID, models.AuditUbah, sebelum, sesudah)
+	c.JSON(http.StatusOK, sesudah)
+}
+
+func (s *server) deleteKatalogHandler(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+	k, ok := s.katalogSupplier(c, id)
+	if !ok {
+		return
+	}
+	if err := s.katalog.DeleteKatalog(c.Request.Context(), k.SupplierProdukID); err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Katalog tidak ditemukan"})
+			return
+		}
+		log.Printf("Error menghapus katalog %d: %v", k.SupplierProdukID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menghapus katalog"})
+		return
+	}
+	s.catatAudit(c, "katalog", k.SupplierProdukID, models.AuditHapus, k, nil)
+	c.JSON(http.StatusOK, gin.H{"message": "Katalog berhasil dihapus"})
+}
+
+// getKatalogProdukHandler membandingkan harga produk dari semua supplier yang
+// berlaku pada ?tanggal=YYYY-MM-DD (bawaan hari ini), termurah lebih dulu
+func (s *server) getKatalogProdukHandler(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+	tanggal := c.DefaultQuery("tanggal", time.Now().Format("2006-01-02"))
+	if tanggal == "" || !tanggalValid(tanggal) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Validasi gagal", "fields": gin.H{"tanggal": "harus berformat YYYY-MM-DD"}})
+		return
+	}
+	if _, err := s.produk.GetProduk(c.Request.Context(), id); err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Produk tidak ditemukan"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Terjadi kesalahan internal"})
+		return
+	}
+	daftar, err := s.katalog.KatalogAktif(c.Request.Context(), 0, id, tanggal)
+	if err != nil {
+		log.Printf("Error mengambil katalog produk %d: %v", id, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil katalog produk"})
+		return
+	}
+	c.JSON(http.StatusOK, daftar)
+}
+
+// supplierUntukKatalog memastikan supplier ada. Jika ubah bernilai true, supplier
+// yang sudah dihapus juga ditolak karena katalognya tidak boleh diubah.
+func (s *server) supplierUntukKatalog(c *gin.Context, id int64, ubah bool) (models.Supplier, bool) {
+	sp, err := s.supplier.GetSupplier(c.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Supplier tidak ditemukan"})
+			return sp, false
+		}
+		log.Printf("Error mengambil supplier %d: %v", id, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Terjadi kesalahan internal"})
+		return sp, false
+	}
+	if ubah && sp.DeletedAt.Valid {
+		c.JSON(http.StatusConflict, gin.H{"error": "Supplier sudah dihapus; pulihkan dulu sebelum mengubah katalognya"})
+		return sp, false
+	}
+	return sp, true
+}
+
+// katalogSupplier mengambil baris katalog dari parameter :katalog_id dan memastikan
+// baris itu milik supplier id
+func (s *server) katalogSupplier(c *gin.Context, supplierID int64) (models.SupplierProduk, bool) {
+	id, ok := paramID(c, "katalog_id")
+	if !ok {
+		return models.SupplierProduk{}, false
+	}
+	k, err := s.katalog.GetKatalog(c.Request.Context(), id)
+	if err != nil && !errors.Is(err, store.ErrNotFound) {
+		log.Printf("Error mengambil katalog %d: %v", id, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Terjadi kesalahan internal"})
+		return k, false
+	}
+	if err != nil || k.SupplierID != supplierID {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Katalog tidak ditemukan di supplier ini"})
+		return k, false
+	}
+	return k, true
+}
+
+// katalogDariRequest memeriksa body katalog untuk supplier lalu menyusun barisnya.
+// Masa berlaku tidak boleh beririsan dengan baris lain untuk produk dan satuan yang
+// sama di supplier ini (kecuali baris kecuali, yaitu baris yang sedang diganti),
+// agar harga aktif pada satu tanggal selalu tunggal. Jika ada kesalahan, respons
+// sudah dikirim dan ok bernilai false.
+func (s *server) katalogDariRequest(c *gin.Context, supplierID, kecuali int64, req katalogRequest) (models.SupplierProduk, bool) {
+	ctx := c.Request.Context()
+	k := models.SupplierProduk{
+		SupplierID:   supplierID,
+		ProdukID:     req.ProdukID,
+		HargaBeli:    bulatkanRupiah(req.HargaBeli),
+		MOQ:          1,
+		LeadTimeHari: req.LeadTimeHari,
+	}
+	fe := make(fieldErrors)
+
+	if req.ProdukID < 1 {
+		fe.add("produk_id", "wajib diisi")
+	} else if p, err := s.produk.GetProduk(ctx, req.ProdukID); errors.Is(err, store.ErrNotFound) {
+		fe.add("produk_id", fmt.Sprintf("produk dengan ID %d tidak ditemukan", req.ProdukID))
+	} else if err != nil {
+		log.Printf("Error memeriksa produk %d: %v", req.ProdukID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memeriksa data produk"})
+		return k, false
+	} else if p.DeletedAt.Valid {
+		fe.add("produk_id", fmt.Sprintf("produk dengan ID %d sudah dihapus", req.ProdukID))
+	} else if ps, pesan, err := s.satuanPembelian(c, p, req.Satuan); err != nil {
+		log.Printf("Error memeriksa satuan produk %d: %v", req.ProdukID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memeriksa data produk"})
+		return k, false
+	} else if pesan != "" {
+		fe.add("satuan", pesan)
+	} else {
+		k.Satuan = ps.Satuan
+	}
+
+	if req.SKUSupplier != nil {
+		sku := strings.TrimSpace(*req.SKUSupplier)
+		if utf8.RuneCountInString(sku) > panjangSKUSupplierMaks {
+			fe.add("sku_supplier", fmt.Sprintf("maksimal %d karakter", panjangSKUSupplierMaks))
+		}
+		k.SKUSupplier = sql.NullString{String: sku, Valid: sku != ""}
+	}
+	if req.HargaBeli <= 0 {
+		fe.add("harga_beli", "harus lebih dari 0")
+	}
+	if req.MOQ != nil {
+		if *req.MOQ < 1 {
+			fe.add("moq", "minimal 1")
+		}
+		k.MOQ = *req.MOQ
+	}
+	if req.LeadTimeHari < 0 || req.LeadTimeHari > leadTimeMaks {
+		fe.add("lead_time_hari", fmt.Sprintf("harus antara 0 dan %d", leadTimeMaks))
+	}
+	for field, v := range map[string]*string{"berlaku_dari": req.BerlakuDari, "berlaku_sampai": req.BerlakuSampai} {
+		if v != nil && (*v == "" || !tanggalValid(*v)) {
+			fe.add(field, "harus berformat YYYY-MM-DD")
+		}
+	}
+	if req.BerlakuDari != nil {
+		k.BerlakuDari = sql.NullString{String: *req.BerlakuDari, Valid: true}
+	}
+	if req.BerlakuSampai != nil {
+		k.BerlakuSampai = sql.NullString{String: *req.BerlakuSampai, Valid: true}
+	}
+	if k.BerlakuDari.Valid && k.BerlakuSampai.Valid && k.BerlakuSampai.String < k.BerlakuDari.String {
+		fe.add("berlaku_sampai", "tidak boleh sebelum berlaku_dari")
+	}
+	if fe.respond(c) {
+		return k, false
+	}
+
+	q := store.Kueri{Filter: map[string]string{
+		"supplier_id": strconv.FormatInt(supplierID, 10),
+		"produk_id":   strconv.FormatInt(k.ProdukID, 10),
+	}}
+	lain, _, err := s.katalog.ListKatalog(ctx, q)
+	if err != nil {
+		log.Printf("Error memeriksa katalog supplier %d: %v", supplierID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menyimpan katalog"})
+		return k, false
+	}
+	for _, l := range lain {
+		if l.SupplierProdukID != kecuali && strings.EqualFold(l.Satuan, k.Satuan) && l.Bertumpuk(k) {
+			c.JSON(http.StatusConflict, gin.H{"error": fmt.Sprintf("Masa berlaku beririsan dengan katalog %d untuk produk dan satuan yang sama", l.SupplierProdukID)})
+			return k, false
+		}
+	}
+	return k, true
+}
+
+// hargaKatalog mencari harga supplier yang berlaku pada tanggal untuk produk dalam
+// satuan tersebut. ok bernilai false jika supplier tidak punya harga aktif.
+func (s *server) hargaKatalog(c *gin.Context, supplierID, produkID int64, satuan, tanggal string) (models.SupplierProduk, bool, error) {
+	daftar, err := s.katalog.KatalogAktif(c.Request.Context(), supplierID, produkID, tanggal)
+	if err != nil {
+		return models.SupplierProduk{}, false, err
+	}
+	for _, k := range daftar {
+		if strings.EqualFold(k.Satuan, satuan) {
+			return k, true, nil
+		}
+	}
+	return models.SupplierProduk{}, false, nil
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"strconv"
+
+	"scm-api/internal/database"
+)
+
+// =================================================================
+// SUBCOMMAND MIGRATE
+// =================================================================
+
+const migrateUsage = `Penggunaan: scm-api [-config file] migrate <perintah>
+
+Perintah:
+  up [n]     jalankan n migrasi berikutnya (bawaan: semua)
+  down [n]   batalkan n migrasi terakhir (bawaan: 1)
+  status     tampilkan status setiap migrasi`
+
+// runMigrate menjalankan subcommand "migrate" dengan argumen sisanya
+func runMigrate(db *sql.DB, args []string) {
+	if len(args) == 0 {
+		log.Fatal(migrateUsage)
+	}
+
+	steps := 0
+	if len(args) > 1 {
+		n, err := strconv.Atoi(args[1])
+		if err != nil || n < 1 {
+			log.Fatalf("Jumlah langkah harus bilangan bulat positif, didapat %q", args[1])
+		}
+		steps = n
+	}
+
+	migrator, err := database.NewMigrator(db)
+	if err != nil {
+		log.Fatalf("Gagal memuat migrasi: %v", err)
+	}
+	ctx := context.Background()
+
+	switch args[0] {
+	case "up":
+		done, err := migrator.Up(ctx, steps)
+		for _, m := range done {
+			fmt.Printf("Diterapkan: %04d_%s\n", m.Version, m.Name)
+		}
+		if err != nil {
+			log.Fatalf("Migrasi gagal: %v", err)
+		}
+		if len(done) == 0 {
+			fmt.Println("Skema sudah versi terbaru")
+		}
+	case "down":
+		done, err := migrator.Down(ctx, steps)
+		for _, m := range done {
+			fmt.Printf("Dibatalkan: %04d_%s\n", m.Version, m.Name)
+		}
+		if err != nil {
+			log.Fatalf("Rollback migrasi gagal: %v", err)
+		}
+		if len(done) == 0 {
+			fmt.Println("Tidak ada migrasi yang bisa dibatalkan")
+		}
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			log.Fatalf("Gagal membaca status migrasi: %v", err)
+		}
+		for _, s := range statuses {
+			status := "belum"
+			if s.Applied {
+				status = "sudah (" + s.AppliedAt.Time.Format("2006-01-02 15:04:05") + ")"
+			}
+			fmt.Printf("%04d_%-40s %s\n", s.Version, s.Name, status)
+		}
+	default:
+		log.Fatal(migrateUsage)
+	}
+}
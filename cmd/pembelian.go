@@ -0,0 +1,609 @@
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"time"
+
+	"scm-api/internal/models"
+	"scm-api/internal/store"
+
+	"github.com/gin-gonic/gin"
+)
+
+// =================================================================
+// HANDLER UNTUK MODUL PEMBELIAN
+// =================================================================
+
+func (s *server) getPembelianHandler(c *gin.Context) {
+	q, ok := bacaKueri(c, store.DaftarPembelian)
+	if !ok {
+		return
+	}
+	daftarPembelian, total, err := s.pembelian.ListPembelian(c.Request.Context(), q)
+	if err != nil {
+		log.Printf("Error mengambil pembelian: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil data pembelian"})
+		return
+	}
+	kirimHalaman(c, daftarPembelian, total, q)
+}
+
+// pembelianRequest adalah body POST /api/pembelian. Konversi permintaan pembelian
+// juga menyusun nilai ini agar aturan validasinya sama persis.
+type pembelianRequest struct {
+	SupplierID   int64    `json:"supplier_id"`
+	TanggalPesan string   `json:"tanggal_pesan"`
+	EstimasiTiba *string  `json:"estimasi_tiba"`
+	Status       string   `json:"status"`
+	TotalBiaya   *float64 `json:"total_biaya"`
+	// GudangTujuanID adalah gudang bawaan saat pesanan diterima (opsional)
+	GudangTujuanID *int64                   `json:"gudang_tujuan_id"`
+	Details        []detailPembelianRequest `json:"details"`
+}
+
+type detailPembelianRequest struct {
+	ProdukID int64 `json:"produk_id"`
+	Jumlah   int   `json:"jumlah"`
+	// Satuan beli baris ini (opsional, bawaannya satuan dasar produk);
+	// jumlah dan harga_beli_satuan dinyatakan dalam satuan ini
+	Satuan string `json:"satuan"`
+	// HargaBeliSatuan boleh dikosongkan jika supplier punya harga aktif di katalog
+	HargaBeliSatuan *float64 `json:"harga_beli_satuan"`
+	Subtotal        *float64 `json:"subtotal"`
+
+	// hargaCadangan dipakai jika harga tidak dikirim dan katalog juga tidak punya
+	// harga, misalnya harga perkiraan dari permintaan pembelian
+	hargaCadangan *float64
+}
+
+// createPembelianHandler menyimpan pesanan baru. Subtotal setiap item dan total_biaya
+// dihitung di server; jika klien ikut mengirim nilainya, nilai itu harus sama dengan
+// hasil hitungan. Kesalahan validasi dikembalikan per field:
+//
+//	{"error": "Validasi gagal", "fields": {"details[0].jumlah": "harus lebih dari 0"}}
+func (s *server) createPembelianHandler(c *gin.Context) {
+	if s.wajibPermintaan {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Pesanan baru harus dibuat dari permintaan pembelian yang disetujui (POST /api/permintaan/:id/pembelian)"})
+		return
+	}
+	var req pembelianRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Data JSON tidak valid: " + err.Error()})
+		return
+	}
+	pembelianBaru, details, ok := s.siapkanPembelian(c, req)
+	if !ok {
+		return
+	}
+	total := pembelianBaru.TotalBiaya.Float64
+
+	// Pesanan bernilai besar hanya boleh dibuat oleh pengguna dengan izin khusus (biasanya manajer)
+	if s.batasNilaiPembelian > 0 && total > s.batasNilaiPembelian {
+		ada, ok := s.punyaIzin(c, models.IzinPembelianNilaiBesar)
+		if !ok {
+			return
+		}
+		if !ada {
+			c.JSON(http.StatusForbidden, gin.H{"error": fmt.Sprintf("Total pembelian %.2f melebihi batas %.2f; dibutuhkan izin %s", total, s.batasNilaiPembelian, models.IzinPembelianNilaiBesar)})
+			return
+		}
+	}
+
+	if err := s.pembelian.CreatePembelian(c.Request.Context(), &pembelianBaru, details, aktor(c)); err != nil {
+		log.Printf("Error membuat pembelian: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menyimpan data pembelian"})
+		return
+	}
+	s.catatAudit(c, "pembelian", pembelianBaru.PembelianID, models.AuditBuat, nil, s.pembelianUntukAudit(c, pembelianBaru.PembelianID))
+	c.JSON(http.StatusCreated, gin.H{"message": "Pesanan pembelian berhasil dibuat", "pembelian_id": pembelianBaru.PembelianID, "total_biaya": total})
+}
+
+// siapkanPembelian memvalidasi req lalu menyusun header dan baris pesanan beserta
+// subtotal, total, harga katalog, dan estimasi tiba. Jika ada kesalahan, respons
+// sudah dikirim dan ok bernilai false.
+func (s *server) siapkanPembelian(c *gin.Context, req pembelianRequest) (models.Pembelian, []models.DetailPembelian, bool) {
+	ctx := c.Request.Context()
+	fe := make(fieldErrors)
+
+	// Pesanan baru boleh disimpan sebagai Draft; selain itu langsung Dipesan
+	status := req.Status
+	if status == "" {
+		status = models.StatusPembelianDipesan
+	}
+	if status != models.StatusPembelianDraft && status != models.StatusPembelianDipesan {
+		fe.add("status", "status awal pembelian harus Draft atau Dipesan")
+	}
+
+	// katalogSiap berarti supplier dan tanggal pesan valid sehingga harga katalog bisa dicari
+	katalogSiap := true
+	if req.SupplierID < 1 {
+		katalogSiap = false
+		fe.add("supplier_id", "wajib diisi")
+	} else if sp, err := s.supplier.GetSupplier(ctx, req.SupplierID); errors.Is(err, store.ErrNotFound) {
+		katalogSiap = false
+		fe.add("supplier_id", fmt.Sprintf("supplier dengan ID %d tidak ditemukan", req.SupplierID))
+	} else if err != nil {
+		log.Printf("Error memeriksa supplier %d: %v", req.SupplierID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memeriksa data supplier"})
+		return models.Pembelian{}, nil, false
+	} else if sp.DeletedAt.Valid {
+		katalogSiap = false
+		fe.add("supplier_id", fmt.Sprintf("supplier dengan ID %d sudah dihapus", req.SupplierID))
+	}
+
+	if req.TanggalPesan == "" {
+		katalogSiap = false
+		fe.add("tanggal_pesan", "wajib diisi")
+	} else if !tanggalValid(req.TanggalPesan) {
+		katalogSiap = false
+		fe.add("tanggal_pesan", "harus berformat YYYY-MM-DD")
+	}
+	if req.EstimasiTiba != nil && !tanggalValid(*req.EstimasiTiba) {
+		fe.add("estimasi_tiba", "harus berformat YYYY-MM-DD")
+	}
+
+	if req.GudangTujuanID != nil {
+		if g, err := s.gudang.GetGudang(ctx, *req.GudangTujuanID); errors.Is(err, store.ErrNotFound) {
+			fe.add("gudang_tujuan_id", fmt.Sprintf("gudang dengan ID %d tidak ditemukan", *req.GudangTujuanID))
+		} else if err != nil {
+			log.Printf("Error memeriksa gudang %d: %v", *req.GudangTujuanID, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memeriksa data gudang"})
+			return models.Pembelian{}, nil, false
+		} else if g.DeletedAt.Valid {
+			fe.add("gudang_tujuan_id", fmt.Sprintf("gudang dengan ID %d sudah diarsipkan", *req.GudangTujuanID))
+		}
+	}
+
+	if len(req.Details) == 0 {
+		fe.add("details", "pesanan minimal berisi satu produk")
+	}
+	details := make([]models.DetailPembelian, 0, len(req.Details))
+	var total float64
+	// leadTime adalah lead time katalog terlama; semuaDariKatalog berarti setiap item punya harga katalog
+	leadTime, semuaDariKatalog := 0, katalogSiap
+	for i, d := range req.Details {
+		field := fmt.Sprintf("details[%d]", i)
+		satuan := models.ProdukSatuan{Satuan: d.Satuan, Faktor: 1}
+		satuanValid := false
+		if d.ProdukID < 1 {
+			fe.add(field+".produk_id", "wajib diisi")
+		} else if p, err := s.produk.GetProduk(ctx, d.ProdukID); errors.Is(err, store.ErrNotFound) {
+			fe.add(field+".produk_id", fmt.Sprintf("produk dengan ID %d tidak ditemukan", d.ProdukID))
+		} else if err != nil {
+			log.Printf("Error memeriksa produk %d: %v", d.ProdukID, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memeriksa data produk"})
+			return models.Pembelian{}, nil, false
+		} else if p.DeletedAt.Valid {
+			fe.add(field+".produk_id", fmt.Sprintf("produk dengan ID %d sudah dihapus", d.ProdukID))
+		} else if ps, pesan, err := s.satuanPembelian(c, p, d.Satuan); err != nil {
+			log.Printf("Error memeriksa satuan produk %d: %v", d.ProdukID, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memeriksa data produk"})
+			return models.Pembelian{}, nil, false
+		} else if pesan != "" {
+			fe.add(field+".satuan", pesan)
+		} else {
+			satuan, satuanValid = ps, true
+		}
+		if d.Jumlah <= 0 {
+			fe.add(field+".jumlah", "harus lebih dari 0")
+		} else if d.Jumlah > math.MaxInt32/satuan.Faktor {
+			fe.add(field+".jumlah", fmt.Sprintf("terlalu besar: %d %s melebihi batas stok", d.Jumlah, satuan.Satuan))
+		}
+
+		// Harga kosong diisi dari katalog supplier yang berlaku pada tanggal pesan, dan
+		// jumlahnya tidak boleh di bawah MOQ katalog walaupun harganya ditulis sendiri
+		harga := d.HargaBeliSatuan
+		if katalogSiap && satuanValid {
+			k, ada, err := s.hargaKatalog(c, req.SupplierID, d.ProdukID, satuan.Satuan, req.TanggalPesan)
+			if err != nil {
+				log.Printf("Error mencari harga katalog produk %d: %v", d.ProdukID, err)
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memeriksa katalog supplier"})
+				return models.Pembelian{}, nil, false
+			}
+			if ada {
+				if harga == nil {
+					harga = &k.HargaBeli
+				}
+				if d.Jumlah > 0 && d.Jumlah < k.MOQ {
+					fe.add(field+".jumlah", fmt.Sprintf("minimal pemesanan %d %s menurut katalog supplier", k.MOQ, k.Satuan))
+				}
+				leadTime = max(leadTime, k.LeadTimeHari)
+			}
+			semuaDariKatalog = semuaDariKatalog && ada
+		}
+		if harga == nil {
+			harga = d.hargaCadangan
+		}
+		if harga == nil {
+			fe.add(field+".harga_beli_satuan", "wajib diisi karena supplier belum punya harga aktif untuk produk dan satuan ini")
+			harga = new(float64)
+		} else if *harga <= 0 {
+			fe.add(field+".harga_beli_satuan", "harus lebih dari 0")
+		}
+
+		subtotal := bulatkanRupiah(float64(d.Jumlah) * *harga)
+		if d.Subtotal != nil && !samaRupiah(*d.Subtotal, subtotal) {
+			fe.add(field+".subtotal", fmt.Sprintf("tidak sesuai: jumlah × harga_beli_satuan = %.2f, dikirim %.2f", subtotal, *d.Subtotal))
+		}
+		total += subtotal
+		details = append(details, models.DetailPembelian{
+			ProdukID:        d.ProdukID,
+			Jumlah:          d.Jumlah,
+			Satuan:          satuan.Satuan,
+			FaktorKonversi:  satuan.Faktor,
+			HargaBeliSatuan: *harga,
+			Subtotal:        subtotal,
+		})
+	}
+	total = bulatkanRupiah(total)
+	if req.TotalBiaya != nil && !samaRupiah(*req.TotalBiaya, total) {
+		fe.add("total_biaya", fmt.Sprintf("tidak sesuai: jumlah seluruh subtotal = %.2f, dikirim %.2f", total, *req.TotalBiaya))
+	}
+
+	if fe.respond(c) {
+		return models.Pembelian{}, nil, false
+	}
+
+	pembelianBaru := models.Pembelian{
+		SupplierID:   req.SupplierID,
+		TanggalPesan: req.TanggalPesan,
+		TotalBiaya:   sql.NullFloat64{Float64: total, Valid: true},
+		Status:       status,
+	}
+	if req.EstimasiTiba != nil {
+		pembelianBaru.EstimasiTiba = sql.NullString{String: *req.EstimasiTiba, Valid: true}
+	} else if semuaDariKatalog && leadTime > 0 {
+		// Tanpa estimasi dari klien, pesanan diperkirakan tiba setelah lead time terlama
+		tanggal, _ := time.Parse("2006-01-02", req.TanggalPesan)
+		pembelianBaru.EstimasiTiba = sql.NullString{String: tanggal.AddDate(0, 0, leadTime).Format("2006-01-02"), Valid: true}
+	}
+	if req.GudangTujuanID != nil {
+		pembelianBaru.GudangTujuanID = sql.NullInt64{Int64: *req.GudangTujuanID, Valid: true}
+	}
+	return pembelianBaru, details, true
+}
+
+func (s *server) getPembelianByIdHandler(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+	response, err := s.pembelian.GetPembelian(c.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Pesanan pembelian tidak ditemukan"})
+			return
+		}
+		log.Printf("Error mengambil pembelian %d: %v", id, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil data pembelian"})
+		return
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// HANDLER UNTUK DELETE PEMBELIAN
+// ===============================
+// Hanya pesanan Draft atau Dibatalkan tanpa penerimaan yang bisa dihapus (409 untuk
+// yang lain). Riwayat statusnya tetap disimpan.
+func (s *server) deletePembelianHandler(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+	sebelum, err := s.pembelian.GetPembelian(c.Request.Context(), id)
+	if err == nil {
+		err = s.pembelian.DeletePembelian(c.Request.Context(), id)
+	}
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Pesanan pembelian tidak ditemukan"})
+			return
+		}
+		if errors.Is(err, store.ErrInvalidTransition) && models.BolehHapusPembelian(sebelum.Status) {
+			c.JSON(http.StatusConflict, gin.H{"error": "Pesanan yang sudah memiliki penerimaan barang tidak bisa dihapus"})
+			return
+		}
+		if errors.Is(err, store.ErrInvalidTransition) {
+			c.JSON(http.StatusConflict, gin.H{"error": fmt.Sprintf("Pesanan berstatus %s tidak bisa dihapus; hanya pesanan Draft atau Dibatalkan yang bisa dihapus", sebelum.Status)})
+			return
+		}
+		log.Printf("Error menghapus pembelian %d: %v", id, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menghapus pembelian"})
+		return
+	}
+	s.catatAudit(c, "pembelian", id, models.AuditHapus, sebelum, nil)
+	c.JSON(http.StatusOK, gin.H{"message": "Pesanan pembelian berhasil dihapus"})
+}
+
+// HANDLER UNTUK MENERIMA PESANAN PEMBELIAN & UPDATE STOK
+// ======================================================
+// Body JSON opsional: {"gudang_id": 2}. Tanpa gudang_id, barang masuk ke
+// gudang tujuan bawaan pesanan.
+func (s *server) terimaPembelianHandler(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+	var req struct {
+		GudangID int64 `json:"gudang_id"`
+	}
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Data JSON tidak valid: " + err.Error()})
+			return
+		}
+	}
+	gudangID := req.GudangID
+	if gudangID == 0 {
+		bawaan, ok := s.gudangTujuanPembelian(c, id)
+		if !ok {
+			return
+		}
+		gudangID = bawaan.Int64
+	}
+	// Tanpa gudang sama sekali, store yang menolak penerimaan dengan pesan yang jelas
+	if gudangID != 0 && !s.bolehGudang(c, gudangID) {
+		return
+	}
+	sebelum := s.pembelianUntukAudit(c, id)
+	if err := s.pembelian.TerimaPembelian(c.Request.Context(), id, req.GudangID, aktor(c)); err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Pesanan pembelian tidak ditemukan"})
+			return
+		}
+		if errors.Is(err, store.ErrInvalidTransition) {
+			c.JSON(http.StatusConflict, gin.H{"error": "Pesanan tidak bisa diterima: " + err.Error()})
+			return
+		}
+		if errors.Is(err, store.ErrInvalidReceipt) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		log.Printf("Error menerima pembelian %d: %v", id, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengupdate stok produk"})
+		return
+	}
+	s.catatAudit(c, "pembelian", id, models.AuditUbah, sebelum, s.pembelianUntukAudit(c, id))
+	c.JSON(http.StatusOK, gin.H{"message": "Pesanan berhasil diterima dan stok telah diperbarui"})
+}
+
+// HANDLER UNTUK PENERIMAAN PARSIAL
+// ================================
+// Body JSON:
+//
+//	{
+//	  "tanggal_terima": "2024-05-01 10:00:00",   (opsional, bawaan: sekarang)
+//	  "catatan": "...",                          (opsional)
+//	  "gudang_id": 2,                            (opsional)
+//	  "items": [
+//	    {"produk_id": 1, "jumlah_diterima": 8, "jumlah_ditolak": 2, "alasan_tolak": "kemasan rusak", "gudang_id": 3,
+//	     "nomor_lot": "L2405-01", "tanggal_produksi": "2024-04-28", "tanggal_kedaluwarsa": "2024-05-12"}
+//	  ]
+//	}
+//
+// detail_pembelian_id boleh dipakai sebagai pengganti produk_id, dan wajib
+// jika produk yang sama muncul di lebih dari satu baris pesanan.
+// Gudang tiap baris diambil dari item, lalu gudang_id penerimaan, lalu
+// gudang tujuan bawaan pesanan. Jika nomor_lot atau tanggal_kedaluwarsa diisi,
+// jumlah diterima dicatat sebagai batch baru.
+func (s *server) createPenerimaanHandler(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+	var req struct {
+		TanggalTerima string `json:"tanggal_terima"`
+		Catatan       string `json:"catatan"`
+		GudangID      int64  `json:"gudang_id"`
+		Items         []struct {
+			DetailPembelianID  int64  `json:"detail_pembelian_id"`
+			ProdukID           int64  `json:"produk_id"`
+			GudangID           int64  `json:"gudang_id"`
+			JumlahDiterima     int    `json:"jumlah_diterima"`
+			JumlahDitolak      int    `json:"jumlah_ditolak"`
+			AlasanTolak        string `json:"alasan_tolak"`
+			NomorLot           string `json:"nomor_lot"`
+			TanggalProduksi    string `json:"tanggal_produksi"`
+			TanggalKedaluwarsa string `json:"tanggal_kedaluwarsa"`
+		} `json:"items"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Data JSON tidak valid: " + err.Error()})
+		return
+	}
+	if !waktuValid(req.TanggalTerima) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Tanggal terima harus berformat YYYY-MM-DD atau YYYY-MM-DD HH:MM:SS"})
+		return
+	}
+
+	penerimaan := models.Penerimaan{
+		PembelianID:   id,
+		TanggalTerima: req.TanggalTerima,
+		DiterimaOleh:  aktor(c),
+		Catatan:       sql.NullString{String: req.Catatan, Valid: req.Catatan != ""},
+		GudangID:      req.GudangID,
+		Details:       make([]models.DetailPenerimaan, 0, len(req.Items)),
+	}
+	for i, it := range req.Items {
+		if !tanggalValid(it.TanggalProduksi) || !tanggalValid(it.TanggalKedaluwarsa) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Tanggal produksi/kedaluwarsa harus berformat YYYY-MM-DD (item ke-%d)", i+1)})
+			return
+		}
+		if it.TanggalProduksi != "" && it.TanggalKedaluwarsa != "" && it.TanggalKedaluwarsa < it.TanggalProduksi {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Tanggal kedaluwarsa tidak boleh sebelum tanggal produksi (item ke-%d)", i+1)})
+			return
+		}
+		penerimaan.Details = append(penerimaan.Details, models.DetailPenerimaan{
+			DetailPembelianID:  it.DetailPembelianID,
+			ProdukID:           it.ProdukID,
+			GudangID:           it.GudangID,
+			JumlahDiterima:     it.JumlahDiterima,
+			JumlahDitolak:      it.JumlahDitolak,
+			AlasanTolak:        sql.NullString{String: it.AlasanTolak, Valid: it.AlasanTolak != ""},
+			NomorLot:           sql.NullString{String: it.NomorLot, Valid: it.NomorLot != ""},
+			TanggalProduksi:    sql.NullString{String: it.TanggalProduksi, Valid: it.TanggalProduksi != ""},
+			TanggalKedaluwarsa: sql.NullString{String: it.TanggalKedaluwarsa, Valid: it.TanggalKedaluwarsa != ""},
+		})
+	}
+
+	// Pengguna hanya boleh menerima barang ke gudang tempatnya bekerja
+	bawaan, ok := s.gudangTujuanPembelian(c, id)
+	if !ok {
+		return
+	}
+	if dipakai, err := store.TentukanGudang(&penerimaan, bawaan); err == nil && !s.bolehGudang(c, dipakai...) {
+		return
+	}
+
+	sebelum := s.pembelianUntukAudit(c, id)
+	if err := s.pembelian.CreatePenerimaan(c.Request.Context(), &penerimaan); err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Pesanan pembelian tidak ditemukan"})
+			return
+		}
+		if errors.Is(err, store.ErrInvalidTransition) {
+			c.JSON(http.StatusConflict, gin.H{"error": "Pesanan tidak bisa diterima: " + err.Error()})
+			return
+		}
+		if errors.Is(err, store.ErrInvalidReceipt) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		log.Printf("Error mencatat penerimaan pembelian %d: %v", id, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mencatat penerimaan barang"})
+		return
+	}
+	s.catatAudit(c, "penerimaan", penerimaan.PenerimaanID, models.AuditBuat, nil, penerimaan)
+	s.catatAudit(c, "pembelian", id, models.AuditUbah, sebelum, s.pembelianUntukAudit(c, id))
+	c.JSON(http.StatusCreated, penerimaan)
+}
+
+func (s *server) getPenerimaanHandler(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+	q, ok := bacaKueri(c, store.DaftarPenerimaan)
+	if !ok {
+		return
+	}
+	daftar, total, err := s.pembelian.ListPenerimaan(c.Request.Context(), id, q)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Pesanan pembelian tidak ditemukan"})
+			return
+		}
+		log.Printf("Error mengambil penerimaan pembelian %d: %v", id, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil data penerimaan"})
+		return
+	}
+	kirimHalaman(c, daftar, total, q)
+}
+
+// =================================================================
+// HANDLER UNTUK PERUBAHAN STATUS PEMBELIAN
+// =================================================================
+
+func (s *server) pesanPembelianHandler(c *gin.Context) {
+	s.ubahStatusPembelian(c, models.StatusPembelianDipesan, "Pesanan pembelian berhasil dipesan")
+}
+
+func (s *server) kirimPembelianHandler(c *gin.Context) {
+	s.ubahStatusPembelian(c, models.StatusPembelianDikirim, "Pesanan pembelian ditandai sedang dikirim")
+}
+
+func (s *server) batalPembelianHandler(c *gin.Context) {
+	s.ubahStatusPembelian(c, models.StatusPembelianDibatalkan, "Pesanan pembelian berhasil dibatalkan")
+}
+
+// tutupPembelianHandler menutup pesanan yang diterima sebagian tanpa menunggu sisanya
+func (s *server) tutupPembelianHandler(c *gin.Context) {
+	s.ubahStatusPembelian(c, models.StatusPembelianDitutup, "Sisa pesanan pembelian berhasil ditutup")
+}
+
+// ubahStatusPembelian memindahkan status pembelian ke status ke.
+// Body JSON opsional: {"catatan": "..."}
+func (s *server) ubahStatusPembelian(c *gin.Context, ke, pesanSukses string) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+	var req struct {
+		Catatan string `json:"catatan"`
+	}
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Data JSON tidak valid: " + err.Error()})
+			return
+		}
+	}
+
+	sebelum := s.pembelianUntukAudit(c, id)
+	if err := s.pembelian.UbahStatusPembelian(c.Request.Context(), id, ke, aktor(c), req.Catatan); err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Pesanan pembelian tidak ditemukan"})
+			return
+		}
+		if errors.Is(err, store.ErrInvalidTransition) {
+			c.JSON(http.StatusConflict, gin.H{"error": "Status pembelian tidak bisa diubah: " + err.Error()})
+			return
+		}
+		log.Printf("Error mengubah status pembelian %d: %v", id, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengubah status pembelian"})
+		return
+	}
+	s.catatAudit(c, "pembelian", id, models.AuditUbah, sebelum, s.pembelianUntukAudit(c, id))
+	c.JSON(http.StatusOK, gin.H{"message": pesanSukses, "status": ke})
+}
+
+func (s *server) getRiwayatPembelianHandler(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+	riwayat, err := s.pembelian.RiwayatStatusPembelian(c.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Pesanan pembelian tidak ditemukan"})
+			return
+		}
+		log.Printf("Error mengambil riwayat pembelian %d: %v", id, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil riwayat status pembelian"})
+		return
+	}
+	c.JSON(http.StatusOK, riwayat)
+}
+
+// gudangTujuanPembelian mengembalikan gudang tujuan bawaan pesanan.
+// Jika pesanan tidak ada, respons 404 (atau 500) sudah dikirim dan ok bernilai false.
+func (s *server) gudangTujuanPembelian(c *gin.Context, id int64) (sql.NullInt64, bool) {
+	p, err := s.pembelian.GetPembelian(c.Request.Context(), id)
+	if err == nil {
+		return p.GudangTujuanID, true
+	}
+	if errors.Is(err, store.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Pesanan pembelian tidak ditemukan"})
+		return sql.NullInt64{}, false
+	}
+	log.Printf("Error mengambil pembelian %d: %v", id, err)
+	c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil data pembelian"})
+	return sql.NullInt64{}, false
+}
+
+// pembelianUntukAudit mengambil isi pesanan beserta detailnya untuk jejak audit.
+// Kegagalan hanya dicatat di log, dan entri audit tetap ditulis tanpa isi tersebut.
+func (s *server) pembelianUntukAudit(c *gin.Context, id int64) any {
+	p, err := s.pembelian.GetPembelian(c.Request.Context(), id)
+	if err != nil {
+		if !errors.Is(err, store.ErrNotFound) {
+			log.Printf("Error mengambil pembelian %d untuk audit: %v", id, err)
+		}
+		return nil
+	}
+	return p
+}
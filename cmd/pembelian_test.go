@@ -0,0 +1,331 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"scm-api/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestPembelianCRUD(t *testing.T) {
+	p := pengujiBaru(t)
+	p.dataDasar()
+
+	id := p.buatPembelian(models.StatusPembelianDraft, 3, 2)
+	var pb models.PembelianDenganDetailResponse
+	p.decode(p.harus(http.StatusOK, "admin", "GET", fmt.Sprintf("/api/pembelian/%d", id), nil), &pb)
+	if pb.Status != models.StatusPembelianDraft || len(pb.Details) != 2 {
+		t.Fatalf("pembelian %d: status %q dengan %d baris, ingin Draft dengan 2 baris", id, pb.Status, len(pb.Details))
+	}
+	if pb.TotalBiaya.Float64 != 5000 {
+		t.Errorf("total_biaya = %v, ingin 5000", pb.TotalBiaya.Float64)
+	}
+	if d := pb.Details[0]; d.Jumlah != 3 || d.Sisa != 3 || d.Subtotal != 3000 {
+		t.Errorf("baris pertama = %+v, ingin jumlah 3, sisa 3, subtotal 3000", d)
+	}
+
+	var daftar models.Halaman[models.PembelianResponse]
+	p.decode(p.harus(http.StatusOK, "pembelian", "GET", "/api/pembelian?status=Draft", nil), &daftar)
+	if daftar.Meta.Total != 1 || len(daftar.Data) != 1 || daftar.Data[0].PembelianID != id {
+		t.Errorf("daftar Draft = %+v, ingin hanya pembelian %d", daftar, id)
+	}
+
+	// Validasi dan izin
+	p.harus(http.StatusBadRequest, "admin", "POST", "/api/pembelian", gin.H{"supplier_id": 1, "tanggal_pesan": "2024-05-01", "details": []gin.H{}})
+	p.harus(http.StatusBadRequest, "admin", "POST", "/api/pembelian", gin.H{
+		"supplier_id": 1, "tanggal_pesan": "01-05-2024", "details": []gin.H{{"produk_id": 1, "jumlah": 1, "harga_beli_satuan": 1000}},
+	})
+	p.harus(http.StatusBadRequest, "admin", "POST", "/api/pembelian", gin.H{
+		"supplier_id": 1, "tanggal_pesan": "2024-05-01", "details": []gin.H{{"produk_id": 99, "jumlah": 1, "harga_beli_satuan": 1000}},
+	})
+	p.harus(http.StatusForbidden, "gudang", "POST", "/api/pembelian", gin.H{
+		"supplier_id": 1, "tanggal_pesan": "2024-05-01", "details": []gin.H{{"produk_id": 1, "jumlah": 1, "harga_beli_satuan": 1000}},
+	})
+	p.harus(http.StatusUnauthorized, "", "GET", "/api/pembelian", nil)
+
+	p.harus(http.StatusOK, "admin", "DELETE", fmt.Sprintf("/api/pembelian/%d", id), nil)
+	p.harus(http.StatusNotFound, "admin", "GET", fmt.Sprintf("/api/pembelian/%d", id), nil)
+	p.harus(http.StatusNotFound, "admin", "DELETE", fmt.Sprintf("/api/pembelian/%d", id), nil)
+	p.decode(p.harus(http.StatusOK, "admin", "GET", "/api/pembelian", nil), &daftar)
+	if daftar.Meta.Total != 0 {
+		t.Errorf("total setelah dihapus = %d, ingin 0", daftar.Meta.Total)
+	}
+}
+
+func TestTransisiStatusPembelian(t *testing.T) {
+	p := pengujiBaru(t)
+	p.dataDasar()
+
+	// jalur adalah aksi yang membawa pesanan Draft ke status awal kasus
+	for _, tc := range []struct {
+		jalur  []string
+		aksi   string
+		kode   int
+		status string
+	}{
+		{nil, "pesan", http.StatusOK, models.StatusPembelianDipesan},
+		{nil, "batal", http.StatusOK, models.StatusPembelianDibatalkan},
+		{nil, "kirim", http.StatusConflict, models.StatusPembelianDraft},
+		{nil, "terima", http.StatusConflict, models.StatusPembelianDraft},
+		{[]string{"pesan"}, "kirim", http.StatusOK, models.StatusPembelianDikirim},
+		{[]string{"pesan"}, "terima", http.StatusOK, models.StatusPembelianDiterima},
+		{[]string{"pesan"}, "batal", http.StatusOK, models.StatusPembelianDibatalkan},
+		{[]string{"pesan"}, "pesan", http.StatusConflict, models.StatusPembelianDipesan},
+		{[]string{"pesan", "kirim"}, "terima", http.StatusOK, models.StatusPembelianDiterima},
+		{[]string{"pesan", "kirim"}, "batal", http.StatusOK, models.StatusPembelianDibatalkan},
+		{[]string{"pesan", "kirim"}, "pesan", http.StatusConflict, models.StatusPembelianDikirim},
+		{[]string{"pesan", "kirim"}, "kirim", http.StatusConflict, models.StatusPembelianDikirim},
+		{[]string{"pesan", "terima"}, "batal", http.StatusConflict, models.StatusPembelianDiterima},
+		{[]string{"pesan", "terima"}, "pesan", http.StatusConflict, models.StatusPembelianDiterima},
+		{[]string{"batal"}, "pesan", http.StatusConflict, models.StatusPembelianDibatalkan},
+		{[]string{"batal"}, "terima", http.StatusConflict, models.StatusPembelianDibatalkan},
+		{[]string{"batal"}, "batal", http.StatusConflict, models.StatusPembelianDibatalkan},
+		{nil, "tutup", http.StatusConflict, models.StatusPembelianDraft},
+		{[]string{"pesan"}, "tutup", http.StatusConflict, models.StatusPembelianDipesan},
+		{[]string{"pesan", "kirim"}, "tutup", http.StatusConflict, models.StatusPembelianDikirim},
+		{[]string{"pesan", "terima"}, "tutup", http.StatusConflict, models.StatusPembelianDiterima},
+	} {
+		awal := append([]string{models.StatusPembelianDraft}, tc.jalur...)
+		t.Run(fmt.Sprintf("%v lalu %s", awal, tc.aksi), func(t *testing.T) {
+			p := p.untuk(t)
+			id := p.buatPembelian(models.StatusPembelianDraft, 1)
+			for _, aksi := range tc.jalur {
+				p.harus(http.StatusOK, "admin", "PUT", fmt.Sprintf("/api/pembelian/%d/%s", id, aksi), nil)
+			}
+			if kode, body := p.kirim("admin", "PUT", fmt.Sprintf("/api/pembelian/%d/%s", id, tc.aksi), nil); kode != tc.kode {
+				t.Errorf("%s: status %d, ingin %d; body %s", tc.aksi, kode, tc.kode, body)
+			}
+			var pb models.PembelianDenganDetailResponse
+			p.decode(p.harus(http.StatusOK, "admin", "GET", fmt.Sprintf("/api/pembelian/%d", id), nil), &pb)
+			if pb.Status != tc.status {
+				t.Errorf("status pesanan %q, ingin %q", pb.Status, tc.status)
+			}
+		})
+	}
+	p.harus(http.StatusNotFound, "admin", "PUT", "/api/pembelian/999/pesan", nil)
+}
+
+func TestTerimaPembelianDuaKali(t *testing.T) {
+	p := pengujiBaru(t)
+	p.dataDasar()
+
+	id := p.buatPembelian(models.StatusPembelianDipesan, 5, 2)
+	p.harus(http.StatusOK, "gudang", "PUT", fmt.Sprintf("/api/pembelian/%d/terima", id), nil)
+	p.harus(http.StatusConflict, "gudang", "PUT", fmt.Sprintf("/api/pembelian/%d/terima", id), nil)
+	if n1, n2 := p.stok(1, 1), p.stok(2, 1); n1 != 5 || n2 != 2 {
+		t.Errorf("stok setelah diterima dua kali = %d dan %d, ingin 5 dan 2", n1, n2)
+	}
+
+	var riwayat []models.RiwayatStatusPembelian
+	p.decode(p.harus(http.StatusOK, "admin", "GET", fmt.Sprintf("/api/pembelian/%d/riwayat", id), nil), &riwayat)
+	if n := len(riwayat); n != 2 || riwayat[n-1].StatusKe != models.StatusPembelianDiterima {
+		t.Errorf("riwayat = %+v, ingin Dipesan lalu Diterima sekali", riwayat)
+	}
+}
+
+func TestPenerimaanParsialBerulang(t *testing.T) {
+	p := pengujiBaru(t)
+	p.dataDasar()
+
+	id := p.buatPembelian(models.StatusPembelianDipesan, 5)
+	jalur := fmt.Sprintf("/api/pembelian/%d/penerimaan", id)
+	for _, n := range []int{1, 2} {
+		p.harus(http.StatusCreated, "gudang", "POST", jalur, gin.H{"items": []gin.H{{"produk_id": 1, "jumlah_diterima": n}}})
+		var pb models.PembelianDenganDetailResponse
+		p.decode(p.harus(http.StatusOK, "admin", "GET", fmt.Sprintf("/api/pembelian/%d", id), nil), &pb)
+		if pb.Status != models.StatusPembelianDiterimaSebagian {
+			t.Fatalf("status setelah menerima %d = %q, ingin Diterima Sebagian", n, pb.Status)
+		}
+	}
+	p.harus(http.StatusCreated, "gudang", "POST", jalur, gin.H{"items": []gin.H{{"produk_id": 1, "jumlah_diterima": 2}}})
+
+	var riwayat []models.RiwayatStatusPembelian
+	p.decode(p.harus(http.StatusOK, "admin", "GET", fmt.Sprintf("/api/pembelian/%d/riwayat", id), nil), &riwayat)
+	var status []string
+	for _, r := range riwayat {
+		status = append(status, r.StatusKe)
+	}
+	ingin := []string{models.StatusPembelianDipesan, models.StatusPembelianDiterimaSebagian, models.StatusPembelianDiterimaSebagian, models.StatusPembelianDiterima}
+	if fmt.Sprint(status) != fmt.Sprint(ingin) {
+		t.Errorf("riwayat status = %v, ingin %v", status, ingin)
+	}
+	if n := p.stok(1, 1); n != 5 {
+		t.Errorf("stok = %d, ingin 5", n)
+	}
+}
+
+func TestTutupSisaPembelian(t *testing.T) {
+	p := pengujiBaru(t)
+	p.dataDasar()
+	// Reorder point tinggi agar saran selalu muncul
+	p.harus(http.StatusOK, "admin", "PUT", "/api/stok/parameter/1/1", gin.H{"reorder_point": 100, "safety_stock": 0})
+
+	id := p.buatPembelian(models.StatusPembelianDipesan, 5)
+	jalur := fmt.Sprintf("/api/pembelian/%d", id)
+	p.harus(http.StatusCreated, "gudang", "POST", jalur+"/penerimaan", gin.H{"items": []gin.H{{"produk_id": 1, "jumlah_diterima": 2}}})
+	if n := p.saranProduk1().DalamPesanan; n != 3 {
+		t.Fatalf("dalam pesanan sebelum ditutup = %d, ingin 3", n)
+	}
+
+	p.harus(http.StatusForbidden, "gudang", "PUT", jalur+"/tutup", nil)
+	p.harus(http.StatusOK, "pembelian", "PUT", jalur+"/tutup", gin.H{"catatan": "supplier kehabisan barang"})
+
+	var pb models.PembelianDenganDetailResponse
+	p.decode(p.harus(http.StatusOK, "admin", "GET", jalur, nil), &pb)
+	if pb.Status != models.StatusPembelianDitutup || pb.Details[0].JumlahDiterima != 2 {
+		t.Errorf("pesanan = %q dengan %d diterima, ingin Ditutup dengan 2 diterima", pb.Status, pb.Details[0].JumlahDiterima)
+	}
+	// Sisa yang ditutup tidak masuk stok dan tidak lagi ditunggu
+	if n := p.stok(1, 1); n != 2 {
+		t.Errorf("stok = %d, ingin 2", n)
+	}
+	if n := p.saranProduk1().DalamPesanan; n != 0 {
+		t.Errorf("dalam pesanan setelah ditutup = %d, ingin 0", n)
+	}
+
+	p.harus(http.StatusConflict, "gudang", "POST", jalur+"/penerimaan", gin.H{"items": []gin.H{{"produk_id": 1, "jumlah_diterima": 1}}})
+	p.harus(http.StatusConflict, "admin", "PUT", jalur+"/terima", nil)
+	p.harus(http.StatusConflict, "admin", "PUT", jalur+"/tutup", nil)
+	p.harus(http.StatusConflict, "admin", "DELETE", jalur, nil)
+
+	var riwayat []models.RiwayatStatusPembelian
+	p.decode(p.harus(http.StatusOK, "admin", "GET", jalur+"/riwayat", nil), &riwayat)
+	if r := riwayat[len(riwayat)-1]; r.StatusKe != models.StatusPembelianDitutup || r.Catatan.String != "supplier kehabisan barang" {
+		t.Errorf("riwayat terakhir = %+v, ingin Ditutup dengan catatan", r)
+	}
+}
+
+func TestDeletePembelianHanyaDraftAtauDibatalkan(t *testing.T) {
+	p := pengujiBaru(t)
+	p.dataDasar()
+
+	dipesan := p.buatPembelian(models.StatusPembelianDipesan, 5)
+	p.harus(http.StatusConflict, "admin", "DELETE", fmt.Sprintf("/api/pembelian/%d", dipesan), nil)
+
+	// Pesanan dengan penerimaan tidak bisa dihapus walaupun sudah dibatalkan
+	diterima := p.buatPembelian(models.StatusPembelianDipesan, 5)
+	p.harus(http.StatusCreated, "gudang", "POST", fmt.Sprintf("/api/pembelian/%d/penerimaan", diterima),
+		gin.H{"items": []gin.H{{"produk_id": 1, "jumlah_diterima": 2}}})
+	p.harus(http.StatusConflict, "admin", "DELETE", fmt.Sprintf("/api/pembelian/%d", diterima), nil)
+
+	p.harus(http.StatusOK, "admin", "PUT", fmt.Sprintf("/api/pembelian/%d/batal", dipesan), nil)
+	p.harus(http.StatusOK, "admin", "DELETE", fmt.Sprintf("/api/pembelian/%d", dipesan), nil)
+	if n := p.stok(1, 1); n != 2 {
+		t.Errorf("stok produk 1 di gudang 1 = %d, ingin 2", n)
+	}
+}
+
+func TestHargaBawaanKatalog(t *testing.T) {
+	p := pengujiBaru(t)
+	p.dataDasar()
+	p.harus(http.StatusCreated, "admin", "POST", "/api/supplier", gin.H{"nama_supplier": "Cahaya Abadi"})
+
+	katalog := "/api/supplier/1/katalog"
+	p.harus(http.StatusCreated, "pembelian", "POST", katalog, gin.H{"produk_id": 1, "harga_beli": 1100, "moq": 5, "lead_time_hari": 3, "berlaku_sampai": "2024-05-31"})
+	p.harus(http.StatusCreated, "pembelian", "POST", katalog, gin.H{"produk_id": 1, "harga_beli": 1200, "berlaku_dari": "2024-06-01"})
+	p.harus(http.StatusConflict, "pembelian", "POST", katalog, gin.H{"produk_id": 1, "harga_beli": 1000, "berlaku_dari": "2024-05-15", "berlaku_sampai": "2024-06-15"})
+	p.harus(http.StatusCreated, "pembelian", "POST", "/api/supplier/2/katalog", gin.H{"produk_id": 1, "harga_beli": 1050})
+
+	pesan := func(tanggal string, jumlah int, harga any) (int, models.PembelianDenganDetailResponse) {
+		t.Helper()
+		detail := gin.H{"produk_id": 1, "jumlah": jumlah}
+		if harga != nil {
+			detail["harga_beli_satuan"] = harga
+		}
+		kode, respons := p.kirim("pembelian", "POST", "/api/pembelian", gin.H{
+			"supplier_id": 1, "tanggal_pesan": tanggal, "gudang_tujuan_id": 1, "details": []gin.H{detail},
+		})
+		var pb models.PembelianDenganDetailResponse
+		if kode == http.StatusCreated {
+			var baru struct {
+				PembelianID int64 `json:"pembelian_id"`
+			}
+			p.decode(respons, &baru)
+			p.decode(p.harus(http.StatusOK, "pembelian", "GET", fmt.Sprintf("/api/pembelian/%d", baru.PembelianID), nil), &pb)
+		}
+		return kode, pb
+	}
+
+	// Harga kosong diambil dari baris yang berlaku pada tanggal pesan, bukan hari ini,
+	// dan estimasi tiba mengikuti lead time katalog
+	kode, pb := pesan("2024-05-10", 5, nil)
+	if kode != http.StatusCreated || pb.Details[0].HargaBeliSatuan != 1100 || pb.TotalBiaya.Float64 != 5500 || pb.EstimasiTiba.String != "2024-05-13" {
+		t.Errorf("pesanan Mei = %d %+v, ingin harga 1100 dan tiba 2024-05-13", kode, pb)
+	}
+	kode, pb = pesan("2024-06-10", 2, nil)
+	if kode != http.StatusCreated || pb.Details[0].HargaBeliSatuan != 1200 || pb.EstimasiTiba.Valid {
+		t.Errorf("pesanan Juni = %d %+v, ingin harga 1200 tanpa estimasi tiba", kode, pb)
+	}
+	// Harga yang ditulis sendiri dipakai, tetapi MOQ katalog tetap berlaku
+	if kode, pb = pesan("2024-05-10", 6, 1000); kode != http.StatusCreated || pb.Details[0].HargaBeliSatuan != 1000 {
+		t.Errorf("pesanan dengan harga sendiri = %d %+v, ingin harga 1000", kode, pb)
+	}
+	if kode, _ = pesan("2024-05-10", 4, 1000); kode != http.StatusBadRequest {
+		t.Errorf("pesanan di bawah MOQ: status %d, ingin 400", kode)
+	}
+	// Harga supplier lain tidak dipakai untuk supplier 1
+	p.harus(http.StatusOK, "pembelian", "DELETE", katalog+"/2", nil)
+	if kode, _ = pesan("2024-06-10", 2, nil); kode != http.StatusBadRequest {
+		t.Errorf("pesanan tanpa harga aktif: status %d, ingin 400", kode)
+	}
+
+	var banding []models.SupplierProduk
+	p.decode(p.harus(http.StatusOK, "pembelian", "GET", "/api/produk/1/katalog?tanggal=2024-05-10", nil), &banding)
+	if len(banding) != 2 || banding[0].SupplierID != 2 || banding[1].HargaBeli != 1100 {
+		t.Errorf("perbandingan harga = %+v, ingin supplier 2 (1050) lalu supplier 1 (1100)", banding)
+	}
+}
+
+func TestTotalPembelianDihitungServer(t *testing.T) {
+	p := pengujiBaru(t)
+	p.dataDasar()
+
+	pesan := func(body gin.H) (int, map[string]string) {
+		t.Helper()
+		body["supplier_id"], body["tanggal_pesan"], body["gudang_tujuan_id"] = 1, "2024-05-01", 1
+		kode, respons := p.kirim("pembelian", "POST", "/api/pembelian", body)
+		var galat struct {
+			Fields map[string]string `json:"fields"`
+		}
+		if kode == http.StatusBadRequest {
+			p.decode(respons, &galat)
+		}
+		return kode, galat.Fields
+	}
+
+	// Subtotal dan total dihitung ulang; nilai kiriman klien hanya dicocokkan
+	kode, _ := pesan(gin.H{
+		"total_biaya": 3700.01,
+		"details": []gin.H{
+			{"produk_id": 1, "jumlah": 3, "harga_beli_satuan": 333.337, "subtotal": 1000.01},
+			{"produk_id": 2, "jumlah": 2, "harga_beli_satuan": 1350},
+		},
+	})
+	if kode != http.StatusCreated {
+		t.Fatalf("pesanan dengan total yang cocok: status %d", kode)
+	}
+	var daftar models.Halaman[models.PembelianResponse]
+	p.decode(p.harus(http.StatusOK, "pembelian", "GET", "/api/pembelian", nil), &daftar)
+	if daftar.Data[0].TotalBiaya.Float64 != 3700.01 {
+		t.Errorf("total_biaya = %v, ingin 3700.01", daftar.Data[0].TotalBiaya.Float64)
+	}
+
+	kode, fields := pesan(gin.H{
+		"total_biaya": 1,
+		"details": []gin.H{
+			{"produk_id": 1, "jumlah": 3, "harga_beli_satuan": 1000, "subtotal": 2999},
+			{"produk_id": 2, "jumlah": 0, "harga_beli_satuan": -5},
+		},
+	})
+	for _, field := range []string{"total_biaya", "details[0].subtotal", "details[1].jumlah", "details[1].harga_beli_satuan"} {
+		if kode != http.StatusBadRequest || fields[field] == "" {
+			t.Errorf("status %d, kesalahan %s tidak dilaporkan: %v", kode, field, fields)
+		}
+	}
+	if kode, fields = pesan(gin.H{"details": []gin.H{}}); kode != http.StatusBadRequest || fields["details"] == "" {
+		t.Errorf("pesanan tanpa baris: status %d, fields %v", kode, fields)
+	}
+}
@@ -0,0 +1,199 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"scm-api/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestPenerimaanSisaPerBaris(t *testing.T) {
+	p := pengujiBaru(t)
+	p.dataDasar()
+
+	id := p.buatPembelian(models.StatusPembelianDipesan, 5, 3)
+	jalur := fmt.Sprintf("/api/pembelian/%d/penerimaan", id)
+	p.harus(http.StatusCreated, "gudang", "POST", jalur, gin.H{
+		"tanggal_terima": "2024-05-03 09:00:00",
+		"items": []gin.H{
+			{"produk_id": 1, "jumlah_diterima": 3, "jumlah_ditolak": 1, "alasan_tolak": "kemasan rusak"},
+			{"produk_id": 2, "jumlah_diterima": 3},
+		},
+	})
+
+	cekBaris := func(status string, ingin [][3]int) {
+		t.Helper()
+		var pb models.PembelianDenganDetailResponse
+		p.decode(p.harus(http.StatusOK, "admin", "GET", fmt.Sprintf("/api/pembelian/%d", id), nil), &pb)
+		if pb.Status != status {
+			t.Errorf("status = %q, ingin %q", pb.Status, status)
+		}
+		for i, d := range pb.Details {
+			if got := [3]int{d.JumlahDiterima, d.JumlahDitolak, d.Sisa}; got != ingin[i] {
+				t.Errorf("baris produk %d: diterima, ditolak, sisa = %v, ingin %v", d.ProdukID, got, ingin[i])
+			}
+		}
+	}
+	// Barang yang ditolak tetap dihitung terhadap jumlah pesanan, tetapi tidak masuk stok
+	cekBaris(models.StatusPembelianDiterimaSebagian, [][3]int{{3, 1, 1}, {3, 0, 0}})
+	if n1, n2 := p.stok(1, 1), p.stok(2, 1); n1 != 3 || n2 != 3 {
+		t.Errorf("stok = %d dan %d, ingin 3 dan 3", n1, n2)
+	}
+
+	var daftar models.Halaman[models.Penerimaan]
+	p.decode(p.harus(http.StatusOK, "admin", "GET", jalur, nil), &daftar)
+	if daftar.Meta.Total != 1 || len(daftar.Data[0].Details) != 2 {
+		t.Fatalf("penerimaan = %+v, ingin satu penerimaan dengan 2 baris", daftar.Data)
+	}
+	if d := daftar.Data[0].Details[0]; d.JumlahDitolak != 1 || d.AlasanTolak.String != "kemasan rusak" || d.JumlahStok != 3 {
+		t.Errorf("baris penerimaan = %+v, ingin ditolak 1 karena kemasan rusak dan 3 masuk stok", d)
+	}
+
+	p.harus(http.StatusCreated, "gudang", "POST", jalur, gin.H{"items": []gin.H{{"produk_id": 1, "jumlah_diterima": 1}}})
+	cekBaris(models.StatusPembelianDiterima, [][3]int{{4, 1, 0}, {3, 0, 0}})
+	if n := p.stok(1, 1); n != 4 {
+		t.Errorf("stok produk 1 = %d, ingin 4", n)
+	}
+}
+
+func TestPenerimaanDitolak(t *testing.T) {
+	p := pengujiBaru(t)
+	p.dataDasar()
+
+	id := p.buatPembelian(models.StatusPembelianDipesan, 5)
+	jalur := fmt.Sprintf("/api/pembelian/%d/penerimaan", id)
+	// Penerimaan pertama menyisakan 3 untuk diterima
+	p.harus(http.StatusCreated, "gudang", "POST", jalur, gin.H{"items": []gin.H{{"produk_id": 1, "jumlah_diterima": 2}}})
+
+	for _, tc := range []struct {
+		nama string
+		body any
+		kode int
+	}{
+		{"melebihi sisa", gin.H{"items": []gin.H{{"produk_id": 1, "jumlah_diterima": 4}}}, http.StatusBadRequest},
+		{"diterima dan ditolak melebihi sisa", gin.H{"items": []gin.H{{"produk_id": 1, "jumlah_diterima": 2, "jumlah_ditolak": 2}}}, http.StatusBadRequest},
+		{"baris kedua melebihi sisa", gin.H{"items": []gin.H{{"produk_id": 1, "jumlah_diterima": 2}, {"produk_id": 1, "jumlah_diterima": 2}}}, http.StatusBadRequest},
+		{"jumlah negatif", gin.H{"items": []gin.H{{"produk_id": 1, "jumlah_diterima": -1}}}, http.StatusBadRequest},
+		{"produk tidak dipesan", gin.H{"items": []gin.H{{"produk_id": 2, "jumlah_diterima": 1}}}, http.StatusBadRequest},
+		{"tanpa item", gin.H{"items": []gin.H{}}, http.StatusBadRequest},
+		{"tanggal terima salah", gin.H{"tanggal_terima": "03/05/2024", "items": []gin.H{{"produk_id": 1, "jumlah_diterima": 1}}}, http.StatusBadRequest},
+		{"gudang lain", gin.H{"gudang_id": 2, "items": []gin.H{{"produk_id": 1, "jumlah_diterima": 1}}}, http.StatusForbidden},
+	} {
+		t.Run(tc.nama, func(t *testing.T) {
+			if kode, body := p.kirim("gudang", "POST", jalur, tc.body); kode != tc.kode {
+				t.Errorf("status %d, ingin %d; body %s", kode, tc.kode, body)
+			}
+		})
+	}
+
+	var pb models.PembelianDenganDetailResponse
+	p.decode(p.harus(http.StatusOK, "admin", "GET", fmt.Sprintf("/api/pembelian/%d", id), nil), &pb)
+	if d := pb.Details[0]; d.JumlahDiterima != 2 || d.JumlahDitolak != 0 || d.Sisa != 3 {
+		t.Errorf("baris setelah penerimaan ditolak = %+v, ingin diterima 2 dan sisa 3", d)
+	}
+	if n := p.stok(1, 1); n != 2 {
+		t.Errorf("stok = %d setelah penerimaan ditolak, ingin tetap 2", n)
+	}
+
+	// Pesanan yang belum dipesan atau sudah dibatalkan tidak bisa diterima
+	draft := p.buatPembelian(models.StatusPembelianDraft, 5)
+	p.harus(http.StatusConflict, "gudang", "POST", fmt.Sprintf("/api/pembelian/%d/penerimaan", draft),
+		gin.H{"items": []gin.H{{"produk_id": 1, "jumlah_diterima": 1}}})
+	p.harus(http.StatusNotFound, "gudang", "POST", "/api/pembelian/999/penerimaan",
+		gin.H{"items": []gin.H{{"produk_id": 1, "jumlah_diterima": 1}}})
+}
+
+func TestPenerimaanSatuanBeli(t *testing.T) {
+	p := pengujiBaru(t)
+	p.dataDasar()
+
+	p.harus(http.StatusCreated, "admin", "POST", "/api/produk/1/satuan", gin.H{"satuan": "dus", "faktor": 12})
+	p.harus(http.StatusConflict, "admin", "POST", "/api/produk/1/satuan", gin.H{"satuan": "dus", "faktor": 6})
+	p.harus(http.StatusBadRequest, "admin", "POST", "/api/produk/1/satuan", gin.H{"satuan": "pcs", "faktor": 1})
+	p.harus(http.StatusBadRequest, "admin", "POST", "/api/produk/1/satuan", gin.H{"satuan": "krat", "faktor": 0})
+
+	pesan := func(satuan string) (int, []byte) {
+		return p.kirim("admin", "POST", "/api/pembelian", gin.H{
+			"supplier_id": 1, "tanggal_pesan": "2024-05-01", "status": models.StatusPembelianDipesan, "gudang_tujuan_id": 1,
+			"details": []gin.H{{"produk_id": 1, "jumlah": 3, "satuan": satuan, "harga_beli_satuan": 12000}},
+		})
+	}
+	if kode, _ := pesan("krat"); kode != http.StatusBadRequest {
+		t.Errorf("pesan dengan satuan tidak terdaftar: status %d, ingin 400", kode)
+	}
+	kode, respons := pesan("dus")
+	if kode != http.StatusCreated {
+		t.Fatalf("pesan per dus: status %d, body %s", kode, respons)
+	}
+	var baru struct {
+		PembelianID int64 `json:"pembelian_id"`
+	}
+	p.decode(respons, &baru)
+	jalur := fmt.Sprintf("/api/pembelian/%d/penerimaan", baru.PembelianID)
+
+	// Jumlah diterima dinyatakan dalam dus, stok bertambah dalam pcs
+	p.harus(http.StatusCreated, "gudang", "POST", jalur, gin.H{"items": []gin.H{{"produk_id": 1, "jumlah_diterima": 2}}})
+	if n := p.stok(1, 1); n != 24 {
+		t.Errorf("stok setelah 2 dus = %d, ingin 24", n)
+	}
+	var pb models.PembelianDenganDetailResponse
+	p.decode(p.harus(http.StatusOK, "admin", "GET", fmt.Sprintf("/api/pembelian/%d", baru.PembelianID), nil), &pb)
+	if d := pb.Details[0]; d.Satuan != "dus" || d.FaktorKonversi != 12 || d.SatuanDasar != "pcs" || d.JumlahDiterima != 2 || d.Sisa != 1 {
+		t.Errorf("baris pembelian = %+v, ingin 2 dari 3 dus berisi 12 pcs", d)
+	}
+	var daftar models.Halaman[models.Penerimaan]
+	p.decode(p.harus(http.StatusOK, "admin", "GET", jalur, nil), &daftar)
+	if d := daftar.Data[0].Details[0]; d.JumlahDiterima != 2 || d.JumlahStok != 24 {
+		t.Errorf("baris penerimaan = %+v, ingin 2 dus menjadi 24 pcs", d)
+	}
+
+	// Pesanan yang sudah dibuat tetap memakai faktor saat dipesan
+	p.harus(http.StatusOK, "admin", "PUT", "/api/produk/1/satuan/dus", gin.H{"faktor": 10})
+	p.harus(http.StatusCreated, "gudang", "POST", jalur, gin.H{"items": []gin.H{{"produk_id": 1, "jumlah_diterima": 1}}})
+	if n := p.stok(1, 1); n != 36 {
+		t.Errorf("stok setelah faktor diubah = %d, ingin 36", n)
+	}
+}
+
+func TestGudangPenerimaan(t *testing.T) {
+	p := pengujiBaru(t)
+	p.dataDasar()
+
+	// Gudang baris mengalahkan gudang penerimaan, yang mengalahkan gudang tujuan pesanan
+	id := p.buatPembelian(models.StatusPembelianDipesan, 4, 2)
+	var pn models.Penerimaan
+	p.decode(p.harus(http.StatusCreated, "admin", "POST", fmt.Sprintf("/api/pembelian/%d/penerimaan", id), gin.H{
+		"gudang_id": 2,
+		"items":     []gin.H{{"produk_id": 1, "jumlah_diterima": 3}, {"produk_id": 2, "jumlah_diterima": 2, "gudang_id": 1}},
+	}), &pn)
+	if pn.Details[0].GudangID != 2 || pn.Details[1].GudangID != 1 {
+		t.Errorf("gudang baris penerimaan = %d dan %d, ingin 2 dan 1", pn.Details[0].GudangID, pn.Details[1].GudangID)
+	}
+	if n1, n2 := p.stok(1, 2), p.stok(2, 1); n1 != 3 || n2 != 2 {
+		t.Errorf("stok produk 1 di gudang 2 = %d, produk 2 di gudang 1 = %d; ingin 3 dan 2", n1, n2)
+	}
+	p.harus(http.StatusCreated, "admin", "POST", fmt.Sprintf("/api/pembelian/%d/penerimaan", id), gin.H{"items": []gin.H{{"produk_id": 1, "jumlah_diterima": 1}}})
+	if n := p.stok(1, 1); n != 1 {
+		t.Errorf("stok produk 1 di gudang tujuan pesanan = %d, ingin 1", n)
+	}
+
+	// Pesanan tanpa gudang tujuan wajib menyebut gudang saat diterima
+	var baru struct {
+		PembelianID int64 `json:"pembelian_id"`
+	}
+	p.decode(p.harus(http.StatusCreated, "admin", "POST", "/api/pembelian", gin.H{
+		"supplier_id": 1, "tanggal_pesan": "2024-05-01", "status": models.StatusPembelianDipesan,
+		"details": []gin.H{{"produk_id": 2, "jumlah": 5, "harga_beli_satuan": 1000}},
+	}), &baru)
+	jalur := fmt.Sprintf("/api/pembelian/%d", baru.PembelianID)
+	p.harus(http.StatusBadRequest, "admin", "POST", jalur+"/penerimaan", gin.H{"items": []gin.H{{"produk_id": 2, "jumlah_diterima": 1}}})
+	p.harus(http.StatusBadRequest, "admin", "POST", jalur+"/penerimaan", gin.H{"gudang_id": 99, "items": []gin.H{{"produk_id": 2, "jumlah_diterima": 1}}})
+	p.harus(http.StatusForbidden, "gudang", "PUT", jalur+"/terima", gin.H{"gudang_id": 2})
+	p.harus(http.StatusOK, "gudang", "PUT", jalur+"/terima", gin.H{"gudang_id": 1})
+	if n := p.stok(2, 1); n != 7 {
+		t.Errorf("stok produk 2 di gudang 1 = %d, ingin 7", n)
+	}
+}
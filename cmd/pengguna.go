@@ -0,0 +1,136 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"slices"
+	"strings"
+
+	"scm-api/internal/auth"
+	"scm-api/internal/models"
+	"scm-api/internal/store"
+)
+
+// =================================================================
+// SUBCOMMAND PENGGUNA
+// =================================================================
+
+const penggunaUsage = `Penggunaan: scm-api [-config file] pengguna <perintah>
+
+Perintah:
+  tambah <username> [nama lengkap]   buat pengguna baru (belum punya role)
+  role <username> <role>...          ganti seluruh role pengguna, misalnya: role budi admin
+  nonaktif <username>                nonaktifkan pengguna dan cabut semua tokennya
+  aktifkan <username>                aktifkan kembali pengguna
+
+Password dibaca dari env SCM_PASSWORD_BARU, atau dari stdin jika env kosong.`
+
+// runPengguna menjalankan subcommand "pengguna" dengan argumen sisanya
+func runPengguna(st store.Store, args []string) {
+	if len(args) < 2 {
+		log.Fatal(penggunaUsage)
+	}
+	switch args[0] {
+	case "tambah":
+		tambahPengguna(st, args[1:])
+	case "role":
+		setRolePengguna(st, args[1:])
+	case "nonaktif", "aktifkan":
+		setAktifPengguna(st, args[1], args[0] == "aktifkan")
+	default:
+		log.Fatal(penggunaUsage)
+	}
+}
+
+func tambahPengguna(st store.PenggunaStore, args []string) {
+	p := models.Pengguna{
+		Username:    strings.TrimSpace(args[0]),
+		NamaLengkap: strings.Join(args[1:], " "),
+		Aktif:       true,
+	}
+	if p.Username == "" {
+		log.Fatal("Username wajib diisi")
+	}
+
+	password := os.Getenv("SCM_PASSWORD_BARU")
+	if password == "" {
+		fmt.Fprint(os.Stderr, "Password: ")
+		baris, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil && baris == "" {
+			log.Fatalf("Gagal membaca password: %v", err)
+		}
+		password = strings.TrimRight(baris, "\r\n")
+	}
+	if len(password) < auth.MinPassword {
+		log.Fatalf("Password minimal %d karakter", auth.MinPassword)
+	}
+
+	hash, err := auth.HashPassword(password)
+	if err != nil {
+		log.Fatalf("Gagal membuat hash password: %v", err)
+	}
+	p.PasswordHash = hash
+
+	if err := st.CreatePengguna(context.Background(), &p); err != nil {
+		if errors.Is(err, store.ErrDuplikat) {
+			log.Fatalf("Username %s sudah dipakai", p.Username)
+		}
+		log.Fatalf("Gagal menyimpan pengguna: %v", err)
+	}
+	fmt.Printf("Pengguna %s dibuat dengan ID %d\n", p.Username, p.PenggunaID)
+}
+
+func setRolePengguna(st store.Store, args []string) {
+	if len(args) < 2 {
+		log.Fatal(penggunaUsage)
+	}
+	ctx := context.Background()
+
+	p, err := st.GetPenggunaByUsername(ctx, args[0])
+	if err != nil {
+		log.Fatalf("Pengguna %s tidak ditemukan: %v", args[0], err)
+	}
+	semuaRole, err := st.ListRole(ctx)
+	if err != nil {
+		log.Fatalf("Gagal membaca role: %v", err)
+	}
+	roleIDs := make([]int64, 0, len(args)-1)
+	for _, nama := range args[1:] {
+		var id int64
+		for _, r := range semuaRole {
+			if r.Nama == nama {
+				id = r.RoleID
+			}
+		}
+		if id == 0 {
+			log.Fatalf("Role %s tidak ditemukan", nama)
+		}
+		if !slices.Contains(roleIDs, id) {
+			roleIDs = append(roleIDs, id)
+		}
+	}
+	if err := st.SetRolePengguna(ctx, p.PenggunaID, roleIDs); err != nil {
+		log.Fatalf("Gagal menyimpan role: %v", err)
+	}
+	fmt.Printf("Role %s: %s\n", p.Username, strings.Join(args[1:], ", "))
+}
+
+func setAktifPengguna(st store.PenggunaStore, username string, aktif bool) {
+	ctx := context.Background()
+	p, err := st.GetPenggunaByUsername(ctx, username)
+	if err != nil {
+		log.Fatalf("Pengguna %s tidak ditemukan: %v", username, err)
+	}
+	if err := st.SetAktifPengguna(ctx, p.PenggunaID, aktif); err != nil {
+		log.Fatalf("Gagal mengubah status pengguna: %v", err)
+	}
+	if aktif {
+		fmt.Printf("Pengguna %s diaktifkan kembali\n", username)
+	} else {
+		fmt.Printf("Pengguna %s dinonaktifkan dan semua tokennya dicabut\n", username)
+	}
+}
@@ -0,0 +1,710 @@
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"scm-api/internal/auth"
+	"scm-api/internal/models"
+	"scm-api/internal/store"
+
+	"github.com/gin-gonic/gin"
+)
+
+// =================================================================
+// HANDLER UNTUK PERMINTAAN PEMBELIAN DAN PERSETUJUANNYA
+// =================================================================
+
+// Batas aturan persetujuan
+const (
+	levelPersetujuanMaks = 10
+	panjangKategoriMaks  = 100
+)
+
+func (s *server) getPermintaanHandler(c *gin.Context) {
+	q, ok := bacaKueri(c, store.DaftarPermintaan)
+	if !ok {
+		return
+	}
+	daftar, total, err := s.permintaan.ListPermintaan(c.Request.Context(), q)
+	if err != nil {
+		log.Printf("Error mengambil permintaan pembelian: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil data permintaan"})
+		return
+	}
+	kirimHalaman(c, daftar, total, q)
+}
+
+func (s *server) getPermintaanByIdHandler(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+	p, ok := s.ambilPermintaan(c, id)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, p)
+}
+
+// createPermintaanHandler mengajukan permintaan pembelian. Supplier boleh dikosongkan
+// dan ditentukan saat konversi. harga_perkiraan yang kosong diisi dari katalog: harga
+// supplier yang dipilih, atau harga aktif termurah dari supplier mana pun. Tahap
+// persetujuan disalin dari aturan_persetujuan yang berlaku untuk total dan kategori
+// produknya; tanpa aturan yang berlaku, permintaan langsung Disetujui. Permintaan di
+// atas pembelian.batas_nilai selalu menunggu role dengan izin pembelian.nilai_besar.
+func (s *server) createPermintaanHandler(c *gin.Context) {
+	var req struct {
+		SupplierID     *int64  `json:"supplier_id"`
+		GudangTujuanID *int64  `json:"gudang_tujuan_id"`
+		TanggalButuh   *string `json:"tanggal_butuh"`
+		Catatan        string  `json:"catatan"`
+		Details        []struct {
+			ProdukID int64 `json:"produk_id"`
+			Jumlah   int   `json:"jumlah"`
+			// Satuan beli (opsional, bawaannya satuan dasar produk)
+			Satuan         string   `json:"satuan"`
+			HargaPerkiraan *float64 `json:"harga_perkiraan"`
+		} `json:"details"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Data JSON tidak valid: " + err.Error()})
+		return
+	}
+	ctx := c.Request.Context()
+	fe := make(fieldErrors)
+	p := models.PermintaanDenganDetail{}
+	p.DimintaOleh = aktor(c)
+	catatan := strings.TrimSpace(req.Catatan)
+	p.Catatan = sql.NullString{String: catatan, Valid: catatan != ""}
+
+	if req.SupplierID != nil {
+		if sp, err := s.supplier.GetSupplier(ctx, *req.SupplierID); errors.Is(err, store.ErrNotFound) {
+			fe.add("supplier_id", fmt.Sprintf("supplier dengan ID %d tidak ditemukan", *req.SupplierID))
+		} else if err != nil {
+			log.Printf("Error memeriksa supplier %d: %v", *req.SupplierID, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memeriksa data supplier"})
+			return
+		} else if sp.DeletedAt.Valid {
+			fe.add("supplier_id", fmt.Sprintf("supplier dengan ID %d sudah dihapus", *req.SupplierID))
+		} else {
+			p.SupplierID = sql.NullInt64{Int64: *req.SupplierID, Valid: true}
+		}
+	}
+	if req.GudangTujuanID != nil {
+		if g, err := s.gudang.GetGudang(ctx, *req.GudangTujuanID); errors.Is(err, store.ErrNotFound) {
+			fe.add("gudang_tujuan_id", fmt.Sprintf("gudang dengan ID %d tidak ditemukan", *req.GudangTujuanID))
+		} else if err != nil {
+			log.Printf("Error memeriksa gudang %d: %v", *req.GudangTujuanID, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memeriksa data gudang"})
+			return
+		} else if g.DeletedAt.Valid {
+			fe.add("gudang_tujuan_id", fmt.Sprintf("gudang dengan ID %d sudah diarsipkan", *req.GudangTujuanID))
+		} else {
+			p.GudangTujuanID = sql.NullInt64{Int64: *req.GudangTujuanID, Valid: true}
+		}
+	}
+	if req.TanggalButuh != nil {
+		if !tanggalValid(*req.TanggalButuh) || *req.TanggalButuh == "" {
+			fe.add("tanggal_butuh", "harus berformat YYYY-MM-DD")
+		} else {
+			p.TanggalButuh = sql.NullString{String: *req.TanggalButuh, Valid: true}
+		}
+	}
+
+	if len(req.Details) == 0 {
+		fe.add("details", "permintaan minimal berisi satu produk")
+	}
+	hariIni := time.Now().Format("2006-01-02")
+	var kategori []string
+	for i, d := range req.Details {
+		field := fmt.Sprintf("details[%d]", i)
+		satuan := models.ProdukSatuan{Satuan: d.Satuan, Faktor: 1}
+		satuanValid := false
+		if d.ProdukID < 1 {
+			fe.add(field+".produk_id", "wajib diisi")
+		} else if pr, err := s.produk.GetProduk(ctx, d.ProdukID); errors.Is(err, store.ErrNotFound) {
+			fe.add(field+".produk_id", fmt.Sprintf("produk dengan ID %d tidak ditemukan", d.ProdukID))
+		} else if err != nil {
+			log.Printf("Error memeriksa produk %d: %v", d.ProdukID, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memeriksa data produk"})
+			return
+		} else if pr.DeletedAt.Valid {
+			fe.add(field+".produk_id", fmt.Sprintf("produk dengan ID %d sudah dihapus", d.ProdukID))
+		} else if ps, pesan, err := s.satuanPembelian(c, pr, d.Satuan); err != nil {
+			log.Printf("Error memeriksa satuan produk %d: %v", d.ProdukID, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memeriksa data produk"})
+			return
+		} else if pesan != "" {
+			fe.add(field+".satuan", pesan)
+		} else {
+			satuan, satuanValid = ps, true
+			if pr.Kategori.Valid {
+				kategori = append(kategori, pr.Kategori.String)
+			}
+		}
+		if d.Jumlah <= 0 {
+			fe.add(field+".jumlah", "harus lebih dari 0")
+		} else if d.Jumlah > math.MaxInt32/satuan.Faktor {
+			fe.add(field+".jumlah", fmt.Sprintf("terlalu besar: %d %s melebihi batas stok", d.Jumlah, satuan.Satuan))
+		}
+
+		// Tanpa supplier (ID 0), KatalogAktif mengembalikan harga semua supplier, termurah lebih dulu
+		harga := d.HargaPerkiraan
+		if harga == nil && satuanValid {
+			k, ada, err := s.hargaKatalog(c, p.SupplierID.Int64, d.ProdukID, satuan.Satuan, hariIni)
+			if err != nil {
+				log.Printf("Error mencari harga katalog produk %d: %v", d.ProdukID, err)
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memeriksa katalog supplier"})
+				return
+			}
+			if ada {
+				harga = &k.HargaBeli
+			}
+		}
+		if harga == nil {
+			if satuanValid {
+				fe.add(field+".harga_perkiraan", "wajib diisi karena belum ada harga aktif di katalog untuk produk dan satuan ini")
+			}
+			harga = new(float64)
+		} else if *harga <= 0 {
+			fe.add(field+".harga_perkiraan", "harus lebih dari 0")
+		}
+
+		subtotal := bulatkanRupiah(float64(d.Jumlah) * *harga)
+		p.TotalBiaya += subtotal
+		p.Details = append(p.Details, models.DetailPermintaan{
+			ProdukID:       d.ProdukID,
+			Jumlah:         d.Jumlah,
+			Satuan:         satuan.Satuan,
+			FaktorKonversi: satuan.Faktor,
+			HargaPerkiraan: *harga,
+			Subtotal:       subtotal,
+		})
+	}
+	p.TotalBiaya = bulatkanRupiah(p.TotalBiaya)
+	if fe.respond(c) {
+		return
+	}
+
+	daftarAturan, _, err := s.permintaan.ListAturan(ctx, store.Kueri{})
+	if err != nil {
+		log.Printf("Error mengambil aturan persetujuan: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menyimpan permintaan"})
+		return
+	}
+	// Beberapa aturan bisa menunjuk role dan level yang sama; cukup satu tahap
+	type kunciTahap struct {
+		level  int
+		roleID int64
+	}
+	sudah := make(map[kunciTahap]bool)
+	for _, a := range daftarAturan {
+		k := kunciTahap{a.Level, a.RoleID}
+		if !a.Berlaku(p.TotalBiaya, kategori) || sudah[k] {
+			continue
+		}
+		sudah[k] = true
+		p.Persetujuan = append(p.Persetujuan, models.PersetujuanPermintaan{Level: a.Level, RoleID: a.RoleID})
+	}
+	// Konversi tidak memeriksa pembelian.batas_nilai lagi, jadi permintaan di atasnya
+	// tidak boleh disetujui tanpa role yang berwenang membuat pesanan sebesar itu
+	if s.batasNilaiPembelian > 0 && p.TotalBiaya > s.batasNilaiPembelian {
+		tahap, ok := s.tahapNilaiBesar(c, p.Persetujuan)
+		if !ok {
+			return
+		}
+		p.Persetujuan = tahap
+	}
+
+	if err := s.permintaan.CreatePermintaan(ctx, &p); err != nil {
+		log.Printf("Error menyimpan permintaan pembelian: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menyimpan permintaan"})
+		return
+	}
+	s.catatAudit(c, "permintaan", p.PermintaanID, models.AuditBuat, nil, p)
+	c.JSON(http.StatusCreated, p)
+}
+
+// tahapNilaiBesar memastikan salah satu level tahap hanya berisi role dengan izin
+// permintaan.setujui dan pembelian.nilai_besar. Jika belum ada, satu level untuk
+// role-role tersebut ditambahkan setelah level tertinggi. Jika gagal, respons sudah dikirim.
+func (s *server) tahapNilaiBesar(c *gin.Context, tahap []models.PersetujuanPermintaan) ([]models.PersetujuanPermintaan, bool) {
+	daftarRole, err := s.role.ListRole(c.Request.Context())
+	if err != nil {
+		log.Printf("Error mengambil role untuk persetujuan: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menyimpan permintaan"})
+		return nil, false
+	}
+	berwenang := make(map[int64]bool)
+	var roleIDs []int64
+	for _, r := range daftarRole {
+		if slices.Contains(r.Izin, models.IzinPermintaanSetujui) && slices.Contains(r.Izin, models.IzinPembelianNilaiBesar) {
+			berwenang[r.RoleID] = true
+			roleIDs = append(roleIDs, r.RoleID)
+		}
+	}
+
+	cukup := make(map[int]bool)
+	levelMaks := 0
+	for _, t := range tahap {
+		if _, ada := cukup[t.Level]; !ada {
+			cukup[t.Level] = true
+		}
+		cukup[t.Level] = cukup[t.Level] && berwenang[t.RoleID]
+		levelMaks = max(levelMaks, t.Level)
+	}
+	for _, ok := range cukup {
+		if ok {
+			return tahap, true
+		}
+	}
+	if len(roleIDs) == 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": fmt.Sprintf(
+			"Tidak ada role dengan izin %s dan %s untuk menyetujui permintaan di atas %.2f",
+			models.IzinPermintaanSetujui, models.IzinPembelianNilaiBesar, s.batasNilaiPembelian)})
+		return nil, false
+	}
+	for _, id := range roleIDs {
+		tahap = append(tahap, models.PersetujuanPermintaan{Level: levelMaks + 1, RoleID: id})
+	}
+	return tahap, true
+}
+
+func (s *server) setujuiPermintaanHandler(c *gin.Context) {
+	s.putuskanPermintaan(c, models.PersetujuanDisetujui)
+}
+
+func (s *server) tolakPermintaanHandler(c *gin.Context) {
+	s.putuskanPermintaan(c, models.PersetujuanDitolak)
+}
+
+// putuskanPermintaan mencatat keputusan pengguna pada tahap level terendah yang
+// masih menunggu. Pengguna harus memiliki salah satu role tahap itu dan tidak boleh
+// memutuskan permintaannya sendiri. Penolakan wajib disertai komentar.
+func (s *server) putuskanPermintaan(c *gin.Context, keputusan string) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+	var req struct {
+		Komentar string `json:"komentar"`
+	}
+	// Body boleh kosong untuk persetujuan
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Data JSON tidak valid: " + err.Error()})
+			return
+		}
+	}
+	komentar := strings.TrimSpace(req.Komentar)
+	if keputusan == models.PersetujuanDitolak && komentar == "" {
+		fe := make(fieldErrors)
+		fe.add("komentar", "wajib diisi saat menolak permintaan")
+		fe.respond(c)
+		return
+	}
+
+	sebelum, ok := s.ambilPermintaan(c, id)
+	if !ok {
+		return
+	}
+	if sebelum.Status != models.StatusPermintaanDiajukan {
+		c.JSON(http.StatusConflict, gin.H{"error": fmt.Sprintf("Permintaan berstatus %s dan tidak menunggu persetujuan", sebelum.Status)})
+		return
+	}
+	oleh := aktor(c)
+	if sebelum.DimintaOleh == oleh {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Permintaan tidak boleh diputuskan oleh pengajunya sendiri"})
+		return
+	}
+
+	level := 0
+	for _, t := range sebelum.Persetujuan {
+		if t.Status == models.PersetujuanMenunggu && (level == 0 || t.Level < level) {
+			level = t.Level
+		}
+	}
+	claims := c.MustGet(kunciClaims).(auth.Claims)
+	daftarRole, err := s.role.RolePengguna(c.Request.Context(), claims.PenggunaID)
+	if err != nil {
+		log.Printf("Error mengambil role pengguna %d: %v", claims.PenggunaID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memeriksa role pengguna"})
+		return
+	}
+	milik := make(map[int64]bool, len(daftarRole))
+	for _, r := range daftarRole {
+		milik[r.RoleID] = true
+	}
+	var tahap *models.PersetujuanPermintaan
+	var dibutuhkan []string
+	for i, t := range sebelum.Persetujuan {
+		if t.Status != models.PersetujuanMenunggu || t.Level != level {
+			continue
+		}
+		dibutuhkan = append(dibutuhkan, t.NamaRole)
+		if tahap == nil && milik[t.RoleID] {
+			tahap = &sebelum.Persetujuan[i]
+		}
+	}
+	if tahap == nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": fmt.Sprintf("Permintaan menunggu persetujuan level %d dari role %s", level, strings.Join(dibutuhkan, " atau "))})
+		return
+	}
+
+	if err := s.permintaan.PutuskanPersetujuan(c.Request.Context(), id, tahap.PersetujuanID, keputusan, oleh, komentar); err != nil {
+		if errors.Is(err, store.ErrInvalidTransition) {
+			c.JSON(http.StatusConflict, gin.H{"error": "Tahap persetujuan sudah diputuskan, muat ulang permintaan"})
+			return
+		}
+		log.Printf("Error memutuskan permintaan %d: %v", id, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menyimpan keputusan"})
+		return
+	}
+	sesudah, ok := s.ambilPermintaan(c, id)
+	if !ok {
+		return
+	}
+	s.catatAudit(c, "permintaan", id, models.AuditUbah, sebelum, sesudah)
+	c.JSON(http.StatusOK, sesudah)
+}
+
+// batalPermintaanHandler membatalkan permintaan yang belum dikonversi. Hanya pengaju
+// atau pengguna dengan izin permintaan.setujui yang boleh membatalkan.
+func (s *server) batalPermintaanHandler(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+	sebelum, ok := s.ambilPermintaan(c, id)
+	if !ok {
+		return
+	}
+	if sebelum.DimintaOleh != aktor(c) {
+		ada, ok := s.punyaIzin(c, models.IzinPermintaanSetujui)
+		if !ok {
+			return
+		}
+		if !ada {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Hanya pengaju atau pengguna dengan izin " + models.IzinPermintaanSetujui + " yang boleh membatalkan permintaan"})
+			return
+		}
+	}
+	if err := s.permintaan.BatalkanPermintaan(c.Request.Context(), id); err != nil {
+		if errors.Is(err, store.ErrInvalidTransition) {
+			c.JSON(http.StatusConflict, gin.H{"error": fmt.Sprintf("Permintaan berstatus %s tidak bisa dibatalkan", sebelum.Status)})
+			return
+		}
+		log.Printf("Error membatalkan permintaan %d: %v", id, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal membatalkan permintaan"})
+		return
+	}
+	sesudah, ok := s.ambilPermintaan(c, id)
+	if !ok {
+		return
+	}
+	s.catatAudit(c, "permintaan", id, models.AuditUbah, sebelum, sesudah)
+	c.JSON(http.StatusOK, sesudah)
+}
+
+// konversiPermintaanHandler membuat pesanan pembelian dari permintaan yang sudah
+// disetujui, satu baris detail_pembelian per baris permintaan. Harga setiap baris
+// diambil dari body (details[].harga_beli_satuan per detail_permintaan_id), lalu
+// katalog supplier, lalu harga perkiraan permintaan. Validasinya sama dengan
+// POST /api/pembelian dan kesalahan baris memakai urutan baris permintaan. Supplier
+// tidak boleh berbeda dari supplier permintaan dan total pesanan tidak boleh melebihi
+// total yang disetujui; karena itu batas pembelian.batas_nilai tidak diperiksa lagi.
+func (s *server) konversiPermintaanHandler(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+	var req struct {
+		SupplierID     *int64  `json:"supplier_id"`
+		TanggalPesan   string  `json:"tanggal_pesan"`
+		EstimasiTiba   *string `json:"estimasi_tiba"`
+		GudangTujuanID *int64  `json:"gudang_tujuan_id"`
+		Status         string  `json:"status"`
+		Details        []struct {
+			DetailPermintaanID int64    `json:"detail_permintaan_id"`
+			HargaBeliSatuan    *float64 `json:"harga_beli_satuan"`
+		} `json:"details"`
+	}
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Data JSON tidak valid: " + err.Error()})
+			return
+		}
+	}
+	sebelum, ok := s.ambilPermintaan(c, id)
+	if !ok {
+		return
+	}
+	if sebelum.Status != models.StatusPermintaanDisetujui {
+		c.JSON(http.StatusConflict, gin.H{"error": fmt.Sprintf("Hanya permintaan Disetujui yang bisa dikonversi, status saat ini %s", sebelum.Status)})
+		return
+	}
+
+	harga := make(map[int64]*float64, len(req.Details))
+	fe := make(fieldErrors)
+	for i, d := range req.Details {
+		ada := false
+		for _, dp := range sebelum.Details {
+			ada = ada || dp.DetailPermintaanID == d.DetailPermintaanID
+		}
+		if !ada {
+			fe.add(fmt.Sprintf("details[%d].detail_permintaan_id", i), fmt.Sprintf("baris %d bukan bagian dari permintaan ini", d.DetailPermintaanID))
+			continue
+		}
+		harga[d.DetailPermintaanID] = d.HargaBeliSatuan
+	}
+	if fe.respond(c) {
+		return
+	}
+
+	pr := pembelianRequest{
+		TanggalPesan: req.TanggalPesan,
+		EstimasiTiba: req.EstimasiTiba,
+		Status:       req.Status,
+	}
+	if pr.TanggalPesan == "" {
+		pr.TanggalPesan = time.Now().Format("2006-01-02")
+	}
+	if req.SupplierID != nil {
+		pr.SupplierID = *req.SupplierID
+	} else if sebelum.SupplierID.Valid {
+		pr.SupplierID = sebelum.SupplierID.Int64
+	}
+	if sebelum.SupplierID.Valid && pr.SupplierID != sebelum.SupplierID.Int64 {
+		c.JSON(http.StatusConflict, gin.H{"error": fmt.Sprintf("Permintaan disetujui untuk supplier %d; supplier lain membutuhkan permintaan baru", sebelum.SupplierID.Int64)})
+		return
+	}
+	pr.GudangTujuanID = req.GudangTujuanID
+	if pr.GudangTujuanID == nil && sebelum.GudangTujuanID.Valid {
+		pr.GudangTujuanID = &sebelum.GudangTujuanID.Int64
+	}
+	for _, dp := range sebelum.Details {
+		perkiraan := dp.HargaPerkiraan
+		pr.Details = append(pr.Details, detailPembelianRequest{
+			ProdukID:        dp.ProdukID,
+			Jumlah:          dp.Jumlah,
+			Satuan:          dp.Satuan,
+			HargaBeliSatuan: harga[dp.DetailPermintaanID],
+			hargaCadangan:   &perkiraan,
+		})
+	}
+	pembelianBaru, details, ok := s.siapkanPembelian(c, pr)
+	if !ok {
+		return
+	}
+	if total := pembelianBaru.TotalBiaya.Float64; total > sebelum.TotalBiaya {
+		c.JSON(http.StatusConflict, gin.H{"error": fmt.Sprintf("Total pesanan %.2f melebihi total yang disetujui %.2f; turunkan harga atau ajukan permintaan baru", total, sebelum.TotalBiaya)})
+		return
+	}
+
+	if err := s.permintaan.KonversiPermintaan(c.Request.Context(), id, &pembelianBaru, details, aktor(c)); err != nil {
+		if errors.Is(err, store.ErrInvalidTransition) {
+			c.JSON(http.StatusConflict, gin.H{"error": "Permintaan sudah tidak berstatus Disetujui, muat ulang permintaan"})
+			return
+		}
+		log.Printf("Error mengonversi permintaan %d: %v", id, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menyimpan data pembelian"})
+		return
+	}
+	s.catatAudit(c, "pembelian", pembelianBaru.PembelianID, models.AuditBuat, nil, s.pembelianUntukAudit(c, pembelianBaru.PembelianID))
+	sesudah, ok := s.ambilPermintaan(c, id)
+	if !ok {
+		return
+	}
+	s.catatAudit(c, "permintaan", id, models.AuditUbah, sebelum, sesudah)
+	c.JSON(http.StatusCreated, gin.H{"message": "Pesanan pembelian berhasil dibuat dari permintaan", "pembelian_id": pembelianBaru.PembelianID, "total_biaya": pembelianBaru.TotalBiaya.Float64})
+}
+
+// ambilPermintaan mengambil permintaan beserta detailnya. Jika gagal, respons sudah dikirim.
+func (s *server) ambilPermintaan(c *gin.Context, id int64) (models.PermintaanDenganDetail, bool) {
+	p, err := s.permintaan.GetPermintaan(c.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Permintaan tidak ditemukan"})
+			return p, false
+		}
+		log.Printf("Error mengambil permintaan %d: %v", id, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Terjadi kesalahan internal"})
+		return p, false
+	}
+	return p, true
+}
+
+// =================================================================
+// ATURAN PERSETUJUAN
+// =================================================================
+
+func (s *server) getAturanHandler(c *gin.Context) {
+	q, ok := bacaKueri(c, store.DaftarAturan)
+	if !ok {
+		return
+	}
+	if v, ada := q.Filter["level"]; ada {
+		fe := make(fieldErrors)
+		if n, err := strconv.Atoi(v); err != nil || n < 1 {
+			fe.add("level", "harus bilangan bulat mulai dari 1")
+		}
+		if fe.respond(c) {
+			return
+		}
+	}
+	daftar, total, err := s.permintaan.ListAturan(c.Request.Context(), q)
+	if err != nil {
+		log.Printf("Error mengambil aturan persetujuan: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil aturan persetujuan"})
+		return
+	}
+	kirimHalaman(c, daftar, total, q)
+}
+
+// createAturanHandler menambah aturan, misalnya {"level": 2, "nilai_minimal": 5000000,
+// "role_id": 2} agar permintaan bernilai lima juta ke atas juga disetujui manajer
+func (s *server) createAturanHandler(c *gin.Context) {
+	a, ok := s.bacaAturan(c)
+	if !ok {
+		return
+	}
+	if err := s.permintaan.CreateAturan(c.Request.Context(), &a); err != nil {
+		log.Printf("Error menyimpan aturan persetujuan: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menyimpan aturan persetujuan"})
+		return
+	}
+	s.catatAudit(c, "aturan_persetujuan", a.AturanID, models.AuditBuat, nil, a)
+	c.JSON(http.StatusCreated, a)
+}
+
+// updateAturanHandler mengganti aturan. Permintaan yang sudah diajukan tetap
+// memakai tahap yang disalin saat pengajuan.
+func (s *server) updateAturanHandler(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+	sebelum, ok := s.ambilAturan(c, id)
+	if !ok {
+		return
+	}
+	a, ok := s.bacaAturan(c)
+	if !ok {
+		return
+	}
+	a.AturanID = id
+	if err := s.permintaan.UpdateAturan(c.Request.Context(), a); err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Aturan persetujuan tidak ditemukan"})
+			return
+		}
+		log.Printf("Error mengubah aturan persetujuan %d: %v", id, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengubah aturan persetujuan"})
+		return
+	}
+	sesudah, ok := s.ambilAturan(c, id)
+	if !ok {
+		return
+	}
+	s.catatAudit(c, "aturan_persetujuan", id, models.AuditUbah, sebelum, sesudah)
+	c.JSON(http.StatusOK, sesudah)
+}
+
+func (s *server) deleteAturanHandler(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+	sebelum, ok := s.ambilAturan(c, id)
+	if !ok {
+		return
+	}
+	if err := s.permintaan.DeleteAturan(c.Request.Context(), id); err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Aturan persetujuan tidak ditemukan"})
+			return
+		}
+		log.Printf("Error menghapus aturan persetujuan %d: %v", id, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menghapus aturan persetujuan"})
+		return
+	}
+	s.catatAudit(c, "aturan_persetujuan", id, models.AuditHapus, sebelum, nil)
+	c.JSON(http.StatusOK, gin.H{"message": "Aturan persetujuan berhasil dihapus"})
+}
+
+// bacaAturan membaca dan memvalidasi body aturan persetujuan. Jika gagal, respons sudah dikirim.
+func (s *server) bacaAturan(c *gin.Context) (models.AturanPersetujuan, bool) {
+	var req struct {
+		Level        int     `json:"level"`
+		NilaiMinimal float64 `json:"nilai_minimal"`
+		Kategori     string  `json:"kategori"`
+		RoleID       int64   `json:"role_id"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Data JSON tidak valid: " + err.Error()})
+		return models.AturanPersetujuan{}, false
+	}
+	kategori := strings.TrimSpace(req.Kategori)
+	a := models.AturanPersetujuan{
+		Level:        req.Level,
+		NilaiMinimal: bulatkanRupiah(req.NilaiMinimal),
+		Kategori:     sql.NullString{String: kategori, Valid: kategori != ""},
+		RoleID:       req.RoleID,
+	}
+	fe := make(fieldErrors)
+	if a.Level < 1 || a.Level > levelPersetujuanMaks {
+		fe.add("level", fmt.Sprintf("harus antara 1 dan %d", levelPersetujuanMaks))
+	}
+	if a.NilaiMinimal < 0 {
+		fe.add("nilai_minimal", "tidak boleh negatif")
+	}
+	if utf8.RuneCountInString(a.Kategori.String) > panjangKategoriMaks {
+		fe.add("kategori", fmt.Sprintf("maksimal %d karakter", panjangKategoriMaks))
+	}
+	if a.RoleID < 1 {
+		fe.add("role_id", "wajib diisi")
+	} else {
+		daftarRole, err := s.role.ListRole(c.Request.Context())
+		if err != nil {
+			log.Printf("Error mengambil role: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memeriksa data role"})
+			return a, false
+		}
+		ada := false
+		for _, r := range daftarRole {
+			ada = ada || r.RoleID == a.RoleID
+		}
+		if !ada {
+			fe.add("role_id", fmt.Sprintf("role dengan ID %d tidak ditemukan", a.RoleID))
+		}
+	}
+	if fe.respond(c) {
+		return a, false
+	}
+	return a, true
+}
+
+// ambilAturan mengambil aturan persetujuan. Jika gagal, respons sudah dikirim.
+func (s *server) ambilAturan(c *gin.Context, id int64) (models.AturanPersetujuan, bool) {
+	a, err := s.permintaan.GetAturan(c.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Aturan persetujuan tidak ditemukan"})
+			return a, false
+		}
+		log.Printf("Error mengambil aturan persetujuan %d: %v", id, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Terjadi kesalahan internal"})
+		return a, false
+	}
+	return a, true
+}
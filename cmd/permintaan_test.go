@@ -0,0 +1,141 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"scm-api/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ajukan membuat permintaan produk 1 untuk supplier 1 dan gudang 1 sebagai pengguna
+func (p *penguji) ajukan(pengguna string, jumlah int, harga float64) models.PermintaanDenganDetail {
+	p.t.Helper()
+	var pr models.PermintaanDenganDetail
+	p.decode(p.harus(http.StatusCreated, pengguna, "POST", "/api/permintaan", gin.H{
+		"supplier_id": 1, "gudang_tujuan_id": 1,
+		"details": []gin.H{{"produk_id": 1, "jumlah": jumlah, "harga_perkiraan": harga}},
+	}), &pr)
+	return pr
+}
+
+// tahapPermintaan meringkas tahap persetujuan menjadi "level:role:status"
+func tahapPermintaan(pr models.PermintaanDenganDetail) []string {
+	var hasil []string
+	for _, t := range pr.Persetujuan {
+		hasil = append(hasil, fmt.Sprintf("%d:%s:%s", t.Level, t.NamaRole, t.Status))
+	}
+	return hasil
+}
+
+func TestPersetujuanBertingkat(t *testing.T) {
+	p := pengujiBaru(t)
+	p.dataDasar()
+	// Aturan bawaan meminta manajer di level 1; admin ditambahkan di level 2 mulai lima juta
+	p.harus(http.StatusCreated, "admin", "POST", "/api/permintaan/aturan", gin.H{"level": 2, "nilai_minimal": 5_000_000, "role_id": 1})
+	p.harus(http.StatusBadRequest, "admin", "POST", "/api/permintaan/aturan", gin.H{"level": 1, "role_id": 99})
+	p.harus(http.StatusForbidden, "pembelian", "POST", "/api/permintaan/aturan", gin.H{"level": 1, "role_id": 2})
+
+	kecil := p.ajukan("pembelian", 2, 1000)
+	if kecil.Status != models.StatusPermintaanDiajukan || fmt.Sprint(tahapPermintaan(kecil)) != "[1:manajer:Menunggu]" {
+		t.Fatalf("permintaan kecil = %s %v, ingin Diajukan dengan satu tahap manajer", kecil.Status, tahapPermintaan(kecil))
+	}
+	url := fmt.Sprintf("/api/permintaan/%d", kecil.PermintaanID)
+	p.harus(http.StatusForbidden, "pembelian", "PUT", url+"/setujui", nil)
+	p.harus(http.StatusForbidden, "admin", "PUT", url+"/setujui", nil)
+	p.harus(http.StatusBadRequest, "manajer", "PUT", url+"/tolak", gin.H{"komentar": "  "})
+	var pr models.PermintaanDenganDetail
+	p.decode(p.harus(http.StatusOK, "manajer", "PUT", url+"/setujui", nil), &pr)
+	if pr.Status != models.StatusPermintaanDisetujui || !pr.Persetujuan[0].DiputuskanOleh.Valid || pr.Persetujuan[0].DiputuskanOleh.String != "manajer" {
+		t.Errorf("setelah disetujui = %s %+v, ingin Disetujui oleh manajer", pr.Status, pr.Persetujuan)
+	}
+	p.harus(http.StatusConflict, "manajer", "PUT", url+"/setujui", nil)
+
+	// Permintaan di atas lima juta harus lewat manajer lalu admin, berurutan
+	besar := p.ajukan("pembelian", 6000, 1000)
+	if fmt.Sprint(tahapPermintaan(besar)) != "[1:manajer:Menunggu 2:admin:Menunggu]" {
+		t.Fatalf("tahap permintaan besar = %v, ingin manajer lalu admin", tahapPermintaan(besar))
+	}
+	url = fmt.Sprintf("/api/permintaan/%d", besar.PermintaanID)
+	p.harus(http.StatusForbidden, "admin", "PUT", url+"/setujui", nil)
+	p.decode(p.harus(http.StatusOK, "manajer", "PUT", url+"/setujui", gin.H{"komentar": "sesuai anggaran"}), &pr)
+	if pr.Status != models.StatusPermintaanDiajukan || fmt.Sprint(tahapPermintaan(pr)) != "[1:manajer:Disetujui 2:admin:Menunggu]" {
+		t.Errorf("setelah level 1 = %s %v, ingin masih menunggu admin", pr.Status, tahapPermintaan(pr))
+	}
+	p.harus(http.StatusForbidden, "manajer", "PUT", url+"/setujui", nil)
+	p.decode(p.harus(http.StatusOK, "admin", "PUT", url+"/tolak", gin.H{"komentar": "tunda ke bulan depan"}), &pr)
+	if pr.Status != models.StatusPermintaanDitolak || !pr.Persetujuan[1].Komentar.Valid {
+		t.Errorf("setelah ditolak = %s %+v, ingin Ditolak dengan komentar", pr.Status, pr.Persetujuan)
+	}
+
+	// Pengaju tidak boleh menyetujui permintaannya sendiri walaupun role-nya cocok
+	sendiri := p.ajukan("manajer", 1, 1000)
+	p.harus(http.StatusForbidden, "manajer", "PUT", fmt.Sprintf("/api/permintaan/%d/setujui", sendiri.PermintaanID), nil)
+	p.harus(http.StatusForbidden, "manajer", "PUT", fmt.Sprintf("/api/permintaan/%d/tolak", sendiri.PermintaanID), gin.H{"komentar": "batal"})
+}
+
+func TestPermintaanDiAtasBatasNilai(t *testing.T) {
+	p := pengujiBaru(t)
+	p.dataDasar()
+	p.harus(http.StatusOK, "admin", "DELETE", "/api/permintaan/aturan/1", nil)
+
+	// Tanpa aturan, permintaan biasa langsung disetujui
+	if pr := p.ajukan("pembelian", 1, 1000); pr.Status != models.StatusPermintaanDisetujui || len(pr.Persetujuan) != 0 {
+		t.Errorf("permintaan tanpa aturan = %s %v, ingin langsung Disetujui", pr.Status, tahapPermintaan(pr))
+	}
+	// Di atas pembelian.batas_nilai tetap menunggu role dengan izin pembelian.nilai_besar
+	pr := p.ajukan("pembelian", 11, 1_000_000)
+	if pr.Status != models.StatusPermintaanDiajukan || fmt.Sprint(tahapPermintaan(pr)) != "[1:admin:Menunggu 1:manajer:Menunggu]" {
+		t.Fatalf("permintaan di atas batas = %s %v, ingin menunggu admin atau manajer", pr.Status, tahapPermintaan(pr))
+	}
+	p.decode(p.harus(http.StatusOK, "manajer", "PUT", fmt.Sprintf("/api/permintaan/%d/setujui", pr.PermintaanID), nil), &pr)
+	if pr.Status != models.StatusPermintaanDisetujui || fmt.Sprint(tahapPermintaan(pr)) != "[1:admin:Dilewati 1:manajer:Disetujui]" {
+		t.Errorf("setelah disetujui manajer = %s %v, ingin tahap admin dilewati", pr.Status, tahapPermintaan(pr))
+	}
+
+	// Aturan level 1 untuk role gudang tidak cukup, jadi level nilai besar ditambahkan
+	p.harus(http.StatusCreated, "admin", "POST", "/api/permintaan/aturan", gin.H{"level": 1, "role_id": 4})
+	pr = p.ajukan("pembelian", 11, 1_000_000)
+	if fmt.Sprint(tahapPermintaan(pr)) != "[1:gudang:Menunggu 2:admin:Menunggu 2:manajer:Menunggu]" {
+		t.Errorf("tahap dengan aturan gudang = %v, ingin level 2 untuk admin atau manajer", tahapPermintaan(pr))
+	}
+}
+
+func TestKonversiPermintaan(t *testing.T) {
+	p := pengujiBaru(t)
+	p.dataDasar()
+	p.harus(http.StatusCreated, "admin", "POST", "/api/supplier", gin.H{"nama_supplier": "Cahaya Abadi"})
+
+	pr := p.ajukan("pembelian", 4, 1000)
+	url := fmt.Sprintf("/api/permintaan/%d/pembelian", pr.PermintaanID)
+	p.harus(http.StatusConflict, "pembelian", "POST", url, nil)
+	p.harus(http.StatusOK, "manajer", "PUT", fmt.Sprintf("/api/permintaan/%d/setujui", pr.PermintaanID), nil)
+
+	baris := pr.Details[0].DetailPermintaanID
+	p.harus(http.StatusConflict, "pembelian", "POST", url, gin.H{"supplier_id": 2})
+	p.harus(http.StatusConflict, "pembelian", "POST", url, gin.H{"details": []gin.H{{"detail_permintaan_id": baris, "harga_beli_satuan": 1001}}})
+	p.harus(http.StatusBadRequest, "pembelian", "POST", url, gin.H{"details": []gin.H{{"detail_permintaan_id": baris + 100, "harga_beli_satuan": 900}}})
+	p.harus(http.StatusForbidden, "gudang", "POST", url, nil)
+
+	var hasil struct {
+		PembelianID int64   `json:"pembelian_id"`
+		TotalBiaya  float64 `json:"total_biaya"`
+	}
+	p.decode(p.harus(http.StatusCreated, "pembelian", "POST", url, gin.H{"details": []gin.H{{"detail_permintaan_id": baris, "harga_beli_satuan": 900}}}), &hasil)
+	if hasil.TotalBiaya != 3600 {
+		t.Errorf("total pesanan = %v, ingin 3600 dari harga yang diturunkan", hasil.TotalBiaya)
+	}
+	var sesudah models.PermintaanDenganDetail
+	p.decode(p.harus(http.StatusOK, "pembelian", "GET", fmt.Sprintf("/api/permintaan/%d", pr.PermintaanID), nil), &sesudah)
+	if sesudah.Status != models.StatusPermintaanSelesai || sesudah.PembelianID.Int64 != hasil.PembelianID {
+		t.Errorf("permintaan setelah konversi = %s pembelian %+v, ingin Selesai dengan pembelian %d", sesudah.Status, sesudah.PembelianID, hasil.PembelianID)
+	}
+	var pb models.PembelianDenganDetailResponse
+	p.decode(p.harus(http.StatusOK, "pembelian", "GET", fmt.Sprintf("/api/pembelian/%d", hasil.PembelianID), nil), &pb)
+	if pb.SupplierID != 1 || len(pb.Details) != 1 || pb.Details[0].Jumlah != 4 {
+		t.Errorf("pembelian hasil konversi = %+v, ingin 4 produk dari supplier 1", pb)
+	}
+	p.harus(http.StatusConflict, "pembelian", "POST", url, nil)
+}
@@ -0,0 +1,269 @@
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"scm-api/internal/models"
+	"scm-api/internal/store"
+
+	"github.com/gin-gonic/gin"
+)
+
+// =================================================================
+// HANDLER UNTUK MODUL PRODUK
+// =================================================================
+
+// Pencarian produk: jumlah hasil per halaman jika limit tidak diisi, panjang
+// kueri maksimal, dan umur indeks sebelum dibangun ulang dari database
+const (
+	batasCariBawaan  = 20
+	panjangKueriMaks = 100
+	umurIndeksProduk = 5 * time.Minute
+)
+
+func (s *server) getProdukHandler(c *gin.Context) {
+	q, ok := bacaKueri(c, store.DaftarProduk)
+	if !ok {
+		return
+	}
+	daftarProduk, total, err := s.produk.ListProduk(c.Request.Context(), q)
+	if err != nil {
+		log.Printf("Error mengambil produk: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil data produk"})
+		return
+	}
+	kirimHalaman(c, daftarProduk, total, q)
+}
+
+// searchProdukHandler mencari produk berdasarkan SKU, nama, kategori, dan deskripsi.
+// Hasilnya diurutkan menurut relevansi dan dibagi per halaman seperti endpoint daftar.
+func (s *server) searchProdukHandler(c *gin.Context) {
+	fe := make(fieldErrors)
+	kueri := strings.TrimSpace(c.Query("q"))
+	switch {
+	case kueri == "":
+		fe.add("q", "wajib diisi")
+	case utf8.RuneCountInString(kueri) > panjangKueriMaks:
+		fe.add("q", fmt.Sprintf("maksimal %d karakter", panjangKueriMaks))
+	}
+	q := store.Kueri{Halaman: 1, Batas: batasCariBawaan}
+	if v := c.Query("page"); v != "" {
+		if n, err := strconv.Atoi(v); err != nil || n < 1 {
+			fe.add("page", "harus bilangan bulat mulai dari 1")
+		} else {
+			q.Halaman = n
+		}
+	}
+	if v := c.Query("limit"); v != "" {
+		if n, err := strconv.Atoi(v); err != nil || n < 1 || n > batasMaks {
+			fe.add("limit", fmt.Sprintf("harus antara 1 dan %d", batasMaks))
+		} else {
+			q.Batas = n
+		}
+	}
+	if fe.respond(c) {
+		return
+	}
+
+	hasil, err := s.indeksProduk.Cari(c.Request.Context(), kueri)
+	if err != nil {
+		log.Printf("Error mencari produk: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mencari produk"})
+		return
+	}
+	total := len(hasil)
+	awal := min(q.Offset(), total)
+	q.Urut, q.Turun = "relevansi", true
+	kirimHalaman(c, hasil[awal:min(awal+q.Batas, total)], total, q)
+}
+
+func (s *server) getProdukByIdHandler(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+	p, err := s.produk.GetProduk(c.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Produk tidak ditemukan"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Terjadi kesalahan internal"})
+		return
+	}
+	c.JSON(http.StatusOK, p)
+}
+
+func (s *server) createProdukHandler(c *gin.Context) {
+	var req struct {
+		SKU        string   `json:"sku"`
+		NamaProduk string   `json:"nama_produk"`
+		Deskripsi  *string  `json:"deskripsi"`
+		Kategori   *string  `json:"kategori"`
+		Satuan     string   `json:"satuan"`
+		HargaJual  float64  `json:"harga_jual"`
+		BeratKg    *float64 `json:"berat_kg"`
+		SupplierID *int64   `json:"supplier_id"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Data JSON tidak valid: " + err.Error()})
+		return
+	}
+	produkBaru := models.Produk{SKU: req.SKU, NamaProduk: req.NamaProduk, Satuan: req.Satuan, HargaJual: req.HargaJual}
+	if req.Kategori != nil {
+		produkBaru.Kategori = sql.NullString{String: *req.Kategori, Valid: true}
+	}
+	if req.Deskripsi != nil {
+		produkBaru.Deskripsi = sql.NullString{String: *req.Deskripsi, Valid: true}
+	}
+	if req.BeratKg != nil {
+		produkBaru.BeratKg = sql.NullFloat64{Float64: *req.BeratKg, Valid: true}
+	}
+	if req.SupplierID != nil {
+		produkBaru.SupplierID = sql.NullInt64{Int64: *req.SupplierID, Valid: true}
+	}
+	if err := s.produk.CreateProduk(c.Request.Context(), &produkBaru); err != nil {
+		if errors.Is(err, store.ErrDuplikat) {
+			c.JSON(http.StatusConflict, gin.H{"error": fmt.Sprintf("SKU %q sudah dipakai produk lain", req.SKU)})
+			return
+		}
+		log.Printf("Error menyimpan produk: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menyimpan produk ke database"})
+		return
+	}
+	s.indeksProduk.Kosongkan()
+	s.catatAudit(c, "produk", produkBaru.ProdukID, models.AuditBuat, nil, produkBaru)
+	c.JSON(http.StatusCreated, produkBaru)
+}
+
+func (s *server) updateProdukHandler(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+	var req struct {
+		SKU        string  `json:"sku"`
+		NamaProduk string  `json:"nama_produk"`
+		Kategori   *string `json:"kategori"`
+		Satuan     string  `json:"satuan"`
+		HargaJual  float64 `json:"harga_jual"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Data JSON tidak valid: " + err.Error()})
+		return
+	}
+	p, err := s.produk.GetProduk(c.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Produk tidak ditemukan"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengupdate produk"})
+		return
+	}
+	if p.DeletedAt.Valid {
+		c.JSON(http.StatusConflict, gin.H{"error": "Produk sudah dihapus; pulihkan dulu sebelum mengubahnya"})
+		return
+	}
+	// Satuan dasar tidak boleh sama dengan salah satu satuan beli produk ini
+	if !strings.EqualFold(req.Satuan, p.Satuan) {
+		if ps, err := s.satuan.CariSatuan(c.Request.Context(), id, req.Satuan); err == nil {
+			c.JSON(http.StatusConflict, gin.H{"error": fmt.Sprintf("Satuan %q sudah terdaftar sebagai satuan beli dengan faktor %d; hapus dulu satuan beli tersebut", ps.Satuan, ps.Faktor)})
+			return
+		} else if !errors.Is(err, store.ErrNotFound) {
+			log.Printf("Error memeriksa satuan produk %d: %v", id, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengupdate produk"})
+			return
+		}
+	}
+	sebelum := p
+	p.SKU = req.SKU
+	p.NamaProduk = req.NamaProduk
+	p.Kategori = sql.NullString{}
+	if req.Kategori != nil {
+		p.Kategori = sql.NullString{String: *req.Kategori, Valid: true}
+	}
+	p.Satuan = req.Satuan
+	p.HargaJual = req.HargaJual
+	if err := s.produk.UpdateProduk(c.Request.Context(), p); err != nil {
+		if errors.Is(err, store.ErrDuplikat) {
+			c.JSON(http.StatusConflict, gin.H{"error": fmt.Sprintf("SKU %q sudah dipakai produk lain", req.SKU)})
+			return
+		}
+		log.Printf("Error mengupdate produk: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengupdate produk"})
+		return
+	}
+	s.indeksProduk.Kosongkan()
+	s.catatAudit(c, "produk", id, models.AuditUbah, sebelum, p)
+	c.JSON(http.StatusOK, gin.H{"message": "Produk berhasil diupdate"})
+}
+
+func (s *server) deleteProdukHandler(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+	sebelum, err := s.produk.GetProduk(c.Request.Context(), id)
+	if err == nil && sebelum.DeletedAt.Valid {
+		c.JSON(http.StatusConflict, gin.H{"error": "Produk sudah dihapus"})
+		return
+	}
+	if err == nil {
+		err = s.produk.DeleteProduk(c.Request.Context(), id)
+	}
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Produk tidak ditemukan"})
+			return
+		}
+		log.Printf("Error menghapus produk: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menghapus produk"})
+		return
+	}
+	s.indeksProduk.Kosongkan()
+	s.catatAudit(c, "produk", id, models.AuditHapus, sebelum, nil)
+	c.JSON(http.StatusOK, gin.H{"message": "Produk berhasil dihapus"})
+}
+
+// pulihkanProdukHandler membatalkan penghapusan produk
+func (s *server) pulihkanProdukHandler(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+	sebelum, err := s.produk.GetProduk(c.Request.Context(), id)
+	if err == nil && !sebelum.DeletedAt.Valid {
+		c.JSON(http.StatusConflict, gin.H{"error": "Produk tidak sedang dihapus"})
+		return
+	}
+	if err == nil {
+		err = s.produk.PulihkanProduk(c.Request.Context(), id)
+	}
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Produk tidak ditemukan"})
+			return
+		}
+		if errors.Is(err, store.ErrDuplikat) {
+			c.JSON(http.StatusConflict, gin.H{"error": fmt.Sprintf("SKU %q sudah dipakai produk lain; ubah SKU produk tersebut sebelum memulihkan", sebelum.SKU)})
+			return
+		}
+		log.Printf("Error memulihkan produk %d: %v", id, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memulihkan produk"})
+		return
+	}
+	p := sebelum
+	p.DeletedAt = sql.NullString{}
+	s.indeksProduk.Kosongkan()
+	s.catatAudit(c, "produk", id, models.AuditPulihkan, sebelum, p)
+	c.JSON(http.StatusOK, p)
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"net/http"
+	"testing"
+
+	"scm-api/internal/models"
+	"scm-api/internal/pencarian"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestCariProduk(t *testing.T) {
+	p := pengujiBaru(t)
+	p.dataDasar()
+
+	cari := func(kueri string) models.Halaman[pencarian.Hasil] {
+		var h models.Halaman[pencarian.Hasil]
+		p.decode(p.harus(http.StatusOK, "gudang", "GET", "/api/produk/search?q="+kueri, nil), &h)
+		return h
+	}
+	if h := cari("brg-1"); h.Meta.Total != 1 || h.Data[0].Produk.ProdukID != 1 || h.Data[0].Field != "sku" {
+		t.Errorf("cari brg-1 = %+v, ingin produk 1 lewat SKU", h)
+	}
+	// Indeks dikosongkan saat produk berubah sehingga nama baru langsung bisa dicari
+	p.harus(http.StatusOK, "admin", "PUT", "/api/produk/2", gin.H{"sku": "BRG-2", "nama_produk": "Kopi Bubuk", "satuan": "pcs", "harga_jual": 2500})
+	if h := cari("kopi+bubk"); h.Meta.Total != 1 || h.Data[0].Produk.ProdukID != 2 || h.Data[0].Sorotan != "<mark>Kopi</mark> <mark>Bubuk</mark>" {
+		t.Errorf("cari kopi bubk = %+v, ingin produk 2 dengan sorotan", h)
+	}
+	p.harus(http.StatusOK, "admin", "DELETE", "/api/produk/2", nil)
+	if h := cari("kopi"); h.Meta.Total != 0 {
+		t.Errorf("produk yang dihapus masih ditemukan: %+v", h)
+	}
+	if h := cari("barang&limit=1&page=2"); h.Meta.Total != 1 || len(h.Data) != 0 || h.Meta.Sort != "relevansi" {
+		t.Errorf("halaman kedua = %+v, ingin kosong dari total 1", h)
+	}
+	p.harus(http.StatusBadRequest, "gudang", "GET", "/api/produk/search?q=+", nil)
+}
+
+func TestHapusDanPulihkanProduk(t *testing.T) {
+	p := pengujiBaru(t)
+	p.dataDasar()
+
+	p.harus(http.StatusOK, "admin", "DELETE", "/api/produk/1", nil)
+	p.harus(http.StatusConflict, "admin", "DELETE", "/api/produk/1", nil)
+	var daftar models.Halaman[models.Produk]
+	p.decode(p.harus(http.StatusOK, "admin", "GET", "/api/produk", nil), &daftar)
+	if daftar.Meta.Total != 1 || daftar.Data[0].ProdukID != 2 {
+		t.Errorf("daftar produk = %+v, ingin hanya produk 2", daftar.Data)
+	}
+	p.decode(p.harus(http.StatusOK, "admin", "GET", "/api/produk?include_deleted=true", nil), &daftar)
+	if daftar.Meta.Total != 2 {
+		t.Errorf("daftar produk termasuk yang dihapus = %d, ingin 2", daftar.Meta.Total)
+	}
+	// Produk yang dihapus tidak bisa dipesan atau dijual lagi
+	p.harus(http.StatusBadRequest, "admin", "POST", "/api/pembelian", gin.H{
+		"supplier_id": 1, "tanggal_pesan": "2024-05-01", "gudang_tujuan_id": 1,
+		"details": []gin.H{{"produk_id": 1, "jumlah": 1, "harga_beli_satuan": 1000}},
+	})
+	p.harus(http.StatusBadRequest, "gudang", "POST", "/api/stok/penjualan", gin.H{"produk_id": 1, "gudang_id": 1, "jumlah": 1})
+
+	// SKU hanya unik di antara produk yang belum dihapus
+	p.harus(http.StatusCreated, "admin", "POST", "/api/produk", gin.H{"sku": "BRG-1", "nama_produk": "Barang Satu Baru", "satuan": "pcs", "harga_jual": 1500})
+	p.harus(http.StatusConflict, "admin", "POST", "/api/produk", gin.H{"sku": "BRG-2", "nama_produk": "Kembar", "satuan": "pcs", "harga_jual": 1500})
+	p.harus(http.StatusConflict, "admin", "PUT", "/api/produk/1/pulihkan", nil)
+	p.harus(http.StatusOK, "admin", "DELETE", "/api/produk/3", nil)
+	p.harus(http.StatusOK, "admin", "PUT", "/api/produk/1/pulihkan", nil)
+	p.harus(http.StatusConflict, "admin", "PUT", "/api/produk/1/pulihkan", nil)
+	p.harus(http.StatusNotFound, "admin", "PUT", "/api/produk/99/pulihkan", nil)
+	p.harus(http.StatusOK, "gudang", "POST", "/api/stok/adjust", gin.H{"produk_id": 1, "gudang_id": 1, "jumlah": 5})
+}
@@ -0,0 +1,488 @@
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"time"
+
+	"scm-api/internal/forecast"
+	"scm-api/internal/models"
+	"scm-api/internal/replenishment"
+	"scm-api/internal/store"
+
+	"github.com/gin-gonic/gin"
+)
+
+// =================================================================
+// HANDLER UNTUK PARAMETER STOK DAN SARAN PEMESANAN ULANG
+// =================================================================
+
+// Bawaan dan batas periode perhitungan saran, dalam hari
+const (
+	hariPemakaianBawaan = 28
+	cakupanHariBawaan   = 14
+	hariSaranMaks       = 365
+)
+
+// Query opsional: produk_id, gudang_id
+func (s *server) getParameterStokHandler(c *gin.Context) {
+	produkID, ok := queryID(c, "produk_id")
+	if !ok {
+		return
+	}
+	gudangID, ok := queryID(c, "gudang_id")
+	if !ok {
+		return
+	}
+	l, ok := s.gudangSaya(c)
+	if !ok || (gudangID != 0 && !s.bolehGudang(c, gudangID)) {
+		return
+	}
+	daftar, err := s.replenishment.ListParameterStok(c.Request.Context(), produkID, gudangID)
+	if err != nil {
+		log.Printf("Error mengambil parameter stok: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil parameter stok"})
+		return
+	}
+	c.JSON(http.StatusOK, saring(daftar, func(p models.ParameterStok) bool { return l.boleh(p.GudangID) }))
+}
+
+// simpanParameterStokHandler menambah atau mengganti parameter produk di gudang.
+// reorder_point dan max_level boleh null agar dihitung dari pemakaian.
+func (s *server) simpanParameterStokHandler(c *gin.Context) {
+	produkID, ok := paramID(c, "produk_id")
+	if !ok {
+		return
+	}
+	gudangID, ok := paramID(c, "gudang_id")
+	if !ok {
+		return
+	}
+	var req struct {
+		ReorderPoint *int `json:"reorder_point"`
+		SafetyStock  int  `json:"safety_stock"`
+		MaxLevel     *int `json:"max_level"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Data JSON tidak valid: " + err.Error()})
+		return
+	}
+	fe := make(fieldErrors)
+	if req.ReorderPoint != nil && (*req.ReorderPoint < 0 || *req.ReorderPoint > math.MaxInt32) {
+		fe.add("reorder_point", "harus antara 0 dan batas stok")
+	}
+	if req.SafetyStock < 0 || req.SafetyStock > math.MaxInt32 {
+		fe.add("safety_stock", "harus antara 0 dan batas stok")
+	}
+	if req.MaxLevel != nil {
+		switch {
+		case *req.MaxLevel < 1 || *req.MaxLevel > math.MaxInt32:
+			fe.add("max_level", "harus antara 1 dan batas stok")
+		case req.ReorderPoint != nil && *req.MaxLevel < *req.ReorderPoint:
+			fe.add("max_level", "tidak boleh lebih kecil dari reorder_point")
+		case *req.MaxLevel < req.SafetyStock:
+			fe.add("max_level", "tidak boleh lebih kecil dari safety_stock")
+		}
+	}
+	if fe.respond(c) {
+		return
+	}
+	if !s.bolehGudang(c, gudangID) || !s.gudangAda(c, gudangID) || !s.produkAda(c, produkID) {
+		return
+	}
+
+	ctx := c.Request.Context()
+	var sebelum any
+	aksi := models.AuditUbah
+	if lama, err := s.replenishment.GetParameterStok(ctx, produkID, gudangID); errors.Is(err, store.ErrNotFound) {
+		aksi = models.AuditBuat
+	} else if err != nil {
+		log.Printf("Error mengambil parameter stok produk %d gudang %d: %v", produkID, gudangID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menyimpan parameter stok"})
+		return
+	} else {
+		sebelum = lama
+	}
+
+	p := models.ParameterStok{ProdukID: produkID, GudangID: gudangID, SafetyStock: req.SafetyStock}
+	if req.ReorderPoint != nil {
+		p.ReorderPoint = sql.NullInt64{Int64: int64(*req.ReorderPoint), Valid: true}
+	}
+	if req.MaxLevel != nil {
+		p.MaxLevel = sql.NullInt64{Int64: int64(*req.MaxLevel), Valid: true}
+	}
+	if err := s.replenishment.SimpanParameterStok(ctx, &p); err != nil {
+		log.Printf("Error menyimpan parameter stok produk %d gudang %d: %v", produkID, gudangID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menyimpan parameter stok"})
+		return
+	}
+	s.catatAudit(c, "stok_parameter", p.ParameterID, aksi, sebelum, p)
+	c.JSON(http.StatusOK, p)
+}
+
+func (s *server) hapusParameterStokHandler(c *gin.Context) {
+	produkID, ok := paramID(c, "produk_id")
+	if !ok {
+		return
+	}
+	gudangID, ok := paramID(c, "gudang_id")
+	if !ok {
+		return
+	}
+	if !s.bolehGudang(c, gudangID) {
+		return
+	}
+	ctx := c.Request.Context()
+	p, err := s.replenishment.GetParameterStok(ctx, produkID, gudangID)
+	if err == nil {
+		err = s.replenishment.HapusParameterStok(ctx, produkID, gudangID)
+	}
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Parameter stok tidak ditemukan"})
+			return
+		}
+		log.Printf("Error menghapus parameter stok produk %d gudang %d: %v", produkID, gudangID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menghapus parameter stok"})
+		return
+	}
+	s.catatAudit(c, "stok_parameter", p.ParameterID, models.AuditHapus, p, nil)
+	c.JSON(http.StatusOK, gin.H{"message": "Parameter stok berhasil dihapus"})
+}
+
+// filterSaran membatasi perhitungan saran. ID 0 berarti tidak difilter.
+type filterSaran struct {
+	GudangID    int64 `json:"gudang_id"`
+	SupplierID  int64 `json:"supplier_id"`
+	Hari        int   `json:"hari"`
+	CakupanHari int   `json:"cakupan_hari"`
+	// Metode kosong berarti pemakaian harian dirata-rata; selain itu diramalkan
+	// dengan metode dari paket forecast
+	Metode string `json:"metode"`
+}
+
+// validasi mengisi nilai bawaan lalu memeriksa batasnya
+func (f *filterSaran) validasi(fe fieldErrors) {
+	if f.Hari == 0 {
+		f.Hari = hariPemakaianBawaan
+	}
+	if f.CakupanHari == 0 {
+		f.CakupanHari = cakupanHariBawaan
+	}
+	if f.Hari < 1 || f.Hari > hariSaranMaks {
+		fe.add("hari", fmt.Sprintf("harus antara 1 dan %d", hariSaranMaks))
+	}
+	if f.CakupanHari < 1 || f.CakupanHari > hariSaranMaks {
+		fe.add("cakupan_hari", fmt.Sprintf("harus antara 1 dan %d", hariSaranMaks))
+	}
+	if f.GudangID < 0 {
+		fe.add("gudang_id", "harus berupa bilangan bulat positif")
+	}
+	if f.SupplierID < 0 {
+		fe.add("supplier_id", "harus berupa bilangan bulat positif")
+	}
+	if f.Metode != "" && !forecast.Valid(f.Metode) {
+		fe.add("metode", "harus salah satu dari rata_bergerak, eksponensial, musiman, otomatis")
+	}
+}
+
+// getSaranReplenishmentHandler menghitung saran pemesanan untuk setiap produk dan
+// gudang yang punya parameter stok, dikelompokkan per supplier dan gudang.
+// Query opsional: gudang_id, supplier_id, hari (periode pemakaian, bawaan 28),
+// cakupan_hari (bawaan 14), metode (peramalan pemakaian; kosong berarti rata-rata).
+func (s *server) getSaranReplenishmentHandler(c *gin.Context) {
+	var f filterSaran
+	var ok bool
+	if f.GudangID, ok = queryID(c, "gudang_id"); !ok {
+		return
+	}
+	if f.SupplierID, ok = queryID(c, "supplier_id"); !ok {
+		return
+	}
+	fe := make(fieldErrors)
+	queryAngka(c, fe, "hari", &f.Hari)
+	queryAngka(c, fe, "cakupan_hari", &f.CakupanHari)
+	f.Metode = c.Query("metode")
+	f.validasi(fe)
+	if fe.respond(c) {
+		return
+	}
+	saran, ok := s.hitungSaran(c, f)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"tanggal":      time.Now().Format("2006-01-02"),
+		"hari":         f.Hari,
+		"cakupan_hari": f.CakupanHari,
+		"metode":       f.Metode,
+		"kelompok":     replenishment.Kelompokkan(saran),
+	})
+}
+
+// buatPembelianReplenishmentHandler menghitung ulang saran dengan filter di body lalu
+// membuat satu pembelian Draft untuk setiap kelompok yang siap dipesan. Semua
+// pesanan divalidasi dulu lalu disimpan dalam satu transaksi; jika satu gagal, tidak
+// ada yang disimpan.
+func (s *server) buatPembelianReplenishmentHandler(c *gin.Context) {
+	if s.wajibPermintaan {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Pesanan baru harus dibuat dari permintaan pembelian yang disetujui (POST /api/permintaan/:id/pembelian)"})
+		return
+	}
+	var req struct {
+		filterSaran
+		TanggalPesan string `json:"tanggal_pesan"`
+	}
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Data JSON tidak valid: " + err.Error()})
+			return
+		}
+	}
+	fe := make(fieldErrors)
+	req.validasi(fe)
+	if req.TanggalPesan == "" {
+		req.TanggalPesan = time.Now().Format("2006-01-02")
+	} else if !tanggalValid(req.TanggalPesan) {
+		fe.add("tanggal_pesan", "harus berformat YYYY-MM-DD")
+	}
+	if fe.respond(c) {
+		return
+	}
+	if req.GudangID != 0 && !s.bolehGudang(c, req.GudangID) {
+		return
+	}
+	saran, ok := s.hitungSaran(c, req.filterSaran)
+	if !ok {
+		return
+	}
+
+	var daftarCalon []models.PembelianBaru
+	dilewati := make([]replenishment.Kelompok, 0)
+	for _, k := range replenishment.Kelompokkan(saran) {
+		if !k.SiapDipesan {
+			dilewati = append(dilewati, k)
+			continue
+		}
+		gudangID := k.GudangID
+		pr := pembelianRequest{
+			SupplierID:     *k.SupplierID,
+			TanggalPesan:   req.TanggalPesan,
+			Status:         models.StatusPembelianDraft,
+			GudangTujuanID: &gudangID,
+		}
+		for _, it := range k.Items {
+			pr.Details = append(pr.Details, detailPembelianRequest{
+				ProdukID:        it.ProdukID,
+				Jumlah:          it.Jumlah,
+				Satuan:          it.Satuan,
+				HargaBeliSatuan: it.HargaBeli,
+			})
+		}
+		p, details, ok := s.siapkanPembelian(c, pr)
+		if !ok {
+			return
+		}
+		daftarCalon = append(daftarCalon, models.PembelianBaru{Pembelian: p, Details: details})
+	}
+
+	// Pesanan bernilai besar tetap membutuhkan izin khusus seperti di POST /api/pembelian
+	for _, cl := range daftarCalon {
+		total := cl.Pembelian.TotalBiaya.Float64
+		if s.batasNilaiPembelian <= 0 || total <= s.batasNilaiPembelian {
+			continue
+		}
+		ada, ok := s.punyaIzin(c, models.IzinPembelianNilaiBesar)
+		if !ok {
+			return
+		}
+		if !ada {
+			c.JSON(http.StatusForbidden, gin.H{"error": fmt.Sprintf("Pesanan untuk supplier %d bernilai %.2f, melebihi batas %.2f; dibutuhkan izin %s", cl.Pembelian.SupplierID, total, s.batasNilaiPembelian, models.IzinPembelianNilaiBesar)})
+			return
+		}
+		break
+	}
+
+	if len(daftarCalon) > 0 {
+		if err := s.pembelian.CreatePembelianBanyak(c.Request.Context(), daftarCalon, aktor(c)); err != nil {
+			log.Printf("Error membuat pembelian dari saran pemesanan: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menyimpan data pembelian"})
+			return
+		}
+	}
+	dibuat := make([]gin.H, 0, len(daftarCalon))
+	for _, cl := range daftarCalon {
+		p := cl.Pembelian
+		s.catatAudit(c, "pembelian", p.PembelianID, models.AuditBuat, nil, s.pembelianUntukAudit(c, p.PembelianID))
+		dibuat = append(dibuat, gin.H{"pembelian_id": p.PembelianID, "supplier_id": p.SupplierID, "gudang_tujuan_id": p.GudangTujuanID.Int64, "total_biaya": p.TotalBiaya.Float64})
+	}
+	status := http.StatusCreated
+	if len(dibuat) == 0 {
+		status = http.StatusOK
+	}
+	c.JSON(status, gin.H{"message": fmt.Sprintf("%d pembelian Draft dibuat dari saran pemesanan", len(dibuat)), "pembelian": dibuat, "dilewati": dilewati})
+}
+
+// hitungSaran menghitung saran untuk setiap parameter stok di gudang yang boleh
+// diakses pengguna. Produk yang dihapus dan gudang yang diarsipkan dilewati. Jumlah
+// kueri tetap, berapa pun banyaknya parameter. Jika gagal, respons sudah dikirim dan
+// ok bernilai false.
+func (s *server) hitungSaran(c *gin.Context, f filterSaran) ([]replenishment.Saran, bool) {
+	ctx := c.Request.Context()
+	l, ok := s.gudangSaya(c)
+	if !ok || (f.GudangID != 0 && !s.bolehGudang(c, f.GudangID)) {
+		return nil, false
+	}
+	gagal := func(apa string, err error) ([]replenishment.Saran, bool) {
+		log.Printf("Error mengambil %s untuk saran pemesanan: %v", apa, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menghitung saran pemesanan"})
+		return nil, false
+	}
+
+	// Parameter, stok, produk, dan gudang diambil dalam satu kueri, lalu katalog semua
+	// produknya dalam satu kueri lagi
+	semuaKandidat, err := s.replenishment.KandidatSaran(ctx, f.GudangID)
+	if err != nil {
+		return gagal("parameter stok", err)
+	}
+	kandidat := make([]models.KandidatSaran, 0, len(semuaKandidat))
+	var produkIDs []int64
+	for _, kd := range semuaKandidat {
+		if !l.boleh(kd.GudangID) {
+			continue
+		}
+		kandidat = append(kandidat, kd)
+		if n := len(produkIDs); n == 0 || produkIDs[n-1] != kd.ProdukID {
+			produkIDs = append(produkIDs, kd.ProdukID)
+		}
+	}
+	sekarang := time.Now()
+	hariIni := sekarang.Format("2006-01-02")
+	semuaKatalog, err := s.katalog.KatalogAktifProduk(ctx, produkIDs, hariIni)
+	if err != nil {
+		return gagal("katalog", err)
+	}
+	// Harga dengan satuan yang belum terdaftar untuk produknya tidak bisa dipesan
+	opsiProduk := make(map[int64][]replenishment.Opsi)
+	for _, k := range semuaKatalog {
+		if k.Faktor > 0 {
+			opsiProduk[k.ProdukID] = append(opsiProduk[k.ProdukID], replenishment.Opsi{Katalog: k.SupplierProduk, Faktor: k.Faktor})
+		}
+	}
+
+	type kunci struct{ produkID, gudangID int64 }
+	terbuka, err := s.replenishment.PesananTerbuka(ctx)
+	if err != nil {
+		return gagal("pesanan terbuka", err)
+	}
+	dalamPesanan := make(map[kunci]int, len(terbuka))
+	for _, t := range terbuka {
+		dalamPesanan[kunci{t.ProdukID, t.GudangID}] = t.Jumlah
+	}
+	// Rata-rata memakai hari ini juga; peramalan hanya memakai hari yang sudah lengkap
+	awal, akhir := sekarang.AddDate(0, 0, 1-f.Hari), sekarang
+	if f.Metode != "" {
+		awal, akhir = sekarang.AddDate(0, 0, -f.Hari), sekarang.AddDate(0, 0, -1)
+	}
+	awal, _ = time.Parse("2006-01-02", awal.Format("2006-01-02"))
+	riwayat, err := s.replenishment.PemakaianHarian(ctx, awal.Format("2006-01-02"), akhir.Format("2006-01-02"), 0, f.GudangID, models.TipeMutasiPemakaian)
+	if err != nil {
+		return gagal("pemakaian stok", err)
+	}
+	pemakaian := make(map[kunci]int)
+	perTanggal := make(map[kunci]map[string]float64)
+	for _, p := range riwayat {
+		k := kunci{p.ProdukID, p.GudangID}
+		pemakaian[k] += p.Jumlah
+		if perTanggal[k] == nil {
+			perTanggal[k] = make(map[string]float64)
+		}
+		perTanggal[k][p.Tanggal] += float64(p.Jumlah)
+	}
+
+	saran := make([]replenishment.Saran, 0)
+	for _, kd := range kandidat {
+		prm := kd.ParameterStok
+		pilihan, adaKatalog := replenishment.PilihKatalog(opsiProduk[prm.ProdukID], kd.SupplierID.Int64)
+
+		k := kunci{prm.ProdukID, prm.GudangID}
+		pos := replenishment.Posisi{
+			Stok:            kd.Stok,
+			DalamPesanan:    dalamPesanan[k],
+			PemakaianHarian: float64(pemakaian[k]) / float64(f.Hari),
+			SafetyStock:     prm.SafetyStock,
+			CakupanHari:     f.CakupanHari,
+		}
+		if adaKatalog {
+			pos.LeadTimeHari = pilihan.Katalog.LeadTimeHari
+		}
+		var metodeRamalan string
+		if f.Metode != "" {
+			// Pemakaian harian adalah rata-rata ramalan selama lead time dan cakupan
+			deret := forecast.Deret(awal, f.Hari, perTanggal[k])
+			metodeRamalan = f.Metode
+			if metodeRamalan == forecast.MetodeOtomatis {
+				metodeRamalan, _ = forecast.Pilih(deret, awal, forecast.Opsi{})
+			}
+			var total float64
+			ramalan := forecast.Ramal(metodeRamalan, deret, awal, pos.LeadTimeHari+f.CakupanHari, forecast.Opsi{})
+			for _, v := range ramalan {
+				total += v
+			}
+			pos.PemakaianHarian = total / float64(len(ramalan))
+		}
+		if prm.ReorderPoint.Valid {
+			rop := int(prm.ReorderPoint.Int64)
+			pos.ReorderPoint = &rop
+		}
+		if prm.MaxLevel.Valid {
+			maks := int(prm.MaxLevel.Int64)
+			pos.MaxLevel = &maks
+		}
+		h := replenishment.Hitung(pos)
+		if h.Kebutuhan == 0 {
+			continue
+		}
+
+		sr := replenishment.Saran{
+			ProdukID:        prm.ProdukID,
+			NamaProduk:      prm.NamaProduk,
+			SatuanDasar:     kd.SatuanDasar,
+			GudangID:        prm.GudangID,
+			NamaGudang:      prm.NamaGudang,
+			Stok:            pos.Stok,
+			DalamPesanan:    pos.DalamPesanan,
+			PemakaianHarian: bulat2(pos.PemakaianHarian),
+			LeadTimeHari:    pos.LeadTimeHari,
+			SafetyStock:     pos.SafetyStock,
+			ReorderPoint:    h.ReorderPoint,
+			Target:          h.Target,
+			Kebutuhan:       h.Kebutuhan,
+			Satuan:          kd.SatuanDasar,
+			FaktorKonversi:  1,
+			Jumlah:          h.Kebutuhan,
+			MetodeRamalan:   metodeRamalan,
+		}
+		if adaKatalog {
+			kt := pilihan.Katalog
+			harga := kt.HargaBeli
+			sr.SupplierID, sr.NamaSupplier, sr.SupplierProdukID = &kt.SupplierID, &kt.NamaSupplier, &kt.SupplierProdukID
+			sr.Satuan, sr.FaktorKonversi = kt.Satuan, pilihan.Faktor
+			sr.Jumlah = replenishment.JumlahBeli(h.Kebutuhan, pilihan.Faktor, kt.MOQ)
+			subtotal := bulatkanRupiah(float64(sr.Jumlah) * harga)
+			sr.HargaBeli, sr.Subtotal = &harga, &subtotal
+		} else if kd.NamaSupplier.Valid {
+			// Supplier utama produk tanpa harga katalog: jumlah dalam satuan dasar, harga diisi sendiri
+			sr.SupplierID, sr.NamaSupplier = &kd.SupplierID.Int64, &kd.NamaSupplier.String
+		}
+		if f.SupplierID != 0 && (sr.SupplierID == nil || *sr.SupplierID != f.SupplierID) {
+			continue
+		}
+		saran = append(saran, sr)
+	}
+	return saran, true
+}
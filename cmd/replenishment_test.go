@@ -0,0 +1,78 @@
+package main
+
+import (
+	"net/http"
+	"testing"
+
+	"scm-api/internal/replenishment"
+
+	"github.com/gin-gonic/gin"
+)
+
+// saranProduk1 mengembalikan saran untuk produk 1 di gudang 1 dengan riwayat pemakaian 10 hari
+func (p *penguji) saranProduk1() replenishment.Saran {
+	p.t.Helper()
+	var respons struct {
+		Kelompok []replenishment.Kelompok `json:"kelompok"`
+	}
+	p.decode(p.harus(http.StatusOK, "admin", "GET", "/api/replenishment/saran?hari=10&gudang_id=1", nil), &respons)
+	for _, g := range respons.Kelompok {
+		for _, sr := range g.Items {
+			if sr.ProdukID == 1 && sr.GudangID == 1 {
+				return sr
+			}
+		}
+	}
+	p.t.Fatalf("saran produk 1 di gudang 1 tidak ada: %+v", respons.Kelompok)
+	return replenishment.Saran{}
+}
+
+func TestPemakaianHanyaDariPenjualan(t *testing.T) {
+	p := pengujiBaru(t)
+	p.dataDasar()
+	// Reorder point tinggi agar saran selalu muncul
+	p.harus(http.StatusOK, "admin", "PUT", "/api/stok/parameter/1/1", gin.H{"reorder_point": 100, "safety_stock": 0})
+	p.harus(http.StatusOK, "gudang", "POST", "/api/stok/adjust", gin.H{"produk_id": 1, "gudang_id": 1, "jumlah": 20})
+
+	// Selisih stock opname adalah penyusutan, bukan permintaan
+	p.harus(http.StatusOK, "gudang", "POST", "/api/stok/adjust", gin.H{"produk_id": 1, "gudang_id": 1, "jumlah": 12, "catatan": "rusak"})
+	if got := p.saranProduk1().PemakaianHarian; got != 0 {
+		t.Errorf("pemakaian setelah penyesuaian turun = %v, ingin 0", got)
+	}
+
+	p.harus(http.StatusCreated, "gudang", "POST", "/api/stok/penjualan", gin.H{"produk_id": 1, "gudang_id": 1, "jumlah": 5})
+	if got := p.saranProduk1().PemakaianHarian; got != 0.5 {
+		t.Errorf("pemakaian setelah menjual 5 dalam 10 hari = %v, ingin 0.5", got)
+	}
+	if n := p.stok(1, 1); n != 7 {
+		t.Errorf("stok setelah penjualan = %d, ingin 7", n)
+	}
+}
+
+func TestPenjualanStok(t *testing.T) {
+	p := pengujiBaru(t)
+	p.dataDasar()
+	p.harus(http.StatusOK, "gudang", "POST", "/api/stok/adjust", gin.H{"produk_id": 1, "gudang_id": 1, "jumlah": 3})
+
+	for _, tc := range []struct {
+		nama     string
+		pengguna string
+		body     any
+		kode     int
+	}{
+		{"stok kurang", "gudang", gin.H{"produk_id": 1, "gudang_id": 1, "jumlah": 4}, http.StatusConflict},
+		{"jumlah nol", "gudang", gin.H{"produk_id": 1, "gudang_id": 1, "jumlah": 0}, http.StatusBadRequest},
+		{"produk tidak ada", "gudang", gin.H{"produk_id": 99, "gudang_id": 1, "jumlah": 1}, http.StatusBadRequest},
+		{"gudang lain", "gudang", gin.H{"produk_id": 1, "gudang_id": 2, "jumlah": 1}, http.StatusForbidden},
+		{"tanpa izin", "pembelian", gin.H{"produk_id": 1, "gudang_id": 1, "jumlah": 1}, http.StatusForbidden},
+	} {
+		t.Run(tc.nama, func(t *testing.T) {
+			if kode, body := p.kirim(tc.pengguna, "POST", "/api/stok/penjualan", tc.body); kode != tc.kode {
+				t.Errorf("status %d, ingin %d; body %s", kode, tc.kode, body)
+			}
+		})
+	}
+	if n := p.stok(1, 1); n != 3 {
+		t.Errorf("stok setelah penjualan ditolak = %d, ingin tetap 3", n)
+	}
+}
@@ -0,0 +1,412 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"slices"
+	"strings"
+
+	"scm-api/internal/auth"
+	"scm-api/internal/models"
+	"scm-api/internal/store"
+
+	"github.com/gin-gonic/gin"
+)
+
+// =================================================================
+// HANDLER UNTUK MODUL ROLE DAN IZIN
+// =================================================================
+
+// kunciIzin adalah kunci gin.Context tempat izin pengguna disimpan setelah dibaca sekali
+const kunciIzin = "auth.izin"
+
+// butuhIzin membuat middleware yang menolak permintaan dengan 403 jika pengguna
+// tidak memiliki izin kode dari salah satu role-nya
+func (s *server) butuhIzin(kode string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ada, ok := s.punyaIzin(c, kode)
+		if !ok {
+			c.Abort()
+			return
+		}
+		if !ada {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Anda tidak memiliki izin " + kode})
+			return
+		}
+		c.Next()
+	}
+}
+
+// punyaIzin memeriksa apakah pengguna yang login memiliki izin kode. Izin dibaca dari
+// database sekali per permintaan, jadi perubahan role langsung berlaku.
+// Jika gagal membaca izin, respons 500 sudah dikirim dan ok bernilai false.
+func (s *server) punyaIzin(c *gin.Context, kode string) (ada, ok bool) {
+	izin, found := c.Get(kunciIzin)
+	if !found {
+		claims, login := c.Get(kunciClaims)
+		if !login {
+			return false, true
+		}
+		penggunaID := claims.(auth.Claims).PenggunaID
+		daftar, err := s.role.IzinPengguna(c.Request.Context(), penggunaID)
+		if err != nil {
+			log.Printf("Error mengambil izin pengguna %d: %v", penggunaID, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memeriksa izin pengguna"})
+			return false, false
+		}
+		set := make(map[string]bool, len(daftar))
+		for _, k := range daftar {
+			set[k] = true
+		}
+		c.Set(kunciIzin, set)
+		izin = set
+	}
+	return izin.(map[string]bool)[kode], true
+}
+
+func (s *server) getIzinHandler(c *gin.Context) {
+	daftarIzin, err := s.role.ListIzin(c.Request.Context())
+	if err != nil {
+		log.Printf("Error mengambil izin: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil data izin"})
+		return
+	}
+	c.JSON(http.StatusOK, daftarIzin)
+}
+
+func (s *server) getRoleHandler(c *gin.Context) {
+	daftarRole, err := s.role.ListRole(c.Request.Context())
+	if err != nil {
+		log.Printf("Error mengambil role: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil data role"})
+		return
+	}
+	c.JSON(http.StatusOK, daftarRole)
+}
+
+type roleRequest struct {
+	Nama      string   `json:"nama"`
+	Deskripsi string   `json:"deskripsi"`
+	Izin      []string `json:"izin"`
+}
+
+func (s *server) createRoleHandler(c *gin.Context) {
+	var req roleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Data JSON tidak valid"})
+		return
+	}
+	fe := make(fieldErrors)
+	r := models.Role{Nama: strings.TrimSpace(req.Nama), Deskripsi: req.Deskripsi}
+	if r.Nama == "" {
+		fe.add("nama", "wajib diisi")
+	}
+	var ok bool
+	if r.Izin, ok = s.izinValid(c, req.Izin, fe); !ok || fe.respond(c) {
+		return
+	}
+
+	if err := s.role.CreateRole(c.Request.Context(), &r); err != nil {
+		if errors.Is(err, store.ErrDuplikat) {
+			c.JSON(http.StatusConflict, gin.H{"error": fmt.Sprintf("Role %s sudah ada", r.Nama)})
+			return
+		}
+		log.Printf("Error menyimpan role: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menyimpan role"})
+		return
+	}
+	s.catatAudit(c, "role", r.RoleID, models.AuditBuat, nil, r)
+	c.JSON(http.StatusCreated, r)
+}
+
+func (s *server) ubahIzinRoleHandler(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+	var req struct {
+		Izin []string `json:"izin"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Data JSON tidak valid"})
+		return
+	}
+	fe := make(fieldErrors)
+	izin, ok := s.izinValid(c, req.Izin, fe)
+	if !ok || fe.respond(c) {
+		return
+	}
+
+	// Jangan biarkan pengguna mencabut izin kelola pengguna dari dirinya sendiri
+	roleSaya, ok := s.roleSaya(c)
+	if !ok {
+		return
+	}
+	for i := range roleSaya {
+		if roleSaya[i].RoleID == id {
+			roleSaya[i].Izin = izin
+		}
+	}
+	if !bisaKelolaPengguna(roleSaya) {
+		c.JSON(http.StatusConflict, gin.H{"error": "Perubahan ini akan mencabut izin " + models.IzinPenggunaKelola + " dari akun Anda sendiri"})
+		return
+	}
+
+	var sebelum any
+	if semuaRole, err := s.role.ListRole(c.Request.Context()); err != nil {
+		log.Printf("Error mengambil role untuk audit: %v", err)
+	} else if i := slices.IndexFunc(semuaRole, func(r models.Role) bool { return r.RoleID == id }); i >= 0 {
+		sebelum = gin.H{"izin": semuaRole[i].Izin}
+	}
+	if err := s.role.UbahIzinRole(c.Request.Context(), id, izin); err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Role tidak ditemukan"})
+			return
+		}
+		log.Printf("Error mengubah izin role %d: %v", id, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengubah izin role"})
+		return
+	}
+	s.catatAudit(c, "role", id, models.AuditUbah, sebelum, gin.H{"izin": slices.Sorted(slices.Values(izin))})
+	c.JSON(http.StatusOK, gin.H{"message": "Izin role berhasil diubah"})
+}
+
+func (s *server) getPenggunaHandler(c *gin.Context) {
+	q, ok := bacaKueri(c, store.DaftarPengguna)
+	if !ok {
+		return
+	}
+	daftarPengguna, total, err := s.pengguna.ListPengguna(c.Request.Context(), q)
+	if err != nil {
+		log.Printf("Error mengambil pengguna: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil data pengguna"})
+		return
+	}
+	kirimHalaman(c, daftarPengguna, total, q)
+}
+
+func (s *server) getRolePenggunaHandler(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+	daftarRole, err := s.role.RolePengguna(c.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Pengguna tidak ditemukan"})
+			return
+		}
+		log.Printf("Error mengambil role pengguna %d: %v", id, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil role pengguna"})
+		return
+	}
+	c.JSON(http.StatusOK, daftarRole)
+}
+
+// setRolePenggunaHandler mengganti seluruh role pengguna dengan daftar nama role di body
+func (s *server) setRolePenggunaHandler(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+	var req struct {
+		Role []string `json:"role"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Data JSON tidak valid"})
+		return
+	}
+
+	semuaRole, err := s.role.ListRole(c.Request.Context())
+	if err != nil {
+		log.Printf("Error mengambil role: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil data role"})
+		return
+	}
+	perNama := make(map[string]models.Role, len(semuaRole))
+	for _, r := range semuaRole {
+		perNama[r.Nama] = r
+	}
+
+	fe := make(fieldErrors)
+	var dipilih []models.Role
+	roleIDs := make([]int64, 0, len(req.Role))
+	sudah := make(map[string]bool)
+	for i, nama := range req.Role {
+		r, ada := perNama[nama]
+		if !ada {
+			fe.add(fmt.Sprintf("role[%d]", i), fmt.Sprintf("role %s tidak ditemukan", nama))
+			continue
+		}
+		if sudah[nama] {
+			continue
+		}
+		sudah[nama] = true
+		dipilih = append(dipilih, r)
+		roleIDs = append(roleIDs, r.RoleID)
+	}
+	if fe.respond(c) {
+		return
+	}
+
+	claims := c.MustGet(kunciClaims).(auth.Claims)
+	if id == claims.PenggunaID && !bisaKelolaPengguna(dipilih) {
+		c.JSON(http.StatusConflict, gin.H{"error": "Perubahan ini akan mencabut izin " + models.IzinPenggunaKelola + " dari akun Anda sendiri"})
+		return
+	}
+
+	var sebelum any
+	if lama, err := s.role.RolePengguna(c.Request.Context(), id); err == nil {
+		sebelum = gin.H{"role": namaRole(lama)}
+	}
+	if err := s.role.SetRolePengguna(c.Request.Context(), id, roleIDs); err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Pengguna tidak ditemukan"})
+			return
+		}
+		log.Printf("Error mengubah role pengguna %d: %v", id, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengubah role pengguna"})
+		return
+	}
+	s.catatAudit(c, "pengguna", id, models.AuditUbah, sebelum, gin.H{"role": namaRole(dipilih)})
+	c.JSON(http.StatusOK, gin.H{"message": "Role pengguna berhasil diubah"})
+}
+
+// izinValid memastikan setiap kode izin dikenal dan membuang duplikat.
+// Jika gagal membaca daftar izin, respons 500 sudah dikirim dan ok bernilai false.
+func (s *server) izinValid(c *gin.Context, izin []string, fe fieldErrors) ([]string, bool) {
+	daftarIzin, err := s.role.ListIzin(c.Request.Context())
+	if err != nil {
+		log.Printf("Error mengambil izin: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil data izin"})
+		return nil, false
+	}
+	dikenal := make(map[string]bool, len(daftarIzin))
+	for _, iz := range daftarIzin {
+		dikenal[iz.Kode] = true
+	}
+	hasil := make([]string, 0, len(izin))
+	sudah := make(map[string]bool)
+	for i, kode := range izin {
+		if !dikenal[kode] {
+			fe.add(fmt.Sprintf("izin[%d]", i), fmt.Sprintf("izin %s tidak dikenal", kode))
+			continue
+		}
+		if !sudah[kode] {
+			sudah[kode] = true
+			hasil = append(hasil, kode)
+		}
+	}
+	return hasil, true
+}
+
+// roleSaya mengembalikan role milik pengguna yang login.
+// Jika gagal, respons 500 sudah dikirim dan ok bernilai false.
+func (s *server) roleSaya(c *gin.Context) ([]models.Role, bool) {
+	claims := c.MustGet(kunciClaims).(auth.Claims)
+	daftarRole, err := s.role.RolePengguna(c.Request.Context(), claims.PenggunaID)
+	if err != nil {
+		log.Printf("Error mengambil role pengguna %d: %v", claims.PenggunaID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil role pengguna"})
+		return nil, false
+	}
+	return daftarRole, true
+}
+
+// bisaKelolaPengguna memeriksa apakah salah satu role memberi izin pengguna.kelola
+func bisaKelolaPengguna(daftarRole []models.Role) bool {
+	for _, r := range daftarRole {
+		for _, kode := range r.Izin {
+			if kode == models.IzinPenggunaKelola {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (s *server) getGudangPenggunaHandler(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+	ids, err := s.pengguna.GudangPengguna(c.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Pengguna tidak ditemukan"})
+			return
+		}
+		log.Printf("Error mengambil gudang pengguna %d: %v", id, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil gudang pengguna"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"gudang_id": ids})
+}
+
+// setGudangPenggunaHandler mengganti seluruh gudang tempat pengguna bekerja.
+// Body JSON: {"gudang_id": [1, 2]}
+func (s *server) setGudangPenggunaHandler(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+	var req struct {
+		GudangID []int64 `json:"gudang_id"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Data JSON tidak valid"})
+		return
+	}
+
+	ctx := c.Request.Context()
+	fe := make(fieldErrors)
+	ids := make([]int64, 0, len(req.GudangID))
+	for i, gudangID := range req.GudangID {
+		if g, err := s.gudang.GetGudang(ctx, gudangID); errors.Is(err, store.ErrNotFound) {
+			fe.add(fmt.Sprintf("gudang_id[%d]", i), fmt.Sprintf("gudang dengan ID %d tidak ditemukan", gudangID))
+			continue
+		} else if err != nil {
+			log.Printf("Error memeriksa gudang %d: %v", gudangID, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memeriksa data gudang"})
+			return
+		} else if g.DeletedAt.Valid {
+			fe.add(fmt.Sprintf("gudang_id[%d]", i), fmt.Sprintf("gudang dengan ID %d sudah diarsipkan", gudangID))
+			continue
+		}
+		if !slices.Contains(ids, gudangID) {
+			ids = append(ids, gudangID)
+		}
+	}
+	if fe.respond(c) {
+		return
+	}
+
+	var sebelum any
+	if lama, err := s.pengguna.GudangPengguna(ctx, id); err == nil {
+		sebelum = gin.H{"gudang_id": lama}
+	}
+	if err := s.pengguna.SetGudangPengguna(ctx, id, ids); err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Pengguna tidak ditemukan"})
+			return
+		}
+		log.Printf("Error mengubah gudang pengguna %d: %v", id, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengubah gudang pengguna"})
+		return
+	}
+	slices.Sort(ids)
+	s.catatAudit(c, "pengguna", id, models.AuditUbah, sebelum, gin.H{"gudang_id": ids})
+	c.JSON(http.StatusOK, gin.H{"message": "Gudang pengguna berhasil diubah"})
+}
+
+// namaRole mengembalikan nama setiap role secara berurutan, dipakai sebagai isi jejak audit
+func namaRole(daftar []models.Role) []string {
+	nama := make([]string, 0, len(daftar))
+	for _, r := range daftar {
+		nama = append(nama, r.Nama)
+	}
+	slices.Sort(nama)
+	return nama
+}
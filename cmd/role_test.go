@@ -0,0 +1,93 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// idPengguna mengembalikan pengguna_id untuk username
+func (p *penguji) idPengguna(username string) int64 {
+	p.t.Helper()
+	pg, err := p.store.GetPenggunaByUsername(context.Background(), username)
+	if err != nil {
+		p.t.Fatalf("GetPenggunaByUsername %s: %v", username, err)
+	}
+	return pg.PenggunaID
+}
+
+func TestIzinPerRole(t *testing.T) {
+	p := pengujiBaru(t)
+	p.dataDasar()
+	for _, tc := range []struct {
+		pengguna, method, path string
+		kode                   int
+	}{
+		{"gudang", "GET", "/api/pengguna", http.StatusForbidden},
+		{"gudang", "GET", "/api/audit", http.StatusForbidden},
+		{"gudang", "POST", "/api/role", http.StatusForbidden},
+		{"gudang", "POST", "/api/produk", http.StatusForbidden},
+		{"gudang", "DELETE", "/api/supplier/1", http.StatusForbidden},
+		{"gudang", "POST", "/api/pembelian", http.StatusForbidden},
+		{"gudang", "GET", "/api/produk", http.StatusOK},
+		{"pembelian", "PUT", "/api/pembelian/1/terima", http.StatusForbidden},
+		{"pembelian", "POST", "/api/stok/adjust", http.StatusForbidden},
+		{"manajer", "GET", "/api/pengguna", http.StatusForbidden},
+		{"manajer", "PUT", "/api/pengguna/1/role", http.StatusForbidden},
+		{"manajer", "GET", "/api/audit", http.StatusOK},
+		{"admin", "GET", "/api/pengguna", http.StatusOK},
+	} {
+		if kode, body := p.kirim(tc.pengguna, tc.method, tc.path, gin.H{}); kode != tc.kode {
+			t.Errorf("%s %s %s: status %d, ingin %d; body %s", tc.pengguna, tc.method, tc.path, kode, tc.kode, body)
+		}
+	}
+}
+
+func TestBatasNilaiPembelian(t *testing.T) {
+	p := pengujiBaru(t)
+	p.dataDasar()
+	pesanan := func(jumlah int) gin.H {
+		return gin.H{"supplier_id": 1, "tanggal_pesan": "2024-05-01", "gudang_tujuan_id": 1,
+			"details": []gin.H{{"produk_id": 1, "jumlah": jumlah, "harga_beli_satuan": 1000}}}
+	}
+	// Batas bawaan 10.000.000 masih boleh dibuat tanpa izin nilai besar
+	p.harus(http.StatusCreated, "pembelian", "POST", "/api/pembelian", pesanan(10_000))
+	p.harus(http.StatusForbidden, "pembelian", "POST", "/api/pembelian", pesanan(10_001))
+	p.harus(http.StatusCreated, "manajer", "POST", "/api/pembelian", pesanan(10_001))
+
+	p.srv.batasNilaiPembelian = 0
+	p.harus(http.StatusCreated, "pembelian", "POST", "/api/pembelian", pesanan(10_001))
+}
+
+func TestUbahRolePengguna(t *testing.T) {
+	p := pengujiBaru(t)
+	p.dataDasar()
+	gudang := fmt.Sprintf("/api/pengguna/%d/role", p.idPengguna("gudang"))
+
+	p.harus(http.StatusOK, "admin", "PUT", gudang, gin.H{"role": []string{"pembelian"}})
+	// Role baru langsung berlaku untuk token yang sudah terbit
+	p.harus(http.StatusCreated, "gudang", "POST", "/api/pembelian", gin.H{"supplier_id": 1, "tanggal_pesan": "2024-05-01", "gudang_tujuan_id": 1,
+		"details": []gin.H{{"produk_id": 1, "jumlah": 1, "harga_beli_satuan": 1000}}})
+	p.harus(http.StatusForbidden, "gudang", "POST", "/api/stok/adjust", gin.H{"produk_id": 1, "gudang_id": 1, "jumlah": 1})
+
+	p.harus(http.StatusBadRequest, "admin", "PUT", gudang, gin.H{"role": []string{"tidak-ada"}})
+	p.harus(http.StatusConflict, "admin", "PUT", fmt.Sprintf("/api/pengguna/%d/role", p.idPengguna("admin")), gin.H{"role": []string{"manajer"}})
+
+	var r struct {
+		RoleID int64 `json:"role_id"`
+	}
+	p.decode(p.harus(http.StatusCreated, "admin", "POST", "/api/role", gin.H{"nama": "auditor", "izin": []string{"audit.lihat"}}), &r)
+	p.harus(http.StatusConflict, "admin", "POST", "/api/role", gin.H{"nama": "auditor", "izin": []string{"audit.lihat"}})
+	p.harus(http.StatusBadRequest, "admin", "POST", "/api/role", gin.H{"nama": "aneh", "izin": []string{"semua.boleh"}})
+	p.harus(http.StatusOK, "admin", "PUT", gudang, gin.H{"role": []string{"auditor"}})
+	p.harus(http.StatusOK, "gudang", "GET", "/api/audit", nil)
+	p.harus(http.StatusForbidden, "gudang", "POST", "/api/pembelian", gin.H{})
+
+	// Admin tidak boleh mencabut pengguna.kelola dari role yang dipakainya sendiri
+	p.harus(http.StatusConflict, "admin", "PUT", "/api/role/1/izin", gin.H{"izin": []string{"produk.lihat"}})
+	p.harus(http.StatusOK, "admin", "PUT", fmt.Sprintf("/api/role/%d/izin", r.RoleID), gin.H{"izin": []string{"produk.lihat"}})
+	p.harus(http.StatusForbidden, "gudang", "GET", "/api/audit", nil)
+}
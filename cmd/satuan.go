@@ -0,0 +1,207 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"scm-api/internal/models"
+	"scm-api/internal/store"
+
+	"github.com/gin-gonic/gin"
+)
+
+// =================================================================
+// HANDLER UNTUK SATUAN BELI PRODUK
+// =================================================================
+
+// Batas satuan beli. faktorSatuanMaks menjaga jumlah × faktor tetap muat di kolom INT stok.
+const (
+	panjangSatuanMaks = 32
+	faktorSatuanMaks  = 1_000_000
+)
+
+func (s *server) getSatuanProdukHandler(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+	if _, err := s.produk.GetProduk(c.Request.Context(), id); err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Produk tidak ditemukan"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Terjadi kesalahan internal"})
+		return
+	}
+	daftar, err := s.satuan.ListSatuanProduk(c.Request.Context(), id)
+	if err != nil {
+		log.Printf("Error mengambil satuan produk %d: %v", id, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil satuan"})
+		return
+	}
+	c.JSON(http.StatusOK, daftar)
+}
+
+// createSatuanProdukHandler mendaftarkan satuan beli baru, misalnya
+// {"satuan": "krat", "faktor": 30} untuk telur yang disimpan per butir
+func (s *server) createSatuanProdukHandler(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+	var req struct {
+		Satuan string `json:"satuan"`
+		Faktor int    `json:"faktor"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Data JSON tidak valid: " + err.Error()})
+		return
+	}
+	p, ok := s.produkUntukDiubah(c, id)
+	if !ok {
+		return
+	}
+
+	ps := models.ProdukSatuan{ProdukID: id, Satuan: strings.TrimSpace(req.Satuan), Faktor: req.Faktor}
+	fe := make(fieldErrors)
+	switch {
+	case ps.Satuan == "":
+		fe.add("satuan", "wajib diisi")
+	case utf8.RuneCountInString(ps.Satuan) > panjangSatuanMaks:
+		fe.add("satuan", fmt.Sprintf("maksimal %d karakter", panjangSatuanMaks))
+	case strings.Contains(ps.Satuan, "/"):
+		fe.add("satuan", "tidak boleh mengandung /")
+	case strings.EqualFold(ps.Satuan, p.Satuan):
+		fe.add("satuan", fmt.Sprintf("%q adalah satuan dasar produk ini", p.Satuan))
+	}
+	validasiFaktor(fe, req.Faktor)
+	if fe.respond(c) {
+		return
+	}
+
+	if err := s.satuan.CreateSatuan(c.Request.Context(), &ps); err != nil {
+		if errors.Is(err, store.ErrDuplikat) {
+			c.JSON(http.StatusConflict, gin.H{"error": fmt.Sprintf("Satuan %q sudah terdaftar untuk produk ini", ps.Satuan)})
+			return
+		}
+		log.Printf("Error menyimpan satuan produk %d: %v", id, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menyimpan satuan"})
+		return
+	}
+	s.catatAudit(c, "satuan", ps.SatuanID, models.AuditBuat, nil, ps)
+	c.JSON(http.StatusCreated, ps)
+}
+
+// updateSatuanProdukHandler mengganti faktor satuan beli. Pesanan yang sudah dibuat
+// tetap memakai faktor lama yang tersimpan di baris pembeliannya.
+func (s *server) updateSatuanProdukHandler(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+	var req struct {
+		Faktor int `json:"faktor"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Data JSON tidak valid: " + err.Error()})
+		return
+	}
+	fe := make(fieldErrors)
+	validasiFaktor(fe, req.Faktor)
+	if fe.respond(c) {
+		return
+	}
+	if _, ok := s.produkUntukDiubah(c, id); !ok {
+		return
+	}
+	sebelum, ok := s.satuanProduk(c, id)
+	if !ok {
+		return
+	}
+	ps := sebelum
+	ps.Faktor = req.Faktor
+	if err := s.satuan.UpdateSatuan(c.Request.Context(), ps); err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Satuan tidak terdaftar di produk ini"})
+			return
+		}
+		log.Printf("Error mengubah satuan produk %d: %v", id, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengubah satuan"})
+		return
+	}
+	s.catatAudit(c, "satuan", ps.SatuanID, models.AuditUbah, sebelum, ps)
+	c.JSON(http.StatusOK, ps)
+}
+
+func (s *server) deleteSatuanProdukHandler(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+	ps, ok := s.satuanProduk(c, id)
+	if !ok {
+		return
+	}
+	// Satuan yang masih dipakai katalog supplier tidak boleh dihapus, karena harganya tidak bisa dipakai lagi
+	q := store.Kueri{Filter: map[string]string{"produk_id": strconv.FormatInt(id, 10), "satuan": ps.Satuan}, Batas: 1}
+	if _, dipakai, err := s.katalog.ListKatalog(c.Request.Context(), q); err != nil {
+		log.Printf("Error memeriksa katalog produk %d: %v", id, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menghapus satuan"})
+		return
+	} else if dipakai > 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": fmt.Sprintf("Satuan %q masih dipakai %d baris katalog supplier", ps.Satuan, dipakai)})
+		return
+	}
+	if err := s.satuan.DeleteSatuan(c.Request.Context(), id, ps.Satuan); err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Satuan tidak terdaftar di produk ini"})
+			return
+		}
+		log.Printf("Error menghapus satuan produk %d: %v", id, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menghapus satuan"})
+		return
+	}
+	s.catatAudit(c, "satuan", ps.SatuanID, models.AuditHapus, ps, nil)
+	c.JSON(http.StatusOK, gin.H{"message": "Satuan berhasil dihapus"})
+}
+
+func validasiFaktor(fe fieldErrors, faktor int) {
+	if faktor < 1 || faktor > faktorSatuanMaks {
+		fe.add("faktor", fmt.Sprintf("harus antara 1 dan %d", faktorSatuanMaks))
+	}
+}
+
+// satuanProduk mengambil satuan dari parameter :satuan milik produk id
+func (s *server) satuanProduk(c *gin.Context, id int64) (models.ProdukSatuan, bool) {
+	ps, err := s.satuan.CariSatuan(c.Request.Context(), id, c.Param("satuan"))
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Satuan tidak terdaftar di produk ini"})
+			return ps, false
+		}
+		log.Printf("Error mencari satuan produk %d: %v", id, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Terjadi kesalahan internal"})
+		return ps, false
+	}
+	return ps, true
+}
+
+// satuanPembelian menentukan satuan dan faktor konversi satu baris pembelian.
+// Satuan kosong atau sama dengan satuan dasar produk berarti faktor 1. Jika satuan
+// belum terdaftar, pesan kesalahannya dikembalikan untuk field satuan baris tersebut.
+func (s *server) satuanPembelian(c *gin.Context, p models.Produk, satuan string) (models.ProdukSatuan, string, error) {
+	satuan = strings.TrimSpace(satuan)
+	if satuan == "" || strings.EqualFold(satuan, p.Satuan) {
+		return models.ProdukSatuan{ProdukID: p.ProdukID, Satuan: p.Satuan, Faktor: 1}, "", nil
+	}
+	ps, err := s.satuan.CariSatuan(c.Request.Context(), p.ProdukID, satuan)
+	if errors.Is(err, store.ErrNotFound) {
+		return ps, fmt.Sprintf("satuan %q belum terdaftar untuk produk %d (satuan dasar %q)", satuan, p.ProdukID, p.Satuan), nil
+	}
+	return ps, "", err
+}
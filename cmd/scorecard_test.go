@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"scm-api/internal/models"
+	"scm-api/internal/scorecard"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestScorecardDanRatingSupplier(t *testing.T) {
+	p := pengujiBaru(t)
+	p.dataDasar()
+	p.harus(http.StatusCreated, "admin", "POST", "/api/supplier", gin.H{"nama_supplier": "Cahaya Abadi"})
+	hariIni := time.Now().Format("2006-01-02")
+	kemarin := time.Now().AddDate(0, 0, -1).Format("2006-01-02")
+
+	pesan := func(status string) int64 {
+		var respons struct {
+			PembelianID int64 `json:"pembelian_id"`
+		}
+		p.decode(p.harus(http.StatusCreated, "admin", "POST", "/api/pembelian", gin.H{
+			"supplier_id": 1, "tanggal_pesan": hariIni, "estimasi_tiba": kemarin, "status": status, "gudang_tujuan_id": 1,
+			"details": []gin.H{{"produk_id": 1, "jumlah": 4, "harga_beli_satuan": 1000}},
+		}), &respons)
+		return respons.PembelianID
+	}
+	id := pesan(models.StatusPembelianDipesan)
+	p.harus(http.StatusCreated, "gudang", "POST", fmt.Sprintf("/api/pembelian/%d/penerimaan", id), gin.H{
+		"tanggal_terima": kemarin + " 10:00:00", "items": []gin.H{{"produk_id": 1, "jumlah_diterima": 3, "jumlah_ditolak": 1}},
+	})
+	// Draft dan pesanan batal yang estimasinya sudah lewat tidak ikut dinilai
+	pesan(models.StatusPembelianDraft)
+	p.harus(http.StatusOK, "admin", "PUT", fmt.Sprintf("/api/pembelian/%d/batal", pesan(models.StatusPembelianDipesan)), nil)
+
+	var sc scorecard.Scorecard
+	p.decode(p.harus(http.StatusOK, "admin", "GET", "/api/supplier/1/scorecard", nil), &sc)
+	// (0,4×1 + 0,3×0,75 + 0,2×(1-0,25)) / 0,9 tanpa varians harga karena hanya satu supplier
+	if sc.JumlahPesanan != 1 || sc.PesananJatuhTempo != 1 || sc.PesananTepatWaktu != 1 ||
+		sc.FillRate == nil || *sc.FillRate != 0.75 || sc.Rating == nil || *sc.Rating != 4.31 {
+		t.Errorf("scorecard = %+v, ingin 1 pesanan tepat waktu dengan fill rate 0.75 dan rating 4.31", sc)
+	}
+	p.decode(p.harus(http.StatusOK, "admin", "GET", "/api/supplier/2/scorecard", nil), &sc)
+	if sc.SupplierID != 2 || sc.JumlahPesanan != 0 || sc.Skor != nil {
+		t.Errorf("scorecard tanpa pesanan = %+v, ingin kosong", sc)
+	}
+	p.harus(http.StatusNotFound, "admin", "GET", "/api/supplier/99/scorecard", nil)
+	p.harus(http.StatusBadRequest, "admin", "GET", "/api/supplier/1/scorecard?dari=2024-06-02&sampai=2024-06-01", nil)
+	p.harus(http.StatusBadRequest, "admin", "GET", "/api/supplier/1/scorecard?sampai=kemarin", nil)
+
+	// Rating lama supplier tanpa data penilaian dikosongkan
+	ctx := context.Background()
+	if err := p.store.SetRatingSupplier(ctx, 2, sql.NullFloat64{Float64: 3, Valid: true}); err != nil {
+		t.Fatalf("SetRatingSupplier: %v", err)
+	}
+	if err := p.srv.perbaruiRating(ctx); err != nil {
+		t.Fatalf("perbaruiRating: %v", err)
+	}
+	for id, ingin := range map[int64]sql.NullFloat64{1: {Float64: 4.31, Valid: true}, 2: {}} {
+		sp, err := p.store.GetSupplier(ctx, id)
+		if err != nil {
+			t.Fatalf("GetSupplier %d: %v", id, err)
+		}
+		if sp.Rating != ingin {
+			t.Errorf("rating supplier %d = %+v, ingin %+v", id, sp.Rating, ingin)
+		}
+	}
+}
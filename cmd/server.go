@@ -0,0 +1,466 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"maps"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"scm-api/internal/auth"
+	"scm-api/internal/config"
+	"scm-api/internal/models"
+	"scm-api/internal/pencarian"
+	"scm-api/internal/penyimpanan"
+	"scm-api/internal/store"
+
+	"github.com/gin-contrib/cors"
+	"github.com/gin-gonic/gin"
+)
+
+// server menyimpan dependensi yang dibutuhkan handler.
+// Semua akses data lewat antarmuka store sehingga handler bisa diuji
+// dengan store in-memory dan httptest.
+type server struct {
+	produk        store.ProdukStore
+	supplier      store.SupplierStore
+	pembelian     store.PembelianStore
+	gudang        store.GudangStore
+	stok          store.StokStore
+	transfer      store.TransferStore
+	pengguna      store.PenggunaStore
+	role          store.RoleStore
+	audit         store.AuditStore
+	barcode       store.BarcodeStore
+	satuan        store.SatuanStore
+	katalog       store.KatalogStore
+	scorecard     store.ScorecardStore
+	permintaan    store.PermintaanStore
+	replenishment store.ReplenishmentStore
+
+	// indeksProduk dipakai GET /api/produk/search dan dikosongkan setiap kali produk berubah
+	indeksProduk *pencarian.Indeks
+
+	// media menyimpan gambar produk; URL-nya diawali urlDasarMedia
+	media            penyimpanan.Penyimpanan
+	urlDasarMedia    string
+	ukuranGambarMaks int
+	sisiThumbnail    int
+
+	// awalanBarcodeInternal adalah awalan 20-29 untuk EAN-13 yang dibuat sendiri
+	awalanBarcodeInternal string
+
+	tokens *auth.Tokens
+	// batasNilaiPembelian adalah total_biaya tertinggi tanpa izin pembelian.nilai_besar (0 berarti tanpa batas)
+	batasNilaiPembelian float64
+	// wajibPermintaan berarti pesanan baru hanya boleh berasal dari permintaan yang disetujui
+	wajibPermintaan bool
+	// periodeScorecard adalah panjang periode bawaan scorecard supplier dalam hari
+	periodeScorecard int
+}
+
+// newServer membuat server dari implementasi store yang lengkap
+func newServer(st store.Store, cfg config.Config) *server {
+	muatProduk := func(ctx context.Context) ([]models.Produk, error) {
+		daftarProduk, _, err := st.ListProduk(ctx, store.Kueri{})
+		return daftarProduk, err
+	}
+	return &server{
+		produk:        st,
+		supplier:      st,
+		pembelian:     st,
+		gudang:        st,
+		stok:          st,
+		transfer:      st,
+		pengguna:      st,
+		role:          st,
+		audit:         st,
+		barcode:       st,
+		satuan:        st,
+		katalog:       st,
+		scorecard:     st,
+		permintaan:    st,
+		replenishment: st,
+
+		indeksProduk: pencarian.Baru(muatProduk, umurIndeksProduk),
+
+		media:            penyimpanan.Dari(cfg.Media),
+		urlDasarMedia:    cfg.Media.BaseURL,
+		ukuranGambarMaks: cfg.Media.MaxBytes,
+		sisiThumbnail:    cfg.Media.Thumbnail,
+
+		awalanBarcodeInternal: cfg.Barcode.AwalanInternal,
+
+		tokens:              auth.New(cfg.Auth.JWTSecret, time.Duration(cfg.Auth.AccessTTL), time.Duration(cfg.Auth.RefreshTTL)),
+		batasNilaiPembelian: cfg.Pembelian.BatasNilai,
+		wajibPermintaan:     cfg.Pembelian.WajibPermintaan,
+		periodeScorecard:    cfg.Scorecard.PeriodeHari,
+	}
+}
+
+// newRouter menyiapkan gin.Engine beserta middleware CORS dan seluruh rute API.
+// Hanya /health, /media, dan rute login/refresh/logout yang bisa diakses tanpa access token;
+// rute lainnya juga membutuhkan satu kode izin dari role pengguna.
+func newRouter(cfg config.Config, s *server) *gin.Engine {
+	router := gin.Default()
+	router.Use(cors.New(cors.Config{
+		AllowOrigins:     cfg.CORS.AllowOrigins,
+		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization"},
+		AllowCredentials: true,
+	}))
+
+	router.GET("/health", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"status": "ok"})
+	})
+	router.GET("/media/*kunci", s.mediaHandler)
+
+	// --- Rute-rute Autentikasi (tanpa token) ---
+	publik := router.Group("/api/auth")
+	{
+		publik.POST("/login", s.loginHandler)
+		publik.POST("/refresh", s.refreshTokenHandler)
+		publik.POST("/logout", s.logoutHandler)
+	}
+
+	api := router.Group("/api", s.wajibLogin)
+	{
+		api.GET("/auth/saya", s.getPenggunaSayaHandler)
+
+		// --- Rute-rute Produk ---
+		api.GET("/produk", s.butuhIzin(models.IzinProdukLihat), s.getProdukHandler)
+		api.GET("/produk/search", s.butuhIzin(models.IzinProdukLihat), s.searchProdukHandler)
+		api.GET("/produk/barcode/:kode", s.butuhIzin(models.IzinProdukLihat), s.lookupBarcodeHandler)
+		api.GET("/produk/:id", s.butuhIzin(models.IzinProdukLihat), s.getProdukByIdHandler)
+		api.POST("/produk", s.butuhIzin(models.IzinProdukKelola), s.createProdukHandler)
+		api.PUT("/produk/:id", s.butuhIzin(models.IzinProdukKelola), s.updateProdukHandler)
+		api.DELETE("/produk/:id", s.butuhIzin(models.IzinProdukKelola), s.deleteProdukHandler)
+		api.PUT("/produk/:id/pulihkan", s.butuhIzin(models.IzinProdukKelola), s.pulihkanProdukHandler)
+		api.POST("/produk/:id/gambar", s.butuhIzin(models.IzinProdukKelola), s.uploadGambarProdukHandler)
+		api.DELETE("/produk/:id/gambar", s.butuhIzin(models.IzinProdukKelola), s.hapusGambarProdukHandler)
+		api.GET("/produk/:id/barcode", s.butuhIzin(models.IzinProdukLihat), s.getBarcodeProdukHandler)
+		api.POST("/produk/:id/barcode", s.butuhIzin(models.IzinProdukKelola), s.createBarcodeProdukHandler)
+		api.DELETE("/produk/:id/barcode/:kode", s.butuhIzin(models.IzinProdukKelola), s.deleteBarcodeProdukHandler)
+		api.GET("/produk/:id/barcode/:kode/label", s.butuhIzin(models.IzinProdukLihat), s.labelBarcodeHandler)
+		api.GET("/produk/:id/satuan", s.butuhIzin(models.IzinProdukLihat), s.getSatuanProdukHandler)
+		api.GET("/produk/:id/katalog", s.butuhIzin(models.IzinSupplierLihat), s.getKatalogProdukHandler)
+		api.POST("/produk/:id/satuan", s.butuhIzin(models.IzinProdukKelola), s.createSatuanProdukHandler)
+		api.PUT("/produk/:id/satuan/:satuan", s.butuhIzin(models.IzinProdukKelola), s.updateSatuanProdukHandler)
+		api.DELETE("/produk/:id/satuan/:satuan", s.butuhIzin(models.IzinProdukKelola), s.deleteSatuanProdukHandler)
+
+		// --- Rute-rute Supplier ---
+		api.GET("/supplier", s.butuhIzin(models.IzinSupplierLihat), s.getSuppliersHandler)
+		api.GET("/supplier/:id", s.butuhIzin(models.IzinSupplierLihat), s.getSupplierByIdHandler)
+		api.POST("/supplier", s.butuhIzin(models.IzinSupplierKelola), s.createSupplierHandler)
+		api.PUT("/supplier/:id", s.butuhIzin(models.IzinSupplierKelola), s.updateSupplierHandler)
+		api.DELETE("/supplier/:id", s.butuhIzin(models.IzinSupplierKelola), s.deleteSupplierHandler)
+		api.PUT("/supplier/:id/pulihkan", s.butuhIzin(models.IzinSupplierKelola), s.pulihkanSupplierHandler)
+		api.GET("/supplier/:id/scorecard", s.butuhIzin(models.IzinSupplierLihat), s.getScorecardSupplierHandler)
+		api.GET("/supplier/:id/katalog", s.butuhIzin(models.IzinSupplierLihat), s.getKatalogSupplierHandler)
+		api.GET("/supplier/:id/katalog/:katalog_id", s.butuhIzin(models.IzinSupplierLihat), s.getKatalogByIdHandler)
+		api.POST("/supplier/:id/katalog", s.butuhIzin(models.IzinSupplierKelola), s.createKatalogHandler)
+		api.PUT("/supplier/:id/katalog/:katalog_id", s.butuhIzin(models.IzinSupplierKelola), s.updateKatalogHandler)
+		api.DELETE("/supplier/:id/katalog/:katalog_id", s.butuhIzin(models.IzinSupplierKelola), s.deleteKatalogHandler)
+
+		// --- Rute-rute Pembelian ---
+		api.GET("/pembelian", s.butuhIzin(models.IzinPembelianLihat), s.getPembelianHandler)
+		api.GET("/pembelian/:id", s.butuhIzin(models.IzinPembelianLihat), s.getPembelianByIdHandler)
+		api.POST("/pembelian", s.butuhIzin(models.IzinPembelianKelola), s.createPembelianHandler)
+		api.DELETE("/pembelian/:id", s.butuhIzin(models.IzinPembelianKelola), s.deletePembelianHandler)
+		api.PUT("/pembelian/:id/terima", s.butuhIzin(models.IzinPembelianTerima), s.terimaPembelianHandler)
+		api.PUT("/pembelian/:id/pesan", s.butuhIzin(models.IzinPembelianKelola), s.pesanPembelianHandler)
+		api.PUT("/pembelian/:id/kirim", s.butuhIzin(models.IzinPembelianKelola), s.kirimPembelianHandler)
+		api.PUT("/pembelian/:id/batal", s.butuhIzin(models.IzinPembelianKelola), s.batalPembelianHandler)
+		api.PUT("/pembelian/:id/tutup", s.butuhIzin(models.IzinPembelianKelola), s.tutupPembelianHandler)
+		api.GET("/pembelian/:id/riwayat", s.butuhIzin(models.IzinPembelianLihat), s.getRiwayatPembelianHandler)
+		api.POST("/pembelian/:id/penerimaan", s.butuhIzin(models.IzinPembelianTerima), s.createPenerimaanHandler)
+		api.GET("/pembelian/:id/penerimaan", s.butuhIzin(models.IzinPembelianLihat), s.getPenerimaanHandler)
+
+		// --- Rute-rute Permintaan Pembelian ---
+		api.GET("/permintaan", s.butuhIzin(models.IzinPermintaanLihat), s.getPermintaanHandler)
+		api.GET("/permintaan/aturan", s.butuhIzin(models.IzinPermintaanLihat), s.getAturanHandler)
+		api.POST("/permintaan/aturan", s.butuhIzin(models.IzinPermintaanAtur), s.createAturanHandler)
+		api.PUT("/permintaan/aturan/:id", s.butuhIzin(models.IzinPermintaanAtur), s.updateAturanHandler)
+		api.DELETE("/permintaan/aturan/:id", s.butuhIzin(models.IzinPermintaanAtur), s.deleteAturanHandler)
+		api.GET("/permintaan/:id", s.butuhIzin(models.IzinPermintaanLihat), s.getPermintaanByIdHandler)
+		api.POST("/permintaan", s.butuhIzin(models.IzinPermintaanBuat), s.createPermintaanHandler)
+		api.PUT("/permintaan/:id/setujui", s.butuhIzin(models.IzinPermintaanSetujui), s.setujuiPermintaanHandler)
+		api.PUT("/permintaan/:id/tolak", s.butuhIzin(models.IzinPermintaanSetujui), s.tolakPermintaanHandler)
+		api.PUT("/permintaan/:id/batal", s.butuhIzin(models.IzinPermintaanBuat), s.batalPermintaanHandler)
+		api.POST("/permintaan/:id/pembelian", s.butuhIzin(models.IzinPembelianKelola), s.konversiPermintaanHandler)
+
+		// --- Rute-rute Gudang ---
+		api.GET("/gudang", s.butuhIzin(models.IzinGudangLihat), s.getGudangHandler)
+		api.GET("/gudang/:id", s.butuhIzin(models.IzinGudangLihat), s.getGudangByIdHandler)
+		api.POST("/gudang", s.butuhIzin(models.IzinGudangKelola), s.createGudangHandler)
+		api.PUT("/gudang/:id", s.butuhIzin(models.IzinGudangKelola), s.updateGudangHandler)
+		api.DELETE("/gudang/:id", s.butuhIzin(models.IzinGudangKelola), s.deleteGudangHandler)
+		api.PUT("/gudang/:id/pulihkan", s.butuhIzin(models.IzinGudangKelola), s.pulihkanGudangHandler)
+
+		// --- Rute-rute Stok ---
+		api.GET("/stok", s.butuhIzin(models.IzinStokLihat), s.getStokHandler)
+		api.POST("/stok/adjust", s.butuhIzin(models.IzinStokSesuaikan), s.adjustStokHandler)
+		api.POST("/stok/penjualan", s.butuhIzin(models.IzinStokSesuaikan), s.penjualanStokHandler)
+		api.GET("/stok/mutasi", s.butuhIzin(models.IzinStokLihat), s.getMutasiStokHandler)
+		api.GET("/stok/batch", s.butuhIzin(models.IzinStokLihat), s.getBatchStokHandler)
+		api.GET("/stok/batch/kedaluwarsa", s.butuhIzin(models.IzinStokLihat), s.getBatchKedaluwarsaHandler)
+		api.GET("/stok/parameter", s.butuhIzin(models.IzinStokLihat), s.getParameterStokHandler)
+		api.PUT("/stok/parameter/:produk_id/:gudang_id", s.butuhIzin(models.IzinStokSesuaikan), s.simpanParameterStokHandler)
+		api.DELETE("/stok/parameter/:produk_id/:gudang_id", s.butuhIzin(models.IzinStokSesuaikan), s.hapusParameterStokHandler)
+
+		// --- Rute-rute Saran Pemesanan Ulang ---
+		api.GET("/replenishment/saran", s.butuhIzin(models.IzinPembelianLihat), s.getSaranReplenishmentHandler)
+		api.POST("/replenishment/pembelian", s.butuhIzin(models.IzinPembelianKelola), s.buatPembelianReplenishmentHandler)
+
+		// --- Rute-rute Peramalan ---
+		api.GET("/forecast", s.butuhIzin(models.IzinStokLihat), s.getForecastHandler)
+
+		// --- Rute-rute Transfer Stok ---
+		api.GET("/transfer", s.butuhIzin(models.IzinTransferLihat), s.getTransferHandler)
+		api.GET("/transfer/dalam-perjalanan", s.butuhIzin(models.IzinTransferLihat), s.getStokDalamPerjalananHandler)
+		api.GET("/transfer/:id", s.butuhIzin(models.IzinTransferLihat), s.getTransferByIdHandler)
+		api.POST("/transfer", s.butuhIzin(models.IzinTransferKelola), s.createTransferHandler)
+		api.PUT("/transfer/:id/kirim", s.butuhIzin(models.IzinTransferKelola), s.kirimTransferHandler)
+		api.PUT("/transfer/:id/terima", s.butuhIzin(models.IzinTransferKelola), s.terimaTransferHandler)
+		api.PUT("/transfer/:id/batal", s.butuhIzin(models.IzinTransferKelola), s.batalTransferHandler)
+
+		// --- Rute-rute Pengguna dan Role ---
+		api.GET("/izin", s.butuhIzin(models.IzinPenggunaKelola), s.getIzinHandler)
+		api.GET("/role", s.butuhIzin(models.IzinPenggunaKelola), s.getRoleHandler)
+		api.POST("/role", s.butuhIzin(models.IzinPenggunaKelola), s.createRoleHandler)
+		api.PUT("/role/:id/izin", s.butuhIzin(models.IzinPenggunaKelola), s.ubahIzinRoleHandler)
+		api.GET("/pengguna", s.butuhIzin(models.IzinPenggunaKelola), s.getPenggunaHandler)
+		api.GET("/pengguna/:id/role", s.butuhIzin(models.IzinPenggunaKelola), s.getRolePenggunaHandler)
+		api.PUT("/pengguna/:id/role", s.butuhIzin(models.IzinPenggunaKelola), s.setRolePenggunaHandler)
+		api.GET("/pengguna/:id/gudang", s.butuhIzin(models.IzinPenggunaKelola), s.getGudangPenggunaHandler)
+		api.PUT("/pengguna/:id/gudang", s.butuhIzin(models.IzinPenggunaKelola), s.setGudangPenggunaHandler)
+
+		// --- Rute-rute Audit ---
+		api.GET("/audit", s.butuhIzin(models.IzinAuditLihat), s.getAuditHandler)
+
+		// --- Rute-rute Dashboard ---
+		api.GET("/dashboard/stats", s.butuhIzin(models.IzinDashboardLihat), s.getDashboardStatsHandler)
+		api.GET("/dashboard/stok-per-produk", s.butuhIzin(models.IzinDashboardLihat), s.getStokChartHandler)
+		api.GET("/dashboard/pembelian-terakhir", s.butuhIzin(models.IzinDashboardLihat), s.getPembelianTerakhirHandler)
+	}
+
+	return router
+}
+
+// aktor mengembalikan username pemilik access token yang melakukan permintaan
+func aktor(c *gin.Context) string {
+	if claims, ok := c.Get(kunciClaims); ok {
+		return claims.(auth.Claims).Username
+	}
+	return "anonim"
+}
+
+// paramID membaca parameter path numerik. Jika tidak valid, respons 400 sudah dikirim dan ok bernilai false.
+func paramID(c *gin.Context, name string) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param(name), 10, 64)
+	if err != nil || id < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID tidak valid"})
+		return 0, false
+	}
+	return id, true
+}
+
+// queryID membaca query parameter numerik opsional. Kosong berarti 0.
+// Jika tidak valid, respons 400 sudah dikirim dan ok bernilai false.
+func queryID(c *gin.Context, name string) (int64, bool) {
+	v := c.Query(name)
+	if v == "" {
+		return 0, true
+	}
+	id, err := strconv.ParseInt(v, 10, 64)
+	if err != nil || id < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": name + " tidak valid"})
+		return 0, false
+	}
+	return id, true
+}
+
+// queryAngka membaca query parameter bilangan bulat opsional ke dst. Nilai yang bukan
+// bilangan bulat dicatat di fe dan dst tidak diubah.
+func queryAngka(c *gin.Context, fe fieldErrors, name string, dst *int) {
+	v := c.Query(name)
+	if v == "" {
+		return
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		fe.add(name, "harus berupa bilangan bulat")
+		return
+	}
+	*dst = n
+}
+
+// queryTanggal membaca query parameter tanggal opsional berformat YYYY-MM-DD.
+// Jika tidak valid, respons 400 sudah dikirim dan ok bernilai false.
+func queryTanggal(c *gin.Context, name string) (time.Time, bool) {
+	v := c.Query(name)
+	if v == "" {
+		return time.Time{}, true
+	}
+	t, err := time.Parse("2006-01-02", v)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": name + " harus berformat YYYY-MM-DD"})
+		return time.Time{}, false
+	}
+	return t, true
+}
+
+// tanggalValid memeriksa bahwa v kosong atau berformat YYYY-MM-DD
+func tanggalValid(v string) bool {
+	if v == "" {
+		return true
+	}
+	_, err := time.Parse("2006-01-02", v)
+	return err == nil
+}
+
+// waktuValid memeriksa bahwa v kosong atau berformat YYYY-MM-DD atau YYYY-MM-DD HH:MM:SS
+func waktuValid(v string) bool {
+	if tanggalValid(v) {
+		return true
+	}
+	_, err := time.Parse("2006-01-02 15:04:05", v)
+	return err == nil
+}
+
+// gudangAda memastikan gudang dengan ID tersebut ada dan belum diarsipkan.
+// Jika tidak, respons 400 sudah dikirim.
+func (s *server) gudangAda(c *gin.Context, id int64) bool {
+	g, err := s.gudang.GetGudang(c.Request.Context(), id)
+	if err == nil && g.DeletedAt.Valid {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Gudang dengan ID %d sudah diarsipkan", id)})
+		return false
+	}
+	if err == nil {
+		return true
+	}
+	if errors.Is(err, store.ErrNotFound) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Gudang dengan ID %d tidak ditemukan", id)})
+		return false
+	}
+	log.Printf("Error memeriksa gudang %d: %v", id, err)
+	c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memeriksa data gudang"})
+	return false
+}
+
+// produkAda memastikan produk dengan ID tersebut ada dan belum dihapus.
+// Jika tidak, respons 400 sudah dikirim.
+func (s *server) produkAda(c *gin.Context, id int64) bool {
+	p, err := s.produk.GetProduk(c.Request.Context(), id)
+	if err == nil && p.DeletedAt.Valid {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Produk dengan ID %d sudah dihapus", id)})
+		return false
+	}
+	if err == nil {
+		return true
+	}
+	if errors.Is(err, store.ErrNotFound) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Produk dengan ID %d tidak ditemukan", id)})
+		return false
+	}
+	log.Printf("Error memeriksa produk %d: %v", id, err)
+	c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memeriksa data produk"})
+	return false
+}
+
+// kunciGudang adalah kunci gin.Context tempat lingkup gudang pengguna disimpan setelah dibaca sekali
+const kunciGudang = "auth.gudang"
+
+// lingkupGudang berisi gudang yang boleh diakses pengguna yang login.
+// semua bernilai true untuk pengguna dengan izin gudang.semua.
+type lingkupGudang struct {
+	semua bool
+	ids   map[int64]bool
+}
+
+func (l lingkupGudang) boleh(gudangID int64) bool {
+	return l.semua || l.ids[gudangID]
+}
+
+// daftar mengembalikan ID gudang pengguna secara berurutan (tidak berarti jika semua bernilai true)
+func (l lingkupGudang) daftar() []int64 {
+	return slices.Sorted(maps.Keys(l.ids))
+}
+
+// gudangSaya mengembalikan lingkup gudang pengguna yang login.
+// Jika gagal membacanya, respons 500 sudah dikirim dan ok bernilai false.
+func (s *server) gudangSaya(c *gin.Context) (lingkupGudang, bool) {
+	if l, found := c.Get(kunciGudang); found {
+		return l.(lingkupGudang), true
+	}
+	semua, ok := s.punyaIzin(c, models.IzinGudangSemua)
+	if !ok {
+		return lingkupGudang{}, false
+	}
+	l := lingkupGudang{semua: semua, ids: make(map[int64]bool)}
+	if claims, login := c.Get(kunciClaims); login && !semua {
+		penggunaID := claims.(auth.Claims).PenggunaID
+		ids, err := s.pengguna.GudangPengguna(c.Request.Context(), penggunaID)
+		if err != nil {
+			log.Printf("Error mengambil gudang pengguna %d: %v", penggunaID, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memeriksa gudang pengguna"})
+			return l, false
+		}
+		for _, id := range ids {
+			l.ids[id] = true
+		}
+	}
+	c.Set(kunciGudang, l)
+	return l, true
+}
+
+// bolehGudang memastikan pengguna yang login boleh mengakses setiap gudang di gudangIDs.
+// Jika tidak, respons 403 (atau 500) sudah dikirim.
+func (s *server) bolehGudang(c *gin.Context, gudangIDs ...int64) bool {
+	l, ok := s.gudangSaya(c)
+	if !ok {
+		return false
+	}
+	for _, id := range gudangIDs {
+		if !l.boleh(id) {
+			c.JSON(http.StatusForbidden, gin.H{"error": fmt.Sprintf("Anda tidak memiliki akses ke gudang %d", id)})
+			return false
+		}
+	}
+	return true
+}
+
+// batasiGudang membatasi kueri daftar ke gudang pengguna yang login lewat Lingkup
+// gudang_id. Filter gudang_id ke gudang lain ditolak dengan 403. Jika gagal, respons
+// sudah dikirim dan ok bernilai false.
+func (s *server) batasiGudang(c *gin.Context, q *store.Kueri) bool {
+	l, ok := s.gudangSaya(c)
+	if !ok {
+		return false
+	}
+	if v, ada := q.Filter["gudang_id"]; ada {
+		gudangID, _ := strconv.ParseInt(v, 10, 64)
+		if !s.bolehGudang(c, gudangID) {
+			return false
+		}
+	}
+	if !l.semua {
+		*q = q.DenganLingkup("gudang_id", l.daftar())
+	}
+	return true
+}
+
+// saring mengembalikan elemen daftar yang lolos boleh
+func saring[T any](daftar []T, boleh func(T) bool) []T {
+	hasil := make([]T, 0, len(daftar))
+	for _, v := range daftar {
+		if boleh(v) {
+			hasil = append(hasil, v)
+		}
+	}
+	return hasil
+}
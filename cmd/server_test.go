@@ -0,0 +1,179 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+
+	"scm-api/internal/auth"
+	"scm-api/internal/config"
+	"scm-api/internal/models"
+	"scm-api/internal/store"
+	"scm-api/internal/store/memory"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestMain(m *testing.M) {
+	gin.SetMode(gin.TestMode)
+	gin.DefaultWriter = io.Discard
+	os.Exit(m.Run())
+}
+
+// penguji menjalankan router lengkap di atas store memory. Setiap pengguna uji
+// sudah login dan memiliki role yang namanya sama dengan username-nya.
+type penguji struct {
+	t      *testing.T
+	store  *memory.Store
+	srv    *server
+	router http.Handler
+	token  map[string]string
+}
+
+// pengujiBaru membuat server baru dengan pengguna admin, manajer, pembelian, dan gudang
+func pengujiBaru(t *testing.T) *penguji {
+	t.Helper()
+	cfg := config.Default()
+	cfg.Auth.JWTSecret = strings.Repeat("u", config.MinJWTSecret)
+	cfg.Media.Dir = t.TempDir()
+
+	st := memory.New()
+	ctx := context.Background()
+	daftarRole, err := st.ListRole(ctx)
+	if err != nil {
+		t.Fatalf("ListRole: %v", err)
+	}
+	hash, err := auth.HashPassword("rahasia123")
+	if err != nil {
+		t.Fatalf("HashPassword: %v", err)
+	}
+	srv := newServer(st, cfg)
+	p := &penguji{t: t, store: st, srv: srv, router: newRouter(cfg, srv), token: make(map[string]string)}
+	for _, r := range daftarRole {
+		pg := models.Pengguna{Username: r.Nama, PasswordHash: hash, Aktif: true}
+		if err := st.CreatePengguna(ctx, &pg); err != nil {
+			t.Fatalf("CreatePengguna %s: %v", r.Nama, err)
+		}
+		if err := st.SetRolePengguna(ctx, pg.PenggunaID, []int64{r.RoleID}); err != nil {
+			t.Fatalf("SetRolePengguna %s: %v", r.Nama, err)
+		}
+		var tr models.TokenResponse
+		p.decode(p.harus(http.StatusOK, "", "POST", "/api/auth/login", gin.H{"username": r.Nama, "password": "rahasia123"}), &tr)
+		p.token[r.Nama] = tr.AccessToken
+	}
+	return p
+}
+
+// untuk mengembalikan penguji yang sama untuk dipakai di dalam subtes t
+func (p *penguji) untuk(t *testing.T) *penguji {
+	salinan := *p
+	salinan.t = t
+	return &salinan
+}
+
+// kirim mengirim request sebagai pengguna ("" berarti tanpa token). body selain
+// string dikirim sebagai JSON.
+func (p *penguji) kirim(pengguna, method, path string, body any) (int, []byte) {
+	p.t.Helper()
+	var isi io.Reader
+	switch b := body.(type) {
+	case nil:
+	case string:
+		isi = strings.NewReader(b)
+	default:
+		data, err := json.Marshal(b)
+		if err != nil {
+			p.t.Fatalf("json.Marshal: %v", err)
+		}
+		isi = bytes.NewReader(data)
+	}
+	req := httptest.NewRequest(method, path, isi)
+	req.Header.Set("Content-Type", "application/json")
+	if pengguna != "" {
+		req.Header.Set("Authorization", "Bearer "+p.token[pengguna])
+	}
+	w := httptest.NewRecorder()
+	p.router.ServeHTTP(w, req)
+	return w.Code, w.Body.Bytes()
+}
+
+// harus seperti kirim, tetapi menggagalkan tes jika kode status bukan kode
+func (p *penguji) harus(kode int, pengguna, method, path string, body any) []byte {
+	p.t.Helper()
+	got, respons := p.kirim(pengguna, method, path, body)
+	if got != kode {
+		p.t.Fatalf("%s %s: status %d, ingin %d; body %s", method, path, got, kode, respons)
+	}
+	return respons
+}
+
+func (p *penguji) decode(data []byte, v any) {
+	p.t.Helper()
+	if err := json.Unmarshal(data, v); err != nil {
+		p.t.Fatalf("json.Unmarshal %s: %v", data, err)
+	}
+}
+
+// dataDasar membuat gudang 1 dan 2, supplier 1, serta produk 1 dan 2 bersatuan dasar pcs.
+// Pengguna gudang hanya bekerja di gudang 1.
+func (p *penguji) dataDasar() {
+	p.t.Helper()
+	p.harus(http.StatusCreated, "admin", "POST", "/api/gudang", gin.H{"nama_gudang": "Pusat", "lokasi": "Jakarta"})
+	p.harus(http.StatusCreated, "admin", "POST", "/api/gudang", gin.H{"nama_gudang": "Cabang", "lokasi": "Bandung"})
+	p.harus(http.StatusCreated, "admin", "POST", "/api/supplier", gin.H{"nama_supplier": "Sumber Makmur"})
+	p.harus(http.StatusCreated, "admin", "POST", "/api/produk", gin.H{"sku": "BRG-1", "nama_produk": "Barang Satu", "satuan": "pcs", "harga_jual": 1500})
+	p.harus(http.StatusCreated, "admin", "POST", "/api/produk", gin.H{"sku": "BRG-2", "nama_produk": "Barang Dua", "satuan": "pcs", "harga_jual": 2500})
+	p.aksesGudang("gudang", 1)
+}
+
+// aksesGudang mengganti gudang tempat pengguna bekerja
+func (p *penguji) aksesGudang(username string, gudangIDs ...int64) {
+	p.t.Helper()
+	ctx := context.Background()
+	pg, err := p.store.GetPenggunaByUsername(ctx, username)
+	if err == nil {
+		err = p.store.SetGudangPengguna(ctx, pg.PenggunaID, gudangIDs)
+	}
+	if err != nil {
+		p.t.Fatalf("aksesGudang %s: %v", username, err)
+	}
+}
+
+// buatPembelian membuat pesanan ke supplier 1 untuk gudang 1 dan mengembalikan ID-nya.
+// jumlah berisi jumlah pesanan produk 1, 2, dan seterusnya dengan harga 1000.
+func (p *penguji) buatPembelian(status string, jumlah ...int) int64 {
+	p.t.Helper()
+	details := make([]gin.H, len(jumlah))
+	for i, n := range jumlah {
+		details[i] = gin.H{"produk_id": i + 1, "jumlah": n, "harga_beli_satuan": 1000}
+	}
+	var respons struct {
+		PembelianID int64 `json:"pembelian_id"`
+	}
+	p.decode(p.harus(http.StatusCreated, "admin", "POST", "/api/pembelian", gin.H{
+		"supplier_id": 1, "tanggal_pesan": "2024-05-01", "status": status, "gudang_tujuan_id": 1, "details": details,
+	}), &respons)
+	return respons.PembelianID
+}
+
+// stok mengembalikan jumlah stok produk di gudang, 0 jika belum ada barisnya
+func (p *penguji) stok(produkID, gudangID int64) int {
+	p.t.Helper()
+	daftar, _, err := p.store.ListStok(context.Background(), store.Kueri{Filter: map[string]string{
+		"produk_id": strconv.FormatInt(produkID, 10), "gudang_id": strconv.FormatInt(gudangID, 10),
+	}})
+	if err != nil {
+		p.t.Fatalf("ListStok: %v", err)
+	}
+	if len(daftar) == 0 {
+		return 0
+	}
+	return daftar[0].Jumlah
+}
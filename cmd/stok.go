@@ -0,0 +1,201 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+
+	"scm-api/internal/models"
+	"scm-api/internal/store"
+
+	"github.com/gin-gonic/gin"
+)
+
+// =================================================================
+// HANDLER UNTUK MODUL STOK
+// =================================================================
+
+// getStokHandler hanya menampilkan stok di gudang tempat pengguna bekerja,
+// kecuali untuk pengguna dengan izin gudang.semua
+func (s *server) getStokHandler(c *gin.Context) {
+	q, ok := bacaKueri(c, store.DaftarStok)
+	if !ok {
+		return
+	}
+	if !s.batasiGudang(c, &q) {
+		return
+	}
+	daftarStok, total, err := s.stok.ListStok(c.Request.Context(), q)
+	if err != nil {
+		log.Printf("Error mengambil stok: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil data stok"})
+		return
+	}
+	kirimHalaman(c, daftarStok, total, q)
+}
+
+// HANDLER UNTUK PENYESUAIAN STOK (UPSERT)
+// =======================================
+func (s *server) adjustStokHandler(c *gin.Context) {
+	var req struct {
+		ProdukID int64 `json:"produk_id"`
+		GudangID int64 `json:"gudang_id"`
+		Jumlah   int   `json:"jumlah"`
+		// Catatan menjelaskan alasan penyesuaian, misalnya hasil stock opname
+		Catatan string `json:"catatan"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Data JSON tidak valid"})
+		return
+	}
+	// Produk yang sudah dihapus tetap boleh disesuaikan agar sisa stoknya bisa dihapusbukukan
+	if !s.bolehGudang(c, req.GudangID) || !s.gudangAda(c, req.GudangID) {
+		return
+	}
+	if _, err := s.produk.GetProduk(c.Request.Context(), req.ProdukID); err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Produk dengan ID %d tidak ditemukan", req.ProdukID)})
+			return
+		}
+		log.Printf("Error memeriksa produk %d: %v", req.ProdukID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memeriksa data produk"})
+		return
+	}
+
+	m, err := s.stok.AdjustStok(c.Request.Context(), req.ProdukID, req.GudangID, req.Jumlah, aktor(c), req.Catatan)
+	if err != nil {
+		if errors.Is(err, store.ErrStokTidakCukup) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Jumlah stok tidak boleh negatif"})
+			return
+		}
+		log.Printf("Error upsert stok: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menyesuaikan stok"})
+		return
+	}
+
+	// Stok tidak punya ID sendiri di API, jadi dicatat per produk dengan gudang di isinya
+	s.catatAudit(c, "stok", m.ProdukID, models.AuditUbah,
+		gin.H{"produk_id": m.ProdukID, "gudang_id": m.GudangID, "jumlah": m.JumlahSebelum},
+		gin.H{"produk_id": m.ProdukID, "gudang_id": m.GudangID, "jumlah": m.JumlahSesudah})
+	c.JSON(http.StatusOK, gin.H{"message": "Stok berhasil disesuaikan"})
+}
+
+// HANDLER UNTUK PENJUALAN
+// =======================
+// penjualanStokHandler mengurangi stok karena barang terjual. Berbeda dengan
+// penyesuaian, mutasi penjualan dihitung sebagai permintaan oleh saran pemesanan
+// ulang dan peramalan.
+func (s *server) penjualanStokHandler(c *gin.Context) {
+	var req struct {
+		ProdukID int64  `json:"produk_id"`
+		GudangID int64  `json:"gudang_id"`
+		Jumlah   int    `json:"jumlah"`
+		Catatan  string `json:"catatan"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Data JSON tidak valid"})
+		return
+	}
+	fe := make(fieldErrors)
+	if req.Jumlah < 1 || req.Jumlah > math.MaxInt32 {
+		fe.add("jumlah", "harus antara 1 dan batas stok")
+	}
+	if fe.respond(c) {
+		return
+	}
+	if !s.bolehGudang(c, req.GudangID) || !s.gudangAda(c, req.GudangID) || !s.produkAda(c, req.ProdukID) {
+		return
+	}
+
+	m, err := s.stok.CatatPenjualan(c.Request.Context(), req.ProdukID, req.GudangID, req.Jumlah, aktor(c), req.Catatan)
+	if err != nil {
+		if errors.Is(err, store.ErrStokTidakCukup) {
+			c.JSON(http.StatusConflict, gin.H{"error": "Penjualan tidak bisa dicatat: " + err.Error()})
+			return
+		}
+		log.Printf("Error mencatat penjualan: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mencatat penjualan"})
+		return
+	}
+
+	s.catatAudit(c, "stok", m.ProdukID, models.AuditUbah,
+		gin.H{"produk_id": m.ProdukID, "gudang_id": m.GudangID, "jumlah": m.JumlahSebelum},
+		gin.H{"produk_id": m.ProdukID, "gudang_id": m.GudangID, "jumlah": m.JumlahSesudah})
+	c.JSON(http.StatusCreated, m)
+}
+
+// HANDLER UNTUK RIWAYAT MUTASI STOK
+// =================================
+// getMutasiStokHandler memakai parameter daftar bersama (lihat bacaKueri) dengan filter
+// produk_id, gudang_id, tipe, dan referensi_tipe. Hanya mutasi di gudang pengguna yang tampil.
+func (s *server) getMutasiStokHandler(c *gin.Context) {
+	q, ok := bacaKueri(c, store.DaftarMutasi)
+	if !ok {
+		return
+	}
+	if tipe, ada := q.Filter["tipe"]; ada && !models.TipeMutasiValid(tipe) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Tipe mutasi tidak dikenal: " + tipe})
+		return
+	}
+	if !s.batasiGudang(c, &q) {
+		return
+	}
+	daftar, total, err := s.stok.ListMutasiStok(c.Request.Context(), q)
+	if err != nil {
+		log.Printf("Error mengambil mutasi stok: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil data mutasi stok"})
+		return
+	}
+	kirimHalaman(c, daftar, total, q)
+}
+
+// HANDLER UNTUK BATCH STOK
+// ========================
+// getBatchStokHandler memakai parameter daftar bersama dengan filter produk_id dan
+// gudang_id. Bawaannya urut tanggal kedaluwarsa (FEFO).
+func (s *server) getBatchStokHandler(c *gin.Context) {
+	q, ok := bacaKueri(c, store.DaftarBatch)
+	if !ok || !s.batasiGudang(c, &q) {
+		return
+	}
+	daftar, total, err := s.stok.ListBatchStok(c.Request.Context(), q)
+	if err != nil {
+		log.Printf("Error mengambil batch stok: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil data batch stok"})
+		return
+	}
+	kirimHalaman(c, daftar, total, q)
+}
+
+// Query opsional: hari (bawaan 30), gudang_id.
+// Batch yang sudah kedaluwarsa tetap ditampilkan dengan sisa_hari negatif.
+func (s *server) getBatchKedaluwarsaHandler(c *gin.Context) {
+	hari := 30
+	if v := c.Query("hari"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "hari harus berupa bilangan bulat tidak negatif"})
+			return
+		}
+		hari = n
+	}
+	gudangID, ok := queryID(c, "gudang_id")
+	if !ok {
+		return
+	}
+	l, ok := s.gudangSaya(c)
+	if !ok || (gudangID != 0 && !s.bolehGudang(c, gudangID)) {
+		return
+	}
+	daftar, err := s.stok.BatchKedaluwarsa(c.Request.Context(), hari, gudangID)
+	if err != nil {
+		log.Printf("Error mengambil batch kedaluwarsa: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil data batch kedaluwarsa"})
+		return
+	}
+	c.JSON(http.StatusOK, saring(daftar, func(b models.StokBatchResponse) bool { return l.boleh(b.GudangID) }))
+}
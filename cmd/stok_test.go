@@ -0,0 +1,167 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"scm-api/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestAdjustStok(t *testing.T) {
+	p := pengujiBaru(t)
+	p.dataDasar()
+
+	p.harus(http.StatusOK, "gudang", "POST", "/api/stok/adjust", gin.H{"produk_id": 1, "gudang_id": 1, "jumlah": 10, "catatan": "stok awal"})
+	p.harus(http.StatusOK, "gudang", "POST", "/api/stok/adjust", gin.H{"produk_id": 1, "gudang_id": 1, "jumlah": 4, "catatan": "opname"})
+	if n := p.stok(1, 1); n != 4 {
+		t.Fatalf("stok produk 1 di gudang 1 = %d, ingin 4", n)
+	}
+
+	// Setiap penyesuaian tercatat sebagai mutasi dengan selisihnya
+	var mutasi models.Halaman[models.StokMutasi]
+	p.decode(p.harus(http.StatusOK, "gudang", "GET", "/api/stok/mutasi?produk_id=1&sort=mutasi_id&dir=asc", nil), &mutasi)
+	if len(mutasi.Data) != 2 {
+		t.Fatalf("mutasi = %+v, ingin 2 baris", mutasi.Data)
+	}
+	for i, ingin := range []struct{ sebelum, sesudah, perubahan int }{{0, 10, 10}, {10, 4, -6}} {
+		m := mutasi.Data[i]
+		if m.Tipe != models.MutasiPenyesuaian || m.JumlahSebelum != ingin.sebelum || m.JumlahSesudah != ingin.sesudah || m.Perubahan != ingin.perubahan {
+			t.Errorf("mutasi ke-%d = %+v, ingin %+v", i+1, m, ingin)
+		}
+	}
+
+	for _, tc := range []struct {
+		nama     string
+		pengguna string
+		body     any
+		kode     int
+	}{
+		{"jumlah negatif", "admin", gin.H{"produk_id": 1, "gudang_id": 1, "jumlah": -1}, http.StatusBadRequest},
+		{"produk tidak ada", "admin", gin.H{"produk_id": 99, "gudang_id": 1, "jumlah": 1}, http.StatusBadRequest},
+		{"produk kosong", "admin", gin.H{"gudang_id": 1, "jumlah": 1}, http.StatusBadRequest},
+		{"gudang tidak ada", "admin", gin.H{"produk_id": 1, "gudang_id": 99, "jumlah": 1}, http.StatusBadRequest},
+		{"JSON rusak", "admin", `{"produk_id":`, http.StatusBadRequest},
+		{"gudang lain", "gudang", gin.H{"produk_id": 1, "gudang_id": 2, "jumlah": 1}, http.StatusForbidden},
+		{"tanpa izin", "pembelian", gin.H{"produk_id": 1, "gudang_id": 1, "jumlah": 1}, http.StatusForbidden},
+	} {
+		t.Run(tc.nama, func(t *testing.T) {
+			if kode, body := p.kirim(tc.pengguna, "POST", "/api/stok/adjust", tc.body); kode != tc.kode {
+				t.Errorf("status %d, ingin %d; body %s", kode, tc.kode, body)
+			}
+		})
+	}
+	if n := p.stok(1, 1); n != 4 {
+		t.Errorf("stok berubah menjadi %d setelah penyesuaian yang ditolak, ingin tetap 4", n)
+	}
+}
+
+func TestLingkupGudangPadaDaftar(t *testing.T) {
+	p := pengujiBaru(t)
+	p.dataDasar()
+	p.harus(http.StatusCreated, "admin", "POST", "/api/gudang", gin.H{"nama_gudang": "Timur", "lokasi": "Surabaya"})
+
+	// Stok dan batch di gudang 1 dan 2, lalu transfer 2 -> 1 dan 2 -> 3
+	id := p.buatPembelian(models.StatusPembelianDipesan, 10)
+	p.harus(http.StatusCreated, "admin", "POST", fmt.Sprintf("/api/pembelian/%d/penerimaan", id), gin.H{"items": []gin.H{{"produk_id": 1, "jumlah_diterima": 4, "nomor_lot": "L1"}}})
+	p.harus(http.StatusCreated, "admin", "POST", fmt.Sprintf("/api/pembelian/%d/penerimaan", id), gin.H{"gudang_id": 2, "items": []gin.H{{"produk_id": 1, "jumlah_diterima": 6, "nomor_lot": "L2"}}})
+	for _, tujuan := range []int{1, 3} {
+		p.harus(http.StatusCreated, "admin", "POST", "/api/transfer", gin.H{"gudang_asal_id": 2, "gudang_tujuan_id": tujuan, "details": []gin.H{{"produk_id": 1, "jumlah": 1}}})
+	}
+
+	type baris struct {
+		GudangID       int64 `json:"gudang_id"`
+		GudangAsalID   int64 `json:"gudang_asal_id"`
+		GudangTujuanID int64 `json:"gudang_tujuan_id"`
+	}
+	daftar := func(pengguna, path string) []baris {
+		var h models.Halaman[baris]
+		p.decode(p.harus(http.StatusOK, pengguna, "GET", path, nil), &h)
+		if h.Meta.Total != len(h.Data) {
+			t.Errorf("%s %s: total %d, data %d", pengguna, path, h.Meta.Total, len(h.Data))
+		}
+		return h.Data
+	}
+	for _, path := range []string{"/api/stok", "/api/stok/mutasi", "/api/stok/batch"} {
+		if got := daftar("admin", path); len(got) != 2 {
+			t.Errorf("admin %s = %+v, ingin gudang 1 dan 2", path, got)
+		}
+		if got := daftar("gudang", path); len(got) != 1 || got[0].GudangID != 1 {
+			t.Errorf("gudang %s = %+v, ingin hanya gudang 1", path, got)
+		}
+		p.harus(http.StatusForbidden, "gudang", "GET", path+"?gudang_id=2", nil)
+	}
+	if got := daftar("gudang", "/api/transfer"); len(got) != 1 || got[0].GudangTujuanID != 1 {
+		t.Errorf("gudang /api/transfer = %+v, ingin hanya transfer ke gudang 1", got)
+	}
+	if got := daftar("admin", "/api/transfer"); len(got) != 2 {
+		t.Errorf("admin /api/transfer = %+v, ingin 2 transfer", got)
+	}
+	p.harus(http.StatusForbidden, "gudang", "POST", "/api/stok/adjust", gin.H{"produk_id": 1, "gudang_id": 2, "jumlah": 1})
+	p.harus(http.StatusForbidden, "gudang", "GET", "/api/stok/batch/kedaluwarsa?gudang_id=2", nil)
+
+	// Lingkup diubah lewat API dan langsung berlaku; tanpa gudang daftar menjadi kosong
+	gudang := fmt.Sprintf("/api/pengguna/%d/gudang", p.idPengguna("gudang"))
+	p.harus(http.StatusOK, "admin", "PUT", gudang, gin.H{"gudang_id": []int64{2}})
+	if got := daftar("gudang", "/api/stok"); len(got) != 1 || got[0].GudangID != 2 {
+		t.Errorf("gudang /api/stok setelah pindah = %+v, ingin hanya gudang 2", got)
+	}
+	p.harus(http.StatusOK, "gudang", "POST", "/api/stok/adjust", gin.H{"produk_id": 1, "gudang_id": 2, "jumlah": 1})
+	p.harus(http.StatusForbidden, "gudang", "POST", "/api/stok/adjust", gin.H{"produk_id": 1, "gudang_id": 1, "jumlah": 1})
+	p.harus(http.StatusOK, "admin", "PUT", gudang, gin.H{"gudang_id": []int64{}})
+	if got := daftar("gudang", "/api/stok/mutasi"); len(got) != 0 {
+		t.Errorf("gudang tanpa lingkup melihat mutasi %+v", got)
+	}
+	p.harus(http.StatusBadRequest, "admin", "PUT", gudang, gin.H{"gudang_id": []int64{99}})
+}
+
+func TestBatchFEFO(t *testing.T) {
+	p := pengujiBaru(t)
+	p.dataDasar()
+	tanggal := func(hari int) string { return time.Now().AddDate(0, 0, hari).Format("2006-01-02") }
+
+	id := p.buatPembelian(models.StatusPembelianDipesan, 12)
+	jalur := fmt.Sprintf("/api/pembelian/%d/penerimaan", id)
+	p.harus(http.StatusBadRequest, "gudang", "POST", jalur, gin.H{"items": []gin.H{{"produk_id": 1, "jumlah_diterima": 1, "tanggal_kedaluwarsa": "31-12-2030"}}})
+	p.harus(http.StatusCreated, "gudang", "POST", jalur, gin.H{"items": []gin.H{
+		{"produk_id": 1, "jumlah_diterima": 5, "nomor_lot": "LOT-A", "tanggal_kedaluwarsa": tanggal(60)},
+		{"produk_id": 1, "jumlah_diterima": 5, "nomor_lot": "LOT-B", "tanggal_kedaluwarsa": tanggal(10)},
+		{"produk_id": 1, "jumlah_diterima": 2},
+	}})
+
+	sisaBatch := func() map[string]int {
+		t.Helper()
+		var h models.Halaman[models.StokBatchResponse]
+		p.decode(p.harus(http.StatusOK, "gudang", "GET", "/api/stok/batch?produk_id=1", nil), &h)
+		sisa := make(map[string]int)
+		for _, b := range h.Data {
+			sisa[b.NomorLot] = b.Jumlah
+		}
+		return sisa
+	}
+	// Barang tanpa lot dan kedaluwarsa masuk stok tanpa batch
+	if sisa := sisaBatch(); len(sisa) != 2 || sisa["LOT-A"] != 5 || sisa["LOT-B"] != 5 || p.stok(1, 1) != 12 {
+		t.Fatalf("batch = %v, stok %d; ingin LOT-A dan LOT-B masing-masing 5 dari stok 12", sisa, p.stok(1, 1))
+	}
+
+	// Penjualan mengambil batch yang kedaluwarsa paling dekat lebih dulu
+	p.harus(http.StatusCreated, "gudang", "POST", "/api/stok/penjualan", gin.H{"produk_id": 1, "gudang_id": 1, "jumlah": 3})
+	if sisa := sisaBatch(); sisa["LOT-A"] != 5 || sisa["LOT-B"] != 2 {
+		t.Errorf("batch setelah jual 3 = %v, ingin LOT-B berkurang lebih dulu", sisa)
+	}
+	var segera []models.StokBatchResponse
+	p.decode(p.harus(http.StatusOK, "gudang", "GET", "/api/stok/batch/kedaluwarsa?hari=30", nil), &segera)
+	if len(segera) != 1 || segera[0].NomorLot != "LOT-B" || segera[0].SisaHari.Int64 != 10 {
+		t.Errorf("batch kedaluwarsa 30 hari = %+v, ingin LOT-B dengan sisa 10 hari", segera)
+	}
+	p.harus(http.StatusBadRequest, "gudang", "GET", "/api/stok/batch/kedaluwarsa?hari=-1", nil)
+
+	// Setelah semua batch habis, sisanya diambil dari stok tanpa batch
+	p.harus(http.StatusCreated, "gudang", "POST", "/api/stok/penjualan", gin.H{"produk_id": 1, "gudang_id": 1, "jumlah": 8})
+	if sisa := sisaBatch(); sisa["LOT-A"] != 0 || sisa["LOT-B"] != 0 || p.stok(1, 1) != 1 {
+		t.Errorf("batch setelah jual 8 = %v, stok %d; ingin batch habis dan stok 1", sisa, p.stok(1, 1))
+	}
+}
@@ -0,0 +1,197 @@
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"log"
+	"net/http"
+
+	"scm-api/internal/models"
+	"scm-api/internal/store"
+
+	"github.com/gin-gonic/gin"
+)
+
+// =================================================================
+// HANDLER UNTUK MODUL SUPPLIER
+// =================================================================
+
+func (s *server) getSuppliersHandler(c *gin.Context) {
+	q, ok := bacaKueri(c, store.DaftarSupplier)
+	if !ok {
+		return
+	}
+	daftarSupplier, total, err := s.supplier.ListSupplier(c.Request.Context(), q)
+	if err != nil {
+		log.Printf("Error mengambil supplier: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil data supplier"})
+		return
+	}
+	kirimHalaman(c, daftarSupplier, total, q)
+}
+
+func (s *server) getSupplierByIdHandler(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+	sp, err := s.supplier.GetSupplier(c.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Supplier tidak ditemukan"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Terjadi kesalahan internal"})
+		return
+	}
+	c.JSON(http.StatusOK, sp)
+}
+
+// supplierRequest adalah body JSON untuk membuat dan mengupdate supplier.
+// Rating hanya dibaca untuk menolak klien lama yang masih mengirimnya; nilainya
+// sekarang ditulis oleh scorecard (lihat scorecard.go).
+type supplierRequest struct {
+	NamaSupplier  string   `json:"nama_supplier"`
+	Alamat        *string  `json:"alamat"`
+	Kontak        *string  `json:"kontak"`
+	ContactPerson *string  `json:"contact_person"`
+	Rating        *float64 `json:"rating"`
+}
+
+// tolakRating mengirim 400 jika request masih mengisi rating
+func (req supplierRequest) tolakRating(c *gin.Context) bool {
+	fe := make(fieldErrors)
+	if req.Rating != nil {
+		fe.add("rating", "dihitung otomatis dari scorecard supplier dan tidak bisa diisi manual")
+	}
+	return fe.respond(c)
+}
+
+// apply menyalin isi request ke model supplier
+func (req supplierRequest) apply(sp *models.Supplier) {
+	sp.NamaSupplier = req.NamaSupplier
+	sp.Alamat = sql.NullString{}
+	if req.Alamat != nil {
+		sp.Alamat = sql.NullString{String: *req.Alamat, Valid: true}
+	}
+	sp.Kontak = sql.NullString{}
+	if req.Kontak != nil {
+		sp.Kontak = sql.NullString{String: *req.Kontak, Valid: true}
+	}
+	sp.ContactPerson = sql.NullString{}
+	if req.ContactPerson != nil {
+		sp.ContactPerson = sql.NullString{String: *req.ContactPerson, Valid: true}
+	}
+}
+
+func (s *server) createSupplierHandler(c *gin.Context) {
+	var req supplierRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Data JSON tidak valid: " + err.Error()})
+		return
+	}
+	if req.tolakRating(c) {
+		return
+	}
+	var supplierBaru models.Supplier
+	req.apply(&supplierBaru)
+	if err := s.supplier.CreateSupplier(c.Request.Context(), &supplierBaru); err != nil {
+		log.Printf("Error menyimpan supplier: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menyimpan supplier ke database"})
+		return
+	}
+	s.catatAudit(c, "supplier", supplierBaru.SupplierID, models.AuditBuat, nil, supplierBaru)
+	c.JSON(http.StatusCreated, supplierBaru)
+}
+
+func (s *server) updateSupplierHandler(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+	var req supplierRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Data JSON tidak valid"})
+		return
+	}
+	if req.tolakRating(c) {
+		return
+	}
+	sp, err := s.supplier.GetSupplier(c.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Supplier tidak ditemukan"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengupdate supplier"})
+		return
+	}
+	if sp.DeletedAt.Valid {
+		c.JSON(http.StatusConflict, gin.H{"error": "Supplier sudah dihapus; pulihkan dulu sebelum mengubahnya"})
+		return
+	}
+	sebelum := sp
+	req.apply(&sp)
+	if err := s.supplier.UpdateSupplier(c.Request.Context(), sp); err != nil {
+		log.Printf("Error mengupdate supplier: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengupdate supplier"})
+		return
+	}
+	s.catatAudit(c, "supplier", id, models.AuditUbah, sebelum, sp)
+	c.JSON(http.StatusOK, gin.H{"message": "Supplier berhasil diupdate"})
+}
+
+func (s *server) deleteSupplierHandler(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+	sebelum, err := s.supplier.GetSupplier(c.Request.Context(), id)
+	if err == nil && sebelum.DeletedAt.Valid {
+		c.JSON(http.StatusConflict, gin.H{"error": "Supplier sudah dihapus"})
+		return
+	}
+	if err == nil {
+		err = s.supplier.DeleteSupplier(c.Request.Context(), id)
+	}
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Supplier tidak ditemukan"})
+			return
+		}
+		log.Printf("Error menghapus supplier: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menghapus supplier"})
+		return
+	}
+	s.catatAudit(c, "supplier", id, models.AuditHapus, sebelum, nil)
+	c.JSON(http.StatusOK, gin.H{"message": "Supplier berhasil dihapus"})
+}
+
+// pulihkanSupplierHandler membatalkan penghapusan supplier
+func (s *server) pulihkanSupplierHandler(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+	sebelum, err := s.supplier.GetSupplier(c.Request.Context(), id)
+	if err == nil && !sebelum.DeletedAt.Valid {
+		c.JSON(http.StatusConflict, gin.H{"error": "Supplier tidak sedang dihapus"})
+		return
+	}
+	if err == nil {
+		err = s.supplier.PulihkanSupplier(c.Request.Context(), id)
+	}
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Supplier tidak ditemukan"})
+			return
+		}
+		log.Printf("Error memulihkan supplier %d: %v", id, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memulihkan supplier"})
+		return
+	}
+	sp := sebelum
+	sp.DeletedAt = sql.NullString{}
+	s.catatAudit(c, "supplier", id, models.AuditPulihkan, sebelum, sp)
+	c.JSON(http.StatusOK, sp)
+}
@@ -0,0 +1,206 @@
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+
+	"scm-api/internal/models"
+	"scm-api/internal/store"
+
+	"github.com/gin-gonic/gin"
+)
+
+// =================================================================
+// HANDLER UNTUK MODUL TRANSFER STOK ANTAR GUDANG
+// =================================================================
+
+// getTransferHandler memakai parameter daftar bersama dengan filter status,
+// gudang_asal_id, dan gudang_tujuan_id. Hanya transfer dari atau ke gudang pengguna
+// yang ditampilkan.
+func (s *server) getTransferHandler(c *gin.Context) {
+	q, ok := bacaKueri(c, store.DaftarTransfer)
+	if !ok || !s.batasiGudang(c, &q) {
+		return
+	}
+	daftar, total, err := s.transfer.ListTransfer(c.Request.Context(), q)
+	if err != nil {
+		log.Printf("Error mengambil transfer: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil data transfer"})
+		return
+	}
+	kirimHalaman(c, daftar, total, q)
+}
+
+func (s *server) getTransferByIdHandler(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+	t, err := s.transfer.GetTransfer(c.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Transfer tidak ditemukan"})
+			return
+		}
+		log.Printf("Error mengambil transfer %d: %v", id, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil data transfer"})
+		return
+	}
+	l, ok := s.gudangSaya(c)
+	if !ok {
+		return
+	}
+	if !l.boleh(t.GudangAsalID) && !l.boleh(t.GudangTujuanID) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Anda tidak memiliki akses ke gudang asal maupun tujuan transfer ini"})
+		return
+	}
+	c.JSON(http.StatusOK, t)
+}
+
+func (s *server) createTransferHandler(c *gin.Context) {
+	var req struct {
+		GudangAsalID   int64  `json:"gudang_asal_id"`
+		GudangTujuanID int64  `json:"gudang_tujuan_id"`
+		Catatan        string `json:"catatan"`
+		Details        []struct {
+			ProdukID int64 `json:"produk_id"`
+			Jumlah   int   `json:"jumlah"`
+		} `json:"details"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Data JSON tidak valid: " + err.Error()})
+		return
+	}
+
+	if req.GudangAsalID == req.GudangTujuanID {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Gudang asal dan gudang tujuan harus berbeda"})
+		return
+	}
+	if !s.gudangAda(c, req.GudangAsalID) || !s.gudangAda(c, req.GudangTujuanID) {
+		return
+	}
+	// Transfer hanya boleh dibuat dari gudang tempat pengguna bekerja
+	if !s.bolehGudang(c, req.GudangAsalID) {
+		return
+	}
+	if len(req.Details) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Transfer minimal berisi satu produk"})
+		return
+	}
+
+	transfer := models.Transfer{
+		GudangAsalID:   req.GudangAsalID,
+		GudangTujuanID: req.GudangTujuanID,
+		Catatan:        sql.NullString{String: req.Catatan, Valid: req.Catatan != ""},
+		DibuatOleh:     aktor(c),
+		Details:        make([]models.DetailTransfer, 0, len(req.Details)),
+	}
+	sudah := make(map[int64]bool)
+	for _, d := range req.Details {
+		if d.Jumlah <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Jumlah produk %d harus lebih dari 0", d.ProdukID)})
+			return
+		}
+		if sudah[d.ProdukID] {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Produk %d muncul lebih dari sekali", d.ProdukID)})
+			return
+		}
+		if !s.produkAda(c, d.ProdukID) {
+			return
+		}
+		sudah[d.ProdukID] = true
+		transfer.Details = append(transfer.Details, models.DetailTransfer{ProdukID: d.ProdukID, Jumlah: d.Jumlah, Batch: make([]models.PemakaianBatch, 0)})
+	}
+
+	if err := s.transfer.CreateTransfer(c.Request.Context(), &transfer); err != nil {
+		log.Printf("Error membuat transfer: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menyimpan data transfer"})
+		return
+	}
+	s.catatAudit(c, "transfer", transfer.TransferID, models.AuditBuat, nil, transfer)
+	c.JSON(http.StatusCreated, transfer)
+}
+
+func (s *server) kirimTransferHandler(c *gin.Context) {
+	s.ubahStatusTransfer(c, models.StatusTransferDikirim, "Transfer dikirim dan stok gudang asal telah dikurangi")
+}
+
+func (s *server) terimaTransferHandler(c *gin.Context) {
+	s.ubahStatusTransfer(c, models.StatusTransferDiterima, "Transfer diterima dan stok gudang tujuan telah ditambah")
+}
+
+func (s *server) batalTransferHandler(c *gin.Context) {
+	s.ubahStatusTransfer(c, models.StatusTransferDibatalkan, "Transfer berhasil dibatalkan")
+}
+
+// ubahStatusTransfer memindahkan status transfer. Menerima hanya boleh dilakukan
+// oleh pengguna di gudang tujuan, sedangkan mengirim dan membatalkan oleh pengguna
+// di gudang asal.
+func (s *server) ubahStatusTransfer(c *gin.Context, ke, pesanSukses string) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+	t, err := s.transfer.GetTransfer(c.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Transfer tidak ditemukan"})
+			return
+		}
+		log.Printf("Error mengambil transfer %d: %v", id, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil data transfer"})
+		return
+	}
+	gudangID := t.GudangAsalID
+	if ke == models.StatusTransferDiterima {
+		gudangID = t.GudangTujuanID
+	}
+	if !s.bolehGudang(c, gudangID) {
+		return
+	}
+
+	if err := s.transfer.UbahStatusTransfer(c.Request.Context(), id, ke, aktor(c)); err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Transfer tidak ditemukan"})
+			return
+		}
+		if errors.Is(err, store.ErrInvalidTransition) {
+			c.JSON(http.StatusConflict, gin.H{"error": "Status transfer tidak bisa diubah: " + err.Error()})
+			return
+		}
+		if errors.Is(err, store.ErrStokTidakCukup) {
+			c.JSON(http.StatusConflict, gin.H{"error": "Transfer tidak bisa dikirim: " + err.Error()})
+			return
+		}
+		log.Printf("Error mengubah status transfer %d: %v", id, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengubah status transfer"})
+		return
+	}
+	var sesudah any
+	if baru, err := s.transfer.GetTransfer(c.Request.Context(), id); err == nil {
+		sesudah = baru
+	} else {
+		log.Printf("Error mengambil transfer %d untuk audit: %v", id, err)
+	}
+	s.catatAudit(c, "transfer", id, models.AuditUbah, t, sesudah)
+	c.JSON(http.StatusOK, gin.H{"message": pesanSukses, "status": ke})
+}
+
+func (s *server) getStokDalamPerjalananHandler(c *gin.Context) {
+	l, ok := s.gudangSaya(c)
+	if !ok {
+		return
+	}
+	daftar, err := s.transfer.StokDalamPerjalanan(c.Request.Context())
+	if err != nil {
+		log.Printf("Error mengambil stok dalam perjalanan: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil data stok dalam perjalanan"})
+		return
+	}
+	c.JSON(http.StatusOK, saring(daftar, func(d models.StokDalamPerjalananResponse) bool {
+		return l.boleh(d.GudangAsalID) || l.boleh(d.GudangTujuanID)
+	}))
+}
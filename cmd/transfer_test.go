@@ -0,0 +1,85 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"scm-api/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestTransferStok(t *testing.T) {
+	p := pengujiBaru(t)
+	p.dataDasar()
+	p.harus(http.StatusOK, "admin", "POST", "/api/stok/adjust", gin.H{"produk_id": 1, "gudang_id": 1, "jumlah": 5})
+	p.harus(http.StatusOK, "admin", "POST", "/api/stok/adjust", gin.H{"produk_id": 2, "gudang_id": 1, "jumlah": 1})
+
+	buat := func(jumlah1, jumlah2 int) int64 {
+		t.Helper()
+		var tr models.Transfer
+		p.decode(p.harus(http.StatusCreated, "gudang", "POST", "/api/transfer", gin.H{
+			"gudang_asal_id": 1, "gudang_tujuan_id": 2,
+			"details": []gin.H{{"produk_id": 1, "jumlah": jumlah1}, {"produk_id": 2, "jumlah": jumlah2}},
+		}), &tr)
+		return tr.TransferID
+	}
+	dalamPerjalanan := func() []models.StokDalamPerjalananResponse {
+		t.Helper()
+		var daftar []models.StokDalamPerjalananResponse
+		p.decode(p.harus(http.StatusOK, "gudang", "GET", "/api/transfer/dalam-perjalanan", nil), &daftar)
+		return daftar
+	}
+
+	// Produk 2 hanya ada 1, jadi seluruh pengiriman ditolak dan produk 1 juga tidak berkurang
+	kurang := buat(3, 2)
+	p.harus(http.StatusConflict, "gudang", "PUT", fmt.Sprintf("/api/transfer/%d/kirim", kurang), nil)
+	if n1, n2 := p.stok(1, 1), p.stok(2, 1); n1 != 5 || n2 != 1 {
+		t.Errorf("stok gudang asal setelah pengiriman ditolak = %d dan %d, ingin 5 dan 1", n1, n2)
+	}
+	var tr models.Transfer
+	p.decode(p.harus(http.StatusOK, "gudang", "GET", fmt.Sprintf("/api/transfer/%d", kurang), nil), &tr)
+	if tr.Status != models.StatusTransferDraft {
+		t.Errorf("status transfer yang ditolak = %q, ingin Draft", tr.Status)
+	}
+	if d := dalamPerjalanan(); len(d) != 0 {
+		t.Errorf("dalam perjalanan setelah pengiriman ditolak = %+v, ingin kosong", d)
+	}
+	p.harus(http.StatusOK, "gudang", "PUT", fmt.Sprintf("/api/transfer/%d/batal", kurang), nil)
+
+	id := buat(3, 1)
+	p.harus(http.StatusOK, "gudang", "PUT", fmt.Sprintf("/api/transfer/%d/kirim", id), nil)
+	if n1, n2 := p.stok(1, 1), p.stok(2, 1); n1 != 2 || n2 != 0 {
+		t.Errorf("stok gudang asal setelah dikirim = %d dan %d, ingin 2 dan 0", n1, n2)
+	}
+	if n := p.stok(1, 2); n != 0 {
+		t.Errorf("stok gudang tujuan sebelum diterima = %d, ingin 0", n)
+	}
+	d := dalamPerjalanan()
+	if len(d) != 2 || d[0].ProdukID != 1 || d[0].Jumlah != 3 || d[1].ProdukID != 2 || d[1].Jumlah != 1 {
+		t.Fatalf("dalam perjalanan = %+v, ingin produk 1 sebanyak 3 dan produk 2 sebanyak 1", d)
+	}
+	for _, baris := range d {
+		if baris.GudangAsalID != 1 || baris.GudangTujuanID != 2 {
+			t.Errorf("dalam perjalanan = %+v, ingin dari gudang 1 ke gudang 2", baris)
+		}
+	}
+	p.harus(http.StatusConflict, "gudang", "PUT", fmt.Sprintf("/api/transfer/%d/batal", id), nil)
+
+	// Hanya pengguna di gudang tujuan yang boleh menerima
+	p.harus(http.StatusForbidden, "gudang", "PUT", fmt.Sprintf("/api/transfer/%d/terima", id), nil)
+	p.aksesGudang("gudang", 1, 2)
+	p.harus(http.StatusOK, "gudang", "PUT", fmt.Sprintf("/api/transfer/%d/terima", id), nil)
+	if n1, n2 := p.stok(1, 2), p.stok(2, 2); n1 != 3 || n2 != 1 {
+		t.Errorf("stok gudang tujuan setelah diterima = %d dan %d, ingin 3 dan 1", n1, n2)
+	}
+	if d := dalamPerjalanan(); len(d) != 0 {
+		t.Errorf("dalam perjalanan setelah diterima = %+v, ingin kosong", d)
+	}
+	p.harus(http.StatusConflict, "gudang", "PUT", fmt.Sprintf("/api/transfer/%d/terima", id), nil)
+	if n := p.stok(1, 2); n != 3 {
+		t.Errorf("stok gudang tujuan setelah diterima dua kali = %d, ingin 3", n)
+	}
+	p.harus(http.StatusNotFound, "gudang", "PUT", "/api/transfer/999/kirim", nil)
+}
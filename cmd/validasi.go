@@ -0,0 +1,37 @@
+package main
+
+import (
+	"math"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// fieldErrors mengumpulkan pesan kesalahan per field, misalnya "details[0].jumlah".
+// Hanya pesan pertama untuk setiap field yang disimpan.
+type fieldErrors map[string]string
+
+func (f fieldErrors) add(field, pesan string) {
+	if _, ok := f[field]; !ok {
+		f[field] = pesan
+	}
+}
+
+// respond mengirim 400 berisi seluruh kesalahan field jika ada, dan mengembalikan true jika respons sudah dikirim
+func (f fieldErrors) respond(c *gin.Context) bool {
+	if len(f) == 0 {
+		return false
+	}
+	c.JSON(http.StatusBadRequest, gin.H{"error": "Validasi gagal", "fields": f})
+	return true
+}
+
+// bulatkanRupiah membulatkan nilai uang ke dua angka desimal, sesuai kolom DECIMAL(15,2)
+func bulatkanRupiah(v float64) float64 {
+	return math.Round(v*100) / 100
+}
+
+// samaRupiah membandingkan dua nilai uang setelah dibulatkan ke dua angka desimal
+func samaRupiah(a, b float64) bool {
+	return bulatkanRupiah(a) == bulatkanRupiah(b)
+}
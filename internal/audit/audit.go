@@ -0,0 +1,86 @@
+// file: internal/audit/audit.go
+
+// Package audit menyiapkan entri jejak audit: isi entitas sebelum dan sesudah
+// perubahan disimpan sebagai JSON, lalu field yang berbeda dihitung di sini.
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"scm-api/internal/models"
+)
+
+// Perubahan adalah nilai satu field sebelum dan sesudah perubahan
+type Perubahan struct {
+	Sebelum any `json:"sebelum"`
+	Sesudah any `json:"sesudah"`
+}
+
+// Entri membuat entri audit untuk satu entitas. sebelum bernilai nil untuk aksi
+// buat dan sesudah bernilai nil untuk aksi hapus.
+func Entri(aktor, entitas string, entitasID int64, aksi string, sebelum, sesudah any) (models.AuditLog, error) {
+	e := models.AuditLog{Aktor: aktor, Entitas: entitas, EntitasID: entitasID, Aksi: aksi}
+	var err error
+	if e.Sebelum, err = marshal(sebelum); err != nil {
+		return e, err
+	}
+	if e.Sesudah, err = marshal(sesudah); err != nil {
+		return e, err
+	}
+	beda, err := Diff(e.Sebelum, e.Sesudah)
+	if err != nil {
+		return e, err
+	}
+	if len(beda) > 0 {
+		if e.Perubahan, err = json.Marshal(beda); err != nil {
+			return e, err
+		}
+	}
+	return e, nil
+}
+
+// Diff membandingkan dua objek JSON per field tingkat atas dan mengembalikan field
+// yang nilainya berbeda. Dokumen kosong dianggap objek tanpa field, sehingga pada
+// aksi buat atau hapus semua field ikut tercatat.
+func Diff(sebelum, sesudah json.RawMessage) (map[string]Perubahan, error) {
+	a, err := objek(sebelum)
+	if err != nil {
+		return nil, err
+	}
+	b, err := objek(sesudah)
+	if err != nil {
+		return nil, err
+	}
+	beda := make(map[string]Perubahan)
+	for k, va := range a {
+		if vb, ok := b[k]; !ok || !reflect.DeepEqual(va, vb) {
+			beda[k] = Perubahan{Sebelum: va, Sesudah: b[k]}
+		}
+	}
+	for k, vb := range b {
+		if _, ok := a[k]; !ok {
+			beda[k] = Perubahan{Sesudah: vb}
+		}
+	}
+	return beda, nil
+}
+
+func marshal(v any) (json.RawMessage, error) {
+	if v == nil {
+		return nil, nil
+	}
+	return json.Marshal(v)
+}
+
+func objek(doc json.RawMessage) (map[string]any, error) {
+	m := make(map[string]any)
+	if len(doc) == 0 || string(doc) == "null" {
+		return m, nil
+	}
+	if err := json.Unmarshal(doc, &m); err != nil {
+		return nil, fmt.Errorf("audit: isi entitas harus berupa objek JSON: %w", err)
+	}
+	return m, nil
+}
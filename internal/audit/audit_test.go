@@ -0,0 +1,61 @@
+package audit
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"scm-api/internal/models"
+)
+
+type barang struct {
+	Nama  string  `json:"nama"`
+	Harga float64 `json:"harga"`
+	Catat *string `json:"catatan"`
+}
+
+func TestEntri(t *testing.T) {
+	catatan := "lama"
+	sebelum := barang{Nama: "Kopi", Harga: 1000, Catat: &catatan}
+	sesudah := barang{Nama: "Kopi", Harga: 1200}
+
+	e, err := Entri("admin", "produk", 7, models.AuditUbah, sebelum, sesudah)
+	if err != nil {
+		t.Fatalf("Entri ubah: %v", err)
+	}
+	if e.Aktor != "admin" || e.Entitas != "produk" || e.EntitasID != 7 || e.Aksi != models.AuditUbah {
+		t.Errorf("Entri ubah = %+v", e)
+	}
+	var beda map[string]Perubahan
+	if err := json.Unmarshal(e.Perubahan, &beda); err != nil {
+		t.Fatalf("perubahan %s: %v", e.Perubahan, err)
+	}
+	ingin := map[string]Perubahan{
+		"harga":   {Sebelum: 1000.0, Sesudah: 1200.0},
+		"catatan": {Sebelum: "lama", Sesudah: nil},
+	}
+	if !reflect.DeepEqual(beda, ingin) {
+		t.Errorf("perubahan = %+v, ingin %+v", beda, ingin)
+	}
+
+	// Buat dan hapus mencatat semua field; isi yang sama tidak mencatat perubahan
+	e, err = Entri("admin", "produk", 7, models.AuditBuat, nil, sesudah)
+	if err != nil || e.Sebelum != nil || len(e.Sesudah) == 0 {
+		t.Fatalf("Entri buat = %+v, %v", e, err)
+	}
+	if beda, _ := Diff(e.Sebelum, e.Sesudah); len(beda) != 3 || beda["nama"].Sesudah != "Kopi" {
+		t.Errorf("diff buat = %+v, ingin tiga field baru", beda)
+	}
+	e, err = Entri("admin", "produk", 7, models.AuditHapus, sebelum, nil)
+	if err != nil || e.Sesudah != nil || len(e.Perubahan) == 0 {
+		t.Errorf("Entri hapus = %+v, %v", e, err)
+	}
+	e, err = Entri("admin", "produk", 7, models.AuditUbah, sesudah, sesudah)
+	if err != nil || e.Perubahan != nil {
+		t.Errorf("Entri tanpa perubahan = %+v, %v; ingin perubahan kosong", e, err)
+	}
+
+	if _, err := Entri("admin", "produk", 7, models.AuditBuat, nil, []int{1}); err == nil {
+		t.Error("Entri dengan isi bukan objek tidak ditolak")
+	}
+}
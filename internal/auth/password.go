@@ -0,0 +1,33 @@
+// file: internal/auth/password.go
+
+package auth
+
+import (
+	"golang.org/x/crypto/bcrypt"
+)
+
+// MinPassword adalah panjang minimal password pengguna
+const MinPassword = 8
+
+// hashPalsu dipakai saat username tidak ditemukan agar waktu respons login
+// sama dengan saat password salah
+var hashPalsu, _ = bcrypt.GenerateFromPassword([]byte("bukan-password-sungguhan"), bcrypt.DefaultCost)
+
+// HashPassword menghasilkan hash bcrypt untuk disimpan di tabel pengguna
+func HashPassword(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
+// CocokPassword membandingkan password dengan hash bcrypt.
+// Hash kosong tetap dibandingkan dengan hash palsu supaya waktunya tidak membocorkan apa pun.
+func CocokPassword(hash, password string) bool {
+	if hash == "" {
+		bcrypt.CompareHashAndPassword(hashPalsu, []byte(password))
+		return false
+	}
+	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil
+}
@@ -0,0 +1,26 @@
+package auth
+
+import "testing"
+
+func TestPassword(t *testing.T) {
+	hash, err := HashPassword("rahasia123")
+	if err != nil {
+		t.Fatalf("HashPassword: %v", err)
+	}
+	if hash == "rahasia123" {
+		t.Fatal("password disimpan apa adanya")
+	}
+	if !CocokPassword(hash, "rahasia123") {
+		t.Error("password yang benar ditolak")
+	}
+	for _, tc := range []struct{ hash, password string }{
+		{hash, "rahasia124"},
+		{hash, ""},
+		{"", "rahasia123"},
+		{"bukan-hash-bcrypt", "rahasia123"},
+	} {
+		if CocokPassword(tc.hash, tc.password) {
+			t.Errorf("CocokPassword(%q, %q) diterima", tc.hash, tc.password)
+		}
+	}
+}
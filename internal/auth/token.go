@@ -0,0 +1,116 @@
+// file: internal/auth/token.go
+
+// Package auth berisi pembuatan dan verifikasi token login (JWT HS256),
+// token refresh, serta hash password.
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+)
+
+// ErrTokenTidakValid dikembalikan ketika token rusak, tanda tangannya salah, atau sudah kedaluwarsa
+var ErrTokenTidakValid = errors.New("token tidak valid")
+
+// header JWT selalu sama karena hanya HS256 yang didukung
+var jwtHeader = base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`))
+
+// Claims adalah isi access token
+type Claims struct {
+	PenggunaID int64  `json:"uid"`
+	Username   string `json:"sub"`
+	// Versi adalah versi_token pengguna saat token diterbitkan
+	Versi     int   `json:"ver"`
+	IssuedAt  int64 `json:"iat"`
+	ExpiresAt int64 `json:"exp"`
+}
+
+// Tokens membuat dan memverifikasi access token dengan satu kunci rahasia
+type Tokens struct {
+	secret     []byte
+	AccessTTL  time.Duration
+	RefreshTTL time.Duration
+
+	// now bisa diganti saat pengujian agar waktu dapat diprediksi
+	now func() time.Time
+}
+
+// New membuat Tokens dari kunci rahasia dan masa berlaku token
+func New(secret string, accessTTL, refreshTTL time.Duration) *Tokens {
+	return &Tokens{
+		secret:     []byte(secret),
+		AccessTTL:  accessTTL,
+		RefreshTTL: refreshTTL,
+		now:        time.Now,
+	}
+}
+
+// BuatAccessToken menandatangani access token untuk pengguna yang berlaku selama AccessTTL
+func (t *Tokens) BuatAccessToken(penggunaID int64, username string, versi int) (string, error) {
+	now := t.now()
+	payload, err := json.Marshal(Claims{
+		PenggunaID: penggunaID,
+		Username:   username,
+		Versi:      versi,
+		IssuedAt:   now.Unix(),
+		ExpiresAt:  now.Add(t.AccessTTL).Unix(),
+	})
+	if err != nil {
+		return "", err
+	}
+	unsigned := jwtHeader + "." + base64.RawURLEncoding.EncodeToString(payload)
+	return unsigned + "." + t.sign(unsigned), nil
+}
+
+// VerifikasiAccessToken memeriksa tanda tangan dan masa berlaku token lalu mengembalikan isinya
+func (t *Tokens) VerifikasiAccessToken(token string) (Claims, error) {
+	var claims Claims
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 || parts[0] != jwtHeader {
+		return claims, ErrTokenTidakValid
+	}
+	if !hmac.Equal([]byte(parts[2]), []byte(t.sign(parts[0]+"."+parts[1]))) {
+		return claims, ErrTokenTidakValid
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return claims, ErrTokenTidakValid
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return claims, ErrTokenTidakValid
+	}
+	if claims.PenggunaID < 1 || t.now().Unix() >= claims.ExpiresAt {
+		return claims, ErrTokenTidakValid
+	}
+	return claims, nil
+}
+
+func (t *Tokens) sign(unsigned string) string {
+	mac := hmac.New(sha256.New, t.secret)
+	mac.Write([]byte(unsigned))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// BuatRefreshToken membuat token refresh acak. Yang dikirim ke klien adalah token,
+// sedangkan yang disimpan di database hanya hash-nya.
+func BuatRefreshToken() (token, hash string, err error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", err
+	}
+	token = base64.RawURLEncoding.EncodeToString(b)
+	return token, HashRefreshToken(token), nil
+}
+
+// HashRefreshToken menghitung hash token refresh untuk dicari di database
+func HashRefreshToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
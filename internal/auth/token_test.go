@@ -0,0 +1,101 @@
+package auth
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func tokensUji(secret string, sekarang time.Time) *Tokens {
+	t := New(secret, 15*time.Minute, 24*time.Hour)
+	t.now = func() time.Time { return sekarang }
+	return t
+}
+
+func TestAccessToken(t *testing.T) {
+	waktu := time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC)
+	tk := tokensUji("rahasia-yang-cukup-panjang", waktu)
+	token, err := tk.BuatAccessToken(7, "budi", 3)
+	if err != nil {
+		t.Fatalf("BuatAccessToken: %v", err)
+	}
+	claims, err := tk.VerifikasiAccessToken(token)
+	if err != nil {
+		t.Fatalf("VerifikasiAccessToken: %v", err)
+	}
+	ingin := Claims{PenggunaID: 7, Username: "budi", Versi: 3, IssuedAt: waktu.Unix(), ExpiresAt: waktu.Add(15 * time.Minute).Unix()}
+	if claims != ingin {
+		t.Errorf("claims = %+v, ingin %+v", claims, ingin)
+	}
+
+	bagian := strings.Split(token, ".")
+	enc := base64.RawURLEncoding.EncodeToString
+	payloadAdmin := enc([]byte(`{"uid":1,"sub":"admin","ver":0,"iat":0,"exp":9999999999}`))
+	tolak := map[string]string{
+		"kosong":              "",
+		"bukan tiga bagian":   bagian[0] + "." + bagian[1],
+		"tanda tangan diubah": bagian[0] + "." + bagian[1] + "." + enc([]byte("palsu")),
+		"payload diubah":      bagian[0] + "." + payloadAdmin + "." + bagian[2],
+		"alg none":            enc([]byte(`{"alg":"none","typ":"JWT"}`)) + "." + payloadAdmin + ".",
+		"alg none ditandatangani": func() string {
+			unsigned := enc([]byte(`{"alg":"none","typ":"JWT"}`)) + "." + payloadAdmin
+			return unsigned + "." + tk.sign(unsigned)
+		}(),
+		"alg HS512": func() string {
+			unsigned := enc([]byte(`{"alg":"HS512","typ":"JWT"}`)) + "." + bagian[1]
+			return unsigned + "." + tk.sign(unsigned)
+		}(),
+		"kunci lain": func() string {
+			lain, _ := tokensUji("kunci-lain-yang-juga-panjang", waktu).BuatAccessToken(7, "budi", 3)
+			return lain
+		}(),
+		"payload bukan JSON": func() string {
+			unsigned := jwtHeader + "." + enc([]byte("bukan json"))
+			return unsigned + "." + tk.sign(unsigned)
+		}(),
+		"tanpa pengguna": func() string {
+			unsigned := jwtHeader + "." + enc([]byte(`{"uid":0,"exp":9999999999}`))
+			return unsigned + "." + tk.sign(unsigned)
+		}(),
+	}
+	for nama, token := range tolak {
+		if _, err := tk.VerifikasiAccessToken(token); !errors.Is(err, ErrTokenTidakValid) {
+			t.Errorf("%s: galat %v, ingin ErrTokenTidakValid", nama, err)
+		}
+	}
+}
+
+func TestAccessTokenKedaluwarsa(t *testing.T) {
+	waktu := time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC)
+	tk := tokensUji("rahasia-yang-cukup-panjang", waktu)
+	token, err := tk.BuatAccessToken(7, "budi", 0)
+	if err != nil {
+		t.Fatalf("BuatAccessToken: %v", err)
+	}
+	for selisih, berlaku := range map[time.Duration]bool{
+		15*time.Minute - time.Second: true,
+		15 * time.Minute:             false,
+		time.Hour:                    false,
+	} {
+		tk.now = func() time.Time { return waktu.Add(selisih) }
+		if _, err := tk.VerifikasiAccessToken(token); (err == nil) != berlaku {
+			t.Errorf("%v setelah terbit: galat %v, ingin berlaku %v", selisih, err, berlaku)
+		}
+	}
+}
+
+func TestRefreshToken(t *testing.T) {
+	token, hash, err := BuatRefreshToken()
+	if err != nil {
+		t.Fatalf("BuatRefreshToken: %v", err)
+	}
+	if hash != HashRefreshToken(token) || hash == token || len(hash) != 64 {
+		t.Errorf("hash %q bukan SHA-256 hex dari token %q", hash, token)
+	}
+	lain, _, err := BuatRefreshToken()
+	if err != nil || lain == token {
+		t.Errorf("dua token refresh sama: %q", token)
+	}
+}
@@ -0,0 +1,193 @@
+// file: internal/barcode/ean13.go
+
+package barcode
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"strings"
+)
+
+// Pola modul setiap digit. Digit kiri memakai set L atau G sesuai digit pertama,
+// digit kanan memakai set R (kebalikan L).
+var (
+	polaL = [10]string{"0001101", "0011001", "0010011", "0111101", "0100011", "0110001", "0101111", "0111011", "0110111", "0001011"}
+	polaG = [10]string{"0100111", "0110011", "0011011", "0100001", "0011101", "0111001", "0000101", "0010001", "0001001", "0010111"}
+	polaR = [10]string{"1110010", "1100110", "1101100", "1000010", "1011100", "1001110", "1010000", "1000100", "1001000", "1110100"}
+	// paritas menentukan set L/G untuk enam digit kiri berdasarkan digit pertama
+	paritas = [10]string{"LLLLLL", "LLGLGG", "LLGGLG", "LLGGGL", "LGLLGG", "LGGLLG", "LGGGLL", "LGLGLG", "LGLGGL", "LGGLGL"}
+)
+
+// Ukuran label dalam modul (lebar satu batang tersempit). Zona tenang kiri 11 dan
+// kanan 7 modul sesuai spesifikasi; batang pembatas lebih panjang dari batang data.
+const (
+	modulKiri      = 11
+	modulKanan     = 7
+	modulBarcode   = 95
+	modulLebar     = modulKiri + modulBarcode + modulKanan
+	modulAtas      = 5
+	tinggiBatang   = 60
+	tinggiPembatas = 65
+	tinggiTeks     = 7
+	modulTinggi    = modulAtas + tinggiBatang + 1 + tinggiTeks + 4
+)
+
+// SkalaMaks membatasi ukuran gambar yang dibuat
+const SkalaMaks = 10
+
+// Modul mengembalikan 95 modul EAN-13 (true berarti batang hitam)
+func Modul(ean13 string) ([]bool, error) {
+	if err := periksaEAN13(ean13); err != nil {
+		return nil, err
+	}
+	var b strings.Builder
+	b.WriteString("101")
+	p := paritas[ean13[0]-'0']
+	for i := 1; i <= 6; i++ {
+		d := ean13[i] - '0'
+		if p[i-1] == 'L' {
+			b.WriteString(polaL[d])
+		} else {
+			b.WriteString(polaG[d])
+		}
+	}
+	b.WriteString("01010")
+	for i := 7; i <= 12; i++ {
+		b.WriteString(polaR[ean13[i]-'0'])
+	}
+	b.WriteString("101")
+
+	modul := make([]bool, 0, modulBarcode)
+	for _, c := range b.String() {
+		modul = append(modul, c == '1')
+	}
+	return modul, nil
+}
+
+func periksaEAN13(ean13 string) error {
+	if len(ean13) != 13 {
+		return ErrFormat
+	}
+	_, err := Normalisasi(ean13)
+	return err
+}
+
+// pembatas menandai modul batang pembatas awal, tengah, dan akhir
+func pembatas(i int) bool {
+	return i < 3 || (i >= 45 && i < 50) || i >= 92
+}
+
+// posisiDigit mengembalikan modul kiri sel setiap digit (lebar sel 7 modul).
+// Digit pertama dicetak di zona tenang kiri.
+func posisiDigit(i int) int {
+	switch {
+	case i == 0:
+		return modulKiri - 7
+	case i <= 6:
+		return modulKiri + 3 + (i-1)*7
+	default:
+		return modulKiri + 3 + 42 + 5 + (i-7)*7
+	}
+}
+
+// PNG menggambar label EAN-13 hitam putih; skala adalah lebar satu modul dalam piksel
+func PNG(ean13 string, skala int) ([]byte, error) {
+	modul, err := Modul(ean13)
+	if err != nil {
+		return nil, err
+	}
+	if skala < 1 || skala > SkalaMaks {
+		return nil, fmt.Errorf("skala harus 1 sampai %d", SkalaMaks)
+	}
+	img := image.NewPaletted(image.Rect(0, 0, modulLebar*skala, modulTinggi*skala), color.Palette{color.White, color.Black})
+	kotak := func(x, y, w, h int) {
+		for py := y * skala; py < (y+h)*skala; py++ {
+			for px := x * skala; px < (x+w)*skala; px++ {
+				img.SetColorIndex(px, py, 1)
+			}
+		}
+	}
+	for i, hitam := range modul {
+		if !hitam {
+			continue
+		}
+		tinggi := tinggiBatang
+		if pembatas(i) {
+			tinggi = tinggiPembatas
+		}
+		kotak(modulKiri+i, modulAtas, 1, tinggi)
+	}
+	yTeks := modulAtas + tinggiBatang + 1
+	for i := 0; i < 13; i++ {
+		glyph := hurufDigit[ean13[i]-'0']
+		x0 := posisiDigit(i) + 1
+		for gy, baris := range glyph {
+			for gx, c := range baris {
+				if c == '1' {
+					kotak(x0+gx, yTeks+gy, 1, 1)
+				}
+			}
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// SVG menggambar label EAN-13 sebagai vektor, cocok untuk dicetak di ukuran berapa pun
+func SVG(ean13 string, skala int) ([]byte, error) {
+	modul, err := Modul(ean13)
+	if err != nil {
+		return nil, err
+	}
+	if skala < 1 || skala > SkalaMaks {
+		return nil, fmt.Errorf("skala harus 1 sampai %d", SkalaMaks)
+	}
+	var b bytes.Buffer
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" shape-rendering="crispEdges">`,
+		modulLebar*skala, modulTinggi*skala, modulLebar, modulTinggi)
+	fmt.Fprintf(&b, `<rect width="%d" height="%d" fill="#fff"/><g fill="#000">`, modulLebar, modulTinggi)
+	// batang hitam yang bersebelahan dengan tinggi sama digabung menjadi satu rect
+	for i := 0; i < len(modul); {
+		if !modul[i] {
+			i++
+			continue
+		}
+		j := i
+		for j < len(modul) && modul[j] && pembatas(j) == pembatas(i) {
+			j++
+		}
+		tinggi := tinggiBatang
+		if pembatas(i) {
+			tinggi = tinggiPembatas
+		}
+		fmt.Fprintf(&b, `<rect x="%d" y="%d" width="%d" height="%d"/>`, modulKiri+i, modulAtas, j-i, tinggi)
+		i = j
+	}
+	fmt.Fprintf(&b, `</g><g font-family="monospace" font-size="%d" text-anchor="middle" fill="#000">`, tinggiTeks+2)
+	for i := 0; i < 13; i++ {
+		fmt.Fprintf(&b, `<text x="%.1f" y="%d">%c</text>`, float64(posisiDigit(i))+3.5, modulAtas+tinggiBatang+1+tinggiTeks, ean13[i])
+	}
+	b.WriteString(`</g></svg>`)
+	return b.Bytes(), nil
+}
+
+// hurufDigit adalah huruf bitmap 5x7 untuk angka di bawah batang pada label PNG,
+// karena pustaka standar tidak menyediakan font
+var hurufDigit = [10][7]string{
+	{"01110", "10001", "10011", "10101", "11001", "10001", "01110"},
+	{"00100", "01100", "00100", "00100", "00100", "00100", "01110"},
+	{"01110", "10001", "00001", "00010", "00100", "01000", "11111"},
+	{"11111", "00010", "00100", "00010", "00001", "10001", "01110"},
+	{"00010", "00110", "01010", "10010", "11111", "00010", "00010"},
+	{"11111", "10000", "11110", "00001", "00001", "10001", "01110"},
+	{"00110", "01000", "10000", "11110", "10001", "10001", "01110"},
+	{"11111", "00001", "00010", "00100", "01000", "01000", "01000"},
+	{"01110", "10001", "10001", "01110", "10001", "10001", "01110"},
+	{"01110", "10001", "10001", "01111", "00001", "00010", "01100"},
+}
@@ -0,0 +1,83 @@
+package barcode
+
+import (
+	"bytes"
+	"errors"
+	"image/png"
+	"strings"
+	"testing"
+)
+
+func teksModul(modul []bool) string {
+	var b strings.Builder
+	for _, hitam := range modul {
+		if hitam {
+			b.WriteByte('1')
+		} else {
+			b.WriteByte('0')
+		}
+	}
+	return b.String()
+}
+
+func TestModul(t *testing.T) {
+	modul, err := Modul("4006381333931")
+	if err != nil {
+		t.Fatalf("Modul: %v", err)
+	}
+	got := teksModul(modul)
+	// Digit pertama 4 memberi paritas LGLLGG untuk enam digit kiri 006381,
+	// enam digit kanan 333931 memakai set R
+	ingin := "101" +
+		polaL[0] + polaG[0] + polaL[6] + polaL[3] + polaG[8] + polaG[1] +
+		"01010" +
+		polaR[3] + polaR[3] + polaR[3] + polaR[9] + polaR[3] + polaR[1] +
+		"101"
+	if got != ingin {
+		t.Errorf("Modul(4006381333931) =\n%s\ningin\n%s", got, ingin)
+	}
+	if len(modul) != modulBarcode {
+		t.Errorf("panjang modul = %d, ingin %d", len(modul), modulBarcode)
+	}
+
+	for kode, galat := range map[string]error{
+		"036000291452":   ErrFormat,
+		"00036000291452": ErrFormat,
+		"4006381333932":  ErrDigitPemeriksa,
+	} {
+		if _, err := Modul(kode); !errors.Is(err, galat) {
+			t.Errorf("Modul(%q) galat %v, ingin %v", kode, err, galat)
+		}
+	}
+}
+
+func TestPNGDanSVG(t *testing.T) {
+	data, err := PNG("4006381333931", 2)
+	if err != nil {
+		t.Fatalf("PNG: %v", err)
+	}
+	cfg, err := png.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("png.DecodeConfig: %v", err)
+	}
+	if cfg.Width != modulLebar*2 || cfg.Height != modulTinggi*2 {
+		t.Errorf("ukuran PNG = %dx%d, ingin %dx%d", cfg.Width, cfg.Height, modulLebar*2, modulTinggi*2)
+	}
+
+	svg, err := SVG("4006381333931", 1)
+	if err != nil {
+		t.Fatalf("SVG: %v", err)
+	}
+	if !bytes.HasPrefix(svg, []byte("<svg")) || bytes.Count(svg, []byte("<text")) != 13 {
+		t.Errorf("SVG = %s, ingin satu svg dengan 13 digit", svg)
+	}
+
+	for _, skala := range []int{0, SkalaMaks + 1} {
+		if _, err := PNG("4006381333931", skala); err == nil {
+			t.Errorf("PNG dengan skala %d tidak ditolak", skala)
+		}
+		if _, err := SVG("4006381333931", skala); err == nil {
+			t.Errorf("SVG dengan skala %d tidak ditolak", skala)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+// file: internal/barcode/gtin.go
+
+// Package barcode memvalidasi kode GTIN (EAN-8, UPC-A, EAN-13, GTIN-14), membuat
+// kode EAN-13 internal untuk barang tanpa kode pabrik, dan menggambar label EAN-13
+// sebagai PNG atau SVG.
+package barcode
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	// ErrFormat dikembalikan jika kode bukan 8, 12, 13, atau 14 digit angka
+	ErrFormat = errors.New("barcode harus berupa 8, 12, 13, atau 14 digit angka")
+	// ErrDigitPemeriksa dikembalikan jika digit terakhir tidak sesuai perhitungan GS1
+	ErrDigitPemeriksa = errors.New("digit pemeriksa barcode salah")
+	// ErrBukanEAN13 dikembalikan jika GTIN-14 tidak bisa ditulis sebagai EAN-13
+	ErrBukanEAN13 = errors.New("hanya EAN-13, UPC-A, dan EAN-8 yang bisa dicetak sebagai EAN-13")
+)
+
+// DigitPemeriksa menghitung digit pemeriksa GS1 (modulo 10) untuk digit tanpa
+// digit pemeriksanya. Dari kanan, digit diberi bobot 3, 1, 3, 1, dan seterusnya.
+func DigitPemeriksa(digit string) byte {
+	jumlah := 0
+	for i := 0; i < len(digit); i++ {
+		d := int(digit[len(digit)-1-i] - '0')
+		if i%2 == 0 {
+			d *= 3
+		}
+		jumlah += d
+	}
+	return byte('0' + (10-jumlah%10)%10)
+}
+
+// Normalisasi memeriksa kode lalu mengembalikannya sebagai GTIN-14, yaitu ditambah
+// nol di depan sampai 14 digit. Bentuk ini dipakai untuk menyimpan dan mencari
+// barcode, sehingga UPC-A "036000291452" dan EAN-13 "0036000291452" dianggap sama.
+func Normalisasi(kode string) (string, error) {
+	kode = strings.TrimSpace(kode)
+	switch len(kode) {
+	case 8, 12, 13, 14:
+	default:
+		return "", ErrFormat
+	}
+	for _, r := range kode {
+		if r < '0' || r > '9' {
+			return "", ErrFormat
+		}
+	}
+	if DigitPemeriksa(kode[:len(kode)-1]) != kode[len(kode)-1] {
+		return "", ErrDigitPemeriksa
+	}
+	return strings.Repeat("0", 14-len(kode)) + kode, nil
+}
+
+// EAN13 mengubah GTIN-14 hasil Normalisasi menjadi 13 digit EAN-13
+func EAN13(gtin string) (string, error) {
+	if len(gtin) != 14 {
+		return "", ErrFormat
+	}
+	if gtin[0] != '0' {
+		return "", ErrBukanEAN13
+	}
+	return gtin[1:], nil
+}
+
+// Internal membuat EAN-13 untuk barang dalam toko dari awalan 20-29 (rentang GS1
+// untuk peredaran terbatas) dan nomor urut hingga 10 digit
+func Internal(awalan string, nomor int64) (string, error) {
+	if !AwalanInternalValid(awalan) {
+		return "", fmt.Errorf("awalan barcode internal harus 20 sampai 29, didapat %q", awalan)
+	}
+	if nomor < 0 || nomor > 9_999_999_999 {
+		return "", fmt.Errorf("nomor barcode internal harus 0 sampai 9999999999, didapat %d", nomor)
+	}
+	tanpaCek := fmt.Sprintf("%s%010d", awalan, nomor)
+	return tanpaCek + string(DigitPemeriksa(tanpaCek)), nil
+}
+
+// AwalanInternalValid memastikan awalan berada di rentang 20-29
+func AwalanInternalValid(awalan string) bool {
+	return len(awalan) == 2 && awalan[0] == '2' && awalan[1] >= '0' && awalan[1] <= '9'
+}
@@ -0,0 +1,93 @@
+package barcode
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestDigitPemeriksa(t *testing.T) {
+	for tanpaCek, ingin := range map[string]byte{
+		"400638133393":  '1', // EAN-13 4006381333931
+		"03600029145":   '2', // UPC-A 036000291452
+		"9638507":       '4', // EAN-8 96385074
+		"1001234567890": '2', // GTIN-14 10012345678902
+		"200000000000":  '8',
+	} {
+		if got := DigitPemeriksa(tanpaCek); got != ingin {
+			t.Errorf("DigitPemeriksa(%q) = %c, ingin %c", tanpaCek, got, ingin)
+		}
+	}
+}
+
+func TestNormalisasi(t *testing.T) {
+	for _, tc := range []struct {
+		kode  string
+		ingin string
+		galat error
+	}{
+		{"4006381333931", "04006381333931", nil},
+		{"036000291452", "00036000291452", nil},
+		{"0036000291452", "00036000291452", nil},
+		{" 036000291452 ", "00036000291452", nil},
+		{"96385074", "00000096385074", nil},
+		{"10012345678902", "10012345678902", nil},
+		{"4006381333932", "", ErrDigitPemeriksa},
+		{"036000291453", "", ErrDigitPemeriksa},
+		{"400638133393", "", ErrDigitPemeriksa},
+		{"40063813339", "", ErrFormat},
+		{"400638133393A", "", ErrFormat},
+		{"", "", ErrFormat},
+	} {
+		got, err := Normalisasi(tc.kode)
+		if got != tc.ingin || !errors.Is(err, tc.galat) {
+			t.Errorf("Normalisasi(%q) = %q, %v; ingin %q, %v", tc.kode, got, err, tc.ingin, tc.galat)
+		}
+	}
+}
+
+func TestEAN13(t *testing.T) {
+	for _, tc := range []struct {
+		gtin  string
+		ingin string
+		galat error
+	}{
+		{"00036000291452", "0036000291452", nil},
+		{"04006381333931", "4006381333931", nil},
+		{"10012345678902", "", ErrBukanEAN13},
+		{"4006381333931", "", ErrFormat},
+	} {
+		got, err := EAN13(tc.gtin)
+		if got != tc.ingin || !errors.Is(err, tc.galat) {
+			t.Errorf("EAN13(%q) = %q, %v; ingin %q, %v", tc.gtin, got, err, tc.ingin, tc.galat)
+		}
+	}
+}
+
+func TestInternal(t *testing.T) {
+	for _, tc := range []struct {
+		awalan string
+		nomor  int64
+		ingin  string
+	}{
+		{"20", 0, "2000000000008"},
+		{"20", 1, "2000000000015"},
+		{"29", 9_999_999_999, "2999999999991"},
+		{"19", 1, ""},
+		{"30", 1, ""},
+		{"2", 1, ""},
+		{"2a", 1, ""},
+		{"20", -1, ""},
+		{"20", 10_000_000_000, ""},
+	} {
+		got, err := Internal(tc.awalan, tc.nomor)
+		if got != tc.ingin || (err == nil) != (tc.ingin != "") {
+			t.Errorf("Internal(%q, %d) = %q, %v; ingin %q", tc.awalan, tc.nomor, got, err, tc.ingin)
+		}
+		if got == "" {
+			continue
+		}
+		if _, err := Normalisasi(got); err != nil {
+			t.Errorf("Internal(%q, %d) = %q tidak lolos Normalisasi: %v", tc.awalan, tc.nomor, got, err)
+		}
+	}
+}
@@ -0,0 +1,432 @@
+// file: internal/config/config.go
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"scm-api/internal/barcode"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// Config menampung seluruh konfigurasi aplikasi
+type Config struct {
+	Database  DatabaseConfig  `toml:"database" yaml:"database"`
+	Server    ServerConfig    `toml:"server" yaml:"server"`
+	CORS      CORSConfig      `toml:"cors" yaml:"cors"`
+	Auth      AuthConfig      `toml:"auth" yaml:"auth"`
+	Pembelian PembelianConfig `toml:"pembelian" yaml:"pembelian"`
+	Media     MediaConfig     `toml:"media" yaml:"media"`
+	Barcode   BarcodeConfig   `toml:"barcode" yaml:"barcode"`
+	Scorecard ScorecardConfig `toml:"scorecard" yaml:"scorecard"`
+}
+
+// DatabaseConfig berisi DSN dan pengaturan connection pool
+type DatabaseConfig struct {
+	DSN             string   `toml:"dsn" yaml:"dsn"`
+	MaxOpenConns    int      `toml:"max_open_conns" yaml:"max_open_conns"`
+	MaxIdleConns    int      `toml:"max_idle_conns" yaml:"max_idle_conns"`
+	ConnMaxLifetime Duration `toml:"conn_max_lifetime" yaml:"conn_max_lifetime"`
+	ConnMaxIdleTime Duration `toml:"conn_max_idle_time" yaml:"conn_max_idle_time"`
+}
+
+// ServerConfig berisi alamat yang didengarkan oleh server HTTP
+type ServerConfig struct {
+	ListenAddr string `toml:"listen_addr" yaml:"listen_addr"`
+}
+
+// CORSConfig berisi daftar origin yang boleh mengakses API
+type CORSConfig struct {
+	AllowOrigins []string `toml:"allow_origins" yaml:"allow_origins"`
+}
+
+// AuthConfig berisi kunci penandatangan JWT dan masa berlaku token
+type AuthConfig struct {
+	JWTSecret  string   `toml:"jwt_secret" yaml:"jwt_secret"`
+	AccessTTL  Duration `toml:"access_ttl" yaml:"access_ttl"`
+	RefreshTTL Duration `toml:"refresh_ttl" yaml:"refresh_ttl"`
+}
+
+// PembelianConfig berisi aturan bisnis pesanan pembelian
+type PembelianConfig struct {
+	// BatasNilai adalah total_biaya tertinggi yang boleh dibuat tanpa izin pembelian.nilai_besar.
+	// 0 berarti tanpa batas.
+	BatasNilai float64 `toml:"batas_nilai" yaml:"batas_nilai"`
+	// WajibPermintaan menutup POST /api/pembelian sehingga pesanan baru hanya bisa
+	// dibuat dari permintaan pembelian yang sudah disetujui
+	WajibPermintaan bool `toml:"wajib_permintaan" yaml:"wajib_permintaan"`
+}
+
+// MediaConfig mengatur penyimpanan dan penyajian gambar produk
+type MediaConfig struct {
+	// Backend adalah "lokal" (disk) atau "s3" (layanan yang kompatibel dengan S3)
+	Backend string `toml:"backend" yaml:"backend"`
+	// Dir adalah direktori berkas untuk backend lokal
+	Dir string `toml:"dir" yaml:"dir"`
+	// BaseURL adalah awalan URL yang disimpan di gambar_produk. Bawaannya /media,
+	// yang disajikan oleh server ini sendiri; bisa diganti dengan URL CDN atau bucket publik.
+	BaseURL string `toml:"base_url" yaml:"base_url"`
+	// MaxBytes adalah ukuran unggahan terbesar
+	MaxBytes int `toml:"max_bytes" yaml:"max_bytes"`
+	// Thumbnail adalah sisi terpanjang thumbnail dalam piksel
+	Thumbnail int      `toml:"thumbnail" yaml:"thumbnail"`
+	S3        S3Config `toml:"s3" yaml:"s3"`
+}
+
+// S3Config berisi alamat dan kredensial bucket untuk backend s3
+type S3Config struct {
+	Endpoint  string `toml:"endpoint" yaml:"endpoint"`
+	Region    string `toml:"region" yaml:"region"`
+	Bucket    string `toml:"bucket" yaml:"bucket"`
+	AccessKey string `toml:"access_key" yaml:"access_key"`
+	SecretKey string `toml:"secret_key" yaml:"secret_key"`
+	// PathStyle memakai endpoint/bucket/kunci alih-alih bucket.endpoint/kunci,
+	// yang dibutuhkan MinIO dan kebanyakan layanan lokal
+	PathStyle bool `toml:"path_style" yaml:"path_style"`
+}
+
+// BarcodeConfig berisi aturan barcode produk
+type BarcodeConfig struct {
+	// AwalanInternal adalah dua digit awal (20-29) EAN-13 yang dibuat untuk barang tanpa kode pabrik
+	AwalanInternal string `toml:"awalan_internal" yaml:"awalan_internal"`
+}
+
+// ScorecardConfig mengatur penilaian kinerja supplier
+type ScorecardConfig struct {
+	// Interval adalah jarak antarpenulisan ulang rating supplier dari scorecard; 0 mematikannya
+	Interval Duration `toml:"interval" yaml:"interval"`
+	// PeriodeHari adalah panjang periode bawaan scorecard, dihitung mundur dari hari ini
+	PeriodeHari int `toml:"periode_hari" yaml:"periode_hari"`
+}
+
+// Duration adalah time.Duration yang bisa dibaca dari teks seperti "3m" atau "90s"
+type Duration time.Duration
+
+// UnmarshalText mengurai durasi dari file konfigurasi
+func (d *Duration) UnmarshalText(text []byte) error {
+	v, err := time.ParseDuration(string(text))
+	if err != nil {
+		return err
+	}
+	*d = Duration(v)
+	return nil
+}
+
+// MinJWTSecret adalah panjang minimal kunci HS256
+const MinJWTSecret = 32
+
+// Default mengembalikan konfigurasi bawaan yang sama dengan nilai lama yang di-hard-code
+func Default() Config {
+	return Config{
+		Database: DatabaseConfig{
+			DSN:             "root:@tcp(127.0.0.1:3306)/prima?parseTime=true",
+			MaxOpenConns:    10,
+			MaxIdleConns:    10,
+			ConnMaxLifetime: Duration(3 * time.Minute),
+			ConnMaxIdleTime: Duration(1 * time.Minute),
+		},
+		Server: ServerConfig{
+			ListenAddr: ":8080",
+		},
+		CORS: CORSConfig{
+			AllowOrigins: []string{"http://127.0.0.1:8000"},
+		},
+		Auth: AuthConfig{
+			AccessTTL:  Duration(15 * time.Minute),
+			RefreshTTL: Duration(7 * 24 * time.Hour),
+		},
+		Pembelian: PembelianConfig{
+			BatasNilai: 10_000_000,
+		},
+		Media: MediaConfig{
+			Backend:   "lokal",
+			Dir:       "data/media",
+			BaseURL:   "/media",
+			MaxBytes:  5 << 20,
+			Thumbnail: 320,
+			S3: S3Config{
+				Region:    "us-east-1",
+				PathStyle: true,
+			},
+		},
+		Barcode: BarcodeConfig{
+			AwalanInternal: "20",
+		},
+		Scorecard: ScorecardConfig{
+			Interval:    Duration(24 * time.Hour),
+			PeriodeHari: 365,
+		},
+	}
+}
+
+// Load membaca konfigurasi dengan urutan prioritas:
+// nilai bawaan, lalu file (jika path tidak kosong), lalu environment variable.
+// Bagian database divalidasi sebelum dikembalikan karena semua perintah membutuhkannya;
+// sisanya diperiksa Validate sebelum server dijalankan.
+func Load(path string) (Config, error) {
+	cfg := Default()
+
+	if path != "" {
+		if err := loadFile(path, &cfg); err != nil {
+			return cfg, err
+		}
+	}
+
+	if err := applyEnv(&cfg); err != nil {
+		return cfg, err
+	}
+
+	if err := cfg.ValidateDatabase(); err != nil {
+		return cfg, err
+	}
+	return cfg, nil
+}
+
+// loadFile membaca file TOML atau YAML berdasarkan ekstensinya
+func loadFile(path string, cfg *Config) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("tidak bisa membaca file konfigurasi %s: %w", path, err)
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".toml":
+		err = toml.Unmarshal(data, cfg)
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, cfg)
+	default:
+		return fmt.Errorf("format file konfigurasi %s tidak dikenal (gunakan .toml, .yaml, atau .yml)", path)
+	}
+	if err != nil {
+		return fmt.Errorf("file konfigurasi %s tidak valid: %w", path, err)
+	}
+	return nil
+}
+
+// applyEnv menimpa konfigurasi dengan environment variable berawalan SCM_
+func applyEnv(cfg *Config) error {
+	var errs []error
+
+	if v, ok := os.LookupEnv("SCM_DB_DSN"); ok {
+		cfg.Database.DSN = v
+	}
+	envInt("SCM_DB_MAX_OPEN_CONNS", &cfg.Database.MaxOpenConns, &errs)
+	envInt("SCM_DB_MAX_IDLE_CONNS", &cfg.Database.MaxIdleConns, &errs)
+	envDuration("SCM_DB_CONN_MAX_LIFETIME", &cfg.Database.ConnMaxLifetime, &errs)
+	envDuration("SCM_DB_CONN_MAX_IDLE_TIME", &cfg.Database.ConnMaxIdleTime, &errs)
+
+	if v, ok := os.LookupEnv("SCM_LISTEN_ADDR"); ok {
+		cfg.Server.ListenAddr = v
+	}
+	if v, ok := os.LookupEnv("SCM_CORS_ORIGINS"); ok {
+		cfg.CORS.AllowOrigins = splitList(v)
+	}
+
+	if v, ok := os.LookupEnv("SCM_JWT_SECRET"); ok {
+		cfg.Auth.JWTSecret = v
+	}
+	envDuration("SCM_JWT_ACCESS_TTL", &cfg.Auth.AccessTTL, &errs)
+	envDuration("SCM_JWT_REFRESH_TTL", &cfg.Auth.RefreshTTL, &errs)
+	envFloat("SCM_PEMBELIAN_BATAS_NILAI", &cfg.Pembelian.BatasNilai, &errs)
+	envBool("SCM_PEMBELIAN_WAJIB_PERMINTAAN", &cfg.Pembelian.WajibPermintaan, &errs)
+
+	envString("SCM_MEDIA_BACKEND", &cfg.Media.Backend)
+	envString("SCM_MEDIA_DIR", &cfg.Media.Dir)
+	envString("SCM_MEDIA_BASE_URL", &cfg.Media.BaseURL)
+	envInt("SCM_MEDIA_MAX_BYTES", &cfg.Media.MaxBytes, &errs)
+	envInt("SCM_MEDIA_THUMBNAIL", &cfg.Media.Thumbnail, &errs)
+	envString("SCM_S3_ENDPOINT", &cfg.Media.S3.Endpoint)
+	envString("SCM_S3_REGION", &cfg.Media.S3.Region)
+	envString("SCM_S3_BUCKET", &cfg.Media.S3.Bucket)
+	envString("SCM_S3_ACCESS_KEY", &cfg.Media.S3.AccessKey)
+	envString("SCM_S3_SECRET_KEY", &cfg.Media.S3.SecretKey)
+	envBool("SCM_S3_PATH_STYLE", &cfg.Media.S3.PathStyle, &errs)
+	envString("SCM_BARCODE_AWALAN_INTERNAL", &cfg.Barcode.AwalanInternal)
+	envDuration("SCM_SCORECARD_INTERVAL", &cfg.Scorecard.Interval, &errs)
+	envInt("SCM_SCORECARD_PERIODE_HARI", &cfg.Scorecard.PeriodeHari, &errs)
+
+	return errors.Join(errs...)
+}
+
+func envString(key string, dst *string) {
+	if v, ok := os.LookupEnv(key); ok {
+		*dst = v
+	}
+}
+
+func envBool(key string, dst *bool, errs *[]error) {
+	v, ok := os.LookupEnv(key)
+	if !ok {
+		return
+	}
+	b, err := strconv.ParseBool(strings.TrimSpace(v))
+	if err != nil {
+		*errs = append(*errs, fmt.Errorf("%s harus bernilai true atau false, didapat %q", key, v))
+		return
+	}
+	*dst = b
+}
+
+func envInt(key string, dst *int, errs *[]error) {
+	v, ok := os.LookupEnv(key)
+	if !ok {
+		return
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(v))
+	if err != nil {
+		*errs = append(*errs, fmt.Errorf("%s harus berupa bilangan bulat, didapat %q", key, v))
+		return
+	}
+	*dst = n
+}
+
+func envFloat(key string, dst *float64, errs *[]error) {
+	v, ok := os.LookupEnv(key)
+	if !ok {
+		return
+	}
+	n, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+	if err != nil {
+		*errs = append(*errs, fmt.Errorf("%s harus berupa angka, didapat %q", key, v))
+		return
+	}
+	*dst = n
+}
+
+func envDuration(key string, dst *Duration, errs *[]error) {
+	v, ok := os.LookupEnv(key)
+	if !ok {
+		return
+	}
+	if err := dst.UnmarshalText([]byte(strings.TrimSpace(v))); err != nil {
+		*errs = append(*errs, fmt.Errorf("%s harus berupa durasi (contoh: 3m, 90s), didapat %q", key, v))
+	}
+}
+
+// splitList memecah daftar yang dipisahkan koma dan membuang elemen kosong
+func splitList(v string) []string {
+	hasil := make([]string, 0)
+	for _, s := range strings.Split(v, ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			hasil = append(hasil, s)
+		}
+	}
+	return hasil
+}
+
+// ValidateDatabase memeriksa pengaturan database, satu-satunya bagian yang dipakai
+// subcommand migrate dan pengguna
+func (c Config) ValidateDatabase() error {
+	var errs []error
+
+	if strings.TrimSpace(c.Database.DSN) == "" {
+		errs = append(errs, errors.New("database.dsn wajib diisi"))
+	} else if _, err := mysql.ParseDSN(c.Database.DSN); err != nil {
+		errs = append(errs, fmt.Errorf("database.dsn tidak valid: %w", err))
+	}
+	if c.Database.MaxOpenConns < 1 {
+		errs = append(errs, fmt.Errorf("database.max_open_conns harus minimal 1, didapat %d", c.Database.MaxOpenConns))
+	}
+	if c.Database.MaxIdleConns < 0 {
+		errs = append(errs, fmt.Errorf("database.max_idle_conns tidak boleh negatif, didapat %d", c.Database.MaxIdleConns))
+	}
+	if c.Database.MaxIdleConns > c.Database.MaxOpenConns {
+		errs = append(errs, fmt.Errorf("database.max_idle_conns (%d) tidak boleh melebihi max_open_conns (%d)", c.Database.MaxIdleConns, c.Database.MaxOpenConns))
+	}
+	if c.Database.ConnMaxLifetime < 0 {
+		errs = append(errs, errors.New("database.conn_max_lifetime tidak boleh negatif"))
+	}
+	if c.Database.ConnMaxIdleTime < 0 {
+		errs = append(errs, errors.New("database.conn_max_idle_time tidak boleh negatif"))
+	}
+	return errors.Join(errs...)
+}
+
+// Validate memeriksa seluruh nilai konfigurasi yang dibutuhkan server dan mengembalikan
+// semua kesalahan sekaligus
+func (c Config) Validate() error {
+	errs := []error{c.ValidateDatabase()}
+
+	if strings.TrimSpace(c.Server.ListenAddr) == "" {
+		errs = append(errs, errors.New("server.listen_addr wajib diisi"))
+	} else if !strings.Contains(c.Server.ListenAddr, ":") {
+		errs = append(errs, fmt.Errorf("server.listen_addr harus berformat host:port atau :port, didapat %q", c.Server.ListenAddr))
+	}
+
+	if len(c.CORS.AllowOrigins) == 0 {
+		errs = append(errs, errors.New("cors.allow_origins minimal berisi satu origin"))
+	}
+	for _, origin := range c.CORS.AllowOrigins {
+		u, err := url.Parse(origin)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			errs = append(errs, fmt.Errorf("cors.allow_origins berisi origin tidak valid %q (contoh: http://127.0.0.1:8000)", origin))
+		}
+	}
+
+	if len(c.Auth.JWTSecret) < MinJWTSecret {
+		errs = append(errs, fmt.Errorf("auth.jwt_secret wajib diisi minimal %d karakter", MinJWTSecret))
+	}
+	if c.Auth.AccessTTL <= 0 {
+		errs = append(errs, errors.New("auth.access_ttl harus lebih dari 0"))
+	}
+	if c.Auth.RefreshTTL <= c.Auth.AccessTTL {
+		errs = append(errs, errors.New("auth.refresh_ttl harus lebih lama dari access_ttl"))
+	}
+
+	if c.Pembelian.BatasNilai < 0 {
+		errs = append(errs, fmt.Errorf("pembelian.batas_nilai tidak boleh negatif, didapat %.2f", c.Pembelian.BatasNilai))
+	}
+
+	switch c.Media.Backend {
+	case "lokal":
+		if strings.TrimSpace(c.Media.Dir) == "" {
+			errs = append(errs, errors.New("media.dir wajib diisi untuk backend lokal"))
+		}
+	case "s3":
+		if u, err := url.Parse(c.Media.S3.Endpoint); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			errs = append(errs, fmt.Errorf("media.s3.endpoint harus berupa URL http(s), didapat %q", c.Media.S3.Endpoint))
+		}
+		for _, f := range []struct{ kunci, nilai string }{
+			{"region", c.Media.S3.Region}, {"bucket", c.Media.S3.Bucket},
+			{"access_key", c.Media.S3.AccessKey}, {"secret_key", c.Media.S3.SecretKey},
+		} {
+			if strings.TrimSpace(f.nilai) == "" {
+				errs = append(errs, fmt.Errorf("media.s3.%s wajib diisi untuk backend s3", f.kunci))
+			}
+		}
+	default:
+		errs = append(errs, fmt.Errorf("media.backend harus lokal atau s3, didapat %q", c.Media.Backend))
+	}
+	if strings.TrimSpace(c.Media.BaseURL) == "" || strings.HasSuffix(c.Media.BaseURL, "/") {
+		errs = append(errs, fmt.Errorf("media.base_url wajib diisi tanpa garis miring di akhir, didapat %q", c.Media.BaseURL))
+	}
+	if c.Media.MaxBytes < 1 {
+		errs = append(errs, fmt.Errorf("media.max_bytes harus minimal 1, didapat %d", c.Media.MaxBytes))
+	}
+	if c.Media.Thumbnail < 16 || c.Media.Thumbnail > 2048 {
+		errs = append(errs, fmt.Errorf("media.thumbnail harus antara 16 dan 2048 piksel, didapat %d", c.Media.Thumbnail))
+	}
+
+	if !barcode.AwalanInternalValid(c.Barcode.AwalanInternal) {
+		errs = append(errs, fmt.Errorf("barcode.awalan_internal harus dua digit antara 20 dan 29, didapat %q", c.Barcode.AwalanInternal))
+	}
+
+	if c.Scorecard.Interval < 0 {
+		errs = append(errs, errors.New("scorecard.interval tidak boleh negatif"))
+	}
+	if c.Scorecard.PeriodeHari < 1 || c.Scorecard.PeriodeHari > 3660 {
+		errs = append(errs, fmt.Errorf("scorecard.periode_hari harus antara 1 dan 3660, didapat %d", c.Scorecard.PeriodeHari))
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,143 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func tulis(t *testing.T, nama, isi string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), nama)
+	if err := os.WriteFile(path, []byte(isi), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadFile(t *testing.T) {
+	for _, tc := range []struct{ nama, isi string }{
+		{"scm.toml", `
+[database]
+dsn = "app:rahasia@tcp(db:3306)/scm?parseTime=true"
+conn_max_lifetime = "5m"
+
+[cors]
+allow_origins = ["https://scm.example.com"]
+
+[pembelian]
+batas_nilai = 2500000
+`},
+		{"scm.yaml", `
+database:
+  dsn: "app:rahasia@tcp(db:3306)/scm?parseTime=true"
+  conn_max_lifetime: 5m
+cors:
+  allow_origins: ["https://scm.example.com"]
+pembelian:
+  batas_nilai: 2500000
+`},
+	} {
+		cfg, err := Load(tulis(t, tc.nama, tc.isi))
+		if err != nil {
+			t.Fatalf("Load %s: %v", tc.nama, err)
+		}
+		if cfg.Database.DSN != "app:rahasia@tcp(db:3306)/scm?parseTime=true" || cfg.Database.ConnMaxLifetime != Duration(5*time.Minute) ||
+			cfg.CORS.AllowOrigins[0] != "https://scm.example.com" || cfg.Pembelian.BatasNilai != 2_500_000 {
+			t.Errorf("Load %s = %+v, ingin nilai dari file", tc.nama, cfg)
+		}
+		// Field yang tidak ada di file tetap bernilai bawaan
+		if cfg.Database.MaxOpenConns != 10 || cfg.Server.ListenAddr != ":8080" || cfg.Media.Thumbnail != 320 {
+			t.Errorf("Load %s menghapus nilai bawaan: %+v", tc.nama, cfg)
+		}
+	}
+
+	for nama, path := range map[string]string{
+		"ekstensi tidak dikenal": tulis(t, "scm.json", "{}"),
+		"file tidak ada":         filepath.Join(t.TempDir(), "tidak-ada.toml"),
+		"toml rusak":             tulis(t, "rusak.toml", "[database\ndsn ="),
+		"durasi salah":           tulis(t, "durasi.yml", "auth:\n  access_ttl: sebentar\n"),
+		"dsn salah":              tulis(t, "dsn.toml", "[database]\ndsn = \"bukan dsn\"\n"),
+	} {
+		if _, err := Load(path); err == nil {
+			t.Errorf("Load dengan %s tidak gagal", nama)
+		}
+	}
+}
+
+func TestLoadEnv(t *testing.T) {
+	path := tulis(t, "scm.toml", "[server]\nlisten_addr = \":9000\"\n[auth]\naccess_ttl = \"10m\"\n")
+	t.Setenv("SCM_LISTEN_ADDR", ":9100")
+	t.Setenv("SCM_CORS_ORIGINS", " http://a.test , ,https://b.test")
+	t.Setenv("SCM_PEMBELIAN_WAJIB_PERMINTAAN", "true")
+	t.Setenv("SCM_MEDIA_THUMBNAIL", " 256 ")
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	// Environment menimpa file, file menimpa bawaan
+	if cfg.Server.ListenAddr != ":9100" || cfg.Auth.AccessTTL != Duration(10*time.Minute) {
+		t.Errorf("listen_addr %q access_ttl %v, ingin :9100 dari env dan 10m dari file", cfg.Server.ListenAddr, time.Duration(cfg.Auth.AccessTTL))
+	}
+	if strings.Join(cfg.CORS.AllowOrigins, " ") != "http://a.test https://b.test" || !cfg.Pembelian.WajibPermintaan || cfg.Media.Thumbnail != 256 {
+		t.Errorf("Load = %+v, ingin nilai dari env", cfg)
+	}
+
+	// Semua env yang salah dilaporkan sekaligus
+	t.Setenv("SCM_DB_MAX_OPEN_CONNS", "banyak")
+	t.Setenv("SCM_JWT_REFRESH_TTL", "seminggu")
+	t.Setenv("SCM_S3_PATH_STYLE", "ya")
+	_, err = Load("")
+	for _, kunci := range []string{"SCM_DB_MAX_OPEN_CONNS", "SCM_JWT_REFRESH_TTL", "SCM_S3_PATH_STYLE"} {
+		if err == nil || !strings.Contains(err.Error(), kunci) {
+			t.Errorf("Load dengan env salah = %v, ingin menyebut %s", err, kunci)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	valid := Default()
+	valid.Auth.JWTSecret = strings.Repeat("k", MinJWTSecret)
+	if err := valid.Validate(); err != nil {
+		t.Fatalf("Validate konfigurasi bawaan dengan jwt_secret: %v", err)
+	}
+	if err := Default().Validate(); err == nil || !strings.Contains(err.Error(), "auth.jwt_secret") {
+		t.Errorf("Validate tanpa jwt_secret = %v", err)
+	}
+
+	for _, tc := range []struct {
+		ubah  func(*Config)
+		ingin string
+	}{
+		{func(c *Config) { c.Database.MaxIdleConns = 20 }, "max_idle_conns (20)"},
+		{func(c *Config) { c.Server.ListenAddr = "8080" }, "server.listen_addr"},
+		{func(c *Config) { c.CORS.AllowOrigins = []string{"127.0.0.1:8000"} }, "cors.allow_origins"},
+		{func(c *Config) { c.Auth.RefreshTTL = c.Auth.AccessTTL }, "auth.refresh_ttl"},
+		{func(c *Config) { c.Pembelian.BatasNilai = -1 }, "pembelian.batas_nilai"},
+		{func(c *Config) { c.Media.Backend = "ftp" }, "media.backend"},
+		{func(c *Config) { c.Media.Backend = "s3" }, "media.s3.bucket"},
+		{func(c *Config) { c.Media.BaseURL = "/media/" }, "media.base_url"},
+		{func(c *Config) { c.Media.Thumbnail = 4096 }, "media.thumbnail"},
+		{func(c *Config) { c.Barcode.AwalanInternal = "30" }, "barcode.awalan_internal"},
+		{func(c *Config) { c.Scorecard.PeriodeHari = 0 }, "scorecard.periode_hari"},
+	} {
+		c := valid
+		c.CORS.AllowOrigins = append([]string(nil), valid.CORS.AllowOrigins...)
+		tc.ubah(&c)
+		if err := c.Validate(); err == nil || !strings.Contains(err.Error(), tc.ingin) {
+			t.Errorf("Validate = %v, ingin kesalahan %s", err, tc.ingin)
+		}
+	}
+
+	// Kesalahan dikembalikan sekaligus, bukan berhenti di yang pertama
+	c := valid
+	c.Database.MaxOpenConns = 0
+	c.Media.MaxBytes = 0
+	err := c.Validate()
+	if err == nil || !strings.Contains(err.Error(), "database.max_open_conns") || !strings.Contains(err.Error(), "media.max_bytes") {
+		t.Errorf("Validate = %v, ingin dua kesalahan", err)
+	}
+}
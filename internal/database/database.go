@@ -8,35 +8,33 @@ import (
 	"log"
 	"time"
 
+	"scm-api/internal/config"
+
 	_ "github.com/go-sql-driver/mysql"
 )
 
-var DB *sql.DB
-
-// Connect terhubung ke database MariaDB/MySQL
-func Connect() {
-	dsn := "root:@tcp(127.0.0.1:3306)/prima?parseTime=true"
-
-	var err error
-	DB, err = sql.Open("mysql", dsn)
+// Connect terhubung ke database MariaDB/MySQL sesuai konfigurasi
+func Connect(cfg config.DatabaseConfig) *sql.DB {
+	db, err := sql.Open("mysql", cfg.DSN)
 	if err != nil {
 		log.Fatalf("Tidak bisa membuka koneksi database: %v", err)
 	}
 
-	err = DB.Ping()
+	err = db.Ping()
 	if err != nil {
 		log.Fatalf("Tidak bisa terhubung ke database: %v", err)
 	}
 
 	// --- KONFIGURASI CONNECTION POOL ---
-	// Atur masa pakai maksimum koneksi (misalnya 3 menit)
-	DB.SetConnMaxLifetime(time.Minute * 3)
+	// Atur masa pakai maksimum koneksi
+	db.SetConnMaxLifetime(time.Duration(cfg.ConnMaxLifetime))
 	// Atur jumlah maksimum koneksi yang terbuka
-	DB.SetMaxOpenConns(10)
+	db.SetMaxOpenConns(cfg.MaxOpenConns)
 	// Atur jumlah maksimum koneksi yang diam (tidak terpakai)
-	DB.SetMaxIdleConns(10)
-	// Atur waktu diam maksimum koneksi. Koneksi yang diam lebih dari 1 menit akan ditutup.
-	DB.SetConnMaxIdleTime(time.Minute * 1) // <-- INI ADALAH PERBAIKAN UTAMANYA
+	db.SetMaxIdleConns(cfg.MaxIdleConns)
+	// Atur waktu diam maksimum koneksi. Koneksi yang diam lebih lama akan ditutup.
+	db.SetConnMaxIdleTime(time.Duration(cfg.ConnMaxIdleTime))
 
 	fmt.Println("Berhasil terhubung ke database!")
+	return db
 }
@@ -0,0 +1,298 @@
+// file: internal/database/migrate.go
+
+package database
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// Migration adalah satu langkah perubahan skema beserta skrip untuk membatalkannya
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+// MigrationStatus menunjukkan apakah sebuah migrasi sudah dijalankan
+type MigrationStatus struct {
+	Version   int
+	Name      string
+	Applied   bool
+	AppliedAt sql.NullTime
+}
+
+// Nama file migrasi: 0001_nama_migrasi.up.sql dan 0001_nama_migrasi.down.sql
+var migrationName = regexp.MustCompile(`^(\d+)_(.+)\.(up|down)\.sql$`)
+
+// Pernyataan SQL dipisahkan oleh titik koma di akhir baris
+var statementSeparator = regexp.MustCompile(`;\s*(\r?\n|$)`)
+
+// LoadMigrations membaca semua migrasi yang ditanam di binary, terurut berdasarkan versi
+func LoadMigrations() ([]Migration, error) {
+	entries, err := fs.ReadDir(migrationFiles, "migrations")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[int]*Migration)
+	for _, e := range entries {
+		m := migrationName.FindStringSubmatch(e.Name())
+		if m == nil {
+			return nil, fmt.Errorf("nama file migrasi tidak valid: %s", e.Name())
+		}
+		version, _ := strconv.Atoi(m[1])
+		content, err := migrationFiles.ReadFile("migrations/" + e.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		mig, ok := byVersion[version]
+		if !ok {
+			mig = &Migration{Version: version, Name: m[2]}
+			byVersion[version] = mig
+		} else if mig.Name != m[2] {
+			return nil, fmt.Errorf("versi migrasi %d dipakai oleh dua nama berbeda: %s dan %s", version, mig.Name, m[2])
+		}
+		if m[3] == "up" {
+			mig.Up = string(content)
+		} else {
+			mig.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, mig := range byVersion {
+		if mig.Up == "" || mig.Down == "" {
+			return nil, fmt.Errorf("migrasi %04d_%s harus punya file .up.sql dan .down.sql", mig.Version, mig.Name)
+		}
+		migrations = append(migrations, *mig)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// Migrator menjalankan migrasi skema dan mencatat versinya di tabel schema_migrations
+type Migrator struct {
+	db         *sql.DB
+	migrations []Migration
+}
+
+// NewMigrator menyiapkan migrator dengan migrasi yang ditanam di binary
+func NewMigrator(db *sql.DB) (*Migrator, error) {
+	migrations, err := LoadMigrations()
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{db: db, migrations: migrations}, nil
+}
+
+// Up menjalankan migrasi yang belum diterapkan. steps <= 0 berarti semuanya.
+func (m *Migrator) Up(ctx context.Context, steps int) ([]Migration, error) {
+	return m.run(ctx, func(conn *sql.Conn, applied map[int]bool) ([]Migration, error) {
+		done := make([]Migration, 0)
+		for _, mig := range m.pending(applied, steps) {
+			if err := m.apply(ctx, conn, mig, mig.Up, true); err != nil {
+				return done, err
+			}
+			done = append(done, mig)
+		}
+		return done, nil
+	})
+}
+
+// Down membatalkan migrasi terakhir yang sudah diterapkan sebanyak steps langkah
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	if steps <= 0 {
+		steps = 1
+	}
+	return m.run(ctx, func(conn *sql.Conn, applied map[int]bool) ([]Migration, error) {
+		done := make([]Migration, 0)
+		for _, mig := range m.rollback(applied, steps) {
+			if err := m.apply(ctx, conn, mig, mig.Down, false); err != nil {
+				return done, err
+			}
+			done = append(done, mig)
+		}
+		return done, nil
+	})
+}
+
+// pending memilih migrasi yang belum diterapkan dari versi terkecil, paling banyak
+// steps buah (steps <= 0 berarti semuanya). Celah versi yang belum diterapkan di
+// antara versi yang sudah diterapkan ikut dijalankan.
+func (m *Migrator) pending(applied map[int]bool, steps int) []Migration {
+	hasil := make([]Migration, 0)
+	for _, mig := range m.migrations {
+		if steps > 0 && len(hasil) >= steps {
+			break
+		}
+		if !applied[mig.Version] {
+			hasil = append(hasil, mig)
+		}
+	}
+	return hasil
+}
+
+// rollback memilih steps migrasi yang sudah diterapkan mulai dari versi terbesar
+func (m *Migrator) rollback(applied map[int]bool, steps int) []Migration {
+	hasil := make([]Migration, 0)
+	for i := len(m.migrations) - 1; i >= 0 && len(hasil) < steps; i-- {
+		if applied[m.migrations[i].Version] {
+			hasil = append(hasil, m.migrations[i])
+		}
+	}
+	return hasil
+}
+
+// Status mengembalikan daftar semua migrasi beserta status penerapannya
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	if err := m.ensureTable(ctx, m.db); err != nil {
+		return nil, err
+	}
+	rows, err := m.db.QueryContext(ctx, "SELECT version, applied_at FROM schema_migrations")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	appliedAt := make(map[int]sql.NullTime)
+	for rows.Next() {
+		var version int
+		var at sql.NullTime
+		if err := rows.Scan(&version, &at); err != nil {
+			return nil, err
+		}
+		appliedAt[version] = at
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	statuses := make([]MigrationStatus, 0, len(m.migrations))
+	for _, mig := range m.migrations {
+		at, ok := appliedAt[mig.Version]
+		statuses = append(statuses, MigrationStatus{Version: mig.Version, Name: mig.Name, Applied: ok, AppliedAt: at})
+	}
+	return statuses, nil
+}
+
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+func (m *Migrator) ensureTable(ctx context.Context, db execer) error {
+	_, err := db.ExecContext(ctx, `
+        CREATE TABLE IF NOT EXISTS schema_migrations (
+            version    INT          NOT NULL,
+            name       VARCHAR(255) NOT NULL,
+            applied_at DATETIME     NOT NULL DEFAULT CURRENT_TIMESTAMP,
+            PRIMARY KEY (version)
+        ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4
+    `)
+	if err != nil {
+		return fmt.Errorf("gagal menyiapkan tabel schema_migrations: %w", err)
+	}
+	return nil
+}
+
+// run memegang satu koneksi dan named lock selama migrasi berjalan,
+// supaya dua proses migrate tidak berjalan bersamaan.
+func (m *Migrator) run(ctx context.Context, fn func(conn *sql.Conn, applied map[int]bool) ([]Migration, error)) ([]Migration, error) {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	var locked sql.NullInt64
+	if err := conn.QueryRowContext(ctx, "SELECT GET_LOCK('scm_api_migrate', 30)").Scan(&locked); err != nil {
+		return nil, fmt.Errorf("gagal mengambil lock migrasi: %w", err)
+	}
+	if !locked.Valid || locked.Int64 != 1 {
+		return nil, fmt.Errorf("migrasi lain sedang berjalan")
+	}
+	defer conn.ExecContext(context.Background(), "SELECT RELEASE_LOCK('scm_api_migrate')")
+
+	if err := m.ensureTable(ctx, conn); err != nil {
+		return nil, err
+	}
+
+	rows, err := conn.QueryContext(ctx, "SELECT version FROM schema_migrations")
+	if err != nil {
+		return nil, err
+	}
+	applied := make(map[int]bool)
+	for rows.Next() {
+		var version int
+		if err := rows.Scan(&version); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		applied[version] = true
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return fn(conn, applied)
+}
+
+// apply menjalankan satu skrip migrasi lalu mencatat (atau menghapus) versinya.
+// Catatan: DDL di MariaDB melakukan commit implisit, jadi migrasi yang gagal
+// di tengah jalan mungkin perlu dibereskan secara manual.
+func (m *Migrator) apply(ctx context.Context, conn *sql.Conn, mig Migration, script string, up bool) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	for _, stmt := range splitStatements(script) {
+		if _, err := tx.ExecContext(ctx, stmt); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("migrasi %04d_%s gagal: %w\n%s", mig.Version, mig.Name, err, stmt)
+		}
+	}
+
+	if up {
+		_, err = tx.ExecContext(ctx, "INSERT INTO schema_migrations (version, name) VALUES (?, ?)", mig.Version, mig.Name)
+	} else {
+		_, err = tx.ExecContext(ctx, "DELETE FROM schema_migrations WHERE version = ?", mig.Version)
+	}
+	if err != nil {
+		tx.Rollback()
+		return fmt.Errorf("gagal mencatat versi migrasi %04d: %w", mig.Version, err)
+	}
+	return tx.Commit()
+}
+
+// splitStatements memecah skrip menjadi pernyataan tunggal dan membuang komentar baris
+func splitStatements(script string) []string {
+	var lines []string
+	for _, line := range strings.Split(script, "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "--") {
+			continue
+		}
+		lines = append(lines, line)
+	}
+
+	stmts := make([]string, 0)
+	for _, stmt := range statementSeparator.Split(strings.Join(lines, "\n"), -1) {
+		if stmt = strings.TrimSpace(stmt); stmt != "" {
+			stmts = append(stmts, stmt)
+		}
+	}
+	return stmts
+}
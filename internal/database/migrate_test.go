@@ -0,0 +1,139 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSplitStatements(t *testing.T) {
+	for _, tc := range []struct {
+		skrip string
+		ingin []string
+	}{
+		{"CREATE TABLE a (id INT);\nCREATE TABLE b (id INT);\n", []string{"CREATE TABLE a (id INT)", "CREATE TABLE b (id INT)"}},
+		// komentar baris dibuang, titik koma di tengah baris tidak memisahkan
+		{"-- buat tabel\nINSERT INTO a VALUES ('x;y');\n  -- selesai\n", []string{"INSERT INTO a VALUES ('x;y')"}},
+		{"ALTER TABLE a\n  ADD COLUMN b INT;\r\nDROP TABLE c;", []string{"ALTER TABLE a\n  ADD COLUMN b INT", "DROP TABLE c"}},
+		{"SELECT 1;   \n\n;\n", []string{"SELECT 1"}},
+		{"-- hanya komentar\n", []string{}},
+	} {
+		if got := splitStatements(tc.skrip); !reflect.DeepEqual(got, tc.ingin) {
+			t.Errorf("splitStatements(%q) = %q, ingin %q", tc.skrip, got, tc.ingin)
+		}
+	}
+}
+
+func TestLoadMigrations(t *testing.T) {
+	migrations, err := LoadMigrations()
+	if err != nil {
+		t.Fatalf("LoadMigrations: %v", err)
+	}
+	for i, mig := range migrations {
+		if mig.Version != i+1 {
+			t.Errorf("migrasi ke-%d bernomor %04d, ingin versi berurutan tanpa celah", i+1, mig.Version)
+		}
+		if len(splitStatements(mig.Up)) == 0 || len(splitStatements(mig.Down)) == 0 {
+			t.Errorf("migrasi %04d_%s punya skrip kosong", mig.Version, mig.Name)
+		}
+	}
+}
+
+func versi(migrations []Migration) []int {
+	hasil := make([]int, 0, len(migrations))
+	for _, mig := range migrations {
+		hasil = append(hasil, mig.Version)
+	}
+	return hasil
+}
+
+func TestUrutanMigrasi(t *testing.T) {
+	m := &Migrator{migrations: []Migration{{Version: 1}, {Version: 2}, {Version: 3}, {Version: 4}}}
+	for _, tc := range []struct {
+		applied map[int]bool
+		steps   int
+		up      []int
+		down    []int
+	}{
+		{map[int]bool{}, 0, []int{1, 2, 3, 4}, []int{}},
+		{map[int]bool{1: true}, 2, []int{2, 3}, []int{1}},
+		{map[int]bool{1: true, 3: true}, 0, []int{2, 4}, []int{3}},
+		{map[int]bool{1: true, 2: true, 3: true, 4: true}, 2, []int{}, []int{4, 3}},
+	} {
+		if got := versi(m.pending(tc.applied, tc.steps)); !reflect.DeepEqual(got, tc.up) {
+			t.Errorf("pending(%v, %d) = %v, ingin %v", tc.applied, tc.steps, got, tc.up)
+		}
+		// Down membatalkan minimal satu langkah
+		steps := max(tc.steps, 1)
+		if got := versi(m.rollback(tc.applied, steps)); !reflect.DeepEqual(got, tc.down) {
+			t.Errorf("rollback(%v, %d) = %v, ingin %v", tc.applied, steps, got, tc.down)
+		}
+	}
+}
+
+// TestMigrasiNaikTurun menjalankan semua migrasi naik lalu turun lagi di database
+// SCM_TEST_DATABASE_DSN, yang isinya akan dikosongkan
+func TestMigrasiNaikTurun(t *testing.T) {
+	dsn := os.Getenv("SCM_TEST_DATABASE_DSN")
+	if dsn == "" {
+		t.Skip("SCM_TEST_DATABASE_DSN tidak diisi")
+	}
+	db, err := sql.Open("mysql", dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	ctx := context.Background()
+	m, err := NewMigrator(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	semua := len(m.migrations)
+	if _, err := m.Down(ctx, semua); err != nil {
+		t.Fatalf("Down awal: %v", err)
+	}
+
+	done, err := m.Up(ctx, 2)
+	if err != nil || !reflect.DeepEqual(versi(done), []int{1, 2}) {
+		t.Fatalf("Up 2 langkah = %v, %v", versi(done), err)
+	}
+	if done, err = m.Up(ctx, 0); err != nil || len(done) != semua-2 {
+		t.Fatalf("Up sisanya = %v, %v", versi(done), err)
+	}
+	status, err := m.Status(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range status {
+		if !s.Applied {
+			t.Errorf("migrasi %04d_%s belum diterapkan setelah Up", s.Version, s.Name)
+		}
+	}
+
+	if done, err = m.Down(ctx, 2); err != nil || !reflect.DeepEqual(versi(done), []int{semua, semua - 1}) {
+		t.Fatalf("Down 2 langkah = %v, %v", versi(done), err)
+	}
+	// Setiap skrip down harus bisa membatalkan up-nya sampai skema kosong
+	if done, err = m.Down(ctx, semua); err != nil || len(done) != semua-2 {
+		t.Fatalf("Down semua = %v, %v", versi(done), err)
+	}
+	var sisa []string
+	rows, err := db.QueryContext(ctx, `SELECT table_name FROM information_schema.tables WHERE table_schema = DATABASE() AND table_name <> 'schema_migrations'`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var nama string
+		if err := rows.Scan(&nama); err != nil {
+			t.Fatal(err)
+		}
+		sisa = append(sisa, nama)
+	}
+	if len(sisa) != 0 {
+		t.Errorf("tabel tersisa setelah semua migrasi dibatalkan: %s", strings.Join(sisa, ", "))
+	}
+}
@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS stok;
+DROP TABLE IF EXISTS gudang;
+DROP TABLE IF EXISTS detail_pembelian;
+DROP TABLE IF EXISTS pembelian;
+DROP TABLE IF EXISTS produk;
+DROP TABLE IF EXISTS supplier;
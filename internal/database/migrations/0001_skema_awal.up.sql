@@ -0,0 +1,72 @@
+-- Skema awal yang dibutuhkan oleh handler produk, supplier, pembelian, gudang, dan stok
+
+CREATE TABLE supplier (
+    supplier_id    BIGINT       NOT NULL AUTO_INCREMENT,
+    nama_supplier  VARCHAR(150) NOT NULL,
+    alamat         TEXT         NULL,
+    kontak         VARCHAR(100) NULL,
+    contact_person VARCHAR(100) NULL,
+    rating         DECIMAL(3,2) NULL,
+    PRIMARY KEY (supplier_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE produk (
+    produk_id     BIGINT        NOT NULL AUTO_INCREMENT,
+    sku           VARCHAR(64)   NOT NULL,
+    nama_produk   VARCHAR(150)  NOT NULL,
+    deskripsi     TEXT          NULL,
+    kategori      VARCHAR(100)  NULL,
+    satuan        VARCHAR(32)   NOT NULL,
+    harga_jual    DECIMAL(15,2) NOT NULL DEFAULT 0,
+    berat_kg      DECIMAL(10,3) NULL,
+    gambar_produk VARCHAR(255)  NULL,
+    supplier_id   BIGINT        NULL,
+    PRIMARY KEY (produk_id),
+    UNIQUE KEY uq_produk_sku (sku),
+    CONSTRAINT fk_produk_supplier FOREIGN KEY (supplier_id) REFERENCES supplier (supplier_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE pembelian (
+    pembelian_id  BIGINT        NOT NULL AUTO_INCREMENT,
+    supplier_id   BIGINT        NOT NULL,
+    tanggal_pesan DATE          NOT NULL,
+    estimasi_tiba DATE          NULL,
+    total_biaya   DECIMAL(15,2) NULL,
+    status        VARCHAR(32)   NOT NULL DEFAULT 'Dipesan',
+    PRIMARY KEY (pembelian_id),
+    KEY idx_pembelian_tanggal (tanggal_pesan),
+    CONSTRAINT fk_pembelian_supplier FOREIGN KEY (supplier_id) REFERENCES supplier (supplier_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE detail_pembelian (
+    detail_pembelian_id BIGINT        NOT NULL AUTO_INCREMENT,
+    pembelian_id        BIGINT        NOT NULL,
+    produk_id           BIGINT        NOT NULL,
+    jumlah              INT           NOT NULL,
+    harga_beli_satuan   DECIMAL(15,2) NOT NULL,
+    subtotal            DECIMAL(15,2) NOT NULL,
+    PRIMARY KEY (detail_pembelian_id),
+    CONSTRAINT fk_detail_pembelian_pembelian FOREIGN KEY (pembelian_id) REFERENCES pembelian (pembelian_id),
+    CONSTRAINT fk_detail_pembelian_produk FOREIGN KEY (produk_id) REFERENCES produk (produk_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE gudang (
+    gudang_id   BIGINT       NOT NULL AUTO_INCREMENT,
+    nama_gudang VARCHAR(100) NOT NULL,
+    lokasi      VARCHAR(255) NOT NULL DEFAULT '',
+    PRIMARY KEY (gudang_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+-- UNIQUE (produk_id, gudang_id) dibutuhkan oleh INSERT ... ON DUPLICATE KEY UPDATE
+-- di adjustStokHandler dan terimaPembelianHandler
+CREATE TABLE stok (
+    stok_id        BIGINT   NOT NULL AUTO_INCREMENT,
+    produk_id      BIGINT   NOT NULL,
+    gudang_id      BIGINT   NOT NULL,
+    jumlah         INT      NOT NULL DEFAULT 0,
+    tanggal_update DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (stok_id),
+    UNIQUE KEY uq_stok_produk_gudang (produk_id, gudang_id),
+    CONSTRAINT fk_stok_produk FOREIGN KEY (produk_id) REFERENCES produk (produk_id),
+    CONSTRAINT fk_stok_gudang FOREIGN KEY (gudang_id) REFERENCES gudang (gudang_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS pembelian_status_riwayat;
@@ -0,0 +1,14 @@
+-- Riwayat perpindahan status pembelian: siapa yang mengubah dan kapan
+
+CREATE TABLE pembelian_status_riwayat (
+    riwayat_id   BIGINT       NOT NULL AUTO_INCREMENT,
+    pembelian_id BIGINT       NOT NULL,
+    status_dari  VARCHAR(32)  NULL,
+    status_ke    VARCHAR(32)  NOT NULL,
+    diubah_oleh  VARCHAR(100) NOT NULL,
+    catatan      TEXT         NULL,
+    waktu        DATETIME     NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (riwayat_id),
+    KEY idx_riwayat_pembelian (pembelian_id, waktu),
+    CONSTRAINT fk_riwayat_pembelian FOREIGN KEY (pembelian_id) REFERENCES pembelian (pembelian_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
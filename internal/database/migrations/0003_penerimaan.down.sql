@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS detail_penerimaan;
+DROP TABLE IF EXISTS penerimaan;
@@ -0,0 +1,29 @@
+-- Dokumen penerimaan barang (goods receipt). Satu pembelian bisa diterima
+-- beberapa kali ketika kiriman supplier datang tidak lengkap.
+
+CREATE TABLE penerimaan (
+    penerimaan_id  BIGINT       NOT NULL AUTO_INCREMENT,
+    pembelian_id   BIGINT       NOT NULL,
+    tanggal_terima DATETIME     NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    diterima_oleh  VARCHAR(100) NOT NULL,
+    catatan        TEXT         NULL,
+    PRIMARY KEY (penerimaan_id),
+    KEY idx_penerimaan_pembelian (pembelian_id),
+    CONSTRAINT fk_penerimaan_pembelian FOREIGN KEY (pembelian_id) REFERENCES pembelian (pembelian_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+-- Jumlah yang diterima dan ditolak (rusak/tidak sesuai) per baris detail_pembelian
+CREATE TABLE detail_penerimaan (
+    detail_penerimaan_id BIGINT       NOT NULL AUTO_INCREMENT,
+    penerimaan_id        BIGINT       NOT NULL,
+    detail_pembelian_id  BIGINT       NOT NULL,
+    produk_id            BIGINT       NOT NULL,
+    jumlah_diterima      INT          NOT NULL DEFAULT 0,
+    jumlah_ditolak       INT          NOT NULL DEFAULT 0,
+    alasan_tolak         VARCHAR(255) NULL,
+    PRIMARY KEY (detail_penerimaan_id),
+    KEY idx_detail_penerimaan_detail_pembelian (detail_pembelian_id),
+    CONSTRAINT fk_detail_penerimaan_penerimaan FOREIGN KEY (penerimaan_id) REFERENCES penerimaan (penerimaan_id),
+    CONSTRAINT fk_detail_penerimaan_detail_pembelian FOREIGN KEY (detail_pembelian_id) REFERENCES detail_pembelian (detail_pembelian_id),
+    CONSTRAINT fk_detail_penerimaan_produk FOREIGN KEY (produk_id) REFERENCES produk (produk_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
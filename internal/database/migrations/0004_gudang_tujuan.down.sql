@@ -0,0 +1,7 @@
+ALTER TABLE detail_penerimaan
+    DROP FOREIGN KEY fk_detail_penerimaan_gudang,
+    DROP COLUMN gudang_id;
+
+ALTER TABLE pembelian
+    DROP FOREIGN KEY fk_pembelian_gudang_tujuan,
+    DROP COLUMN gudang_tujuan_id;
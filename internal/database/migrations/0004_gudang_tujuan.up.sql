@@ -0,0 +1,14 @@
+-- Gudang tujuan bawaan untuk penerimaan sebuah pembelian
+ALTER TABLE pembelian
+    ADD COLUMN gudang_tujuan_id BIGINT NULL AFTER status,
+    ADD CONSTRAINT fk_pembelian_gudang_tujuan FOREIGN KEY (gudang_tujuan_id) REFERENCES gudang (gudang_id);
+
+-- Gudang tempat setiap baris penerimaan dimasukkan ke stok
+ALTER TABLE detail_penerimaan
+    ADD COLUMN gudang_id BIGINT NULL AFTER produk_id,
+    ADD CONSTRAINT fk_detail_penerimaan_gudang FOREIGN KEY (gudang_id) REFERENCES gudang (gudang_id);
+
+-- Penerimaan lama selalu masuk ke gudang 1
+UPDATE detail_penerimaan
+SET gudang_id = 1
+WHERE gudang_id IS NULL AND EXISTS (SELECT 1 FROM gudang WHERE gudang_id = 1);
@@ -0,0 +1,24 @@
+-- Buku besar mutasi stok. Hanya ditambah, tidak pernah diubah atau dihapus.
+-- Setiap perubahan tabel stok menulis satu baris di sini dalam transaksi yang sama.
+
+CREATE TABLE stok_mutasi (
+    mutasi_id      BIGINT       NOT NULL AUTO_INCREMENT,
+    produk_id      BIGINT       NOT NULL,
+    gudang_id      BIGINT       NOT NULL,
+    tipe           VARCHAR(20)  NOT NULL,
+    jumlah_sebelum INT          NOT NULL,
+    jumlah_sesudah INT          NOT NULL,
+    perubahan      INT          NOT NULL,
+    referensi_tipe VARCHAR(32)  NULL,
+    referensi_id   BIGINT       NULL,
+    dibuat_oleh    VARCHAR(100) NOT NULL,
+    catatan        TEXT         NULL,
+    waktu          DATETIME     NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (mutasi_id),
+    KEY idx_mutasi_produk_gudang (produk_id, gudang_id, waktu),
+    KEY idx_mutasi_gudang (gudang_id, waktu),
+    KEY idx_mutasi_waktu (waktu),
+    KEY idx_mutasi_referensi (referensi_tipe, referensi_id),
+    CONSTRAINT fk_mutasi_produk FOREIGN KEY (produk_id) REFERENCES produk (produk_id),
+    CONSTRAINT fk_mutasi_gudang FOREIGN KEY (gudang_id) REFERENCES gudang (gudang_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS detail_transfer;
+DROP TABLE IF EXISTS transfer_stok;
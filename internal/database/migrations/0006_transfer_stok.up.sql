@@ -0,0 +1,30 @@
+-- Dokumen transfer stok antar gudang. Stok gudang asal berkurang saat dikirim
+-- dan stok gudang tujuan bertambah saat diterima; di antaranya barang berstatus
+-- dalam perjalanan.
+
+CREATE TABLE transfer_stok (
+    transfer_id      BIGINT       NOT NULL AUTO_INCREMENT,
+    gudang_asal_id   BIGINT       NOT NULL,
+    gudang_tujuan_id BIGINT       NOT NULL,
+    status           VARCHAR(32)  NOT NULL DEFAULT 'Draft',
+    catatan          TEXT         NULL,
+    dibuat_oleh      VARCHAR(100) NOT NULL,
+    tanggal_dibuat   DATETIME     NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    tanggal_kirim    DATETIME     NULL,
+    tanggal_terima   DATETIME     NULL,
+    PRIMARY KEY (transfer_id),
+    KEY idx_transfer_status (status),
+    CONSTRAINT fk_transfer_gudang_asal FOREIGN KEY (gudang_asal_id) REFERENCES gudang (gudang_id),
+    CONSTRAINT fk_transfer_gudang_tujuan FOREIGN KEY (gudang_tujuan_id) REFERENCES gudang (gudang_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE detail_transfer (
+    detail_transfer_id BIGINT NOT NULL AUTO_INCREMENT,
+    transfer_id        BIGINT NOT NULL,
+    produk_id          BIGINT NOT NULL,
+    jumlah             INT    NOT NULL,
+    PRIMARY KEY (detail_transfer_id),
+    UNIQUE KEY uq_detail_transfer_produk (transfer_id, produk_id),
+    CONSTRAINT fk_detail_transfer_transfer FOREIGN KEY (transfer_id) REFERENCES transfer_stok (transfer_id),
+    CONSTRAINT fk_detail_transfer_produk FOREIGN KEY (produk_id) REFERENCES produk (produk_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
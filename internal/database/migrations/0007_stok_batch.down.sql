@@ -0,0 +1,8 @@
+DROP TABLE IF EXISTS detail_transfer_batch;
+
+ALTER TABLE detail_penerimaan
+    DROP COLUMN tanggal_kedaluwarsa,
+    DROP COLUMN tanggal_produksi,
+    DROP COLUMN nomor_lot;
+
+DROP TABLE IF EXISTS stok_batch;
@@ -0,0 +1,43 @@
+-- Stok per batch/lot untuk produk yang punya tanggal kedaluwarsa.
+-- Jumlah di stok_batch adalah rincian dari stok.jumlah; sisanya (misalnya stok
+-- lama sebelum batch dicatat) dianggap stok tanpa batch.
+
+CREATE TABLE stok_batch (
+    batch_id            BIGINT      NOT NULL AUTO_INCREMENT,
+    produk_id           BIGINT      NOT NULL,
+    gudang_id           BIGINT      NOT NULL,
+    nomor_lot           VARCHAR(64) NOT NULL DEFAULT '',
+    tanggal_produksi    DATE        NULL,
+    tanggal_kedaluwarsa DATE        NULL,
+    jumlah              INT         NOT NULL,
+    penerimaan_id       BIGINT      NULL,
+    tanggal_masuk       DATETIME    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (batch_id),
+    KEY idx_batch_fefo (produk_id, gudang_id, tanggal_kedaluwarsa),
+    KEY idx_batch_kedaluwarsa (tanggal_kedaluwarsa),
+    CONSTRAINT fk_batch_produk FOREIGN KEY (produk_id) REFERENCES produk (produk_id),
+    CONSTRAINT fk_batch_gudang FOREIGN KEY (gudang_id) REFERENCES gudang (gudang_id),
+    CONSTRAINT fk_batch_penerimaan FOREIGN KEY (penerimaan_id) REFERENCES penerimaan (penerimaan_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+-- Data lot yang dicatat saat barang pesanan diterima
+ALTER TABLE detail_penerimaan
+    ADD COLUMN nomor_lot           VARCHAR(64) NULL AFTER alasan_tolak,
+    ADD COLUMN tanggal_produksi    DATE        NULL AFTER nomor_lot,
+    ADD COLUMN tanggal_kedaluwarsa DATE        NULL AFTER tanggal_produksi;
+
+-- Batch yang diambil (FEFO) saat transfer dikirim, agar lot yang sama
+-- dibuat kembali di gudang tujuan saat transfer diterima
+CREATE TABLE detail_transfer_batch (
+    detail_transfer_batch_id BIGINT      NOT NULL AUTO_INCREMENT,
+    detail_transfer_id       BIGINT      NOT NULL,
+    batch_id                 BIGINT      NOT NULL,
+    nomor_lot                VARCHAR(64) NOT NULL DEFAULT '',
+    tanggal_produksi         DATE        NULL,
+    tanggal_kedaluwarsa      DATE        NULL,
+    jumlah                   INT         NOT NULL,
+    PRIMARY KEY (detail_transfer_batch_id),
+    KEY idx_transfer_batch_detail (detail_transfer_id),
+    CONSTRAINT fk_transfer_batch_detail FOREIGN KEY (detail_transfer_id) REFERENCES detail_transfer (detail_transfer_id),
+    CONSTRAINT fk_transfer_batch_batch FOREIGN KEY (batch_id) REFERENCES stok_batch (batch_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -0,0 +1,26 @@
+-- Pengguna yang boleh login ke API dan token refresh yang pernah diterbitkan.
+-- Password disimpan sebagai hash bcrypt; token refresh hanya disimpan hash SHA-256-nya.
+
+CREATE TABLE pengguna (
+    pengguna_id    BIGINT       NOT NULL AUTO_INCREMENT,
+    username       VARCHAR(100) NOT NULL,
+    nama_lengkap   VARCHAR(255) NULL,
+    password_hash  VARCHAR(100) NOT NULL,
+    aktif          TINYINT(1)   NOT NULL DEFAULT 1,
+    tanggal_dibuat DATETIME     NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (pengguna_id),
+    UNIQUE KEY uq_pengguna_username (username)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE refresh_token (
+    token_id         BIGINT   NOT NULL AUTO_INCREMENT,
+    pengguna_id      BIGINT   NOT NULL,
+    token_hash       CHAR(64) NOT NULL,
+    tanggal_dibuat   DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    kedaluwarsa_pada DATETIME NOT NULL,
+    dicabut_pada     DATETIME NULL,
+    PRIMARY KEY (token_id),
+    UNIQUE KEY uq_refresh_token_hash (token_hash),
+    KEY idx_refresh_token_pengguna (pengguna_id),
+    CONSTRAINT fk_refresh_token_pengguna FOREIGN KEY (pengguna_id) REFERENCES pengguna (pengguna_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS pengguna_role;
+DROP TABLE IF EXISTS role_izin;
+DROP TABLE IF EXISTS role;
+DROP TABLE IF EXISTS izin;
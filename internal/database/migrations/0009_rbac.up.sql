@@ -0,0 +1,78 @@
+-- Role dan izin. Setiap rute API membutuhkan satu kode izin; pengguna mendapat
+-- izin dari seluruh role yang dimilikinya.
+
+CREATE TABLE izin (
+    kode      VARCHAR(64)  NOT NULL,
+    deskripsi VARCHAR(255) NOT NULL,
+    PRIMARY KEY (kode)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE role (
+    role_id   BIGINT       NOT NULL AUTO_INCREMENT,
+    nama      VARCHAR(64)  NOT NULL,
+    deskripsi VARCHAR(255) NULL,
+    PRIMARY KEY (role_id),
+    UNIQUE KEY uq_role_nama (nama)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE role_izin (
+    role_id   BIGINT      NOT NULL,
+    kode_izin VARCHAR(64) NOT NULL,
+    PRIMARY KEY (role_id, kode_izin),
+    CONSTRAINT fk_role_izin_role FOREIGN KEY (role_id) REFERENCES role (role_id),
+    CONSTRAINT fk_role_izin_izin FOREIGN KEY (kode_izin) REFERENCES izin (kode)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE pengguna_role (
+    pengguna_id BIGINT NOT NULL,
+    role_id     BIGINT NOT NULL,
+    PRIMARY KEY (pengguna_id, role_id),
+    KEY idx_pengguna_role_role (role_id),
+    CONSTRAINT fk_pengguna_role_pengguna FOREIGN KEY (pengguna_id) REFERENCES pengguna (pengguna_id),
+    CONSTRAINT fk_pengguna_role_role FOREIGN KEY (role_id) REFERENCES role (role_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+INSERT INTO izin (kode, deskripsi) VALUES
+    ('produk.lihat',          'Melihat produk'),
+    ('produk.kelola',         'Menambah, mengubah, dan menghapus produk'),
+    ('supplier.lihat',        'Melihat supplier'),
+    ('supplier.kelola',       'Menambah, mengubah, dan menghapus supplier'),
+    ('pembelian.lihat',       'Melihat pesanan pembelian dan penerimaannya'),
+    ('pembelian.kelola',      'Membuat, memesan, membatalkan, dan menghapus pesanan pembelian'),
+    ('pembelian.terima',      'Mencatat penerimaan barang pesanan'),
+    ('pembelian.nilai_besar', 'Membuat pesanan pembelian di atas batas nilai'),
+    ('gudang.lihat',          'Melihat gudang'),
+    ('gudang.kelola',         'Menambah, mengubah, dan menghapus gudang'),
+    ('stok.lihat',            'Melihat stok, mutasi, dan batch'),
+    ('stok.sesuaikan',        'Menyesuaikan jumlah stok'),
+    ('transfer.lihat',        'Melihat transfer stok'),
+    ('transfer.kelola',       'Membuat, mengirim, menerima, dan membatalkan transfer stok'),
+    ('dashboard.lihat',       'Melihat dashboard'),
+    ('pengguna.kelola',       'Mengelola role dan izin pengguna');
+
+INSERT INTO role (role_id, nama, deskripsi) VALUES
+    (1, 'admin',     'Semua izin'),
+    (2, 'manajer',   'Semua izin kecuali mengelola pengguna'),
+    (3, 'pembelian', 'Staf pembelian'),
+    (4, 'gudang',    'Staf gudang');
+
+INSERT INTO role_izin (role_id, kode_izin)
+SELECT 1, kode FROM izin;
+
+INSERT INTO role_izin (role_id, kode_izin)
+SELECT 2, kode FROM izin WHERE kode <> 'pengguna.kelola';
+
+INSERT INTO role_izin (role_id, kode_izin) VALUES
+    (3, 'produk.lihat'), (3, 'produk.kelola'),
+    (3, 'supplier.lihat'), (3, 'supplier.kelola'),
+    (3, 'pembelian.lihat'), (3, 'pembelian.kelola'),
+    (3, 'gudang.lihat'), (3, 'stok.lihat'), (3, 'transfer.lihat'), (3, 'dashboard.lihat'),
+    (4, 'produk.lihat'), (4, 'supplier.lihat'),
+    (4, 'pembelian.lihat'), (4, 'pembelian.terima'),
+    (4, 'gudang.lihat'), (4, 'stok.lihat'), (4, 'stok.sesuaikan'),
+    (4, 'transfer.lihat'), (4, 'transfer.kelola'), (4, 'dashboard.lihat');
+
+-- Sebelum migrasi ini setiap pengguna yang login boleh melakukan apa saja,
+-- jadi pengguna yang sudah ada dijadikan admin agar tidak terkunci.
+INSERT INTO pengguna_role (pengguna_id, role_id)
+SELECT pengguna_id, 1 FROM pengguna;
@@ -0,0 +1,3 @@
+DELETE FROM role_izin WHERE kode_izin = 'gudang.semua';
+DELETE FROM izin WHERE kode = 'gudang.semua';
+DROP TABLE IF EXISTS pengguna_gudang;
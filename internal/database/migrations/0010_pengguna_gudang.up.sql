@@ -0,0 +1,18 @@
+-- Gudang tempat seorang pengguna bekerja. Stok, penyesuaian, transfer, dan
+-- penerimaan barang dibatasi ke gudang ini, kecuali untuk pengguna dengan izin
+-- gudang.semua.
+
+CREATE TABLE pengguna_gudang (
+    pengguna_id BIGINT NOT NULL,
+    gudang_id   BIGINT NOT NULL,
+    PRIMARY KEY (pengguna_id, gudang_id),
+    KEY idx_pengguna_gudang_gudang (gudang_id),
+    CONSTRAINT fk_pengguna_gudang_pengguna FOREIGN KEY (pengguna_id) REFERENCES pengguna (pengguna_id),
+    CONSTRAINT fk_pengguna_gudang_gudang FOREIGN KEY (gudang_id) REFERENCES gudang (gudang_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+INSERT INTO izin (kode, deskripsi) VALUES
+    ('gudang.semua', 'Mengakses stok dan dokumen semua gudang');
+
+INSERT INTO role_izin (role_id, kode_izin)
+SELECT role_id, 'gudang.semua' FROM role WHERE nama IN ('admin', 'manajer');
@@ -0,0 +1,3 @@
+DELETE FROM role_izin WHERE kode_izin = 'audit.lihat';
+DELETE FROM izin WHERE kode = 'audit.lihat';
+DROP TABLE IF EXISTS audit_log;
@@ -0,0 +1,25 @@
+-- Jejak audit setiap perubahan data lewat API. Hanya ditambah, tidak pernah diubah
+-- atau dihapus. sebelum dan sesudah menyimpan isi entitas, perubahan hanya field
+-- yang berbeda dalam bentuk {"field": {"sebelum": ..., "sesudah": ...}}.
+
+CREATE TABLE audit_log (
+    audit_id   BIGINT       NOT NULL AUTO_INCREMENT,
+    waktu      DATETIME     NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    aktor      VARCHAR(100) NOT NULL,
+    entitas    VARCHAR(32)  NOT NULL,
+    entitas_id BIGINT       NOT NULL,
+    aksi       VARCHAR(10)  NOT NULL,
+    sebelum    JSON         NULL,
+    sesudah    JSON         NULL,
+    perubahan  JSON         NULL,
+    PRIMARY KEY (audit_id),
+    KEY idx_audit_entitas (entitas, entitas_id, waktu),
+    KEY idx_audit_aktor (aktor, waktu),
+    KEY idx_audit_waktu (waktu)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+INSERT INTO izin (kode, deskripsi) VALUES
+    ('audit.lihat', 'Melihat jejak audit perubahan data');
+
+INSERT INTO role_izin (role_id, kode_izin)
+SELECT role_id, 'audit.lihat' FROM role WHERE nama IN ('admin', 'manajer');
@@ -0,0 +1,11 @@
+ALTER TABLE gudang
+    DROP KEY idx_gudang_deleted_at,
+    DROP COLUMN deleted_at;
+
+ALTER TABLE supplier
+    DROP KEY idx_supplier_deleted_at,
+    DROP COLUMN deleted_at;
+
+ALTER TABLE produk
+    DROP KEY idx_produk_deleted_at,
+    DROP COLUMN deleted_at;
@@ -0,0 +1,14 @@
+-- Produk, supplier, dan gudang tidak lagi dihapus dari tabel, hanya ditandai
+-- deleted_at, agar pembelian, stok, dan mutasi lama tetap merujuk ke data yang ada.
+
+ALTER TABLE produk
+    ADD COLUMN deleted_at DATETIME NULL,
+    ADD KEY idx_produk_deleted_at (deleted_at);
+
+ALTER TABLE supplier
+    ADD COLUMN deleted_at DATETIME NULL,
+    ADD KEY idx_supplier_deleted_at (deleted_at);
+
+ALTER TABLE gudang
+    ADD COLUMN deleted_at DATETIME NULL,
+    ADD KEY idx_gudang_deleted_at (deleted_at);
@@ -0,0 +1,2 @@
+ALTER TABLE produk
+    DROP COLUMN gambar_thumbnail;
@@ -0,0 +1,5 @@
+-- gambar_produk berisi URL gambar asli yang diunggah lewat POST /api/produk/:id/gambar,
+-- dan gambar_thumbnail berisi URL versi kecilnya untuk daftar dan hasil pencarian.
+
+ALTER TABLE produk
+    ADD COLUMN gambar_thumbnail VARCHAR(255) NULL AFTER gambar_produk;
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS produk_barcode;
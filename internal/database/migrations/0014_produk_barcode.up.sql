@@ -0,0 +1,17 @@
+-- Satu produk bisa memiliki beberapa barcode (misalnya kemasan dari pabrik berbeda).
+-- gtin adalah kode yang sudah dinormalisasi menjadi 14 digit dan unik di seluruh
+-- produk; kode menyimpan digit seperti yang dimasukkan. internal menandai EAN-13
+-- berawalan 20-29 yang dibuat sendiri untuk barang tanpa kode pabrik.
+
+CREATE TABLE produk_barcode (
+    barcode_id BIGINT      NOT NULL AUTO_INCREMENT,
+    produk_id  BIGINT      NOT NULL,
+    kode       VARCHAR(14) NOT NULL,
+    gtin       CHAR(14)    NOT NULL,
+    internal   TINYINT(1)  NOT NULL DEFAULT 0,
+    created_at DATETIME    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (barcode_id),
+    UNIQUE KEY uq_produk_barcode_gtin (gtin),
+    KEY idx_produk_barcode_produk (produk_id),
+    CONSTRAINT fk_produk_barcode_produk FOREIGN KEY (produk_id) REFERENCES produk (produk_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
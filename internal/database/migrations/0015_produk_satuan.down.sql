@@ -0,0 +1,3 @@
+ALTER TABLE detail_penerimaan DROP COLUMN jumlah_stok;
+ALTER TABLE detail_pembelian DROP COLUMN faktor_konversi, DROP COLUMN satuan;
+DROP TABLE IF EXISTS produk_satuan;
@@ -0,0 +1,29 @@
+-- Satuan lain untuk membeli produk, misalnya "krat" untuk telur yang disimpan per
+-- "butir". faktor adalah isi satu satuan ini dalam satuan dasar produk (produk.satuan).
+CREATE TABLE produk_satuan (
+    satuan_id  BIGINT      NOT NULL AUTO_INCREMENT,
+    produk_id  BIGINT      NOT NULL,
+    satuan     VARCHAR(32) NOT NULL,
+    faktor     INT         NOT NULL,
+    created_at DATETIME    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (satuan_id),
+    UNIQUE KEY uq_produk_satuan (produk_id, satuan),
+    CONSTRAINT fk_produk_satuan_produk FOREIGN KEY (produk_id) REFERENCES produk (produk_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+-- Setiap baris pembelian menyimpan satuan dan faktornya saat dipesan, sehingga
+-- perubahan faktor di produk_satuan tidak mengubah pesanan yang sudah ada.
+-- jumlah dan harga_beli_satuan dinyatakan dalam satuan baris ini.
+ALTER TABLE detail_pembelian
+    ADD COLUMN satuan          VARCHAR(32) NULL AFTER jumlah,
+    ADD COLUMN faktor_konversi INT         NOT NULL DEFAULT 1 AFTER satuan;
+
+UPDATE detail_pembelian d JOIN produk p ON d.produk_id = p.produk_id SET d.satuan = p.satuan;
+
+ALTER TABLE detail_pembelian MODIFY COLUMN satuan VARCHAR(32) NOT NULL;
+
+-- jumlah_diterima dan jumlah_ditolak mengikuti satuan baris pembelian; jumlah_stok
+-- adalah jumlah diterima dalam satuan dasar, yaitu yang benar-benar masuk ke stok.
+ALTER TABLE detail_penerimaan ADD COLUMN jumlah_stok INT NOT NULL DEFAULT 0 AFTER jumlah_ditolak;
+
+UPDATE detail_penerimaan SET jumlah_stok = jumlah_diterima;
@@ -0,0 +1,23 @@
+-- Katalog harga supplier. Satu produk bisa dibeli dari beberapa supplier, dan satu
+-- supplier bisa punya beberapa baris untuk produk yang sama dengan satuan atau masa
+-- berlaku berbeda. harga_beli dan moq dinyatakan dalam satuan baris ini (satuan
+-- dasar produk atau satuan di produk_satuan). Masa berlaku kosong berarti terbuka.
+CREATE TABLE supplier_produk (
+    supplier_produk_id BIGINT        NOT NULL AUTO_INCREMENT,
+    supplier_id        BIGINT        NOT NULL,
+    produk_id          BIGINT        NOT NULL,
+    sku_supplier       VARCHAR(64)   NULL,
+    satuan             VARCHAR(32)   NOT NULL,
+    harga_beli         DECIMAL(15,2) NOT NULL,
+    moq                INT           NOT NULL DEFAULT 1,
+    lead_time_hari     INT           NOT NULL DEFAULT 0,
+    berlaku_dari       DATE          NULL,
+    berlaku_sampai     DATE          NULL,
+    created_at         DATETIME      NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at         DATETIME      NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (supplier_produk_id),
+    KEY idx_supplier_produk_supplier (supplier_id, produk_id),
+    KEY idx_supplier_produk_produk (produk_id),
+    CONSTRAINT fk_supplier_produk_supplier FOREIGN KEY (supplier_id) REFERENCES supplier (supplier_id),
+    CONSTRAINT fk_supplier_produk_produk FOREIGN KEY (produk_id) REFERENCES produk (produk_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
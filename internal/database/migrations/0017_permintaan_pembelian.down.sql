@@ -0,0 +1,6 @@
+DELETE FROM role_izin WHERE kode_izin LIKE 'permintaan.%';
+DELETE FROM izin WHERE kode LIKE 'permintaan.%';
+DROP TABLE IF EXISTS persetujuan_permintaan;
+DROP TABLE IF EXISTS detail_permintaan;
+DROP TABLE IF EXISTS permintaan_pembelian;
+DROP TABLE IF EXISTS aturan_persetujuan;
@@ -0,0 +1,81 @@
+-- Permintaan pembelian (purchase requisition) yang diajukan staf sebelum pesanan
+-- dibuat. Saat diajukan, aturan_persetujuan yang berlaku disalin menjadi tahap di
+-- persetujuan_permintaan. Tahap diputuskan berurutan per level; satu persetujuan
+-- dari role mana pun di level itu sudah cukup. Permintaan yang disetujui di semua
+-- level bisa dikonversi menjadi pembelian.
+
+CREATE TABLE aturan_persetujuan (
+    aturan_id     BIGINT        NOT NULL AUTO_INCREMENT,
+    level         INT           NOT NULL,
+    nilai_minimal DECIMAL(15,2) NOT NULL DEFAULT 0,
+    kategori      VARCHAR(100)  NULL,
+    role_id       BIGINT        NOT NULL,
+    created_at    DATETIME      NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (aturan_id),
+    KEY idx_aturan_persetujuan_level (level),
+    CONSTRAINT fk_aturan_persetujuan_role FOREIGN KEY (role_id) REFERENCES role (role_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE permintaan_pembelian (
+    permintaan_id    BIGINT        NOT NULL AUTO_INCREMENT,
+    diminta_oleh     VARCHAR(100)  NOT NULL,
+    supplier_id      BIGINT        NULL,
+    gudang_tujuan_id BIGINT        NULL,
+    tanggal_butuh    DATE          NULL,
+    catatan          TEXT          NULL,
+    total_biaya      DECIMAL(15,2) NOT NULL,
+    status           VARCHAR(32)   NOT NULL DEFAULT 'Diajukan',
+    pembelian_id     BIGINT        NULL,
+    created_at       DATETIME      NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at       DATETIME      NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (permintaan_id),
+    KEY idx_permintaan_status (status),
+    KEY idx_permintaan_diminta_oleh (diminta_oleh),
+    CONSTRAINT fk_permintaan_supplier FOREIGN KEY (supplier_id) REFERENCES supplier (supplier_id),
+    CONSTRAINT fk_permintaan_gudang FOREIGN KEY (gudang_tujuan_id) REFERENCES gudang (gudang_id),
+    CONSTRAINT fk_permintaan_pembelian FOREIGN KEY (pembelian_id) REFERENCES pembelian (pembelian_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE detail_permintaan (
+    detail_permintaan_id BIGINT        NOT NULL AUTO_INCREMENT,
+    permintaan_id        BIGINT        NOT NULL,
+    produk_id            BIGINT        NOT NULL,
+    jumlah               INT           NOT NULL,
+    satuan               VARCHAR(32)   NOT NULL,
+    faktor_konversi      INT           NOT NULL DEFAULT 1,
+    harga_perkiraan      DECIMAL(15,2) NOT NULL,
+    subtotal             DECIMAL(15,2) NOT NULL,
+    PRIMARY KEY (detail_permintaan_id),
+    KEY idx_detail_permintaan_permintaan (permintaan_id),
+    CONSTRAINT fk_detail_permintaan_permintaan FOREIGN KEY (permintaan_id) REFERENCES permintaan_pembelian (permintaan_id),
+    CONSTRAINT fk_detail_permintaan_produk FOREIGN KEY (produk_id) REFERENCES produk (produk_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE persetujuan_permintaan (
+    persetujuan_id  BIGINT       NOT NULL AUTO_INCREMENT,
+    permintaan_id   BIGINT       NOT NULL,
+    level           INT          NOT NULL,
+    role_id         BIGINT       NOT NULL,
+    status          VARCHAR(32)  NOT NULL DEFAULT 'Menunggu',
+    diputuskan_oleh VARCHAR(100) NULL,
+    komentar        TEXT         NULL,
+    waktu           DATETIME     NULL,
+    PRIMARY KEY (persetujuan_id),
+    UNIQUE KEY uq_persetujuan_permintaan (permintaan_id, level, role_id),
+    CONSTRAINT fk_persetujuan_permintaan FOREIGN KEY (permintaan_id) REFERENCES permintaan_pembelian (permintaan_id),
+    CONSTRAINT fk_persetujuan_role FOREIGN KEY (role_id) REFERENCES role (role_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+INSERT INTO izin (kode, deskripsi) VALUES
+    ('permintaan.lihat',    'Melihat permintaan pembelian'),
+    ('permintaan.buat',     'Mengajukan dan membatalkan permintaan pembelian sendiri'),
+    ('permintaan.setujui',  'Menyetujui atau menolak permintaan pembelian sesuai role di aturan persetujuan'),
+    ('permintaan.atur',     'Mengelola aturan persetujuan permintaan pembelian');
+
+INSERT INTO role_izin (role_id, kode_izin)
+SELECT role_id, kode FROM role, izin
+WHERE role.nama IN ('admin', 'manajer') AND izin.kode LIKE 'permintaan.%';
+
+INSERT INTO role_izin (role_id, kode_izin)
+SELECT role_id, kode FROM role, izin
+WHERE role.nama IN ('pembelian', 'gudang') AND izin.kode IN ('permintaan.lihat', 'permintaan.buat');
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS stok_parameter;
@@ -0,0 +1,17 @@
+-- Batas persediaan per produk per gudang untuk saran pemesanan ulang. Semua jumlah
+-- dalam satuan dasar produk. reorder_point dan max_level boleh kosong; nilainya
+-- lalu dihitung dari pemakaian stok dan lead time katalog supplier.
+CREATE TABLE stok_parameter (
+    parameter_id  BIGINT   NOT NULL AUTO_INCREMENT,
+    produk_id     BIGINT   NOT NULL,
+    gudang_id     BIGINT   NOT NULL,
+    reorder_point INT      NULL,
+    safety_stock  INT      NOT NULL DEFAULT 0,
+    max_level     INT      NULL,
+    updated_at    DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (parameter_id),
+    UNIQUE KEY uq_stok_parameter (produk_id, gudang_id),
+    KEY idx_stok_parameter_gudang (gudang_id),
+    CONSTRAINT fk_stok_parameter_produk FOREIGN KEY (produk_id) REFERENCES produk (produk_id),
+    CONSTRAINT fk_stok_parameter_gudang FOREIGN KEY (gudang_id) REFERENCES gudang (gudang_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -0,0 +1,5 @@
+DELETE FROM pembelian_status_riwayat
+WHERE pembelian_id NOT IN (SELECT pembelian_id FROM pembelian);
+
+ALTER TABLE pembelian_status_riwayat
+    ADD CONSTRAINT fk_riwayat_pembelian FOREIGN KEY (pembelian_id) REFERENCES pembelian (pembelian_id);
@@ -0,0 +1,5 @@
+-- Riwayat status tetap disimpan setelah pesanan Draft atau Dibatalkan dihapus,
+-- jadi baris riwayat tidak lagi wajib merujuk ke pembelian yang masih ada.
+
+ALTER TABLE pembelian_status_riwayat
+    DROP FOREIGN KEY fk_riwayat_pembelian;
@@ -0,0 +1,2 @@
+ALTER TABLE pengguna
+    DROP COLUMN versi_token;
@@ -0,0 +1,5 @@
+-- versi_token dinaikkan saat logout dan saat pengguna dinonaktifkan.
+-- Access token membawa versi saat diterbitkan dan ditolak jika sudah tertinggal.
+
+ALTER TABLE pengguna
+    ADD COLUMN versi_token INT NOT NULL DEFAULT 0;
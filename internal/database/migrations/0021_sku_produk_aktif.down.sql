@@ -0,0 +1,6 @@
+-- Gagal jika SKU produk yang dihapus sudah dipakai lagi oleh produk lain
+ALTER TABLE produk
+    DROP KEY uq_produk_sku_aktif,
+    DROP KEY idx_produk_sku,
+    DROP COLUMN sku_aktif,
+    ADD UNIQUE KEY uq_produk_sku (sku);
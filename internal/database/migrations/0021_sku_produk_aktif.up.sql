@@ -0,0 +1,9 @@
+-- SKU hanya wajib unik di antara produk yang belum dihapus, agar SKU produk yang
+-- sudah dihapus bisa dipakai lagi. sku_aktif bernilai NULL untuk produk yang
+-- dihapus, dan NULL tidak pernah bentrok di UNIQUE KEY.
+
+ALTER TABLE produk
+    ADD COLUMN sku_aktif VARCHAR(64) AS (IF(deleted_at IS NULL, sku, NULL)) STORED,
+    DROP KEY uq_produk_sku,
+    ADD KEY idx_produk_sku (sku),
+    ADD UNIQUE KEY uq_produk_sku_aktif (sku_aktif);
@@ -0,0 +1,3 @@
+DELETE FROM aturan_persetujuan
+WHERE level = 1 AND nilai_minimal = 0 AND kategori IS NULL
+  AND role_id IN (SELECT role_id FROM role WHERE nama = 'manajer');
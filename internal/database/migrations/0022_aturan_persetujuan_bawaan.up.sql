@@ -0,0 +1,5 @@
+-- Aturan bawaan: setiap permintaan pembelian disetujui manajer lebih dulu, agar
+-- permintaan tidak langsung Disetujui sebelum aturan diatur lewat API.
+
+INSERT INTO aturan_persetujuan (level, nilai_minimal, kategori, role_id)
+SELECT 1, 0, NULL, role_id FROM role WHERE nama = 'manajer';
@@ -0,0 +1,217 @@
+// file: internal/forecast/forecast.go
+
+// Package forecast meramalkan permintaan harian dari deret pemakaian stok. Tersedia
+// rata-rata bergerak, exponential smoothing, dan musiman per hari dalam minggu, beserta
+// akurasinya dari uji holdout di ujung deret. Dipakai GET /api/forecast dan saran
+// pemesanan ulang.
+package forecast
+
+import (
+	"math"
+	"slices"
+	"time"
+)
+
+// Metode peramalan
+const (
+	MetodeRataBergerak = "rata_bergerak"
+	MetodeEksponensial = "eksponensial"
+	MetodeMusiman      = "musiman"
+	// MetodeOtomatis memilih metode dengan MAE terkecil pada uji holdout
+	MetodeOtomatis = "otomatis"
+)
+
+// Metode adalah metode yang bisa dihitung langsung, urut dari yang paling sederhana
+var Metode = []string{MetodeRataBergerak, MetodeEksponensial, MetodeMusiman}
+
+// Bawaan parameter peramalan
+const (
+	JendelaBawaan = 7
+	AlphaBawaan   = 0.3
+	// hariUjiMaks adalah panjang holdout; data latih minimal sepanjang jendela bawaan
+	hariUjiMaks = 7
+)
+
+// Opsi parameter peramalan. Nilai nol berarti memakai bawaan.
+type Opsi struct {
+	// Jendela adalah jumlah hari terakhir untuk rata-rata bergerak
+	Jendela int
+	// Alpha adalah bobot pengamatan terbaru pada exponential smoothing (0–1)
+	Alpha float64
+}
+
+func (o Opsi) lengkapi() Opsi {
+	if o.Jendela <= 0 {
+		o.Jendela = JendelaBawaan
+	}
+	if o.Alpha <= 0 || o.Alpha > 1 {
+		o.Alpha = AlphaBawaan
+	}
+	return o
+}
+
+// Valid melaporkan apakah metode dikenal, termasuk MetodeOtomatis
+func Valid(metode string) bool {
+	return metode == MetodeOtomatis || slices.Contains(Metode, metode)
+}
+
+// Deret menyusun pemakaian per hari mulai awal sepanjang hari hari. Tanggal yang
+// tidak ada di jumlah (format YYYY-MM-DD) dianggap tanpa pemakaian.
+func Deret(awal time.Time, hari int, jumlah map[string]float64) []float64 {
+	deret := make([]float64, max(hari, 0))
+	for i := range deret {
+		deret[i] = jumlah[awal.AddDate(0, 0, i).Format("2006-01-02")]
+	}
+	return deret
+}
+
+// Ramal menghasilkan ramalan horizon hari setelah riwayat berakhir. awal adalah
+// tanggal riwayat[0]; tanggal dipakai untuk indeks musiman. Metode tak dikenal
+// diperlakukan sebagai rata-rata bergerak.
+func Ramal(metode string, riwayat []float64, awal time.Time, horizon int, o Opsi) []float64 {
+	o = o.lengkapi()
+	hasil := make([]float64, max(horizon, 0))
+	if len(riwayat) == 0 {
+		return hasil
+	}
+	switch metode {
+	case MetodeEksponensial:
+		isi(hasil, pemulusan(riwayat, o.Alpha))
+	case MetodeMusiman:
+		indeks := indeksMusiman(riwayat, awal)
+		desimusim := make([]float64, 0, len(riwayat))
+		for i, v := range riwayat {
+			// Hari yang tidak pernah ada pemakaiannya tidak memberi informasi tentang level
+			if f := indeks[hariKe(awal, i)]; f > 0 {
+				desimusim = append(desimusim, v/f)
+			}
+		}
+		level := pemulusan(desimusim, o.Alpha)
+		for h := range hasil {
+			hasil[h] = level * indeks[hariKe(awal, len(riwayat)+h)]
+		}
+	default:
+		n := min(o.Jendela, len(riwayat))
+		isi(hasil, rataRata(riwayat[len(riwayat)-n:]))
+	}
+	return hasil
+}
+
+// Akurasi adalah galat ramalan terhadap HariUji hari terakhir riwayat yang disisihkan.
+// MAPE dalam persen dan hanya menghitung hari dengan pemakaian; nil jika tidak ada.
+type Akurasi struct {
+	Metode  string   `json:"metode"`
+	MAE     float64  `json:"mae"`
+	MAPE    *float64 `json:"mape"`
+	RMSE    float64  `json:"rmse"`
+	HariUji int      `json:"hari_uji"`
+}
+
+// Uji menyisihkan sampai 7 hari terakhir riwayat, meramalkannya dari sisa data, lalu
+// menghitung galatnya. ok bernilai false jika riwayat terlalu pendek.
+func Uji(metode string, riwayat []float64, awal time.Time, o Opsi) (Akurasi, bool) {
+	uji := min(hariUjiMaks, len(riwayat)-JendelaBawaan)
+	if uji < 1 {
+		return Akurasi{}, false
+	}
+	latih, aktual := riwayat[:len(riwayat)-uji], riwayat[len(riwayat)-uji:]
+	ramalan := Ramal(metode, latih, awal, uji, o)
+
+	a := Akurasi{Metode: metode, HariUji: uji}
+	var kuadrat, persen float64
+	adaPersen := 0
+	for i, v := range aktual {
+		galat := math.Abs(ramalan[i] - v)
+		a.MAE += galat
+		kuadrat += galat * galat
+		if v > 0 {
+			persen += galat / v
+			adaPersen++
+		}
+	}
+	a.MAE /= float64(uji)
+	a.RMSE = math.Sqrt(kuadrat / float64(uji))
+	if adaPersen > 0 {
+		mape := persen / float64(adaPersen) * 100
+		a.MAPE = &mape
+	}
+	return a, true
+}
+
+// Pilih menguji semua metode dan mengembalikan metode dengan MAE terkecil beserta
+// akurasi setiap metode. Jika riwayat terlalu pendek untuk diuji, dipilih rata-rata
+// bergerak dan daftar akurasinya kosong.
+func Pilih(riwayat []float64, awal time.Time, o Opsi) (string, []Akurasi) {
+	terbaik, maeTerbaik := MetodeRataBergerak, math.Inf(1)
+	daftar := make([]Akurasi, 0, len(Metode))
+	for _, m := range Metode {
+		a, ok := Uji(m, riwayat, awal, o)
+		if !ok {
+			continue
+		}
+		// Jika sama, metode yang lebih sederhana (lebih awal di Metode) dipertahankan
+		if a.MAE < maeTerbaik {
+			terbaik, maeTerbaik = m, a.MAE
+		}
+		daftar = append(daftar, a)
+	}
+	return terbaik, daftar
+}
+
+// pemulusan mengembalikan level akhir simple exponential smoothing. Level awal adalah
+// rata-rata minggu pertama agar tidak terlalu bergantung pada satu hari.
+func pemulusan(deret []float64, alpha float64) float64 {
+	if len(deret) == 0 {
+		return 0
+	}
+	n := min(JendelaBawaan, len(deret))
+	level := rataRata(deret[:n])
+	for _, v := range deret[n:] {
+		level = alpha*v + (1-alpha)*level
+	}
+	return level
+}
+
+// indeksMusiman adalah rata-rata pemakaian tiap hari dalam minggu dibagi rata-rata
+// keseluruhan. Dengan kurang dari dua minggu data, atau tanpa pemakaian sama sekali,
+// semua indeks bernilai 1.
+func indeksMusiman(deret []float64, awal time.Time) [7]float64 {
+	var indeks, total [7]float64
+	var jumlah [7]int
+	for i := range indeks {
+		indeks[i] = 1
+	}
+	rata := rataRata(deret)
+	if len(deret) < 14 || rata == 0 {
+		return indeks
+	}
+	for i, v := range deret {
+		total[hariKe(awal, i)] += v
+		jumlah[hariKe(awal, i)]++
+	}
+	for d := range indeks {
+		indeks[d] = total[d] / float64(jumlah[d]) / rata
+	}
+	return indeks
+}
+
+func hariKe(awal time.Time, i int) time.Weekday {
+	return awal.AddDate(0, 0, i).Weekday()
+}
+
+func rataRata(deret []float64) float64 {
+	if len(deret) == 0 {
+		return 0
+	}
+	var total float64
+	for _, v := range deret {
+		total += v
+	}
+	return total / float64(len(deret))
+}
+
+func isi(dst []float64, v float64) {
+	for i := range dst {
+		dst[i] = v
+	}
+}
@@ -0,0 +1,156 @@
+package forecast
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+// senin adalah hari pertama deret uji agar indeks musiman mudah dihitung
+var senin = time.Date(2024, 5, 6, 0, 0, 0, 0, time.UTC)
+
+func ulang(v float64, n int) []float64 {
+	d := make([]float64, n)
+	isi(d, v)
+	return d
+}
+
+func gabung(deret ...[]float64) []float64 {
+	var hasil []float64
+	for _, d := range deret {
+		hasil = append(hasil, d...)
+	}
+	return hasil
+}
+
+// mingguan adalah n minggu mulai Senin dengan pemakaian 10 pada hari kerja dan 0 pada akhir pekan
+func mingguan(n int) []float64 {
+	var deret []float64
+	for range n {
+		deret = append(deret, 10, 10, 10, 10, 10, 0, 0)
+	}
+	return deret
+}
+
+func hampir(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func samaDeret(a, b []float64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !hampir(a[i], b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func TestDeret(t *testing.T) {
+	got := Deret(senin, 3, map[string]float64{"2024-05-07": 4, "2024-05-10": 9})
+	if !samaDeret(got, []float64{0, 4, 0}) {
+		t.Errorf("Deret = %v, ingin [0 4 0]", got)
+	}
+	if got := Deret(senin, -1, nil); len(got) != 0 {
+		t.Errorf("Deret dengan hari negatif = %v, ingin kosong", got)
+	}
+}
+
+func TestRamal(t *testing.T) {
+	satuSampaiSepuluh := []float64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+	for _, tc := range []struct {
+		nama    string
+		metode  string
+		riwayat []float64
+		horizon int
+		opsi    Opsi
+		ingin   []float64
+	}{
+		{"rata-rata 7 hari terakhir", MetodeRataBergerak, satuSampaiSepuluh, 3, Opsi{}, []float64{7, 7, 7}},
+		{"jendela diatur", MetodeRataBergerak, satuSampaiSepuluh, 1, Opsi{Jendela: 2}, []float64{9.5}},
+		{"riwayat lebih pendek dari jendela", MetodeRataBergerak, []float64{2, 4}, 2, Opsi{}, []float64{3, 3}},
+		{"metode tak dikenal", "acak", satuSampaiSepuluh, 1, Opsi{}, []float64{7}},
+		{"riwayat kosong", MetodeEksponensial, nil, 2, Opsi{}, []float64{0, 0}},
+		{"horizon negatif", MetodeRataBergerak, satuSampaiSepuluh, -1, Opsi{}, []float64{}},
+		// Level awal 10, lalu 0,3×20 + 0,7×10 = 13, lalu 0,7×13 = 9,1
+		{"eksponensial", MetodeEksponensial, gabung(ulang(10, 7), []float64{20, 0}), 2, Opsi{}, []float64{9.1, 9.1}},
+		// Dengan alpha 0,5: 10, lalu 15, lalu 7,5
+		{"eksponensial dengan alpha", MetodeEksponensial, gabung(ulang(10, 7), []float64{20, 0}), 1, Opsi{Alpha: 0.5}, []float64{7.5}},
+		{"alpha di luar batas memakai bawaan", MetodeEksponensial, gabung(ulang(10, 7), []float64{20, 0}), 1, Opsi{Alpha: 1.5}, []float64{9.1}},
+		{"eksponensial lebih pendek dari seminggu", MetodeEksponensial, []float64{4, 6}, 1, Opsi{}, []float64{5}},
+		// Indeks hari kerja 10 / (100/14) = 1,4 dan akhir pekan 0; level hasil desimusim 100/14
+		{"musiman", MetodeMusiman, mingguan(2), 7, Opsi{}, []float64{10, 10, 10, 10, 10, 0, 0}},
+		{"musiman berlanjut dari hari Selasa", MetodeMusiman, mingguan(3)[:15], 6, Opsi{}, []float64{10, 10, 10, 10, 0, 0}},
+		{"musiman kurang dari dua minggu", MetodeMusiman, gabung(ulang(10, 7), []float64{20, 0}), 1, Opsi{}, []float64{9.1}},
+		{"musiman tanpa pemakaian", MetodeMusiman, ulang(0, 14), 2, Opsi{}, []float64{0, 0}},
+	} {
+		t.Run(tc.nama, func(t *testing.T) {
+			if got := Ramal(tc.metode, tc.riwayat, senin, tc.horizon, tc.opsi); !samaDeret(got, tc.ingin) {
+				t.Errorf("Ramal = %v, ingin %v", got, tc.ingin)
+			}
+		})
+	}
+}
+
+func TestUji(t *testing.T) {
+	// Data latih 7 hari bernilai 10, jadi rata-rata bergerak meramalkan 10 untuk 7 hari uji
+	riwayat := gabung(ulang(10, 7), []float64{10, 20, 0, 10, 10, 10, 10})
+	a, ok := Uji(MetodeRataBergerak, riwayat, senin, Opsi{})
+	if !ok {
+		t.Fatal("Uji riwayat 14 hari tidak bisa dihitung")
+	}
+	if a.HariUji != 7 || !hampir(a.MAE, 20.0/7) || !hampir(a.RMSE, math.Sqrt(200.0/7)) {
+		t.Errorf("akurasi = %+v, ingin hari_uji 7, MAE 20/7, RMSE akar(200/7)", a)
+	}
+	// Hari dengan pemakaian 0 tidak ikut MAPE: 0,5 dibagi 6 hari
+	if a.MAPE == nil || !hampir(*a.MAPE, 0.5/6*100) {
+		t.Errorf("MAPE = %v, ingin %v", a.MAPE, 0.5/6*100)
+	}
+
+	a, ok = Uji(MetodeRataBergerak, gabung(ulang(5, 7), []float64{0}), senin, Opsi{})
+	if !ok || a.HariUji != 1 || a.MAE != 5 || a.RMSE != 5 || a.MAPE != nil {
+		t.Errorf("akurasi dengan aktual 0 = %+v, %v; ingin hari_uji 1, MAE 5, RMSE 5, MAPE nil", a, ok)
+	}
+
+	if _, ok := Uji(MetodeRataBergerak, ulang(3, JendelaBawaan), senin, Opsi{}); ok {
+		t.Error("Uji riwayat sepanjang JendelaBawaan seharusnya terlalu pendek")
+	}
+}
+
+func TestPilih(t *testing.T) {
+	for _, tc := range []struct {
+		nama    string
+		riwayat []float64
+		ingin   string
+		jumlah  int
+	}{
+		{"riwayat terlalu pendek", ulang(4, 5), MetodeRataBergerak, 0},
+		{"sama baiknya memilih yang paling sederhana", ulang(10, 21), MetodeRataBergerak, 3},
+		{"pola mingguan", mingguan(3), MetodeMusiman, 3},
+		// Lonjakan di awal jendela terakhir membuat rata-rata bergerak terlalu tinggi,
+		// sedangkan smoothing sudah kembali mendekati 10
+		{"lonjakan sesaat", gabung(ulang(10, 7), []float64{40}, ulang(10, 13)), MetodeEksponensial, 3},
+	} {
+		t.Run(tc.nama, func(t *testing.T) {
+			metode, akurasi := Pilih(tc.riwayat, senin, Opsi{})
+			if metode != tc.ingin || len(akurasi) != tc.jumlah {
+				t.Errorf("Pilih = %q dengan %d akurasi, ingin %q dengan %d", metode, len(akurasi), tc.ingin, tc.jumlah)
+			}
+			if akurasi == nil {
+				t.Error("daftar akurasi nil, ingin daftar kosong agar dikirim sebagai []")
+			}
+		})
+	}
+}
+
+func TestValid(t *testing.T) {
+	for metode, ingin := range map[string]bool{
+		MetodeRataBergerak: true, MetodeEksponensial: true, MetodeMusiman: true, MetodeOtomatis: true, "": false, "acak": false,
+	} {
+		if Valid(metode) != ingin {
+			t.Errorf("Valid(%q) = %v, ingin %v", metode, !ingin, ingin)
+		}
+	}
+}
@@ -0,0 +1,127 @@
+// file: internal/gambar/gambar.go
+
+// Package gambar memeriksa berkas gambar yang diunggah dan membuat thumbnail-nya.
+// Hanya format yang bisa didekode pustaka standar Go yang diterima: JPEG, PNG, dan GIF.
+package gambar
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/jpeg"
+	"net/http"
+
+	// mendaftarkan dekoder GIF dan PNG untuk image.Decode
+	_ "image/gif"
+	_ "image/png"
+)
+
+// Batas dimensi gambar, agar berkas kecil dengan dimensi raksasa tidak menghabiskan memori
+const (
+	SisiMaks   = 8000
+	PikselMaks = 40_000_000
+)
+
+var (
+	// ErrFormat dikembalikan jika isi berkas bukan JPEG, PNG, atau GIF
+	ErrFormat = errors.New("format gambar harus JPEG, PNG, atau GIF")
+	// ErrDimensi dikembalikan jika gambar terlalu besar untuk diproses
+	ErrDimensi = fmt.Errorf("dimensi gambar maksimal %dx%d piksel", SisiMaks, SisiMaks)
+)
+
+// formatDikenal memetakan tipe MIME hasil deteksi isi berkas ke ekstensinya
+var formatDikenal = map[string]string{
+	"image/jpeg": "jpg",
+	"image/png":  "png",
+	"image/gif":  "gif",
+}
+
+// Hasil adalah gambar yang sudah diperiksa beserta thumbnail JPEG-nya
+type Hasil struct {
+	Tipe      string
+	Ekstensi  string
+	Lebar     int
+	Tinggi    int
+	Thumbnail []byte
+}
+
+// Proses memeriksa format dan dimensi isi, lalu membuat thumbnail yang sisi
+// terpanjangnya paling besar sisi piksel. Format ditentukan dari isi berkas,
+// bukan dari nama atau Content-Type yang dikirim klien.
+func Proses(isi []byte, sisi int) (Hasil, error) {
+	tipe := http.DetectContentType(isi)
+	ext, ok := formatDikenal[tipe]
+	if !ok {
+		return Hasil{}, ErrFormat
+	}
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(isi))
+	if err != nil {
+		return Hasil{}, ErrFormat
+	}
+	if cfg.Width < 1 || cfg.Height < 1 || cfg.Width > SisiMaks || cfg.Height > SisiMaks || cfg.Width*cfg.Height > PikselMaks {
+		return Hasil{}, ErrDimensi
+	}
+	img, _, err := image.Decode(bytes.NewReader(isi))
+	if err != nil {
+		return Hasil{}, ErrFormat
+	}
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, Perkecil(img, sisi), &jpeg.Options{Quality: 80}); err != nil {
+		return Hasil{}, err
+	}
+	return Hasil{Tipe: tipe, Ekstensi: ext, Lebar: cfg.Width, Tinggi: cfg.Height, Thumbnail: buf.Bytes()}, nil
+}
+
+// Perkecil mengecilkan img agar sisi terpanjangnya paling besar sisi piksel dengan
+// merata-ratakan piksel sumber di setiap kotak tujuan. Gambar yang sudah kecil
+// tidak diperbesar. Bagian transparan diberi latar putih karena hasilnya JPEG.
+func Perkecil(img image.Image, sisi int) *image.RGBA {
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	tw, th := w, h
+	if w > sisi || h > sisi {
+		if w >= h {
+			tw, th = sisi, max(1, h*sisi/w)
+		} else {
+			tw, th = max(1, w*sisi/h), sisi
+		}
+	}
+
+	// salin ke RGBA berlatar putih dulu agar piksel bisa dibaca langsung dari Pix
+	src := image.NewRGBA(image.Rect(0, 0, w, h))
+	draw.Draw(src, src.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	draw.Draw(src, src.Bounds(), img, b.Min, draw.Over)
+	if tw == w && th == h {
+		return src
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, tw, th))
+	for y := 0; y < th; y++ {
+		y0, y1 := y*h/th, max((y+1)*h/th, y*h/th+1)
+		for x := 0; x < tw; x++ {
+			x0, x1 := x*w/tw, max((x+1)*w/tw, x*w/tw+1)
+			var r, g, bl, a, n int
+			for sy := y0; sy < y1; sy++ {
+				i := src.PixOffset(x0, sy)
+				for sx := x0; sx < x1; sx++ {
+					r += int(src.Pix[i])
+					g += int(src.Pix[i+1])
+					bl += int(src.Pix[i+2])
+					a += int(src.Pix[i+3])
+					n++
+					i += 4
+				}
+			}
+			j := dst.PixOffset(x, y)
+			dst.Pix[j] = uint8(r / n)
+			dst.Pix[j+1] = uint8(g / n)
+			dst.Pix[j+2] = uint8(bl / n)
+			dst.Pix[j+3] = uint8(a / n)
+		}
+	}
+	return dst
+}
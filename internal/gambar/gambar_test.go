@@ -0,0 +1,123 @@
+package gambar
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"testing"
+)
+
+func polos(w, h int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for i := range img.Pix {
+		img.Pix[i] = 0xff
+	}
+	return img
+}
+
+func kodekan(t *testing.T, img image.Image, format string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	var err error
+	switch format {
+	case "png":
+		err = png.Encode(&buf, img)
+	case "jpeg":
+		err = jpeg.Encode(&buf, img, nil)
+	case "gif":
+		err = gif.Encode(&buf, img, nil)
+	}
+	if err != nil {
+		t.Fatalf("encode %s: %v", format, err)
+	}
+	return buf.Bytes()
+}
+
+// headerPNG membuat PNG 1x1 lalu mengganti ukuran di header IHDR-nya, agar
+// dimensi raksasa bisa diuji tanpa membuat gambar sebesar itu
+func headerPNG(t *testing.T, w, h uint32) []byte {
+	t.Helper()
+	data := kodekan(t, polos(1, 1), "png")
+	// tanda tangan 8 byte, lalu panjang dan tipe chunk IHDR 8 byte
+	ihdr := data[16:29]
+	binary.BigEndian.PutUint32(ihdr[0:4], w)
+	binary.BigEndian.PutUint32(ihdr[4:8], h)
+	binary.BigEndian.PutUint32(data[29:33], crc32.ChecksumIEEE(data[12:29]))
+	return data
+}
+
+func TestProses(t *testing.T) {
+	for _, tc := range []struct {
+		format, tipe, ext string
+	}{
+		{"png", "image/png", "png"},
+		{"jpeg", "image/jpeg", "jpg"},
+		{"gif", "image/gif", "gif"},
+	} {
+		h, err := Proses(kodekan(t, polos(400, 100), tc.format), 200)
+		if err != nil {
+			t.Fatalf("Proses %s: %v", tc.format, err)
+		}
+		if h.Tipe != tc.tipe || h.Ekstensi != tc.ext || h.Lebar != 400 || h.Tinggi != 100 {
+			t.Errorf("Proses %s = %s %s %dx%d", tc.format, h.Tipe, h.Ekstensi, h.Lebar, h.Tinggi)
+		}
+		thumb, err := jpeg.DecodeConfig(bytes.NewReader(h.Thumbnail))
+		if err != nil {
+			t.Fatalf("thumbnail %s bukan JPEG: %v", tc.format, err)
+		}
+		if thumb.Width != 200 || thumb.Height != 50 {
+			t.Errorf("thumbnail %s = %dx%d, ingin 200x50", tc.format, thumb.Width, thumb.Height)
+		}
+	}
+
+	for nama, isi := range map[string][]byte{
+		"teks":          []byte("bukan gambar"),
+		"svg":           []byte(`<svg xmlns="http://www.w3.org/2000/svg"></svg>`),
+		"png terpotong": kodekan(t, polos(10, 10), "png")[:40],
+		"kosong":        nil,
+	} {
+		if _, err := Proses(isi, 200); !errors.Is(err, ErrFormat) {
+			t.Errorf("Proses %s = %v, ingin ErrFormat", nama, err)
+		}
+	}
+	for _, ukuran := range [][2]uint32{{SisiMaks + 1, 1}, {1, SisiMaks + 1}, {7000, 7000}} {
+		if _, err := Proses(headerPNG(t, ukuran[0], ukuran[1]), 200); !errors.Is(err, ErrDimensi) {
+			t.Errorf("Proses %dx%d = %v, ingin ErrDimensi", ukuran[0], ukuran[1], err)
+		}
+	}
+}
+
+func TestPerkecil(t *testing.T) {
+	for _, tc := range []struct {
+		w, h, sisi, tw, th int
+	}{
+		{400, 100, 200, 200, 50},
+		{100, 400, 200, 50, 200},
+		{1000, 1, 100, 100, 1},
+		{50, 30, 200, 50, 30}, // tidak diperbesar
+	} {
+		if b := Perkecil(polos(tc.w, tc.h), tc.sisi).Bounds(); b.Dx() != tc.tw || b.Dy() != tc.th {
+			t.Errorf("Perkecil %dx%d ke %d = %dx%d, ingin %dx%d", tc.w, tc.h, tc.sisi, b.Dx(), b.Dy(), tc.tw, tc.th)
+		}
+	}
+
+	// Piksel transparan menjadi putih, setengah hitam setengah putih dirata-ratakan
+	img := image.NewRGBA(image.Rect(0, 0, 4, 2))
+	img.Set(0, 0, color.Black)
+	img.Set(0, 1, color.Black)
+	img.Set(1, 0, color.Black)
+	img.Set(1, 1, color.Black)
+	kecil := Perkecil(img, 2)
+	if c := kecil.RGBAAt(0, 0); c.R != 0 || c.A != 0xff {
+		t.Errorf("piksel kiri = %+v, ingin hitam", c)
+	}
+	if c := kecil.RGBAAt(1, 0); c.R != 0xff || c.A != 0xff {
+		t.Errorf("piksel kanan = %+v, ingin putih dari latar transparan", c)
+	}
+}
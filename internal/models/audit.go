@@ -0,0 +1,26 @@
+// file: scm-api/internal/models/audit.go
+package models
+
+import "encoding/json"
+
+// Aksi yang dicatat di jejak audit
+const (
+	AuditBuat     = "buat"
+	AuditUbah     = "ubah"
+	AuditHapus    = "hapus"
+	AuditPulihkan = "pulihkan"
+)
+
+// AuditLog merepresentasikan tabel 'audit_log'. Sebelum kosong untuk aksi buat,
+// Sesudah kosong untuk aksi hapus, dan Perubahan hanya berisi field yang berbeda.
+type AuditLog struct {
+	AuditID   int64           `json:"audit_id"`
+	Waktu     string          `json:"waktu"`
+	Aktor     string          `json:"aktor"`
+	Entitas   string          `json:"entitas"`
+	EntitasID int64           `json:"entitas_id"`
+	Aksi      string          `json:"aksi"`
+	Sebelum   json.RawMessage `json:"sebelum"`
+	Sesudah   json.RawMessage `json:"sesudah"`
+	Perubahan json.RawMessage `json:"perubahan"`
+}
@@ -0,0 +1,13 @@
+// file: scm-api/internal/models/barcode.go
+package models
+
+// ProdukBarcode merepresentasikan tabel 'produk_barcode'. GTIN adalah Kode yang
+// sudah dinormalisasi menjadi 14 digit dan dipakai untuk pencarian.
+type ProdukBarcode struct {
+	BarcodeID int64  `json:"barcode_id"`
+	ProdukID  int64  `json:"produk_id"`
+	Kode      string `json:"kode"`
+	GTIN      string `json:"gtin"`
+	Internal  bool   `json:"internal"`
+	CreatedAt string `json:"created_at"`
+}
@@ -1,11 +1,15 @@
 package models
 
-// DetailPembelian merepresentasikan tabel 'detail_pembelian' (item dalam transaksi)
+// DetailPembelian merepresentasikan tabel 'detail_pembelian' (item dalam transaksi).
+// Jumlah dan HargaBeliSatuan dinyatakan dalam Satuan; satu Satuan berisi
+// FaktorKonversi satuan dasar produk.
 type DetailPembelian struct {
 	DetailPembelianID int64   `json:"detail_pembelian_id"`
 	PembelianID       int64   `json:"pembelian_id"`
 	ProdukID          int64   `json:"produk_id"`
 	Jumlah            int     `json:"jumlah"`
+	Satuan            string  `json:"satuan"`
+	FaktorKonversi    int     `json:"faktor_konversi"`
 	HargaBeliSatuan   float64 `json:"harga_beli_satuan"`
 	Subtotal          float64 `json:"subtotal"`
 }
@@ -1,9 +1,13 @@
 // file: scm-api/internal/models/gudang.go
 package models
 
+import "database/sql"
+
 // Gudang merepresentasikan tabel gudang di database
 type Gudang struct {
 	GudangID   int64  `json:"gudang_id"`
 	NamaGudang string `json:"nama_gudang"`
 	Lokasi     string `json:"lokasi"`
+	// DeletedAt terisi jika gudang sudah diarsipkan (soft delete)
+	DeletedAt sql.NullString `json:"deleted_at"`
 }
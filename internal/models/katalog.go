@@ -0,0 +1,44 @@
+// file: scm-api/internal/models/katalog.go
+package models
+
+import "database/sql"
+
+// SupplierProduk merepresentasikan tabel 'supplier_produk': satu baris katalog harga
+// supplier untuk satu produk. HargaBeli dan MOQ dinyatakan dalam Satuan. Masa berlaku
+// yang kosong berarti tidak dibatasi di sisi itu. NamaSupplier dan NamaProduk diisi
+// dari tabel supplier dan produk saat dibaca.
+type SupplierProduk struct {
+	SupplierProdukID int64          `json:"supplier_produk_id"`
+	SupplierID       int64          `json:"supplier_id"`
+	NamaSupplier     string         `json:"nama_supplier"`
+	ProdukID         int64          `json:"produk_id"`
+	NamaProduk       string         `json:"nama_produk"`
+	SKUSupplier      sql.NullString `json:"sku_supplier"`
+	Satuan           string         `json:"satuan"`
+	HargaBeli        float64        `json:"harga_beli"`
+	MOQ              int            `json:"moq"`
+	LeadTimeHari     int            `json:"lead_time_hari"`
+	BerlakuDari      sql.NullString `json:"berlaku_dari"`
+	BerlakuSampai    sql.NullString `json:"berlaku_sampai"`
+	CreatedAt        string         `json:"created_at"`
+	UpdatedAt        string         `json:"updated_at"`
+}
+
+// KatalogBerfaktor adalah harga katalog beserta faktor satuannya ke satuan dasar
+// produk. Faktor 0 berarti satuan katalog belum terdaftar untuk produk tersebut.
+type KatalogBerfaktor struct {
+	SupplierProduk
+	Faktor int
+}
+
+// BerlakuPada memberi tahu apakah harga ini berlaku pada tanggal (YYYY-MM-DD)
+func (k SupplierProduk) BerlakuPada(tanggal string) bool {
+	return (!k.BerlakuDari.Valid || k.BerlakuDari.String <= tanggal) &&
+		(!k.BerlakuSampai.Valid || k.BerlakuSampai.String >= tanggal)
+}
+
+// Bertumpuk memberi tahu apakah masa berlaku k dan lain beririsan
+func (k SupplierProduk) Bertumpuk(lain SupplierProduk) bool {
+	return (!k.BerlakuDari.Valid || !lain.BerlakuSampai.Valid || k.BerlakuDari.String <= lain.BerlakuSampai.String) &&
+		(!lain.BerlakuDari.Valid || !k.BerlakuSampai.Valid || lain.BerlakuDari.String <= k.BerlakuSampai.String)
+}
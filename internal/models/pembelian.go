@@ -12,4 +12,62 @@ type Pembelian struct {
 	EstimasiTiba sql.NullString  `json:"estimasi_tiba"`
 	TotalBiaya   sql.NullFloat64 `json:"total_biaya"`
 	Status       string          `json:"status"`
+	// GudangTujuanID adalah gudang bawaan saat barang pesanan diterima
+	GudangTujuanID sql.NullInt64 `json:"gudang_tujuan_id"`
+}
+
+// Status yang bisa dimiliki sebuah pembelian
+const (
+	StatusPembelianDraft            = "Draft"
+	StatusPembelianDipesan          = "Dipesan"
+	StatusPembelianDikirim          = "Dikirim"
+	StatusPembelianDiterimaSebagian = "Diterima Sebagian"
+	StatusPembelianDiterima         = "Diterima"
+	StatusPembelianDibatalkan       = "Dibatalkan"
+	// StatusPembelianDitutup berarti sisa pesanan yang diterima sebagian tidak akan
+	// dikirim supplier. Sisanya tidak dicatat sebagai stok maupun pesanan terbuka.
+	StatusPembelianDitutup = "Ditutup"
+)
+
+// transisiStatusPembelian berisi perpindahan status yang diizinkan.
+// Diterima, Ditutup, dan Dibatalkan adalah status akhir. Diterima Sebagian boleh
+// "berpindah" ke dirinya sendiri karena penerimaan parsial bisa terjadi berkali-kali.
+var transisiStatusPembelian = map[string][]string{
+	StatusPembelianDraft:            {StatusPembelianDipesan, StatusPembelianDibatalkan},
+	StatusPembelianDipesan:          {StatusPembelianDikirim, StatusPembelianDiterimaSebagian, StatusPembelianDiterima, StatusPembelianDibatalkan},
+	StatusPembelianDikirim:          {StatusPembelianDiterimaSebagian, StatusPembelianDiterima, StatusPembelianDibatalkan},
+	StatusPembelianDiterimaSebagian: {StatusPembelianDiterimaSebagian, StatusPembelianDiterima, StatusPembelianDitutup},
+}
+
+// BolehTransisiPembelian memeriksa apakah status pembelian boleh berpindah dari dari ke ke
+func BolehTransisiPembelian(dari, ke string) bool {
+	for _, s := range transisiStatusPembelian[dari] {
+		if s == ke {
+			return true
+		}
+	}
+	return false
+}
+
+// BolehHapusPembelian memeriksa apakah pembelian berstatus status boleh dihapus.
+// Pesanan yang sudah dikirim ke supplier harus dibatalkan lebih dulu.
+func BolehHapusPembelian(status string) bool {
+	return status == StatusPembelianDraft || status == StatusPembelianDibatalkan
+}
+
+// PembelianBaru adalah header dan baris satu pesanan yang disimpan bersama pesanan lain
+type PembelianBaru struct {
+	Pembelian Pembelian
+	Details   []DetailPembelian
+}
+
+// RiwayatStatusPembelian merepresentasikan tabel 'pembelian_status_riwayat'
+type RiwayatStatusPembelian struct {
+	RiwayatID   int64          `json:"riwayat_id"`
+	PembelianID int64          `json:"pembelian_id"`
+	StatusDari  sql.NullString `json:"status_dari"`
+	StatusKe    string         `json:"status_ke"`
+	DiubahOleh  string         `json:"diubah_oleh"`
+	Catatan     sql.NullString `json:"catatan"`
+	Waktu       string         `json:"waktu"`
 }
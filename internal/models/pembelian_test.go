@@ -0,0 +1,48 @@
+package models
+
+import "testing"
+
+func TestBolehTransisiPembelian(t *testing.T) {
+	semua := []string{
+		StatusPembelianDraft, StatusPembelianDipesan, StatusPembelianDikirim,
+		StatusPembelianDiterimaSebagian, StatusPembelianDiterima, StatusPembelianDibatalkan, StatusPembelianDitutup,
+	}
+	boleh := map[[2]string]bool{
+		{StatusPembelianDraft, StatusPembelianDipesan}:                     true,
+		{StatusPembelianDraft, StatusPembelianDibatalkan}:                  true,
+		{StatusPembelianDipesan, StatusPembelianDikirim}:                   true,
+		{StatusPembelianDipesan, StatusPembelianDiterimaSebagian}:          true,
+		{StatusPembelianDipesan, StatusPembelianDiterima}:                  true,
+		{StatusPembelianDipesan, StatusPembelianDibatalkan}:                true,
+		{StatusPembelianDikirim, StatusPembelianDiterimaSebagian}:          true,
+		{StatusPembelianDikirim, StatusPembelianDiterima}:                  true,
+		{StatusPembelianDikirim, StatusPembelianDibatalkan}:                true,
+		{StatusPembelianDiterimaSebagian, StatusPembelianDiterimaSebagian}: true,
+		{StatusPembelianDiterimaSebagian, StatusPembelianDiterima}:         true,
+		{StatusPembelianDiterimaSebagian, StatusPembelianDitutup}:          true,
+	}
+	// Setiap pasangan status diperiksa, termasuk status akhir dan status yang tidak dikenal
+	for _, dari := range append(semua, "Hilang") {
+		for _, ke := range append(semua, "Hilang") {
+			if got := BolehTransisiPembelian(dari, ke); got != boleh[[2]string{dari, ke}] {
+				t.Errorf("BolehTransisiPembelian(%q, %q) = %v, ingin %v", dari, ke, got, !got)
+			}
+		}
+	}
+}
+
+func TestBolehHapusPembelian(t *testing.T) {
+	for status, ingin := range map[string]bool{
+		StatusPembelianDraft:            true,
+		StatusPembelianDipesan:          false,
+		StatusPembelianDikirim:          false,
+		StatusPembelianDiterimaSebagian: false,
+		StatusPembelianDiterima:         false,
+		StatusPembelianDibatalkan:       true,
+		StatusPembelianDitutup:          false,
+	} {
+		if got := BolehHapusPembelian(status); got != ingin {
+			t.Errorf("BolehHapusPembelian(%q) = %v, ingin %v", status, got, ingin)
+		}
+	}
+}
@@ -0,0 +1,52 @@
+// file: scm-api/internal/models/penerimaan.go
+
+package models
+
+import "database/sql"
+
+// Penerimaan merepresentasikan tabel 'penerimaan' (dokumen penerimaan barang)
+type Penerimaan struct {
+	PenerimaanID  int64          `json:"penerimaan_id"`
+	PembelianID   int64          `json:"pembelian_id"`
+	TanggalTerima string         `json:"tanggal_terima"`
+	DiterimaOleh  string         `json:"diterima_oleh"`
+	Catatan       sql.NullString `json:"catatan"`
+	// GudangID adalah gudang tujuan untuk baris yang tidak menyebut gudangnya sendiri.
+	// Tidak disimpan di header; gudang akhirnya dicatat per baris.
+	GudangID int64              `json:"gudang_id,omitempty"`
+	Details  []DetailPenerimaan `json:"details"`
+}
+
+// DetailPenerimaan merepresentasikan tabel 'detail_penerimaan'.
+// JumlahDiterima dan JumlahDitolak dinyatakan dalam satuan baris pembelian.
+// JumlahStok adalah JumlahDiterima dalam satuan dasar produk, yaitu yang masuk
+// ke stok; JumlahDitolak hanya dicatat.
+type DetailPenerimaan struct {
+	DetailPenerimaanID int64          `json:"detail_penerimaan_id"`
+	PenerimaanID       int64          `json:"penerimaan_id"`
+	DetailPembelianID  int64          `json:"detail_pembelian_id"`
+	ProdukID           int64          `json:"produk_id"`
+	GudangID           int64          `json:"gudang_id"`
+	JumlahDiterima     int            `json:"jumlah_diterima"`
+	JumlahDitolak      int            `json:"jumlah_ditolak"`
+	JumlahStok         int            `json:"jumlah_stok"`
+	AlasanTolak        sql.NullString `json:"alasan_tolak"`
+	// Data lot. Jika nomor lot atau tanggal kedaluwarsa diisi, jumlah diterima
+	// dicatat sebagai batch baru di stok_batch.
+	NomorLot           sql.NullString `json:"nomor_lot"`
+	TanggalProduksi    sql.NullString `json:"tanggal_produksi"`
+	TanggalKedaluwarsa sql.NullString `json:"tanggal_kedaluwarsa"`
+}
+
+// Batch mengembalikan data lot baris ini sebagai batch baru, atau nil jika tidak ada data lot
+func (d DetailPenerimaan) Batch() *StokBatch {
+	if !d.NomorLot.Valid && !d.TanggalKedaluwarsa.Valid {
+		return nil
+	}
+	return &StokBatch{
+		NomorLot:           d.NomorLot.String,
+		TanggalProduksi:    d.TanggalProduksi,
+		TanggalKedaluwarsa: d.TanggalKedaluwarsa,
+		PenerimaanID:       sql.NullInt64{Int64: d.PenerimaanID, Valid: d.PenerimaanID != 0},
+	}
+}
@@ -0,0 +1,14 @@
+// file: scm-api/internal/models/pengguna.go
+package models
+
+// Pengguna merepresentasikan tabel pengguna di database
+type Pengguna struct {
+	PenggunaID    int64  `json:"pengguna_id"`
+	Username      string `json:"username"`
+	NamaLengkap   string `json:"nama_lengkap"`
+	PasswordHash  string `json:"-"`
+	Aktif         bool   `json:"aktif"`
+	TanggalDibuat string `json:"tanggal_dibuat"`
+	// VersiToken harus sama dengan versi di access token agar token diterima
+	VersiToken int `json:"-"`
+}
@@ -0,0 +1,132 @@
+// file: scm-api/internal/models/permintaan.go
+
+package models
+
+import (
+	"database/sql"
+	"strings"
+)
+
+// Status yang bisa dimiliki sebuah permintaan pembelian
+const (
+	StatusPermintaanDiajukan   = "Diajukan"
+	StatusPermintaanDisetujui  = "Disetujui"
+	StatusPermintaanDitolak    = "Ditolak"
+	StatusPermintaanDibatalkan = "Dibatalkan"
+	// StatusPermintaanSelesai berarti permintaan sudah dikonversi menjadi pembelian
+	StatusPermintaanSelesai = "Selesai"
+)
+
+// transisiStatusPermintaan berisi perpindahan status permintaan yang diizinkan.
+// Ditolak, Dibatalkan, dan Selesai adalah status akhir.
+var transisiStatusPermintaan = map[string][]string{
+	StatusPermintaanDiajukan:  {StatusPermintaanDisetujui, StatusPermintaanDitolak, StatusPermintaanDibatalkan},
+	StatusPermintaanDisetujui: {StatusPermintaanSelesai, StatusPermintaanDibatalkan},
+}
+
+// BolehTransisiPermintaan memeriksa apakah status permintaan boleh berpindah dari dari ke ke
+func BolehTransisiPermintaan(dari, ke string) bool {
+	for _, s := range transisiStatusPermintaan[dari] {
+		if s == ke {
+			return true
+		}
+	}
+	return false
+}
+
+// Status satu tahap persetujuan. Tahap lain di level yang sama menjadi Dilewati
+// setelah salah satunya disetujui, dan semua tahap yang masih menunggu menjadi
+// Dilewati jika permintaan ditolak atau dibatalkan.
+const (
+	PersetujuanMenunggu  = "Menunggu"
+	PersetujuanDisetujui = "Disetujui"
+	PersetujuanDitolak   = "Ditolak"
+	PersetujuanDilewati  = "Dilewati"
+)
+
+// PermintaanPembelian merepresentasikan tabel 'permintaan_pembelian' (header
+// purchase requisition). NamaSupplier diisi dari tabel supplier saat dibaca.
+// PembelianID terisi setelah permintaan dikonversi menjadi pembelian.
+type PermintaanPembelian struct {
+	PermintaanID   int64          `json:"permintaan_id"`
+	DimintaOleh    string         `json:"diminta_oleh"`
+	SupplierID     sql.NullInt64  `json:"supplier_id"`
+	NamaSupplier   sql.NullString `json:"nama_supplier"`
+	GudangTujuanID sql.NullInt64  `json:"gudang_tujuan_id"`
+	TanggalButuh   sql.NullString `json:"tanggal_butuh"`
+	Catatan        sql.NullString `json:"catatan"`
+	TotalBiaya     float64        `json:"total_biaya"`
+	Status         string         `json:"status"`
+	PembelianID    sql.NullInt64  `json:"pembelian_id"`
+	CreatedAt      string         `json:"created_at"`
+	UpdatedAt      string         `json:"updated_at"`
+}
+
+// PermintaanDenganDetail adalah permintaan beserta item dan tahap persetujuannya
+type PermintaanDenganDetail struct {
+	PermintaanPembelian
+	Details     []DetailPermintaan      `json:"details"`
+	Persetujuan []PersetujuanPermintaan `json:"persetujuan"`
+}
+
+// DetailPermintaan merepresentasikan tabel 'detail_permintaan'. Jumlah dan
+// HargaPerkiraan dinyatakan dalam Satuan, seperti detail_pembelian. NamaProduk dan
+// Kategori diisi dari tabel produk saat dibaca.
+type DetailPermintaan struct {
+	DetailPermintaanID int64          `json:"detail_permintaan_id"`
+	PermintaanID       int64          `json:"permintaan_id"`
+	ProdukID           int64          `json:"produk_id"`
+	NamaProduk         string         `json:"nama_produk"`
+	Kategori           sql.NullString `json:"kategori"`
+	Jumlah             int            `json:"jumlah"`
+	Satuan             string         `json:"satuan"`
+	FaktorKonversi     int            `json:"faktor_konversi"`
+	HargaPerkiraan     float64        `json:"harga_perkiraan"`
+	Subtotal           float64        `json:"subtotal"`
+}
+
+// PersetujuanPermintaan merepresentasikan tabel 'persetujuan_permintaan': satu tahap
+// persetujuan yang harus diputuskan oleh pengguna dengan role RoleID. Tahap disalin
+// dari aturan_persetujuan saat permintaan diajukan, sehingga perubahan aturan tidak
+// mengubah permintaan yang sedang berjalan.
+type PersetujuanPermintaan struct {
+	PersetujuanID  int64          `json:"persetujuan_id"`
+	PermintaanID   int64          `json:"permintaan_id"`
+	Level          int            `json:"level"`
+	RoleID         int64          `json:"role_id"`
+	NamaRole       string         `json:"nama_role"`
+	Status         string         `json:"status"`
+	DiputuskanOleh sql.NullString `json:"diputuskan_oleh"`
+	Komentar       sql.NullString `json:"komentar"`
+	Waktu          sql.NullString `json:"waktu"`
+}
+
+// AturanPersetujuan merepresentasikan tabel 'aturan_persetujuan'. Aturan berlaku
+// untuk permintaan dengan total_biaya minimal NilaiMinimal dan, jika Kategori diisi,
+// yang memuat produk berkategori tersebut. NamaRole diisi dari tabel role saat dibaca.
+type AturanPersetujuan struct {
+	AturanID     int64          `json:"aturan_id"`
+	Level        int            `json:"level"`
+	NilaiMinimal float64        `json:"nilai_minimal"`
+	Kategori     sql.NullString `json:"kategori"`
+	RoleID       int64          `json:"role_id"`
+	NamaRole     string         `json:"nama_role"`
+	CreatedAt    string         `json:"created_at"`
+}
+
+// Berlaku memberi tahu apakah aturan ini berlaku untuk permintaan dengan total dan
+// kategori produk tersebut. Kategori dibandingkan tanpa membedakan huruf besar/kecil.
+func (a AturanPersetujuan) Berlaku(total float64, kategori []string) bool {
+	if total < a.NilaiMinimal {
+		return false
+	}
+	if !a.Kategori.Valid {
+		return true
+	}
+	for _, k := range kategori {
+		if strings.EqualFold(k, a.Kategori.String) {
+			return true
+		}
+	}
+	return false
+}
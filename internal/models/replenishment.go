@@ -0,0 +1,63 @@
+// file: scm-api/internal/models/replenishment.go
+
+package models
+
+import "database/sql"
+
+// ParameterStok merepresentasikan tabel 'stok_parameter': batas persediaan satu produk
+// di satu gudang, dalam satuan dasar produk. ReorderPoint kosong berarti dihitung dari
+// pemakaian harian × lead time ditambah SafetyStock; MaxLevel kosong berarti target
+// stok dihitung dari cakupan hari. NamaProduk dan NamaGudang diisi saat dibaca.
+type ParameterStok struct {
+	ParameterID  int64         `json:"parameter_id"`
+	ProdukID     int64         `json:"produk_id"`
+	NamaProduk   string        `json:"nama_produk"`
+	GudangID     int64         `json:"gudang_id"`
+	NamaGudang   string        `json:"nama_gudang"`
+	ReorderPoint sql.NullInt64 `json:"reorder_point"`
+	SafetyStock  int           `json:"safety_stock"`
+	MaxLevel     sql.NullInt64 `json:"max_level"`
+	UpdatedAt    string        `json:"updated_at"`
+}
+
+// KandidatSaran adalah parameter stok produk yang belum dihapus di gudang yang tidak
+// diarsipkan, beserta stok dan data produk yang dibutuhkan saran pemesanan
+type KandidatSaran struct {
+	ParameterStok
+	Stok        int
+	SatuanDasar string
+	// SupplierID adalah supplier utama produk. NamaSupplier NULL jika produk tidak
+	// punya supplier utama atau suppliernya sudah dihapus.
+	SupplierID   sql.NullInt64
+	NamaSupplier sql.NullString
+}
+
+// PesananTerbuka adalah jumlah barang (satuan dasar) yang sudah dipesan untuk sebuah
+// gudang tetapi belum datang: sisa baris pembelian Draft, Dipesan, Dikirim, dan
+// Diterima Sebagian dengan gudang_tujuan_id tersebut.
+type PesananTerbuka struct {
+	ProdukID int64 `json:"produk_id"`
+	GudangID int64 `json:"gudang_id"`
+	Jumlah   int   `json:"jumlah"`
+}
+
+// PemakaianHarian adalah jumlah stok keluar (satuan dasar) satu produk di satu gudang
+// pada satu tanggal (YYYY-MM-DD), dari mutasi penjualan dan transfer yang mengurangi
+// stok. Hari tanpa pemakaian tidak punya baris.
+type PemakaianHarian struct {
+	ProdukID int64  `json:"produk_id"`
+	GudangID int64  `json:"gudang_id"`
+	Tanggal  string `json:"tanggal"`
+	Jumlah   int    `json:"jumlah"`
+}
+
+// TipeMutasiPemakaian adalah tipe mutasi yang dihitung sebagai pemakaian jika
+// perubahannya negatif. Penyesuaian tidak termasuk karena selisih stock opname dan
+// penghapusan batch kedaluwarsa adalah penyusutan, bukan permintaan; retur ke
+// supplier juga bukan permintaan.
+var TipeMutasiPemakaian = []string{MutasiPenjualan, MutasiTransfer}
+
+// TipeMutasiPermintaan adalah tipe mutasi yang dihitung sebagai permintaan beberapa
+// gudang sekaligus. Transfer keluar tidak termasuk karena barangnya masih milik
+// perusahaan dan baru dihitung saat terjual di gudang tujuan.
+var TipeMutasiPermintaan = []string{MutasiPenjualan}
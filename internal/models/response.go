@@ -0,0 +1,129 @@
+// file: scm-api/internal/models/response.go
+
+package models
+
+import "database/sql"
+
+// PembelianResponse adalah header pembelian yang digabung dengan nama supplier
+type PembelianResponse struct {
+	PembelianID    int64           `json:"pembelian_id"`
+	SupplierID     int64           `json:"supplier_id"`
+	NamaSupplier   string          `json:"nama_supplier"`
+	TanggalPesan   string          `json:"tanggal_pesan"`
+	EstimasiTiba   sql.NullString  `json:"estimasi_tiba"`
+	TotalBiaya     sql.NullFloat64 `json:"total_biaya"`
+	Status         string          `json:"status"`
+	GudangTujuanID sql.NullInt64   `json:"gudang_tujuan_id"`
+}
+
+// DetailPembelianResponse adalah item pembelian yang digabung dengan nama produk
+// beserta jumlah yang sudah diterima/ditolak dan sisa yang masih ditunggu.
+// Semua jumlah dinyatakan dalam Satuan baris, bukan SatuanDasar produk.
+type DetailPembelianResponse struct {
+	DetailPembelianID int64   `json:"detail_pembelian_id"`
+	ProdukID          int64   `json:"produk_id"`
+	NamaProduk        string  `json:"nama_produk"`
+	Jumlah            int     `json:"jumlah"`
+	Satuan            string  `json:"satuan"`
+	FaktorKonversi    int     `json:"faktor_konversi"`
+	SatuanDasar       string  `json:"satuan_dasar"`
+	HargaBeliSatuan   float64 `json:"harga_beli_satuan"`
+	Subtotal          float64 `json:"subtotal"`
+	JumlahDiterima    int     `json:"jumlah_diterima"`
+	JumlahDitolak     int     `json:"jumlah_ditolak"`
+	Sisa              int     `json:"sisa"`
+}
+
+// PembelianDenganDetailResponse adalah header pembelian beserta seluruh itemnya
+type PembelianDenganDetailResponse struct {
+	PembelianID    int64                     `json:"pembelian_id"`
+	SupplierID     int64                     `json:"supplier_id"`
+	NamaSupplier   string                    `json:"nama_supplier"`
+	TanggalPesan   string                    `json:"tanggal_pesan"`
+	EstimasiTiba   sql.NullString            `json:"estimasi_tiba"`
+	TotalBiaya     sql.NullFloat64           `json:"total_biaya"`
+	Status         string                    `json:"status"`
+	GudangTujuanID sql.NullInt64             `json:"gudang_tujuan_id"`
+	Details        []DetailPembelianResponse `json:"details"`
+}
+
+// Halaman adalah amplop respons endpoint daftar: satu halaman data beserta keterangannya
+type Halaman[T any] struct {
+	Data []T         `json:"data"`
+	Meta MetaHalaman `json:"meta"`
+}
+
+// MetaHalaman menjelaskan halaman yang dikirim. Total adalah jumlah seluruh baris
+// yang cocok dengan filter, bukan hanya yang ada di halaman ini.
+type MetaHalaman struct {
+	Page       int    `json:"page"`
+	Limit      int    `json:"limit"`
+	Total      int    `json:"total"`
+	TotalPages int    `json:"total_pages"`
+	Sort       string `json:"sort"`
+	Dir        string `json:"dir"`
+}
+
+// StokResponse adalah struct untuk menampung data gabungan stok, produk, dan gudang
+type StokResponse struct {
+	StokID        int64  `json:"stok_id"`
+	ProdukID      int64  `json:"produk_id"`
+	NamaProduk    string `json:"nama_produk"`
+	GudangID      int64  `json:"gudang_id"`
+	NamaGudang    string `json:"nama_gudang"`
+	Jumlah        int    `json:"jumlah"`
+	TanggalUpdate string `json:"tanggal_update"`
+}
+
+// DashboardStats adalah struct untuk menampung data ringkasan dashboard
+type DashboardStats struct {
+	JumlahProduk    int `json:"jumlah_produk"`
+	JumlahSupplier  int `json:"jumlah_supplier"`
+	JumlahPembelian int `json:"jumlah_pembelian"`
+	JumlahGudang    int `json:"jumlah_gudang"`
+}
+
+// StokChartResponse adalah struct untuk data grafik stok per produk
+type StokChartResponse struct {
+	Labels []string `json:"labels"` // Untuk nama produk
+	Data   []int    `json:"data"`   // Untuk jumlah stok
+}
+
+// StokDalamPerjalananResponse adalah jumlah produk yang sudah dikirim dari gudang asal
+// tetapi belum diterima di gudang tujuan
+type StokDalamPerjalananResponse struct {
+	ProdukID         int64  `json:"produk_id"`
+	NamaProduk       string `json:"nama_produk"`
+	GudangAsalID     int64  `json:"gudang_asal_id"`
+	NamaGudangAsal   string `json:"nama_gudang_asal"`
+	GudangTujuanID   int64  `json:"gudang_tujuan_id"`
+	NamaGudangTujuan string `json:"nama_gudang_tujuan"`
+	Jumlah           int    `json:"jumlah"`
+}
+
+// StokBatchResponse adalah batch stok yang digabung dengan nama produk dan gudang.
+// SisaHari negatif berarti batch sudah kedaluwarsa.
+type StokBatchResponse struct {
+	StokBatch
+	NamaProduk string        `json:"nama_produk"`
+	NamaGudang string        `json:"nama_gudang"`
+	SisaHari   sql.NullInt64 `json:"sisa_hari"`
+}
+
+// TokenResponse dikembalikan setelah login atau refresh berhasil
+type TokenResponse struct {
+	AccessToken  string   `json:"access_token"`
+	TokenType    string   `json:"token_type"`
+	ExpiresIn    int64    `json:"expires_in"`
+	RefreshToken string   `json:"refresh_token"`
+	Pengguna     Pengguna `json:"pengguna"`
+}
+
+// PenggunaDenganRole adalah pengguna beserta nama role dan ID gudang tempatnya bekerja.
+// Izin hanya diisi untuk pengguna yang sedang login (GET /api/auth/saya).
+type PenggunaDenganRole struct {
+	Pengguna
+	Role   []string `json:"role"`
+	Gudang []int64  `json:"gudang"`
+	Izin   []string `json:"izin,omitempty"`
+}
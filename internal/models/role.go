@@ -0,0 +1,44 @@
+// file: scm-api/internal/models/role.go
+
+package models
+
+// Kode izin yang diperiksa per rute. Daftar yang sama disimpan di tabel izin
+// (lihat migrasi 0009_rbac dan sesudahnya) agar bisa diberikan ke role lewat API.
+const (
+	IzinProdukLihat         = "produk.lihat"
+	IzinProdukKelola        = "produk.kelola"
+	IzinSupplierLihat       = "supplier.lihat"
+	IzinSupplierKelola      = "supplier.kelola"
+	IzinPembelianLihat      = "pembelian.lihat"
+	IzinPembelianKelola     = "pembelian.kelola"
+	IzinPembelianTerima     = "pembelian.terima"
+	IzinPembelianNilaiBesar = "pembelian.nilai_besar"
+	IzinPermintaanLihat     = "permintaan.lihat"
+	IzinPermintaanBuat      = "permintaan.buat"
+	IzinPermintaanSetujui   = "permintaan.setujui"
+	IzinPermintaanAtur      = "permintaan.atur"
+	IzinGudangLihat         = "gudang.lihat"
+	IzinGudangKelola        = "gudang.kelola"
+	IzinGudangSemua         = "gudang.semua"
+	IzinStokLihat           = "stok.lihat"
+	IzinStokSesuaikan       = "stok.sesuaikan"
+	IzinTransferLihat       = "transfer.lihat"
+	IzinTransferKelola      = "transfer.kelola"
+	IzinDashboardLihat      = "dashboard.lihat"
+	IzinPenggunaKelola      = "pengguna.kelola"
+	IzinAuditLihat          = "audit.lihat"
+)
+
+// Izin merepresentasikan tabel izin
+type Izin struct {
+	Kode      string `json:"kode"`
+	Deskripsi string `json:"deskripsi"`
+}
+
+// Role merepresentasikan tabel role beserta kode izin yang dimilikinya
+type Role struct {
+	RoleID    int64    `json:"role_id"`
+	Nama      string   `json:"nama"`
+	Deskripsi string   `json:"deskripsi"`
+	Izin      []string `json:"izin"`
+}
@@ -0,0 +1,13 @@
+// file: scm-api/internal/models/satuan.go
+package models
+
+// ProdukSatuan merepresentasikan tabel 'produk_satuan': satuan beli selain satuan
+// dasar produk. Faktor adalah isi satu Satuan dalam satuan dasar, misalnya satu
+// "karung" beras berisi 25 "kg".
+type ProdukSatuan struct {
+	SatuanID  int64  `json:"satuan_id"`
+	ProdukID  int64  `json:"produk_id"`
+	Satuan    string `json:"satuan"`
+	Faktor    int    `json:"faktor"`
+	CreatedAt string `json:"created_at"`
+}
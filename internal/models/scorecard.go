@@ -0,0 +1,24 @@
+// file: scm-api/internal/models/scorecard.go
+
+package models
+
+import "database/sql"
+
+// BarisScorecard adalah satu baris detail_pembelian beserta data header dan
+// penerimaannya, bahan perhitungan scorecard supplier. Jumlah, JumlahDiterima, dan
+// JumlahDitolak dinyatakan dalam satuan baris; TerimaTerakhir adalah tanggal_terima
+// penerimaan terakhir untuk pesanan tersebut.
+type BarisScorecard struct {
+	PembelianID     int64          `json:"pembelian_id"`
+	SupplierID      int64          `json:"supplier_id"`
+	Status          string         `json:"status"`
+	TanggalPesan    string         `json:"tanggal_pesan"`
+	EstimasiTiba    sql.NullString `json:"estimasi_tiba"`
+	TerimaTerakhir  sql.NullString `json:"terima_terakhir"`
+	ProdukID        int64          `json:"produk_id"`
+	Jumlah          int            `json:"jumlah"`
+	FaktorKonversi  int            `json:"faktor_konversi"`
+	HargaBeliSatuan float64        `json:"harga_beli_satuan"`
+	JumlahDiterima  int            `json:"jumlah_diterima"`
+	JumlahDitolak   int            `json:"jumlah_ditolak"`
+}
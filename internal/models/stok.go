@@ -1,6 +1,8 @@
 // file: scm-api/internal/models/stok.go
 package models
 
+import "database/sql"
+
 // Stok merepresentasikan tabel stok di database
 type Stok struct {
 	StokID        int64  `json:"stok_id"`
@@ -9,3 +11,59 @@ type Stok struct {
 	Jumlah        int    `json:"jumlah"`
 	TanggalUpdate string `json:"tanggal_update"`
 }
+
+// Tipe mutasi stok
+const (
+	MutasiPenerimaan  = "penerimaan"
+	MutasiPenyesuaian = "penyesuaian"
+	MutasiTransfer    = "transfer"
+	MutasiPenjualan   = "penjualan"
+	MutasiRetur       = "retur"
+)
+
+// TipeMutasiValid memeriksa apakah tipe adalah salah satu tipe mutasi yang dikenal
+func TipeMutasiValid(tipe string) bool {
+	switch tipe {
+	case MutasiPenerimaan, MutasiPenyesuaian, MutasiTransfer, MutasiPenjualan, MutasiRetur:
+		return true
+	}
+	return false
+}
+
+// StokMutasi merepresentasikan tabel 'stok_mutasi' (buku besar perubahan stok)
+type StokMutasi struct {
+	MutasiID      int64          `json:"mutasi_id"`
+	ProdukID      int64          `json:"produk_id"`
+	GudangID      int64          `json:"gudang_id"`
+	Tipe          string         `json:"tipe"`
+	JumlahSebelum int            `json:"jumlah_sebelum"`
+	JumlahSesudah int            `json:"jumlah_sesudah"`
+	Perubahan     int            `json:"perubahan"`
+	ReferensiTipe sql.NullString `json:"referensi_tipe"`
+	ReferensiID   sql.NullInt64  `json:"referensi_id"`
+	DibuatOleh    string         `json:"dibuat_oleh"`
+	Catatan       sql.NullString `json:"catatan"`
+	Waktu         string         `json:"waktu"`
+}
+
+// StokBatch merepresentasikan tabel 'stok_batch' (stok per lot di sebuah gudang)
+type StokBatch struct {
+	BatchID            int64          `json:"batch_id"`
+	ProdukID           int64          `json:"produk_id"`
+	GudangID           int64          `json:"gudang_id"`
+	NomorLot           string         `json:"nomor_lot"`
+	TanggalProduksi    sql.NullString `json:"tanggal_produksi"`
+	TanggalKedaluwarsa sql.NullString `json:"tanggal_kedaluwarsa"`
+	Jumlah             int            `json:"jumlah"`
+	PenerimaanID       sql.NullInt64  `json:"penerimaan_id"`
+	TanggalMasuk       string         `json:"tanggal_masuk"`
+}
+
+// PemakaianBatch adalah jumlah yang diambil dari satu batch saat stok keluar
+type PemakaianBatch struct {
+	BatchID            int64          `json:"batch_id"`
+	NomorLot           string         `json:"nomor_lot"`
+	TanggalProduksi    sql.NullString `json:"tanggal_produksi"`
+	TanggalKedaluwarsa sql.NullString `json:"tanggal_kedaluwarsa"`
+	Jumlah             int            `json:"jumlah"`
+}
@@ -12,4 +12,6 @@ type Supplier struct {
 	Kontak        sql.NullString  `json:"kontak"`
 	ContactPerson sql.NullString  `json:"contact_person"`
 	Rating        sql.NullFloat64 `json:"rating"`
+	// DeletedAt terisi jika supplier sudah dihapus (soft delete)
+	DeletedAt sql.NullString `json:"deleted_at"`
 }
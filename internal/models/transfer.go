@@ -0,0 +1,54 @@
+// file: scm-api/internal/models/transfer.go
+
+package models
+
+import "database/sql"
+
+// Status yang bisa dimiliki sebuah transfer stok
+const (
+	StatusTransferDraft      = "Draft"
+	StatusTransferDikirim    = "Dikirim"
+	StatusTransferDiterima   = "Diterima"
+	StatusTransferDibatalkan = "Dibatalkan"
+)
+
+// transisiStatusTransfer berisi perpindahan status transfer yang diizinkan.
+// Transfer yang sudah dikirim tidak bisa dibatalkan karena stoknya sudah keluar.
+var transisiStatusTransfer = map[string][]string{
+	StatusTransferDraft:   {StatusTransferDikirim, StatusTransferDibatalkan},
+	StatusTransferDikirim: {StatusTransferDiterima},
+}
+
+// BolehTransisiTransfer memeriksa apakah status transfer boleh berpindah dari dari ke ke
+func BolehTransisiTransfer(dari, ke string) bool {
+	for _, s := range transisiStatusTransfer[dari] {
+		if s == ke {
+			return true
+		}
+	}
+	return false
+}
+
+// Transfer merepresentasikan tabel 'transfer_stok' (header dokumen transfer antar gudang)
+type Transfer struct {
+	TransferID     int64            `json:"transfer_id"`
+	GudangAsalID   int64            `json:"gudang_asal_id"`
+	GudangTujuanID int64            `json:"gudang_tujuan_id"`
+	Status         string           `json:"status"`
+	Catatan        sql.NullString   `json:"catatan"`
+	DibuatOleh     string           `json:"dibuat_oleh"`
+	TanggalDibuat  string           `json:"tanggal_dibuat"`
+	TanggalKirim   sql.NullString   `json:"tanggal_kirim"`
+	TanggalTerima  sql.NullString   `json:"tanggal_terima"`
+	Details        []DetailTransfer `json:"details"`
+}
+
+// DetailTransfer merepresentasikan tabel 'detail_transfer'
+type DetailTransfer struct {
+	DetailTransferID int64 `json:"detail_transfer_id"`
+	TransferID       int64 `json:"transfer_id"`
+	ProdukID         int64 `json:"produk_id"`
+	Jumlah           int   `json:"jumlah"`
+	// Batch berisi lot yang diambil (FEFO) saat transfer dikirim
+	Batch []PemakaianBatch `json:"batch"`
+}
@@ -0,0 +1,315 @@
+// file: internal/pencarian/pencarian.go
+
+// Package pencarian menyimpan indeks produk di memori untuk GET /api/produk/search.
+// Indeks dibangun dari store saat pertama kali dipakai, lalu dibangun ulang setelah
+// Kosongkan dipanggil (setiap kali produk berubah) atau setelah umurnya habis, agar
+// perubahan dari instance lain ikut terlihat.
+package pencarian
+
+import (
+	"context"
+	"html"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"scm-api/internal/models"
+)
+
+// Bobot setiap jenis kecocokan. SKU paling diutamakan karena kasir biasanya
+// mengetik atau memindai kode, lalu nama, kategori, dan terakhir deskripsi.
+const (
+	skorSKUPersis   = 100
+	skorSKUAwalan   = 60
+	skorKataPersis  = 30
+	skorKataAwalan  = 20
+	skorKataMirip   = 10
+	bobotKategori   = 0.4
+	bobotDeskripsi  = 0.2
+	bonusAwalanNama = 15
+)
+
+// Hasil adalah satu produk yang cocok beserta skor relevansinya. Field berisi
+// field yang paling menentukan skor, dan Sorotan berisi isi field itu dengan
+// bagian yang cocok dibungkus <mark>...</mark> (sisanya sudah di-escape HTML).
+type Hasil struct {
+	Produk  models.Produk `json:"produk"`
+	Skor    float64       `json:"skor"`
+	Field   string        `json:"field"`
+	Sorotan string        `json:"sorotan"`
+}
+
+// Muat mengambil semua produk yang bisa dicari (yang belum dihapus)
+type Muat func(ctx context.Context) ([]models.Produk, error)
+
+// Indeks adalah indeks produk yang aman dipakai bersamaan oleh banyak request
+type Indeks struct {
+	muat Muat
+	umur time.Duration
+
+	mu      sync.RWMutex
+	entri   []entri
+	dibuat  time.Time
+	berlaku bool
+}
+
+// entri menyimpan field produk yang sudah dipecah menjadi kata huruf kecil
+type entri struct {
+	produk    models.Produk
+	sku       string
+	nama      []string
+	kategori  []string
+	deskripsi []string
+}
+
+// Baru membuat indeks yang dimuat lewat muat. umur 0 berarti indeks hanya
+// dibangun ulang setelah Kosongkan.
+func Baru(muat Muat, umur time.Duration) *Indeks {
+	return &Indeks{muat: muat, umur: umur}
+}
+
+// Kosongkan menandai indeks usang sehingga pencarian berikutnya membangunnya ulang
+func (i *Indeks) Kosongkan() {
+	i.mu.Lock()
+	i.berlaku = false
+	i.mu.Unlock()
+}
+
+// Cari mengembalikan produk yang cocok dengan kueri, diurutkan dari skor tertinggi.
+// Setiap kata di kueri harus cocok dengan salah satu field.
+func (i *Indeks) Cari(ctx context.Context, kueri string) ([]Hasil, error) {
+	daftar, err := i.siapkan(ctx)
+	if err != nil {
+		return nil, err
+	}
+	token := pecah(kueri)
+	if len(token) == 0 {
+		return []Hasil{}, nil
+	}
+	utuh := strings.ToLower(strings.TrimSpace(kueri))
+
+	hasil := []Hasil{}
+	for _, e := range daftar {
+		if h, ok := nilai(e, utuh, token); ok {
+			hasil = append(hasil, h)
+		}
+	}
+	slices.SortStableFunc(hasil, func(a, b Hasil) int {
+		if a.Skor != b.Skor {
+			if a.Skor > b.Skor {
+				return -1
+			}
+			return 1
+		}
+		if c := strings.Compare(a.Produk.NamaProduk, b.Produk.NamaProduk); c != 0 {
+			return c
+		}
+		return int(a.Produk.ProdukID - b.Produk.ProdukID)
+	})
+	return hasil, nil
+}
+
+// siapkan mengembalikan isi indeks, membangunnya ulang jika sudah usang
+func (i *Indeks) siapkan(ctx context.Context) ([]entri, error) {
+	i.mu.RLock()
+	if i.masihBerlaku() {
+		daftar := i.entri
+		i.mu.RUnlock()
+		return daftar, nil
+	}
+	i.mu.RUnlock()
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if i.masihBerlaku() {
+		return i.entri, nil
+	}
+	daftarProduk, err := i.muat(ctx)
+	if err != nil {
+		return nil, err
+	}
+	daftar := make([]entri, 0, len(daftarProduk))
+	for _, p := range daftarProduk {
+		daftar = append(daftar, entri{
+			produk:    p,
+			sku:       strings.ToLower(p.SKU),
+			nama:      pecah(p.NamaProduk),
+			kategori:  pecah(p.Kategori.String),
+			deskripsi: pecah(p.Deskripsi.String),
+		})
+	}
+	i.entri, i.dibuat, i.berlaku = daftar, time.Now(), true
+	return daftar, nil
+}
+
+func (i *Indeks) masihBerlaku() bool {
+	return i.berlaku && (i.umur <= 0 || time.Since(i.dibuat) < i.umur)
+}
+
+// nilai menghitung skor satu produk. SKU dicocokkan dengan kueri utuh (persis atau
+// awalan), sedangkan field lain per kata: untuk setiap kata diambil kecocokan terbaik.
+// Produk yang SKU-nya tidak cocok gugur jika ada kata yang tidak cocok di mana pun.
+func nilai(e entri, utuh string, token []string) (Hasil, bool) {
+	skorField := map[string]float64{}
+	switch {
+	case e.sku == utuh:
+		skorField["sku"] = skorSKUPersis
+	case strings.HasPrefix(e.sku, utuh):
+		skorField["sku"] = skorSKUAwalan
+	}
+	total := skorField["sku"]
+	for _, t := range token {
+		skor := map[string]float64{
+			"nama_produk": cocokKata(e.nama, t, true),
+			"kategori":    cocokKata(e.kategori, t, false) * bobotKategori,
+			"deskripsi":   cocokKata(e.deskripsi, t, false) * bobotDeskripsi,
+		}
+		terbaik := 0.0
+		for f, v := range skor {
+			skorField[f] += v
+			terbaik = max(terbaik, v)
+		}
+		if terbaik == 0 && skorField["sku"] == 0 {
+			return Hasil{}, false
+		}
+		total += terbaik
+	}
+	if strings.HasPrefix(strings.Join(e.nama, " "), strings.Join(token, " ")) {
+		total += bonusAwalanNama
+		skorField["nama_produk"] += bonusAwalanNama
+	}
+
+	field := "nama_produk"
+	for _, f := range []string{"sku", "nama_produk", "kategori", "deskripsi"} {
+		if skorField[f] > skorField[field] {
+			field = f
+		}
+	}
+	return Hasil{
+		Produk:  e.produk,
+		Skor:    total,
+		Field:   field,
+		Sorotan: sorot(teksField(e.produk, field), field, utuh, token),
+	}, true
+}
+
+// cocokKata mencari kecocokan terbaik t di antara kata-kata sebuah field.
+// Salah ketik hanya ditoleransi jika mirip diizinkan (untuk nama produk).
+func cocokKata(kata []string, t string, mirip bool) float64 {
+	var terbaik float64
+	for _, k := range kata {
+		switch {
+		case k == t:
+			return skorKataPersis
+		case strings.HasPrefix(k, t):
+			terbaik = max(terbaik, skorKataAwalan)
+		case mirip && cukupMirip(k, t):
+			terbaik = max(terbaik, skorKataMirip)
+		}
+	}
+	return terbaik
+}
+
+// cukupMirip menoleransi satu salah ketik untuk kata 4-7 huruf dan dua untuk kata
+// yang lebih panjang. Kata yang lebih pendek harus cocok persis atau sebagai awalan.
+func cukupMirip(kata, t string) bool {
+	n := len([]rune(t))
+	batas := 0
+	switch {
+	case n >= 8:
+		batas = 2
+	case n >= 4:
+		batas = 1
+	}
+	if batas == 0 {
+		return false
+	}
+	return Levenshtein(kata, t) <= batas
+}
+
+// Levenshtein menghitung jumlah sisip, hapus, atau ganti huruf yang dibutuhkan
+// untuk mengubah a menjadi b
+func Levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	sebelum := make([]int, len(rb)+1)
+	kini := make([]int, len(rb)+1)
+	for j := range sebelum {
+		sebelum[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		kini[0] = i
+		for j := 1; j <= len(rb); j++ {
+			ganti := sebelum[j-1]
+			if ra[i-1] != rb[j-1] {
+				ganti++
+			}
+			kini[j] = min(sebelum[j]+1, kini[j-1]+1, ganti)
+		}
+		sebelum, kini = kini, sebelum
+	}
+	return sebelum[len(rb)]
+}
+
+// pecah memecah teks menjadi kata huruf kecil; tanda baca dianggap pemisah
+func pecah(s string) []string {
+	return strings.FieldsFunc(strings.ToLower(s), bukanHuruf)
+}
+
+func bukanHuruf(r rune) bool {
+	return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+}
+
+func teksField(p models.Produk, field string) string {
+	switch field {
+	case "sku":
+		return p.SKU
+	case "kategori":
+		return p.Kategori.String
+	case "deskripsi":
+		return p.Deskripsi.String
+	}
+	return p.NamaProduk
+}
+
+// sorot membungkus bagian teks yang cocok dengan <mark>. Untuk SKU yang disorot
+// adalah awalan sepanjang kueri; untuk field lain setiap kata yang cocok dengan
+// salah satu kata kueri.
+func sorot(teks, field, utuh string, token []string) string {
+	if field == "sku" {
+		n := min(len(utuh), len(teks))
+		return "<mark>" + html.EscapeString(teks[:n]) + "</mark>" + html.EscapeString(teks[n:])
+	}
+
+	var b strings.Builder
+	rs := []rune(teks)
+	for i := 0; i < len(rs); {
+		if bukanHuruf(rs[i]) {
+			b.WriteString(html.EscapeString(string(rs[i])))
+			i++
+			continue
+		}
+		j := i
+		for j < len(rs) && !bukanHuruf(rs[j]) {
+			j++
+		}
+		kata := string(rs[i:j])
+		if cocokSalahSatu(strings.ToLower(kata), token, field == "nama_produk") {
+			b.WriteString("<mark>" + html.EscapeString(kata) + "</mark>")
+		} else {
+			b.WriteString(html.EscapeString(kata))
+		}
+		i = j
+	}
+	return b.String()
+}
+
+func cocokSalahSatu(kata string, token []string, mirip bool) bool {
+	for _, t := range token {
+		if cocokKata([]string{kata}, t, mirip) > 0 {
+			return true
+		}
+	}
+	return false
+}
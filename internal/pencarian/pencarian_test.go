@@ -0,0 +1,154 @@
+package pencarian
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"scm-api/internal/models"
+)
+
+func produk(id int64, sku, nama, kategori string) models.Produk {
+	return models.Produk{ProdukID: id, SKU: sku, NamaProduk: nama, Kategori: sql.NullString{String: kategori, Valid: kategori != ""}}
+}
+
+func indeksTetap(daftar ...models.Produk) *Indeks {
+	return Baru(func(context.Context) ([]models.Produk, error) { return daftar, nil }, 0)
+}
+
+func cari(t *testing.T, i *Indeks, kueri string) []Hasil {
+	t.Helper()
+	hasil, err := i.Cari(context.Background(), kueri)
+	if err != nil {
+		t.Fatalf("Cari(%q): %v", kueri, err)
+	}
+	return hasil
+}
+
+func TestCariUrutanSkor(t *testing.T) {
+	i := indeksTetap(
+		produk(4, "X1", "Kop02 Mug", ""),
+		produk(3, "GUL01", "Tutup KOP01 Plastik", ""),
+		produk(2, "KOP012", "Gula Pasir", ""),
+		produk(1, "KOP01", "Kopi Bubuk", ""),
+		produk(5, "TEH01", "Teh Celup", "Minuman"),
+	)
+	hasil := cari(t, i, "kop01")
+	ingin := []struct {
+		id      int64
+		skor    float64
+		field   string
+		sorotan string
+	}{
+		{1, skorSKUPersis, "sku", "<mark>KOP01</mark>"},
+		{2, skorSKUAwalan, "sku", "<mark>KOP01</mark>2"},
+		{3, skorKataPersis, "nama_produk", "Tutup <mark>KOP01</mark> Plastik"},
+		// Kop02 berjarak satu huruf dari kop01
+		{4, skorKataMirip, "nama_produk", "<mark>Kop02</mark> Mug"},
+	}
+	if len(hasil) != len(ingin) {
+		t.Fatalf("Cari menemukan %d produk, ingin %d: %+v", len(hasil), len(ingin), hasil)
+	}
+	for n, w := range ingin {
+		h := hasil[n]
+		if h.Produk.ProdukID != w.id || h.Skor != w.skor || h.Field != w.field || h.Sorotan != w.sorotan {
+			t.Errorf("hasil ke-%d = produk %d skor %v field %s sorotan %q; ingin produk %d skor %v field %s sorotan %q",
+				n+1, h.Produk.ProdukID, h.Skor, h.Field, h.Sorotan, w.id, w.skor, w.field, w.sorotan)
+		}
+	}
+
+	// Kategori dibobot lebih rendah dari nama
+	hasil = cari(t, i, "minuman")
+	if len(hasil) != 1 || hasil[0].Field != "kategori" || hasil[0].Skor != skorKataPersis*bobotKategori {
+		t.Errorf("Cari(minuman) = %+v, ingin produk 5 lewat kategori", hasil)
+	}
+}
+
+func TestCariSalahKetik(t *testing.T) {
+	i := indeksTetap(produk(1, "GL-1", "Gula Pasir", "Bahan Pokok"), produk(2, "KP-1", "Kopi Bubuk", ""))
+	for _, tc := range []struct {
+		kueri string
+		ingin int
+	}{
+		{"gulla", 1},     // satu huruf lebih
+		{"gula psir", 1}, // satu huruf kurang di kata kedua
+		{"gla", 0},       // kata di bawah 4 huruf harus persis atau awalan
+		{"kopi gula", 0}, // setiap kata harus cocok di produk yang sama
+		{"pokko", 0},     // salah ketik hanya ditoleransi di nama produk
+		{"gula pasir", 1},
+		{"  ", 0},
+	} {
+		hasil := cari(t, i, tc.kueri)
+		if len(hasil) != tc.ingin {
+			t.Errorf("Cari(%q) menemukan %d produk, ingin %d", tc.kueri, len(hasil), tc.ingin)
+		}
+	}
+	if hasil := cari(t, i, "gulla"); len(hasil) != 1 || hasil[0].Sorotan != "<mark>Gula</mark> Pasir" {
+		t.Errorf("Cari(gulla) = %+v, ingin Gula Pasir dengan Gula disorot", hasil)
+	}
+}
+
+func TestSorotanDiEscape(t *testing.T) {
+	i := indeksTetap(produk(1, "A<B", "<script>alert(1)</script> Kopi", ""))
+	hasil := cari(t, i, "kopi")
+	if len(hasil) != 1 || hasil[0].Sorotan != "&lt;script&gt;alert(1)&lt;/script&gt; <mark>Kopi</mark>" {
+		t.Errorf("sorotan nama = %+v", hasil)
+	}
+	hasil = cari(t, i, "a<")
+	if len(hasil) != 1 || hasil[0].Sorotan != "<mark>A&lt;</mark>B" {
+		t.Errorf("sorotan SKU = %+v", hasil)
+	}
+}
+
+func TestIndeksDibangunUlang(t *testing.T) {
+	dimuat := 0
+	daftar := []models.Produk{produk(1, "KP-1", "Kopi Bubuk", "")}
+	muat := func(context.Context) ([]models.Produk, error) {
+		dimuat++
+		return daftar, nil
+	}
+
+	i := Baru(muat, 0)
+	cari(t, i, "kopi")
+	daftar = append(daftar, produk(2, "KP-2", "Kopi Susu", ""))
+	if hasil := cari(t, i, "kopi"); len(hasil) != 1 || dimuat != 1 {
+		t.Errorf("sebelum Kosongkan: %d hasil, dimuat %d kali; ingin 1 hasil dari indeks lama", len(hasil), dimuat)
+	}
+	i.Kosongkan()
+	if hasil := cari(t, i, "kopi"); len(hasil) != 2 || dimuat != 2 {
+		t.Errorf("setelah Kosongkan: %d hasil, dimuat %d kali; ingin 2 hasil dari indeks baru", len(hasil), dimuat)
+	}
+
+	dimuat = 0
+	i = Baru(muat, time.Millisecond)
+	cari(t, i, "kopi")
+	time.Sleep(5 * time.Millisecond)
+	cari(t, i, "kopi")
+	if dimuat != 2 {
+		t.Errorf("indeks kedaluwarsa dimuat %d kali, ingin 2", dimuat)
+	}
+
+	gagal := errors.New("database mati")
+	i = Baru(func(context.Context) ([]models.Produk, error) { return nil, gagal }, 0)
+	if _, err := i.Cari(context.Background(), "kopi"); !errors.Is(err, gagal) {
+		t.Errorf("Cari dengan muat gagal = %v, ingin %v", err, gagal)
+	}
+}
+
+func TestLevenshtein(t *testing.T) {
+	for _, tc := range []struct {
+		a, b  string
+		ingin int
+	}{
+		{"kitten", "sitting", 3},
+		{"", "abc", 3},
+		{"kopi", "kopi", 0},
+		{"café", "cafe", 1},
+	} {
+		if got := Levenshtein(tc.a, tc.b); got != tc.ingin {
+			t.Errorf("Levenshtein(%q, %q) = %d, ingin %d", tc.a, tc.b, got, tc.ingin)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+// file: internal/penyimpanan/lokal.go
+
+package penyimpanan
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"mime"
+	"os"
+	"path/filepath"
+)
+
+// Lokal menyimpan berkas di bawah satu direktori di disk
+type Lokal struct {
+	dir string
+}
+
+// NewLokal membuat backend lokal. Direktori dibuat saat berkas pertama disimpan.
+func NewLokal(dir string) *Lokal {
+	return &Lokal{dir: dir}
+}
+
+func (l *Lokal) path(kunci string) (string, error) {
+	if !KunciValid(kunci) {
+		return "", fmt.Errorf("kunci berkas tidak valid: %q", kunci)
+	}
+	return filepath.Join(l.dir, filepath.FromSlash(kunci)), nil
+}
+
+// Simpan menulis ke berkas sementara lalu mengganti namanya, agar pembaca tidak
+// pernah melihat berkas yang baru setengah tertulis
+func (l *Lokal) Simpan(ctx context.Context, kunci string, isi []byte, tipe string) error {
+	p, err := l.path(kunci)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(filepath.Dir(p), ".unggah-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.Write(isi); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(f.Name(), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), p)
+}
+
+func (l *Lokal) Buka(ctx context.Context, kunci string) (Berkas, error) {
+	p, err := l.path(kunci)
+	if err != nil {
+		return Berkas{}, ErrTidakAda
+	}
+	f, err := os.Open(p)
+	if errors.Is(err, fs.ErrNotExist) {
+		return Berkas{}, ErrTidakAda
+	}
+	if err != nil {
+		return Berkas{}, err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return Berkas{}, err
+	}
+	tipe := mime.TypeByExtension(filepath.Ext(p))
+	if tipe == "" {
+		tipe = "application/octet-stream"
+	}
+	return Berkas{ReadCloser: f, Tipe: tipe, Ukuran: info.Size()}, nil
+}
+
+func (l *Lokal) Hapus(ctx context.Context, kunci string) error {
+	p, err := l.path(kunci)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(p); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
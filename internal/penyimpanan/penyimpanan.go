@@ -0,0 +1,53 @@
+// file: internal/penyimpanan/penyimpanan.go
+
+// Package penyimpanan menyimpan berkas media (gambar produk) di disk lokal atau di
+// layanan yang kompatibel dengan S3. Berkas dialamatkan dengan kunci relatif seperti
+// "produk/12/3f9a...c1.jpg"; URL publiknya disusun oleh pemanggil.
+package penyimpanan
+
+import (
+	"context"
+	"errors"
+	"io"
+	"regexp"
+	"strings"
+
+	"scm-api/internal/config"
+)
+
+// ErrTidakAda dikembalikan jika berkas dengan kunci tersebut tidak ada
+var ErrTidakAda = errors.New("berkas tidak ditemukan")
+
+// Berkas adalah isi berkas yang sedang dibaca. Pemanggil wajib menutupnya.
+type Berkas struct {
+	io.ReadCloser
+	Tipe   string
+	Ukuran int64
+}
+
+// Penyimpanan adalah backend berkas media
+type Penyimpanan interface {
+	// Simpan menulis berkas, menimpa berkas lama dengan kunci yang sama
+	Simpan(ctx context.Context, kunci string, isi []byte, tipe string) error
+	// Buka membaca berkas. Mengembalikan ErrTidakAda jika berkas tidak ada.
+	Buka(ctx context.Context, kunci string) (Berkas, error)
+	// Hapus menghapus berkas. Berkas yang sudah tidak ada tidak dianggap kesalahan.
+	Hapus(ctx context.Context, kunci string) error
+}
+
+// Dari membuat backend sesuai konfigurasi yang sudah divalidasi
+func Dari(cfg config.MediaConfig) Penyimpanan {
+	if cfg.Backend == "s3" {
+		return NewS3(cfg.S3)
+	}
+	return NewLokal(cfg.Dir)
+}
+
+var polaKunci = regexp.MustCompile(`^[a-z0-9_-]+(/[a-z0-9_-]+)*\.[a-z0-9]+$`)
+
+// KunciValid memastikan kunci hanya berisi segmen huruf kecil, angka, garis bawah,
+// dan tanda hubung diakhiri ekstensi, sehingga tidak bisa keluar dari direktori
+// atau bucket lewat "..".
+func KunciValid(kunci string) bool {
+	return len(kunci) <= 200 && polaKunci.MatchString(kunci) && !strings.Contains(kunci, "..")
+}
@@ -0,0 +1,84 @@
+package penyimpanan
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestKunciValid(t *testing.T) {
+	for kunci, ingin := range map[string]bool{
+		"produk/12/3f9a0c1.jpg":           true,
+		"produk/12/thumb_3f-9.jpg":        true,
+		"logo.png":                        true,
+		"../rahasia.txt":                  false,
+		"produk/../../etc/passwd":         false,
+		"produk/12/..jpg":                 false,
+		"/etc/passwd.txt":                 false,
+		"produk//12.jpg":                  false,
+		`produk\12.jpg`:                   false,
+		"produk/12/Gambar.JPG":            false,
+		"produk/12/gambar":                false,
+		"produk/12/gambar.jpg/":           false,
+		"":                                false,
+		strings.Repeat("a", 197) + ".jpg": false,
+	} {
+		if got := KunciValid(kunci); got != ingin {
+			t.Errorf("KunciValid(%q) = %v, ingin %v", kunci, got, ingin)
+		}
+	}
+}
+
+func TestLokal(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	l := NewLokal(filepath.Join(dir, "media"))
+
+	if err := l.Simpan(ctx, "produk/1/a.jpg", []byte("isi lama"), "image/jpeg"); err != nil {
+		t.Fatalf("Simpan: %v", err)
+	}
+	if err := l.Simpan(ctx, "produk/1/a.jpg", []byte("isi baru"), "image/jpeg"); err != nil {
+		t.Fatalf("Simpan menimpa: %v", err)
+	}
+	b, err := l.Buka(ctx, "produk/1/a.jpg")
+	if err != nil {
+		t.Fatalf("Buka: %v", err)
+	}
+	isi, _ := io.ReadAll(b)
+	b.Close()
+	if string(isi) != "isi baru" || b.Tipe != "image/jpeg" || b.Ukuran != 8 {
+		t.Errorf("Buka = %q tipe %s ukuran %d, ingin isi baru bertipe image/jpeg", isi, b.Tipe, b.Ukuran)
+	}
+	// Berkas sementara tidak tertinggal setelah penggantian nama
+	if sisa, _ := filepath.Glob(filepath.Join(dir, "media", "produk", "1", ".unggah-*")); len(sisa) != 0 {
+		t.Errorf("berkas sementara tertinggal: %v", sisa)
+	}
+
+	// Kunci yang keluar dari direktori ditolak sebelum menyentuh disk
+	if err := os.WriteFile(filepath.Join(dir, "rahasia.txt"), []byte("rahasia"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := l.Buka(ctx, "../rahasia.txt"); !errors.Is(err, ErrTidakAda) {
+		t.Errorf("Buka di luar direktori = %v, ingin ErrTidakAda", err)
+	}
+	if err := l.Simpan(ctx, "../rahasia.txt", []byte("ditimpa"), "text/plain"); err == nil {
+		t.Error("Simpan di luar direktori tidak ditolak")
+	}
+	if err := l.Hapus(ctx, "../rahasia.txt"); err == nil {
+		t.Error("Hapus di luar direktori tidak ditolak")
+	}
+
+	if err := l.Hapus(ctx, "produk/1/a.jpg"); err != nil {
+		t.Fatalf("Hapus: %v", err)
+	}
+	if err := l.Hapus(ctx, "produk/1/a.jpg"); err != nil {
+		t.Errorf("Hapus berkas yang sudah tidak ada = %v, ingin nil", err)
+	}
+	if _, err := l.Buka(ctx, "produk/1/a.jpg"); !errors.Is(err, ErrTidakAda) {
+		t.Errorf("Buka setelah dihapus = %v, ingin ErrTidakAda", err)
+	}
+}
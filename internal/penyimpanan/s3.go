@@ -0,0 +1,185 @@
+// file: internal/penyimpanan/s3.go
+
+package penyimpanan
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"scm-api/internal/config"
+)
+
+// S3 menyimpan berkas di bucket yang kompatibel dengan S3 (AWS S3, MinIO, Ceph RGW,
+// dan sebagainya). Permintaan ditandatangani dengan AWS Signature Version 4 tanpa
+// bergantung pada SDK.
+type S3 struct {
+	cfg   config.S3Config
+	klien *http.Client
+}
+
+// NewS3 membuat backend S3 dari konfigurasi yang sudah divalidasi
+func NewS3(cfg config.S3Config) *S3 {
+	cfg.Endpoint = strings.TrimSuffix(cfg.Endpoint, "/")
+	return &S3{cfg: cfg, klien: &http.Client{Timeout: 30 * time.Second}}
+}
+
+// alamat mengembalikan URL objek untuk kunci, dengan gaya path atau virtual host
+func (s *S3) alamat(kunci string) (*url.URL, error) {
+	if !KunciValid(kunci) {
+		return nil, fmt.Errorf("kunci berkas tidak valid: %q", kunci)
+	}
+	u, err := url.Parse(s.cfg.Endpoint)
+	if err != nil {
+		return nil, err
+	}
+	if s.cfg.PathStyle {
+		u.Path = strings.TrimSuffix(u.Path, "/") + "/" + s.cfg.Bucket + "/" + kunci
+	} else {
+		u.Host = s.cfg.Bucket + "." + u.Host
+		u.Path = strings.TrimSuffix(u.Path, "/") + "/" + kunci
+	}
+	return u, nil
+}
+
+func (s *S3) Simpan(ctx context.Context, kunci string, isi []byte, tipe string) error {
+	resp, err := s.kirim(ctx, http.MethodPut, kunci, isi, map[string]string{"Content-Type": tipe})
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return galatS3(resp)
+	}
+	return nil
+}
+
+func (s *S3) Buka(ctx context.Context, kunci string) (Berkas, error) {
+	if !KunciValid(kunci) {
+		return Berkas{}, ErrTidakAda
+	}
+	resp, err := s.kirim(ctx, http.MethodGet, kunci, nil, nil)
+	if err != nil {
+		return Berkas{}, err
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		resp.Body.Close()
+		return Berkas{}, ErrTidakAda
+	}
+	if resp.StatusCode/100 != 2 {
+		defer resp.Body.Close()
+		return Berkas{}, galatS3(resp)
+	}
+	tipe := resp.Header.Get("Content-Type")
+	if tipe == "" {
+		tipe = "application/octet-stream"
+	}
+	return Berkas{ReadCloser: resp.Body, Tipe: tipe, Ukuran: resp.ContentLength}, nil
+}
+
+func (s *S3) Hapus(ctx context.Context, kunci string) error {
+	resp, err := s.kirim(ctx, http.MethodDelete, kunci, nil, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 && resp.StatusCode != http.StatusNotFound {
+		return galatS3(resp)
+	}
+	return nil
+}
+
+// kirim membuat, menandatangani, dan mengirim satu permintaan objek
+func (s *S3) kirim(ctx context.Context, method, kunci string, isi []byte, header map[string]string) (*http.Response, error) {
+	u, err := s.alamat(kunci)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), bytes.NewReader(isi))
+	if err != nil {
+		return nil, err
+	}
+	req.ContentLength = int64(len(isi))
+	for k, v := range header {
+		req.Header.Set(k, v)
+	}
+	s.tandatangani(req, isi)
+	return s.klien.Do(req)
+}
+
+// tandatangani menambahkan header Authorization SigV4 ke req. Header yang ikut
+// ditandatangani adalah host, x-amz-content-sha256, x-amz-date, dan content-type
+// jika ada.
+func (s *S3) tandatangani(req *http.Request, isi []byte) {
+	waktu := time.Now().UTC()
+	amzDate := waktu.Format("20060102T150405Z")
+	tanggal := waktu.Format("20060102")
+	hashIsi := hexSHA256(isi)
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", hashIsi)
+
+	nama := []string{"host"}
+	nilai := map[string]string{"host": req.URL.Host}
+	if t := req.Header.Get("Content-Type"); t != "" {
+		nama = append(nama, "content-type")
+		nilai["content-type"] = t
+	}
+	nama = append(nama, "x-amz-content-sha256", "x-amz-date")
+	nilai["x-amz-content-sha256"] = hashIsi
+	nilai["x-amz-date"] = amzDate
+	slices.Sort(nama)
+
+	var kanonisHeader strings.Builder
+	for _, n := range nama {
+		kanonisHeader.WriteString(n + ":" + strings.TrimSpace(nilai[n]) + "\n")
+	}
+	headerDitandatangani := strings.Join(nama, ";")
+
+	kanonis := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		kanonisHeader.String(),
+		headerDitandatangani,
+		hashIsi,
+	}, "\n")
+
+	cakupan := tanggal + "/" + s.cfg.Region + "/s3/aws4_request"
+	teks := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + cakupan + "\n" + hexSHA256([]byte(kanonis))
+
+	kunci := hmacSHA256([]byte("AWS4"+s.cfg.SecretKey), tanggal)
+	kunci = hmacSHA256(kunci, s.cfg.Region)
+	kunci = hmacSHA256(kunci, "s3")
+	kunci = hmacSHA256(kunci, "aws4_request")
+	tandaTangan := hex.EncodeToString(hmacSHA256(kunci, teks))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.cfg.AccessKey, cakupan, headerDitandatangani, tandaTangan))
+}
+
+func hexSHA256(b []byte) string {
+	h := sha256.Sum256(b)
+	return hex.EncodeToString(h[:])
+}
+
+func hmacSHA256(kunci []byte, teks string) []byte {
+	m := hmac.New(sha256.New, kunci)
+	m.Write([]byte(teks))
+	return m.Sum(nil)
+}
+
+// galatS3 menyertakan awal isi respons galat, yang biasanya berupa XML berisi kode galat S3
+func galatS3(resp *http.Response) error {
+	isi, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	return fmt.Errorf("s3 %s %s: status %d: %s", resp.Request.Method, resp.Request.URL.Path, resp.StatusCode, strings.TrimSpace(string(isi)))
+}
@@ -0,0 +1,196 @@
+// file: internal/replenishment/replenishment.go
+
+// Package replenishment menghitung saran pemesanan ulang per produk per gudang dari
+// stok saat ini, sisa pesanan yang belum datang, pemakaian harian, dan parameter di
+// tabel stok_parameter. Jumlah dihitung dalam satuan dasar lalu diubah ke satuan beli
+// katalog supplier. Hasilnya dipakai GET /api/replenishment/saran dan
+// POST /api/replenishment/pembelian.
+package replenishment
+
+import (
+	"math"
+	"sort"
+
+	"scm-api/internal/models"
+)
+
+// Posisi adalah keadaan persediaan satu produk di satu gudang, dalam satuan dasar
+type Posisi struct {
+	Stok            int
+	DalamPesanan    int
+	PemakaianHarian float64
+	LeadTimeHari    int
+	// ReorderPoint dan MaxLevel nil berarti dihitung
+	ReorderPoint *int
+	SafetyStock  int
+	MaxLevel     *int
+	// CakupanHari adalah lama pemakaian yang ditutup pesanan jika MaxLevel kosong
+	CakupanHari int
+}
+
+// Hasil perhitungan satu posisi, dalam satuan dasar
+type Hasil struct {
+	ReorderPoint int
+	Target       int
+	// Kebutuhan adalah Target dikurangi stok dan pesanan terbuka, atau 0 jika
+	// persediaan masih di atas ReorderPoint
+	Kebutuhan int
+}
+
+// Hitung menentukan reorder point, target, dan kebutuhan. Reorder point bawaan adalah
+// pemakaian selama lead time ditambah safety stock; target bawaan adalah reorder
+// point ditambah pemakaian selama CakupanHari. Pesanan disarankan jika stok ditambah
+// pesanan terbuka sudah sama dengan atau di bawah reorder point.
+func Hitung(p Posisi) Hasil {
+	var h Hasil
+	if p.ReorderPoint != nil {
+		h.ReorderPoint = *p.ReorderPoint
+	} else {
+		h.ReorderPoint = bulatAtas(p.PemakaianHarian*float64(p.LeadTimeHari)) + p.SafetyStock
+	}
+	if p.MaxLevel != nil {
+		h.Target = *p.MaxLevel
+	} else {
+		h.Target = h.ReorderPoint + bulatAtas(p.PemakaianHarian*float64(p.CakupanHari))
+	}
+	h.Target = max(h.Target, h.ReorderPoint)
+
+	posisi := p.Stok + p.DalamPesanan
+	if posisi <= h.ReorderPoint {
+		h.Kebutuhan = max(h.Target-posisi, 0)
+	}
+	return h
+}
+
+// Opsi adalah satu baris katalog yang berlaku beserta faktor satuannya
+type Opsi struct {
+	Katalog models.SupplierProduk
+	Faktor  int
+}
+
+// hargaDasar adalah harga per satuan dasar produk
+func (o Opsi) hargaDasar() float64 {
+	return o.Katalog.HargaBeli / float64(max(o.Faktor, 1))
+}
+
+// PilihKatalog memilih baris katalog termurah per satuan dasar. Jika supplierUtama
+// (supplier_id produk) punya harga yang berlaku, pilihan dibatasi ke supplier itu.
+func PilihKatalog(opsi []Opsi, supplierUtama int64) (Opsi, bool) {
+	var utama, semua []Opsi
+	for _, o := range opsi {
+		semua = append(semua, o)
+		if supplierUtama > 0 && o.Katalog.SupplierID == supplierUtama {
+			utama = append(utama, o)
+		}
+	}
+	if len(utama) > 0 {
+		semua = utama
+	}
+	if len(semua) == 0 {
+		return Opsi{}, false
+	}
+	// Stabil agar pilihan dengan harga sama tetap mengikuti urutan katalog
+	sort.SliceStable(semua, func(i, j int) bool { return semua[i].hargaDasar() < semua[j].hargaDasar() })
+	return semua[0], true
+}
+
+// JumlahBeli mengubah kebutuhan dalam satuan dasar menjadi jumlah satuan beli:
+// dibulatkan ke atas ke kelipatan faktor, dan tidak kurang dari moq
+func JumlahBeli(kebutuhan, faktor, moq int) int {
+	if kebutuhan <= 0 {
+		return 0
+	}
+	faktor = max(faktor, 1)
+	return max((kebutuhan+faktor-1)/faktor, moq)
+}
+
+// Saran adalah satu baris saran pemesanan. Supplier dan harga kosong jika produk
+// tidak punya harga katalog yang berlaku.
+type Saran struct {
+	ProdukID        int64   `json:"produk_id"`
+	NamaProduk      string  `json:"nama_produk"`
+	SatuanDasar     string  `json:"satuan_dasar"`
+	GudangID        int64   `json:"gudang_id"`
+	NamaGudang      string  `json:"nama_gudang"`
+	Stok            int     `json:"stok"`
+	DalamPesanan    int     `json:"dalam_pesanan"`
+	PemakaianHarian float64 `json:"pemakaian_harian"`
+	// MetodeRamalan kosong jika pemakaian harian dirata-rata dari riwayat
+	MetodeRamalan    string   `json:"metode_ramalan,omitempty"`
+	LeadTimeHari     int      `json:"lead_time_hari"`
+	SafetyStock      int      `json:"safety_stock"`
+	ReorderPoint     int      `json:"reorder_point"`
+	Target           int      `json:"target"`
+	Kebutuhan        int      `json:"kebutuhan"`
+	SupplierID       *int64   `json:"supplier_id"`
+	NamaSupplier     *string  `json:"nama_supplier"`
+	SupplierProdukID *int64   `json:"supplier_produk_id"`
+	Satuan           string   `json:"satuan"`
+	FaktorKonversi   int      `json:"faktor_konversi"`
+	Jumlah           int      `json:"jumlah"`
+	HargaBeli        *float64 `json:"harga_beli"`
+	Subtotal         *float64 `json:"subtotal"`
+}
+
+// Kelompok adalah saran untuk satu supplier dan satu gudang, calon satu pesanan.
+// SiapDipesan berarti supplier dan harga setiap baris sudah diketahui.
+type Kelompok struct {
+	SupplierID   *int64  `json:"supplier_id"`
+	NamaSupplier *string `json:"nama_supplier"`
+	GudangID     int64   `json:"gudang_id"`
+	NamaGudang   string  `json:"nama_gudang"`
+	TotalBiaya   float64 `json:"total_biaya"`
+	SiapDipesan  bool    `json:"siap_dipesan"`
+	Items        []Saran `json:"items"`
+}
+
+// Kelompokkan mengelompokkan saran per supplier lalu gudang. Saran tanpa supplier
+// dikumpulkan di kelompok dengan supplier_id null di akhir daftar.
+func Kelompokkan(daftar []Saran) []Kelompok {
+	type kunci struct {
+		supplierID int64
+		gudangID   int64
+	}
+	indeks := make(map[kunci]int)
+	hasil := make([]Kelompok, 0)
+	for _, sr := range daftar {
+		k := kunci{gudangID: sr.GudangID}
+		if sr.SupplierID != nil {
+			k.supplierID = *sr.SupplierID
+		}
+		i, ada := indeks[k]
+		if !ada {
+			i = len(hasil)
+			indeks[k] = i
+			hasil = append(hasil, Kelompok{
+				SupplierID:   sr.SupplierID,
+				NamaSupplier: sr.NamaSupplier,
+				GudangID:     sr.GudangID,
+				NamaGudang:   sr.NamaGudang,
+				SiapDipesan:  sr.SupplierID != nil,
+			})
+		}
+		g := &hasil[i]
+		g.Items = append(g.Items, sr)
+		if sr.Subtotal != nil {
+			g.TotalBiaya = math.Round((g.TotalBiaya+*sr.Subtotal)*100) / 100
+		} else {
+			g.SiapDipesan = false
+		}
+	}
+	sort.SliceStable(hasil, func(i, j int) bool {
+		a, b := hasil[i], hasil[j]
+		if (a.SupplierID == nil) != (b.SupplierID == nil) {
+			return b.SupplierID == nil
+		}
+		if a.SupplierID != nil && *a.SupplierID != *b.SupplierID {
+			return *a.SupplierID < *b.SupplierID
+		}
+		return a.GudangID < b.GudangID
+	})
+	return hasil
+}
+
+func bulatAtas(v float64) int {
+	return int(math.Ceil(v - 1e-9))
+}
@@ -0,0 +1,138 @@
+package replenishment
+
+import (
+	"testing"
+
+	"scm-api/internal/models"
+)
+
+func angka(n int) *int { return &n }
+
+func TestHitung(t *testing.T) {
+	for _, tc := range []struct {
+		nama  string
+		pos   Posisi
+		ingin Hasil
+	}{
+		{"di bawah reorder point", Posisi{Stok: 10, DalamPesanan: 2, PemakaianHarian: 2.5, LeadTimeHari: 4, SafetyStock: 3, CakupanHari: 10}, Hasil{13, 38, 26}},
+		{"tepat di reorder point", Posisi{Stok: 13, PemakaianHarian: 2.5, LeadTimeHari: 4, SafetyStock: 3, CakupanHari: 10}, Hasil{13, 38, 25}},
+		{"di atas reorder point", Posisi{Stok: 14, PemakaianHarian: 2.5, LeadTimeHari: 4, SafetyStock: 3, CakupanHari: 10}, Hasil{13, 38, 0}},
+		{"pesanan terbuka menutup kebutuhan", Posisi{DalamPesanan: 40, PemakaianHarian: 2.5, LeadTimeHari: 4, SafetyStock: 3, CakupanHari: 10}, Hasil{13, 38, 0}},
+		{"pecahan dibulatkan ke atas", Posisi{PemakaianHarian: 0.1, LeadTimeHari: 3, CakupanHari: 7}, Hasil{1, 2, 2}},
+		// 0,7 × 10 bernilai 7,000000000000001 dalam float64 dan tidak boleh menjadi 8
+		{"galat float tidak menambah satu", Posisi{PemakaianHarian: 0.7, LeadTimeHari: 10, CakupanHari: 10}, Hasil{7, 14, 14}},
+		{"tanpa pemakaian", Posisi{CakupanHari: 14}, Hasil{0, 0, 0}},
+		{"reorder point dan max level diisi", Posisi{Stok: 5, DalamPesanan: 5, PemakaianHarian: 9, LeadTimeHari: 9, ReorderPoint: angka(20), MaxLevel: angka(50)}, Hasil{20, 50, 40}},
+		{"max level di bawah reorder point", Posisi{ReorderPoint: angka(20), MaxLevel: angka(10)}, Hasil{20, 20, 20}},
+		{"reorder point diisi, target dari cakupan", Posisi{Stok: 30, PemakaianHarian: 0.5, ReorderPoint: angka(100), CakupanHari: 14}, Hasil{100, 107, 77}},
+	} {
+		t.Run(tc.nama, func(t *testing.T) {
+			if got := Hitung(tc.pos); got != tc.ingin {
+				t.Errorf("Hitung(%+v) = %+v, ingin %+v", tc.pos, got, tc.ingin)
+			}
+		})
+	}
+}
+
+func TestPilihKatalog(t *testing.T) {
+	opsi := func(id, supplierID int64, harga float64, faktor int) Opsi {
+		return Opsi{Katalog: models.SupplierProduk{SupplierProdukID: id, SupplierID: supplierID, HargaBeli: harga}, Faktor: faktor}
+	}
+	pcs := opsi(1, 1, 1000, 1)
+	dus := opsi(2, 2, 10000, 12) // 833,33 per pcs
+	for _, tc := range []struct {
+		nama          string
+		opsi          []Opsi
+		supplierUtama int64
+		ingin         int64
+	}{
+		{"termurah per satuan dasar", []Opsi{pcs, dus}, 0, 2},
+		{"supplier utama didahulukan", []Opsi{pcs, dus}, 1, 1},
+		{"supplier utama tanpa harga", []Opsi{pcs, dus}, 3, 2},
+		{"harga sama mengikuti urutan katalog", []Opsi{opsi(3, 1, 500, 1), opsi(4, 2, 6000, 12)}, 0, 3},
+		{"faktor 0 dianggap 1", []Opsi{opsi(5, 1, 900, 0), pcs}, 0, 5},
+		{"termurah di antara supplier utama", []Opsi{pcs, dus, opsi(6, 1, 9600, 12)}, 1, 6},
+	} {
+		t.Run(tc.nama, func(t *testing.T) {
+			got, ok := PilihKatalog(tc.opsi, tc.supplierUtama)
+			if !ok || got.Katalog.SupplierProdukID != tc.ingin {
+				t.Errorf("PilihKatalog = %d, %v; ingin katalog %d", got.Katalog.SupplierProdukID, ok, tc.ingin)
+			}
+		})
+	}
+	if _, ok := PilihKatalog(nil, 1); ok {
+		t.Error("PilihKatalog tanpa opsi mengembalikan pilihan")
+	}
+}
+
+func TestJumlahBeli(t *testing.T) {
+	for _, tc := range []struct {
+		kebutuhan, faktor, moq, ingin int
+	}{
+		{0, 12, 1, 0},
+		{-5, 1, 1, 0},
+		{24, 12, 1, 2},
+		{25, 12, 1, 3},
+		{5, 12, 3, 3},
+		{5, 0, 0, 5},
+		{1, 1, 10, 10},
+		{30, 1, 10, 30},
+	} {
+		if got := JumlahBeli(tc.kebutuhan, tc.faktor, tc.moq); got != tc.ingin {
+			t.Errorf("JumlahBeli(%d, %d, %d) = %d, ingin %d", tc.kebutuhan, tc.faktor, tc.moq, got, tc.ingin)
+		}
+	}
+}
+
+func TestKelompokkan(t *testing.T) {
+	id := func(n int64) *int64 { return &n }
+	harga := func(v float64) *float64 { return &v }
+	daftar := []Saran{
+		{ProdukID: 1, GudangID: 1, SupplierID: id(2), Subtotal: harga(100.1)},
+		{ProdukID: 2, GudangID: 1},
+		{ProdukID: 3, GudangID: 2, SupplierID: id(1), Subtotal: harga(50)},
+		{ProdukID: 4, GudangID: 1, SupplierID: id(2), Subtotal: harga(0.2)},
+		// Produk dengan supplier utama tetapi tanpa harga katalog
+		{ProdukID: 5, GudangID: 1, SupplierID: id(1)},
+	}
+	type ringkas struct {
+		supplier int64
+		gudang   int64
+		total    float64
+		siap     bool
+		produk   []int64
+	}
+	ingin := []ringkas{
+		{1, 1, 0, false, []int64{5}},
+		{1, 2, 50, true, []int64{3}},
+		{2, 1, 100.3, true, []int64{1, 4}},
+		{0, 1, 0, false, []int64{2}},
+	}
+	hasil := Kelompokkan(daftar)
+	if len(hasil) != len(ingin) {
+		t.Fatalf("Kelompokkan menghasilkan %d kelompok, ingin %d", len(hasil), len(ingin))
+	}
+	for i, g := range hasil {
+		got := ringkas{gudang: g.GudangID, total: g.TotalBiaya, siap: g.SiapDipesan}
+		if g.SupplierID != nil {
+			got.supplier = *g.SupplierID
+		}
+		for _, sr := range g.Items {
+			got.produk = append(got.produk, sr.ProdukID)
+		}
+		w := ingin[i]
+		if got.supplier != w.supplier || got.gudang != w.gudang || got.total != w.total || got.siap != w.siap || len(got.produk) != len(w.produk) {
+			t.Errorf("kelompok ke-%d = %+v, ingin %+v", i+1, got, w)
+			continue
+		}
+		for j := range w.produk {
+			if got.produk[j] != w.produk[j] {
+				t.Errorf("kelompok ke-%d = %+v, ingin %+v", i+1, got, w)
+				break
+			}
+		}
+	}
+	if g := Kelompokkan(nil); g == nil || len(g) != 0 {
+		t.Errorf("Kelompokkan(nil) = %#v, ingin daftar kosong", g)
+	}
+}
@@ -0,0 +1,211 @@
+// file: internal/scorecard/scorecard.go
+
+// Package scorecard menilai kinerja supplier dari data pembelian: ketepatan waktu
+// kirim, fill rate, tingkat penolakan barang, dan selisih harga terhadap supplier
+// lain. Hasilnya dipakai untuk GET /api/supplier/:id/scorecard dan untuk mengisi
+// kolom rating supplier secara berkala.
+package scorecard
+
+import (
+	"math"
+
+	"scm-api/internal/models"
+)
+
+// Bobot setiap metrik dalam skor akhir. Metrik yang belum punya data tidak ikut
+// dihitung dan bobot sisanya dinormalkan ulang.
+const (
+	bobotTepatWaktu = 0.4
+	bobotFillRate   = 0.3
+	bobotTolak      = 0.2
+	bobotHarga      = 0.1
+)
+
+// variansHargaNol adalah selisih harga di atas rata-rata pasar yang membuat nilai
+// harga menjadi 0. Harga sama atau lebih murah dari rata-rata mendapat nilai penuh.
+const variansHargaNol = 0.2
+
+// RatingMaks adalah rating tertinggi, sesuai kolom supplier.rating DECIMAL(3,2)
+const RatingMaks = 5
+
+// Scorecard adalah hasil penilaian satu supplier dalam satu periode. Rasio bernilai
+// nil jika belum ada data untuk menghitungnya.
+//
+//   - TepatWaktu: bagian pesanan jatuh tempo yang diterima lengkap paling lambat
+//     pada estimasi_tiba. Pesanan jatuh tempo adalah pesanan berestimasi yang sudah
+//     Diterima atau Ditutup, atau estimasinya sudah lewat.
+//   - FillRate: jumlah diterima dibanding jumlah dipesan (dalam satuan dasar) pada
+//     pesanan yang sudah Diterima atau Ditutup, atau estimasinya sudah lewat.
+//   - TingkatTolak: jumlah ditolak dibanding seluruh jumlah yang datang.
+//   - VariansHarga: selisih belanja terhadap harga rata-rata semua supplier untuk
+//     produk yang sama; positif berarti lebih mahal. Hanya produk yang dibeli dari
+//     minimal dua supplier dalam periode yang dihitung.
+type Scorecard struct {
+	SupplierID        int64    `json:"supplier_id"`
+	Dari              string   `json:"dari"`
+	Sampai            string   `json:"sampai"`
+	JumlahPesanan     int      `json:"jumlah_pesanan"`
+	PesananJatuhTempo int      `json:"pesanan_jatuh_tempo"`
+	PesananTepatWaktu int      `json:"pesanan_tepat_waktu"`
+	TepatWaktu        *float64 `json:"tepat_waktu"`
+	FillRate          *float64 `json:"fill_rate"`
+	TingkatTolak      *float64 `json:"tingkat_tolak"`
+	VariansHarga      *float64 `json:"varians_harga"`
+	// Skor adalah gabungan berbobot metrik di atas (0 sampai 1), Rating adalah Skor × 5
+	Skor   *float64 `json:"skor"`
+	Rating *float64 `json:"rating"`
+}
+
+// akumulasi menampung penjumlahan per supplier sebelum dijadikan rasio
+type akumulasi struct {
+	pesanan       map[int64]bool
+	jatuhTempo    map[int64]bool
+	tepatWaktu    map[int64]bool
+	dipesan       int
+	diterima      int
+	datang        int
+	ditolak       int
+	belanja       float64
+	belanjaPasar  float64
+	adaHargaPasar bool
+}
+
+// Hitung menilai setiap supplier yang muncul di baris. hariIni (YYYY-MM-DD) menentukan
+// pesanan mana yang sudah lewat estimasinya. dari dan sampai hanya disalin ke hasil.
+func Hitung(baris []models.BarisScorecard, dari, sampai, hariIni string) map[int64]Scorecard {
+	rataPasar := hargaPasar(baris)
+
+	per := make(map[int64]*akumulasi)
+	for _, b := range baris {
+		a := per[b.SupplierID]
+		if a == nil {
+			a = &akumulasi{pesanan: map[int64]bool{}, jatuhTempo: map[int64]bool{}, tepatWaktu: map[int64]bool{}}
+			per[b.SupplierID] = a
+		}
+		a.pesanan[b.PembelianID] = true
+
+		estimasi := tanggal(b.EstimasiTiba.String)
+		lewat := b.EstimasiTiba.Valid && estimasi < hariIni
+		// Pesanan yang ditutup sudah selesai, tetapi tidak pernah lengkap
+		lengkap := b.Status == models.StatusPembelianDiterima
+		selesai := lengkap || b.Status == models.StatusPembelianDitutup
+		if b.EstimasiTiba.Valid && (selesai || lewat) {
+			a.jatuhTempo[b.PembelianID] = true
+			if lengkap && b.TerimaTerakhir.Valid && tanggal(b.TerimaTerakhir.String) <= estimasi {
+				a.tepatWaktu[b.PembelianID] = true
+			}
+		}
+
+		faktor := max(b.FaktorKonversi, 1)
+		if selesai || lewat {
+			a.dipesan += b.Jumlah * faktor
+			a.diterima += b.JumlahDiterima * faktor
+		}
+		a.datang += (b.JumlahDiterima + b.JumlahDitolak) * faktor
+		a.ditolak += b.JumlahDitolak * faktor
+
+		if rata, ok := rataPasar[b.ProdukID]; ok {
+			jumlah := float64(b.Jumlah * faktor)
+			a.belanja += b.HargaBeliSatuan * float64(b.Jumlah)
+			a.belanjaPasar += rata * jumlah
+			a.adaHargaPasar = true
+		}
+	}
+
+	hasil := make(map[int64]Scorecard, len(per))
+	for id, a := range per {
+		sc := Scorecard{
+			SupplierID:        id,
+			Dari:              dari,
+			Sampai:            sampai,
+			JumlahPesanan:     len(a.pesanan),
+			PesananJatuhTempo: len(a.jatuhTempo),
+			PesananTepatWaktu: len(a.tepatWaktu),
+		}
+		if len(a.jatuhTempo) > 0 {
+			sc.TepatWaktu = rasio(float64(len(a.tepatWaktu)), float64(len(a.jatuhTempo)))
+		}
+		if a.dipesan > 0 {
+			sc.FillRate = rasio(float64(a.diterima), float64(a.dipesan))
+		}
+		if a.datang > 0 {
+			sc.TingkatTolak = rasio(float64(a.ditolak), float64(a.datang))
+		}
+		if a.adaHargaPasar && a.belanjaPasar > 0 {
+			sc.VariansHarga = rasio(a.belanja-a.belanjaPasar, a.belanjaPasar)
+		}
+		sc.Skor, sc.Rating = skor(sc)
+		hasil[id] = sc
+	}
+	return hasil
+}
+
+// hargaPasar menghitung harga rata-rata per satuan dasar (dibobot jumlah) untuk
+// produk yang dibeli dari minimal dua supplier
+func hargaPasar(baris []models.BarisScorecard) map[int64]float64 {
+	type total struct {
+		belanja  float64
+		jumlah   int
+		supplier map[int64]bool
+	}
+	per := make(map[int64]*total)
+	for _, b := range baris {
+		t := per[b.ProdukID]
+		if t == nil {
+			t = &total{supplier: map[int64]bool{}}
+			per[b.ProdukID] = t
+		}
+		t.belanja += b.HargaBeliSatuan * float64(b.Jumlah)
+		t.jumlah += b.Jumlah * max(b.FaktorKonversi, 1)
+		t.supplier[b.SupplierID] = true
+	}
+	rata := make(map[int64]float64)
+	for id, t := range per {
+		if len(t.supplier) >= 2 && t.jumlah > 0 {
+			rata[id] = t.belanja / float64(t.jumlah)
+		}
+	}
+	return rata
+}
+
+// skor menggabungkan metrik yang tersedia menjadi skor 0..1 dan rating 0..RatingMaks
+func skor(sc Scorecard) (*float64, *float64) {
+	var total, bobot float64
+	tambah := func(nilai *float64, b float64, ubah func(float64) float64) {
+		if nilai != nil {
+			total += ubah(*nilai) * b
+			bobot += b
+		}
+	}
+	tetap := func(v float64) float64 { return v }
+	tambah(sc.TepatWaktu, bobotTepatWaktu, tetap)
+	tambah(sc.FillRate, bobotFillRate, func(v float64) float64 { return min(v, 1) })
+	tambah(sc.TingkatTolak, bobotTolak, func(v float64) float64 { return 1 - v })
+	tambah(sc.VariansHarga, bobotHarga, func(v float64) float64 {
+		return min(max(1-max(v, 0)/variansHargaNol, 0), 1)
+	})
+	if bobot == 0 {
+		return nil, nil
+	}
+	s := bulatkan(total/bobot, 4)
+	r := bulatkan(s*RatingMaks, 2)
+	return &s, &r
+}
+
+func rasio(a, b float64) *float64 {
+	v := bulatkan(a/b, 4)
+	return &v
+}
+
+func bulatkan(v float64, desimal int) float64 {
+	p := math.Pow(10, float64(desimal))
+	return math.Round(v*p) / p
+}
+
+// tanggal memotong DATETIME atau RFC3339 menjadi YYYY-MM-DD
+func tanggal(v string) string {
+	if len(v) > 10 {
+		return v[:10]
+	}
+	return v
+}
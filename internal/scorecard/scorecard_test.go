@@ -0,0 +1,112 @@
+package scorecard
+
+import (
+	"database/sql"
+	"fmt"
+	"testing"
+
+	"scm-api/internal/models"
+)
+
+func teks(v string) sql.NullString { return sql.NullString{String: v, Valid: v != ""} }
+
+func nilai(v *float64) string {
+	if v == nil {
+		return "nil"
+	}
+	return fmt.Sprint(*v)
+}
+
+func TestHitung(t *testing.T) {
+	baris := []models.BarisScorecard{
+		// Supplier 1: lengkap dan tepat waktu, sebagian barang ditolak
+		{PembelianID: 1, SupplierID: 1, Status: models.StatusPembelianDiterima, EstimasiTiba: teks("2024-06-01"), TerimaTerakhir: teks("2024-06-01 09:00:00"),
+			ProdukID: 1, Jumlah: 10, FaktorKonversi: 1, HargaBeliSatuan: 1000, JumlahDiterima: 9, JumlahDitolak: 1},
+		// Lengkap tetapi terlambat; produk 2 hanya dibeli dari supplier 1 sehingga tanpa harga pasar
+		{PembelianID: 2, SupplierID: 1, Status: models.StatusPembelianDiterima, EstimasiTiba: teks("2024-06-01"), TerimaTerakhir: teks("2024-06-03T08:00:00Z"),
+			ProdukID: 2, Jumlah: 2, FaktorKonversi: 12, HargaBeliSatuan: 12000, JumlahDiterima: 2},
+		// Estimasi sudah lewat dan belum ada barang datang
+		{PembelianID: 3, SupplierID: 1, Status: models.StatusPembelianDipesan, EstimasiTiba: teks("2024-06-05"),
+			ProdukID: 1, Jumlah: 5, FaktorKonversi: 1, HargaBeliSatuan: 1000},
+		// Estimasi belum lewat: tidak ikut tepat waktu maupun fill rate
+		{PembelianID: 4, SupplierID: 1, Status: models.StatusPembelianDikirim, EstimasiTiba: teks("2024-06-20"),
+			ProdukID: 1, Jumlah: 5, FaktorKonversi: 1, HargaBeliSatuan: 1000},
+		// Ditutup sebelum estimasi: jatuh tempo tetapi tidak pernah lengkap
+		{PembelianID: 5, SupplierID: 1, Status: models.StatusPembelianDitutup, EstimasiTiba: teks("2024-06-02"), TerimaTerakhir: teks("2024-06-01 10:00:00"),
+			ProdukID: 1, Jumlah: 10, HargaBeliSatuan: 1000, JumlahDiterima: 6},
+		// Supplier 2: tanpa estimasi, lebih mahal untuk produk 1
+		{PembelianID: 6, SupplierID: 2, Status: models.StatusPembelianDiterima, TerimaTerakhir: teks("2024-06-04 10:00:00"),
+			ProdukID: 1, Jumlah: 10, FaktorKonversi: 1, HargaBeliSatuan: 1200, JumlahDiterima: 10},
+		// Supplier 3: belum ada data apa pun untuk dinilai
+		{PembelianID: 7, SupplierID: 3, Status: models.StatusPembelianDipesan, ProdukID: 3, Jumlah: 4, FaktorKonversi: 1, HargaBeliSatuan: 500},
+	}
+	hasil := Hitung(baris, "2024-05-01", "2024-06-10", "2024-06-10")
+	if len(hasil) != 3 {
+		t.Fatalf("Hitung menghasilkan %d supplier, ingin 3", len(hasil))
+	}
+
+	for _, tc := range []struct {
+		supplier                             int64
+		pesanan, jatuhTempo, tepat           int
+		tepatWaktu, fill, tolak, harga, skor string
+		rating                               string
+	}{
+		// Tepat waktu 1/4; fill rate (9+24+0+6)/(10+24+5+10); tolak 1/40.
+		// Harga pasar produk 1 adalah 42000/40 = 1050, supplier 1 membelanjakan 30000 dari 31500.
+		// Skor 0,4×0,25 + 0,3×0,7959 + 0,2×0,975 + 0,1×1
+		{1, 5, 4, 1, "0.25", "0.7959", "0.025", "-0.0476", "0.6338", "3.17"},
+		// Tanpa tepat waktu, bobot sisanya dinormalkan: (0,3 + 0,2 + 0,1×(1-0,1429/0,2)) / 0,6
+		{2, 1, 0, 0, "nil", "1", "0", "0.1429", "0.8809", "4.4"},
+		{3, 1, 0, 0, "nil", "nil", "nil", "nil", "nil", "nil"},
+	} {
+		sc := hasil[tc.supplier]
+		if sc.SupplierID != tc.supplier || sc.Dari != "2024-05-01" || sc.Sampai != "2024-06-10" {
+			t.Errorf("supplier %d: identitas %+v", tc.supplier, sc)
+		}
+		if sc.JumlahPesanan != tc.pesanan || sc.PesananJatuhTempo != tc.jatuhTempo || sc.PesananTepatWaktu != tc.tepat {
+			t.Errorf("supplier %d: pesanan %d, jatuh tempo %d, tepat waktu %d; ingin %d, %d, %d",
+				tc.supplier, sc.JumlahPesanan, sc.PesananJatuhTempo, sc.PesananTepatWaktu, tc.pesanan, tc.jatuhTempo, tc.tepat)
+		}
+		got := []string{nilai(sc.TepatWaktu), nilai(sc.FillRate), nilai(sc.TingkatTolak), nilai(sc.VariansHarga), nilai(sc.Skor), nilai(sc.Rating)}
+		ingin := []string{tc.tepatWaktu, tc.fill, tc.tolak, tc.harga, tc.skor, tc.rating}
+		if fmt.Sprint(got) != fmt.Sprint(ingin) {
+			t.Errorf("supplier %d: tepat waktu, fill rate, tolak, varians, skor, rating = %v; ingin %v", tc.supplier, got, ingin)
+		}
+	}
+}
+
+func TestHargaPasar(t *testing.T) {
+	rata := hargaPasar([]models.BarisScorecard{
+		// Harga per dus 12 pcs dibagi ke satuan dasar: (12000 + 10×1100) / (12 + 10)
+		{SupplierID: 1, ProdukID: 1, Jumlah: 1, FaktorKonversi: 12, HargaBeliSatuan: 12000},
+		{SupplierID: 2, ProdukID: 1, Jumlah: 10, HargaBeliSatuan: 1100},
+		// Dua pesanan dari supplier yang sama bukan pembanding
+		{SupplierID: 1, ProdukID: 2, Jumlah: 5, FaktorKonversi: 1, HargaBeliSatuan: 700},
+		{SupplierID: 1, ProdukID: 2, Jumlah: 5, FaktorKonversi: 1, HargaBeliSatuan: 900},
+	})
+	if len(rata) != 1 || rata[1] != 23000.0/22 {
+		t.Errorf("hargaPasar = %v, ingin hanya produk 1 seharga %v", rata, 23000.0/22)
+	}
+}
+
+func TestSkor(t *testing.T) {
+	f := func(v float64) *float64 { return &v }
+	for _, tc := range []struct {
+		nama       string
+		sc         Scorecard
+		skor, rate string
+	}{
+		{"tanpa metrik", Scorecard{}, "nil", "nil"},
+		{"hanya penolakan", Scorecard{TingkatTolak: f(0.1)}, "0.9", "4.5"},
+		{"fill rate di atas 1 dibatasi", Scorecard{FillRate: f(1.2)}, "1", "5"},
+		{"lebih murah dari pasar bernilai penuh", Scorecard{VariansHarga: f(-0.3)}, "1", "5"},
+		{"selisih harga separuh batas", Scorecard{VariansHarga: f(variansHargaNol / 2)}, "0.5", "2.5"},
+		{"selisih harga melewati batas", Scorecard{VariansHarga: f(0.5)}, "0", "0"},
+		{"semua metrik", Scorecard{TepatWaktu: f(0.5), FillRate: f(1), TingkatTolak: f(0), VariansHarga: f(0)}, "0.8", "4"},
+	} {
+		s, r := skor(tc.sc)
+		if nilai(s) != tc.skor || nilai(r) != tc.rate {
+			t.Errorf("%s: skor %s, rating %s; ingin %s, %s", tc.nama, nilai(s), nilai(r), tc.skor, tc.rate)
+		}
+	}
+}
@@ -0,0 +1,158 @@
+// file: internal/store/daftar.go
+
+package store
+
+import (
+	"maps"
+	"time"
+)
+
+// Kueri adalah parameter bersama untuk endpoint daftar: filter, rentang tanggal,
+// urutan, dan halaman. Nama field memakai nama JSON entitas dan sudah diperiksa
+// terhadap Daftar milik endpoint sebelum sampai ke store.
+type Kueri struct {
+	// Filter berisi field yang nilainya harus sama persis
+	Filter map[string]string
+	// Lingkup membatasi field ke salah satu nilai, misalnya gudang milik pengguna.
+	// Field yang ada di map dengan daftar kosong berarti tidak ada baris yang boleh tampil.
+	Lingkup map[string][]int64
+	// Dari dan Sampai berlaku untuk field Daftar.Tanggal. Sampai bersifat eksklusif.
+	Dari   time.Time
+	Sampai time.Time
+	Urut   string
+	Turun  bool
+	// Halaman dimulai dari 1. Batas 0 berarti semua baris dalam satu halaman.
+	Halaman int
+	Batas   int
+	// TermasukDihapus ikut menampilkan baris yang sudah dihapus (soft delete)
+	TermasukDihapus bool
+}
+
+// Offset mengembalikan jumlah baris yang dilewati untuk halaman yang diminta
+func (q Kueri) Offset() int {
+	if q.Halaman < 1 {
+		return 0
+	}
+	return (q.Halaman - 1) * q.Batas
+}
+
+// DenganLingkup mengembalikan salinan q yang juga membatasi field ke ids, tanpa
+// mengubah map Lingkup milik pemanggil
+func (q Kueri) DenganLingkup(field string, ids []int64) Kueri {
+	lingkup := make(map[string][]int64, len(q.Lingkup)+1)
+	maps.Copy(lingkup, q.Lingkup)
+	lingkup[field] = ids
+	q.Lingkup = lingkup
+	return q
+}
+
+// Daftar menjelaskan field yang boleh difilter dan dipakai mengurutkan di satu endpoint daftar
+type Daftar struct {
+	Filter []string
+	Urut   []string
+	// UrutBawaan dan TurunBawaan dipakai jika klien tidak meminta urutan
+	UrutBawaan  string
+	TurunBawaan bool
+	// Tanggal adalah field yang disaring oleh dari/sampai. Kosong jika tidak didukung.
+	Tanggal string
+	// BisaDihapus berarti entitas memakai deleted_at dan mendukung include_deleted
+	BisaDihapus bool
+	// Gabungan memetakan kunci Kueri.Lingkup ke beberapa field. Baris lolos jika salah
+	// satu field itu bernilai di daftar, misalnya transfer dari atau ke gudang pengguna.
+	Gabungan map[string][]string
+}
+
+// Field daftar untuk setiap endpoint. Baris dengan nilai urut yang sama selalu
+// diurutkan lagi dengan ID agar pembagian halamannya stabil.
+var (
+	DaftarProduk = Daftar{
+		Filter:      []string{"kategori", "satuan", "supplier_id"},
+		Urut:        []string{"produk_id", "sku", "nama_produk", "kategori", "harga_jual"},
+		UrutBawaan:  "produk_id",
+		BisaDihapus: true,
+	}
+	DaftarSupplier = Daftar{
+		Filter:      []string{"nama_supplier"},
+		Urut:        []string{"supplier_id", "nama_supplier", "rating"},
+		UrutBawaan:  "supplier_id",
+		BisaDihapus: true,
+	}
+	DaftarGudang = Daftar{
+		Filter:      []string{"lokasi"},
+		Urut:        []string{"gudang_id", "nama_gudang", "lokasi"},
+		UrutBawaan:  "gudang_id",
+		BisaDihapus: true,
+	}
+	DaftarPembelian = Daftar{
+		Filter:      []string{"status", "supplier_id", "gudang_tujuan_id"},
+		Urut:        []string{"pembelian_id", "tanggal_pesan", "estimasi_tiba", "total_biaya", "status", "nama_supplier"},
+		UrutBawaan:  "tanggal_pesan",
+		TurunBawaan: true,
+		Tanggal:     "tanggal_pesan",
+	}
+	DaftarPermintaan = Daftar{
+		Filter:      []string{"status", "diminta_oleh", "supplier_id"},
+		Urut:        []string{"permintaan_id", "created_at", "tanggal_butuh", "total_biaya", "status"},
+		UrutBawaan:  "created_at",
+		TurunBawaan: true,
+		Tanggal:     "created_at",
+	}
+	DaftarKatalog = Daftar{
+		Filter:     []string{"supplier_id", "produk_id", "satuan"},
+		Urut:       []string{"supplier_produk_id", "nama_produk", "harga_beli", "moq", "lead_time_hari", "berlaku_dari"},
+		UrutBawaan: "supplier_produk_id",
+	}
+	DaftarStok = Daftar{
+		Filter:     []string{"produk_id", "gudang_id"},
+		Urut:       []string{"stok_id", "nama_produk", "nama_gudang", "jumlah", "tanggal_update"},
+		UrutBawaan: "stok_id",
+		Tanggal:    "tanggal_update",
+	}
+	DaftarMutasi = Daftar{
+		Filter:      []string{"produk_id", "gudang_id", "tipe", "referensi_tipe"},
+		Urut:        []string{"mutasi_id", "waktu", "perubahan"},
+		UrutBawaan:  "waktu",
+		TurunBawaan: true,
+		Tanggal:     "waktu",
+	}
+	// DaftarBatch hanya berisi batch yang masih bersisa. Batch tanpa tanggal
+	// kedaluwarsa tampil paling awal jika diurutkan menurut tanggal kedaluwarsa.
+	DaftarBatch = Daftar{
+		Filter:     []string{"produk_id", "gudang_id"},
+		Urut:       []string{"batch_id", "tanggal_kedaluwarsa", "tanggal_masuk", "jumlah", "nama_produk", "nama_gudang"},
+		UrutBawaan: "tanggal_kedaluwarsa",
+		Tanggal:    "tanggal_masuk",
+	}
+	DaftarTransfer = Daftar{
+		Filter:      []string{"status", "gudang_asal_id", "gudang_tujuan_id"},
+		Urut:        []string{"transfer_id", "tanggal_dibuat", "tanggal_kirim", "tanggal_terima", "status"},
+		UrutBawaan:  "tanggal_dibuat",
+		TurunBawaan: true,
+		Tanggal:     "tanggal_dibuat",
+		Gabungan:    map[string][]string{"gudang_id": {"gudang_asal_id", "gudang_tujuan_id"}},
+	}
+	// DaftarPenerimaan selalu dibatasi ke satu pesanan lewat Lingkup pembelian_id
+	DaftarPenerimaan = Daftar{
+		Urut:       []string{"penerimaan_id", "tanggal_terima"},
+		UrutBawaan: "tanggal_terima",
+		Tanggal:    "tanggal_terima",
+	}
+	DaftarPengguna = Daftar{
+		Filter:     []string{"username"},
+		Urut:       []string{"pengguna_id", "username", "nama_lengkap", "tanggal_dibuat"},
+		UrutBawaan: "pengguna_id",
+		Tanggal:    "tanggal_dibuat",
+	}
+	DaftarAturan = Daftar{
+		Filter:     []string{"level", "role_id", "kategori"},
+		Urut:       []string{"aturan_id", "level", "nilai_minimal"},
+		UrutBawaan: "level",
+	}
+	DaftarAudit = Daftar{
+		Filter:      []string{"entitas", "entitas_id", "aktor", "aksi"},
+		Urut:        []string{"audit_id", "waktu"},
+		UrutBawaan:  "waktu",
+		TurunBawaan: true,
+		Tanggal:     "waktu",
+	}
+)
@@ -0,0 +1,31 @@
+// file: internal/store/memory/audit.go
+
+package memory
+
+import (
+	"context"
+	"slices"
+
+	"scm-api/internal/models"
+	"scm-api/internal/store"
+)
+
+func (s *Store) CatatAudit(ctx context.Context, a *models.AuditLog) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	a.AuditID = s.nextID("audit_log")
+	a.Waktu = s.timestamp()
+	simpan := *a
+	simpan.Sebelum = slices.Clone(a.Sebelum)
+	simpan.Sesudah = slices.Clone(a.Sesudah)
+	simpan.Perubahan = slices.Clone(a.Perubahan)
+	s.audit = append(s.audit, simpan)
+	return nil
+}
+
+func (s *Store) ListAudit(ctx context.Context, q store.Kueri) ([]models.AuditLog, int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	daftar, total := terapkanKueri(s.audit, q, store.DaftarAudit)
+	return daftar, total, nil
+}
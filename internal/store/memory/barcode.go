@@ -0,0 +1,59 @@
+// file: internal/store/memory/barcode.go
+
+package memory
+
+import (
+	"context"
+
+	"scm-api/internal/models"
+	"scm-api/internal/store"
+)
+
+func (s *Store) ListBarcodeProduk(ctx context.Context, produkID int64) ([]models.ProdukBarcode, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	daftar := make([]models.ProdukBarcode, 0)
+	for _, id := range sortedKeys(s.barcode) {
+		if b := s.barcode[id]; b.ProdukID == produkID {
+			daftar = append(daftar, b)
+		}
+	}
+	return daftar, nil
+}
+
+func (s *Store) CariBarcode(ctx context.Context, gtin string) (models.ProdukBarcode, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, b := range s.barcode {
+		if b.GTIN == gtin {
+			return b, nil
+		}
+	}
+	return models.ProdukBarcode{}, store.ErrNotFound
+}
+
+func (s *Store) CreateBarcode(ctx context.Context, b *models.ProdukBarcode) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, v := range s.barcode {
+		if v.GTIN == b.GTIN {
+			return store.ErrDuplikat
+		}
+	}
+	b.BarcodeID = s.nextID("produk_barcode")
+	b.CreatedAt = s.timestamp()
+	s.barcode[b.BarcodeID] = *b
+	return nil
+}
+
+func (s *Store) DeleteBarcode(ctx context.Context, produkID int64, gtin string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for id, b := range s.barcode {
+		if b.ProdukID == produkID && b.GTIN == gtin {
+			delete(s.barcode, id)
+			return nil
+		}
+	}
+	return store.ErrNotFound
+}
@@ -0,0 +1,135 @@
+// file: internal/store/memory/batch.go
+
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"time"
+
+	"scm-api/internal/models"
+	"scm-api/internal/store"
+)
+
+// tambahBatch meniru INSERT INTO stok_batch. Pemanggil harus memegang s.mu.
+func (s *Store) tambahBatch(m *models.StokMutasi, b *models.StokBatch, jumlah int) {
+	b.BatchID = s.nextID("stok_batch")
+	b.ProdukID, b.GudangID, b.Jumlah = m.ProdukID, m.GudangID, jumlah
+	b.TanggalMasuk = s.timestamp()
+	s.batch[b.BatchID] = *b
+}
+
+// pakaiBatch mengambil sebanyak jumlah dari batch secara FEFO: kedaluwarsa paling dekat
+// lebih dulu, batch tanpa tanggal kedaluwarsa paling akhir. Jika seluruh batch habis,
+// sisanya diambil dari stok tanpa batch. Pemanggil harus memegang s.mu.
+func (s *Store) pakaiBatch(m *models.StokMutasi, jumlah int) []models.PemakaianBatch {
+	tersedia := s.batchFEFO(m.ProdukID, m.GudangID)
+	pemakaian := make([]models.PemakaianBatch, 0)
+	for _, b := range tersedia {
+		if jumlah == 0 {
+			break
+		}
+		ambil := min(b.Jumlah, jumlah)
+		b.Jumlah -= ambil
+		s.batch[b.BatchID] = b
+		pemakaian = append(pemakaian, models.PemakaianBatch{
+			BatchID:            b.BatchID,
+			NomorLot:           b.NomorLot,
+			TanggalProduksi:    b.TanggalProduksi,
+			TanggalKedaluwarsa: b.TanggalKedaluwarsa,
+			Jumlah:             ambil,
+		})
+		jumlah -= ambil
+	}
+	return pemakaian
+}
+
+// batchFEFO mengembalikan batch bersisa untuk produk di gudang dengan urutan FEFO. Pemanggil harus memegang s.mu.
+func (s *Store) batchFEFO(produkID, gudangID int64) []models.StokBatch {
+	daftar := make([]models.StokBatch, 0)
+	for _, id := range sortedKeys(s.batch) {
+		if b := s.batch[id]; b.ProdukID == produkID && b.GudangID == gudangID && b.Jumlah > 0 {
+			daftar = append(daftar, b)
+		}
+	}
+	sort.SliceStable(daftar, func(i, j int) bool { return lebihDuluKedaluwarsa(daftar[i], daftar[j]) })
+	return daftar
+}
+
+// lebihDuluKedaluwarsa meniru ORDER BY tanggal_kedaluwarsa IS NULL, tanggal_kedaluwarsa
+func lebihDuluKedaluwarsa(a, b models.StokBatch) bool {
+	if a.TanggalKedaluwarsa.Valid != b.TanggalKedaluwarsa.Valid {
+		return a.TanggalKedaluwarsa.Valid
+	}
+	return a.TanggalKedaluwarsa.String < b.TanggalKedaluwarsa.String
+}
+
+func (s *Store) batchResponse(b models.StokBatch, hariIni time.Time) models.StokBatchResponse {
+	r := models.StokBatchResponse{
+		StokBatch:  b,
+		NamaProduk: s.produk[b.ProdukID].NamaProduk,
+		NamaGudang: s.gudang[b.GudangID].NamaGudang,
+	}
+	if t, err := time.Parse("2006-01-02", tanggalSaja(b.TanggalKedaluwarsa.String)); err == nil && b.TanggalKedaluwarsa.Valid {
+		r.SisaHari = sql.NullInt64{Int64: int64(t.Sub(hariIni).Hours() / 24), Valid: true}
+	}
+	return r
+}
+
+// tanggalSaja memotong tanggal berformat RFC3339 menjadi YYYY-MM-DD
+func tanggalSaja(v string) string {
+	if len(v) > 10 {
+		return v[:10]
+	}
+	return v
+}
+
+// hariIni meniru CURDATE()
+func (s *Store) hariIni() time.Time {
+	t, _ := time.Parse("2006-01-02", s.now().Format("2006-01-02"))
+	return t
+}
+
+func (s *Store) ListBatchStok(ctx context.Context, q store.Kueri) ([]models.StokBatchResponse, int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	hariIni := s.hariIni()
+	daftar := make([]models.StokBatchResponse, 0)
+	for _, id := range sortedKeys(s.batch) {
+		if b := s.batch[id]; b.Jumlah > 0 {
+			daftar = append(daftar, s.batchResponse(b, hariIni))
+		}
+	}
+	daftar, total := terapkanKueri(daftar, q, store.DaftarBatch)
+	return daftar, total, nil
+}
+
+func (s *Store) BatchKedaluwarsa(ctx context.Context, hari int, gudangID int64) ([]models.StokBatchResponse, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	hariIni := s.hariIni()
+	batas := hariIni.AddDate(0, 0, hari).Format("2006-01-02")
+	daftar := make([]models.StokBatchResponse, 0)
+	for _, id := range sortedKeys(s.batch) {
+		b := s.batch[id]
+		if b.Jumlah <= 0 || !b.TanggalKedaluwarsa.Valid || tanggalSaja(b.TanggalKedaluwarsa.String) > batas {
+			continue
+		}
+		if gudangID != 0 && b.GudangID != gudangID {
+			continue
+		}
+		daftar = append(daftar, s.batchResponse(b, hariIni))
+	}
+	sort.SliceStable(daftar, func(i, j int) bool {
+		a, b := daftar[i], daftar[j]
+		if a.TanggalKedaluwarsa.String != b.TanggalKedaluwarsa.String {
+			return a.TanggalKedaluwarsa.String < b.TanggalKedaluwarsa.String
+		}
+		if a.GudangID != b.GudangID {
+			return a.GudangID < b.GudangID
+		}
+		return a.ProdukID < b.ProdukID
+	})
+	return daftar, nil
+}
@@ -0,0 +1,152 @@
+// file: internal/store/memory/daftar.go
+
+package memory
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"scm-api/internal/store"
+)
+
+// terapkanKueri meniru queryDaftar milik store MySQL untuk data di memori. daftar
+// harus sudah terurut menurut ID. Setiap baris dibaca lewat bentuk JSON-nya,
+// sehingga nama field sama dengan yang dipakai di store.Daftar.
+func terapkanKueri[T any](daftar []T, q store.Kueri, d store.Daftar) ([]T, int) {
+	type baris struct {
+		v     T
+		urut  int
+		field map[string]any
+	}
+	cocok := make([]baris, 0, len(daftar))
+	for i, v := range daftar {
+		b := baris{v: v, urut: i, field: fieldJSON(v)}
+		if lolosKueri(b.field, q, d) {
+			cocok = append(cocok, b)
+		}
+	}
+
+	urut := q.Urut
+	if urut == "" {
+		urut = d.UrutBawaan
+	}
+	slices.SortStableFunc(cocok, func(a, b baris) int {
+		c := bandingkan(a.field[urut], b.field[urut])
+		if c == 0 {
+			c = cmp.Compare(a.urut, b.urut)
+		}
+		if q.Turun {
+			return -c
+		}
+		return c
+	})
+
+	total := len(cocok)
+	awal, akhir := 0, total
+	if q.Batas > 0 {
+		awal = min(q.Offset(), total)
+		akhir = min(awal+q.Batas, total)
+	}
+	hasil := make([]T, 0, akhir-awal)
+	for _, b := range cocok[awal:akhir] {
+		hasil = append(hasil, b.v)
+	}
+	return hasil, total
+}
+
+func lolosKueri(field map[string]any, q store.Kueri, d store.Daftar) bool {
+	if d.BisaDihapus && !q.TermasukDihapus && field["deleted_at"] != nil {
+		return false
+	}
+	for f, nilai := range q.Filter {
+		if v := field[f]; v == nil || fmt.Sprint(v) != nilai {
+			return false
+		}
+	}
+	for f, ids := range q.Lingkup {
+		fields, ok := d.Gabungan[f]
+		if !ok {
+			fields = []string{f}
+		}
+		if !slices.ContainsFunc(fields, func(f string) bool {
+			v, ok := field[f].(float64)
+			return ok && slices.Contains(ids, int64(v))
+		}) {
+			return false
+		}
+	}
+	if d.Tanggal != "" && (!q.Dari.IsZero() || !q.Sampai.IsZero()) {
+		s, _ := field[d.Tanggal].(string)
+		t, ok := waktuKolom(s)
+		if !ok || (!q.Dari.IsZero() && t.Before(q.Dari)) || (!q.Sampai.IsZero() && !t.Before(q.Sampai)) {
+			return false
+		}
+	}
+	return true
+}
+
+// waktuKolom membaca nilai DATE atau DATETIME apa adanya, tanpa zona waktu, seperti
+// MySQL membandingkan kolom dengan batas dari/sampai. Jam ikut dibandingkan, sehingga
+// rentang yang batasnya bukan awal hari tetap sama hasilnya dengan store MySQL.
+func waktuKolom(s string) (time.Time, bool) {
+	const panjangTanggal, panjangWaktu = len("2006-01-02"), len("2006-01-02 15:04:05")
+	if len(s) >= panjangWaktu {
+		t, err := time.Parse("2006-01-02 15:04:05", strings.Replace(s[:panjangWaktu], "T", " ", 1))
+		return t, err == nil
+	}
+	if len(s) == panjangTanggal {
+		t, err := time.Parse("2006-01-02", s)
+		return t, err == nil
+	}
+	return time.Time{}, false
+}
+
+// fieldJSON mengubah baris menjadi map field JSON. Nilai sql.Null* yang tidak valid
+// menjadi nil dan yang valid diganti isinya, seperti kolom NULL di database.
+func fieldJSON(v any) map[string]any {
+	b, _ := json.Marshal(v)
+	field := make(map[string]any)
+	json.Unmarshal(b, &field)
+	for k, nilai := range field {
+		obj, ok := nilai.(map[string]any)
+		if !ok {
+			continue
+		}
+		valid, ok := obj["Valid"].(bool)
+		if !ok {
+			continue
+		}
+		field[k] = nil
+		if valid {
+			for isiKey, isi := range obj {
+				if isiKey != "Valid" {
+					field[k] = isi
+				}
+			}
+		}
+	}
+	return field
+}
+
+// bandingkan mengurutkan nilai JSON: NULL paling awal seperti ORDER BY di MySQL,
+// lalu angka menurut nilainya dan teks menurut abjad
+func bandingkan(a, b any) int {
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return -1
+	case b == nil:
+		return 1
+	}
+	if x, ok := a.(float64); ok {
+		if y, ok := b.(float64); ok {
+			return cmp.Compare(x, y)
+		}
+	}
+	return cmp.Compare(fmt.Sprint(a), fmt.Sprint(b))
+}
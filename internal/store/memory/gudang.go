@@ -0,0 +1,96 @@
+// file: internal/store/memory/gudang.go
+
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"scm-api/internal/models"
+	"scm-api/internal/store"
+)
+
+func (s *Store) ListGudang(ctx context.Context, q store.Kueri) ([]models.Gudang, int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	daftarGudang := make([]models.Gudang, 0, len(s.gudang))
+	for _, id := range sortedKeys(s.gudang) {
+		daftarGudang = append(daftarGudang, s.gudang[id])
+	}
+	daftarGudang, total := terapkanKueri(daftarGudang, q, store.DaftarGudang)
+	return daftarGudang, total, nil
+}
+
+func (s *Store) GetGudang(ctx context.Context, id int64) (models.Gudang, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	g, ok := s.gudang[id]
+	if !ok {
+		return g, store.ErrNotFound
+	}
+	return g, nil
+}
+
+func (s *Store) CreateGudang(ctx context.Context, g *models.Gudang) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	g.GudangID = s.nextID("gudang")
+	g.DeletedAt = sql.NullString{}
+	s.gudang[g.GudangID] = *g
+	return nil
+}
+
+func (s *Store) UpdateGudang(ctx context.Context, g models.Gudang) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	lama, ok := s.gudang[g.GudangID]
+	if !ok {
+		return nil
+	}
+	// deleted_at hanya diubah lewat Delete dan Pulihkan
+	g.DeletedAt = lama.DeletedAt
+	s.gudang[g.GudangID] = g
+	return nil
+}
+
+func (s *Store) DeleteGudang(ctx context.Context, id int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	v, ok := s.gudang[id]
+	if !ok || v.DeletedAt.Valid {
+		return store.ErrNotFound
+	}
+	for key, st := range s.stok {
+		if key.gudangID == id && st.Jumlah != 0 {
+			return fmt.Errorf("%w: produk %d masih %d unit", store.ErrStokTersisa, key.produkID, st.Jumlah)
+		}
+	}
+	v.DeletedAt = sql.NullString{String: s.timestamp(), Valid: true}
+	s.gudang[id] = v
+	return nil
+}
+
+func (s *Store) PulihkanGudang(ctx context.Context, id int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	v, ok := s.gudang[id]
+	if !ok || !v.DeletedAt.Valid {
+		return store.ErrNotFound
+	}
+	v.DeletedAt = sql.NullString{}
+	s.gudang[id] = v
+	return nil
+}
+
+func (s *Store) CountGudang(ctx context.Context) (int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	n := 0
+	for _, v := range s.gudang {
+		if !v.DeletedAt.Valid {
+			n++
+		}
+	}
+	return n, nil
+}
@@ -0,0 +1,131 @@
+// file: internal/store/memory/katalog.go
+
+package memory
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"scm-api/internal/models"
+	"scm-api/internal/store"
+)
+
+// lengkapiKatalog mengisi nama supplier dan produk seperti JOIN. Pemanggil harus memegang s.mu.
+func (s *Store) lengkapiKatalog(k models.SupplierProduk) models.SupplierProduk {
+	k.NamaSupplier = s.supplier[k.SupplierID].NamaSupplier
+	k.NamaProduk = s.produk[k.ProdukID].NamaProduk
+	return k
+}
+
+func (s *Store) ListKatalog(ctx context.Context, q store.Kueri) ([]models.SupplierProduk, int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	daftar := make([]models.SupplierProduk, 0, len(s.katalog))
+	for _, id := range sortedKeys(s.katalog) {
+		daftar = append(daftar, s.lengkapiKatalog(s.katalog[id]))
+	}
+	daftar, total := terapkanKueri(daftar, q, store.DaftarKatalog)
+	return daftar, total, nil
+}
+
+func (s *Store) KatalogAktif(ctx context.Context, supplierID, produkID int64, tanggal string) ([]models.SupplierProduk, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	daftar := make([]models.SupplierProduk, 0)
+	for _, id := range sortedKeys(s.katalog) {
+		k := s.katalog[id]
+		if k.ProdukID != produkID || (supplierID != 0 && k.SupplierID != supplierID) || !k.BerlakuPada(tanggal) {
+			continue
+		}
+		if supplierID == 0 && s.supplier[k.SupplierID].DeletedAt.Valid {
+			continue
+		}
+		daftar = append(daftar, s.lengkapiKatalog(k))
+	}
+	sort.SliceStable(daftar, func(i, j int) bool { return daftar[i].HargaBeli < daftar[j].HargaBeli })
+	return daftar, nil
+}
+
+func (s *Store) KatalogAktifProduk(ctx context.Context, produkIDs []int64, tanggal string) ([]models.KatalogBerfaktor, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	daftar := make([]models.KatalogBerfaktor, 0)
+	for _, id := range sortedKeys(s.katalog) {
+		k := s.katalog[id]
+		if !slices.Contains(produkIDs, k.ProdukID) || !k.BerlakuPada(tanggal) || s.supplier[k.SupplierID].DeletedAt.Valid {
+			continue
+		}
+		kb := models.KatalogBerfaktor{SupplierProduk: s.lengkapiKatalog(k)}
+		if strings.EqualFold(k.Satuan, s.produk[k.ProdukID].Satuan) {
+			kb.Faktor = 1
+		} else if sid, ok := s.satuanOf(k.ProdukID, k.Satuan); ok {
+			kb.Faktor = s.satuan[sid].Faktor
+		}
+		daftar = append(daftar, kb)
+	}
+	sort.SliceStable(daftar, func(i, j int) bool {
+		if daftar[i].ProdukID != daftar[j].ProdukID {
+			return daftar[i].ProdukID < daftar[j].ProdukID
+		}
+		return daftar[i].HargaBeli < daftar[j].HargaBeli
+	})
+	return daftar, nil
+}
+
+func (s *Store) GetKatalog(ctx context.Context, id int64) (models.SupplierProduk, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	k, ok := s.katalog[id]
+	if !ok {
+		return models.SupplierProduk{}, store.ErrNotFound
+	}
+	return s.lengkapiKatalog(k), nil
+}
+
+func (s *Store) CreateKatalog(ctx context.Context, k *models.SupplierProduk) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	// Periksa referensi seperti foreign key di database
+	if _, ok := s.supplier[k.SupplierID]; !ok {
+		return fmt.Errorf("gagal menyimpan katalog: supplier %d tidak ada", k.SupplierID)
+	}
+	if _, ok := s.produk[k.ProdukID]; !ok {
+		return fmt.Errorf("gagal menyimpan katalog: produk %d tidak ada", k.ProdukID)
+	}
+	k.SupplierProdukID = s.nextID("supplier_produk")
+	k.CreatedAt = s.timestamp()
+	k.UpdatedAt = k.CreatedAt
+	*k = s.lengkapiKatalog(*k)
+	s.katalog[k.SupplierProdukID] = *k
+	return nil
+}
+
+func (s *Store) UpdateKatalog(ctx context.Context, k models.SupplierProduk) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	lama, ok := s.katalog[k.SupplierProdukID]
+	if !ok {
+		return store.ErrNotFound
+	}
+	if _, ok := s.produk[k.ProdukID]; !ok {
+		return fmt.Errorf("gagal menyimpan katalog: produk %d tidak ada", k.ProdukID)
+	}
+	k.SupplierID = lama.SupplierID
+	k.CreatedAt = lama.CreatedAt
+	k.UpdatedAt = s.timestamp()
+	s.katalog[k.SupplierProdukID] = k
+	return nil
+}
+
+func (s *Store) DeleteKatalog(ctx context.Context, id int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.katalog[id]; !ok {
+		return store.ErrNotFound
+	}
+	delete(s.katalog, id)
+	return nil
+}
@@ -0,0 +1,242 @@
+// file: internal/store/memory/pembelian.go
+
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+
+	"scm-api/internal/models"
+	"scm-api/internal/store"
+)
+
+func (s *Store) ListPembelian(ctx context.Context, q store.Kueri) ([]models.PembelianResponse, int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	daftarPembelian := make([]models.PembelianResponse, 0, len(s.pembelian))
+	for _, id := range sortedKeys(s.pembelian) {
+		daftarPembelian = append(daftarPembelian, s.pembelianResponse(s.pembelian[id]))
+	}
+	daftarPembelian, total := terapkanKueri(daftarPembelian, q, store.DaftarPembelian)
+	return daftarPembelian, total, nil
+}
+
+func (s *Store) RecentPembelian(ctx context.Context, limit int) ([]models.PembelianResponse, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.pembelianTerurut(limit), nil
+}
+
+// pembelianTerurut meniru ORDER BY tanggal_pesan DESC [LIMIT n]. Pemanggil harus memegang s.mu.
+func (s *Store) pembelianTerurut(limit int) []models.PembelianResponse {
+	daftarPembelian := make([]models.PembelianResponse, 0, len(s.pembelian))
+	for _, id := range sortedKeys(s.pembelian) {
+		daftarPembelian = append(daftarPembelian, s.pembelianResponse(s.pembelian[id]))
+	}
+	sort.SliceStable(daftarPembelian, func(i, j int) bool {
+		return daftarPembelian[i].TanggalPesan > daftarPembelian[j].TanggalPesan
+	})
+	if limit > 0 && len(daftarPembelian) > limit {
+		daftarPembelian = daftarPembelian[:limit]
+	}
+	return daftarPembelian
+}
+
+func (s *Store) pembelianResponse(p models.Pembelian) models.PembelianResponse {
+	return models.PembelianResponse{
+		PembelianID:    p.PembelianID,
+		SupplierID:     p.SupplierID,
+		NamaSupplier:   s.supplier[p.SupplierID].NamaSupplier,
+		TanggalPesan:   p.TanggalPesan,
+		EstimasiTiba:   p.EstimasiTiba,
+		TotalBiaya:     p.TotalBiaya,
+		Status:         p.Status,
+		GudangTujuanID: p.GudangTujuanID,
+	}
+}
+
+func (s *Store) GetPembelian(ctx context.Context, id int64) (models.PembelianDenganDetailResponse, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var response models.PembelianDenganDetailResponse
+	p, ok := s.pembelian[id]
+	if !ok {
+		return response, store.ErrNotFound
+	}
+	header := s.pembelianResponse(p)
+	response = models.PembelianDenganDetailResponse{
+		PembelianID:    header.PembelianID,
+		SupplierID:     header.SupplierID,
+		NamaSupplier:   header.NamaSupplier,
+		TanggalPesan:   header.TanggalPesan,
+		EstimasiTiba:   header.EstimasiTiba,
+		TotalBiaya:     header.TotalBiaya,
+		Status:         header.Status,
+		GudangTujuanID: header.GudangTujuanID,
+	}
+	response.Details = s.detailPembelianResponse(id)
+	return response, nil
+}
+
+// detailOf mengembalikan detail sebuah pembelian terurut berdasarkan ID. Pemanggil harus memegang s.mu.
+func (s *Store) detailOf(pembelianID int64) []models.DetailPembelian {
+	details := make([]models.DetailPembelian, 0)
+	for _, id := range sortedKeys(s.detailPembelian) {
+		if d := s.detailPembelian[id]; d.PembelianID == pembelianID {
+			details = append(details, d)
+		}
+	}
+	return details
+}
+
+func (s *Store) CreatePembelian(ctx context.Context, p *models.Pembelian, details []models.DetailPembelian, oleh string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.buatPembelian(p, details, oleh)
+}
+
+func (s *Store) CreatePembelianBanyak(ctx context.Context, daftar []models.PembelianBaru, oleh string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	// Semua diperiksa lebih dulu agar kegagalan tidak meninggalkan sebagian pesanan, seperti rollback
+	for _, pb := range daftar {
+		if err := s.periksaPembelian(pb.Pembelian, pb.Details); err != nil {
+			return err
+		}
+	}
+	for i := range daftar {
+		if err := s.buatPembelian(&daftar[i].Pembelian, daftar[i].Details, oleh); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// buatPembelian menyimpan header, detail, dan riwayat status awal. Pemanggil harus memegang s.mu.
+func (s *Store) buatPembelian(p *models.Pembelian, details []models.DetailPembelian, oleh string) error {
+	if err := s.periksaPembelian(*p, details); err != nil {
+		return err
+	}
+	p.PembelianID = s.nextID("pembelian")
+	s.pembelian[p.PembelianID] = *p
+	for i := range details {
+		details[i].PembelianID = p.PembelianID
+		details[i].DetailPembelianID = s.nextID("detail_pembelian")
+		s.detailPembelian[details[i].DetailPembelianID] = details[i]
+	}
+	s.catatRiwayatStatus(p.PembelianID, "", p.Status, oleh, "")
+	return nil
+}
+
+// periksaPembelian memeriksa referensi seperti foreign key di database. Pemanggil harus memegang s.mu.
+func (s *Store) periksaPembelian(p models.Pembelian, details []models.DetailPembelian) error {
+	if _, ok := s.supplier[p.SupplierID]; !ok {
+		return fmt.Errorf("gagal menyimpan data pembelian: supplier %d tidak ada", p.SupplierID)
+	}
+	if p.GudangTujuanID.Valid {
+		if _, ok := s.gudang[p.GudangTujuanID.Int64]; !ok {
+			return fmt.Errorf("gagal menyimpan data pembelian: gudang %d tidak ada", p.GudangTujuanID.Int64)
+		}
+	}
+	for _, d := range details {
+		if _, ok := s.produk[d.ProdukID]; !ok {
+			return fmt.Errorf("gagal menyimpan detail produk pembelian: produk %d tidak ada", d.ProdukID)
+		}
+	}
+	return nil
+}
+
+func (s *Store) DeletePembelian(ctx context.Context, id int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	p, ok := s.pembelian[id]
+	if !ok {
+		return store.ErrNotFound
+	}
+	if !models.BolehHapusPembelian(p.Status) {
+		return fmt.Errorf("%w: pembelian berstatus %q tidak bisa dihapus", store.ErrInvalidTransition, p.Status)
+	}
+	for _, pn := range s.penerimaan {
+		if pn.PembelianID == id {
+			return fmt.Errorf("%w: pembelian sudah memiliki penerimaan", store.ErrInvalidTransition)
+		}
+	}
+	for _, d := range s.detailOf(id) {
+		delete(s.detailPembelian, d.DetailPembelianID)
+	}
+	delete(s.pembelian, id)
+	return nil
+}
+
+func (s *Store) TerimaPembelian(ctx context.Context, id, gudangID int64, oleh string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.terima(&models.Penerimaan{PembelianID: id, DiterimaOleh: oleh, GudangID: gudangID}, true)
+}
+
+func (s *Store) UbahStatusPembelian(ctx context.Context, id int64, ke, oleh, catatan string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.periksaTransisi(id, ke); err != nil {
+		return err
+	}
+	s.ubahStatus(id, ke, oleh, catatan)
+	return nil
+}
+
+// periksaTransisi memastikan pembelian ada dan boleh berpindah ke status ke. Pemanggil harus memegang s.mu.
+func (s *Store) periksaTransisi(id int64, ke string) error {
+	p, ok := s.pembelian[id]
+	if !ok {
+		return store.ErrNotFound
+	}
+	if !models.BolehTransisiPembelian(p.Status, ke) {
+		return fmt.Errorf("%w: dari %q ke %q", store.ErrInvalidTransition, p.Status, ke)
+	}
+	return nil
+}
+
+// ubahStatus mengganti status pembelian dan mencatat riwayatnya. Pemanggil harus memegang s.mu.
+func (s *Store) ubahStatus(id int64, ke, oleh, catatan string) {
+	p := s.pembelian[id]
+	dari := p.Status
+	p.Status = ke
+	s.pembelian[id] = p
+	s.catatRiwayatStatus(id, dari, ke, oleh, catatan)
+}
+
+// catatRiwayatStatus menambah satu baris riwayat status. Pemanggil harus memegang s.mu.
+func (s *Store) catatRiwayatStatus(id int64, dari, ke, oleh, catatan string) {
+	s.riwayatStatus = append(s.riwayatStatus, models.RiwayatStatusPembelian{
+		RiwayatID:   s.nextID("pembelian_status_riwayat"),
+		PembelianID: id,
+		StatusDari:  sql.NullString{String: dari, Valid: dari != ""},
+		StatusKe:    ke,
+		DiubahOleh:  oleh,
+		Catatan:     sql.NullString{String: catatan, Valid: catatan != ""},
+		Waktu:       s.timestamp(),
+	})
+}
+
+func (s *Store) RiwayatStatusPembelian(ctx context.Context, id int64) ([]models.RiwayatStatusPembelian, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if _, ok := s.pembelian[id]; !ok {
+		return nil, store.ErrNotFound
+	}
+	riwayat := make([]models.RiwayatStatusPembelian, 0)
+	for _, r := range s.riwayatStatus {
+		if r.PembelianID == id {
+			riwayat = append(riwayat, r)
+		}
+	}
+	return riwayat, nil
+}
+
+func (s *Store) CountPembelian(ctx context.Context) (int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.pembelian), nil
+}
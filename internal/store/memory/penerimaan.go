@@ -0,0 +1,134 @@
+// file: internal/store/memory/penerimaan.go
+
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"scm-api/internal/models"
+	"scm-api/internal/store"
+)
+
+// detailPembelianResponse meniru query detail pembelian beserta jumlah diterima/ditolak. Pemanggil harus memegang s.mu.
+func (s *Store) detailPembelianResponse(pembelianID int64) []models.DetailPembelianResponse {
+	diterima := make(map[int64]int)
+	ditolak := make(map[int64]int)
+	for _, p := range s.penerimaan {
+		if p.PembelianID != pembelianID {
+			continue
+		}
+		for _, d := range p.Details {
+			diterima[d.DetailPembelianID] += d.JumlahDiterima
+			ditolak[d.DetailPembelianID] += d.JumlahDitolak
+		}
+	}
+
+	details := make([]models.DetailPembelianResponse, 0)
+	for _, d := range s.detailOf(pembelianID) {
+		details = append(details, models.DetailPembelianResponse{
+			DetailPembelianID: d.DetailPembelianID,
+			ProdukID:          d.ProdukID,
+			NamaProduk:        s.produk[d.ProdukID].NamaProduk,
+			Jumlah:            d.Jumlah,
+			Satuan:            d.Satuan,
+			FaktorKonversi:    d.FaktorKonversi,
+			SatuanDasar:       s.produk[d.ProdukID].Satuan,
+			HargaBeliSatuan:   d.HargaBeliSatuan,
+			Subtotal:          d.Subtotal,
+			JumlahDiterima:    diterima[d.DetailPembelianID],
+			JumlahDitolak:     ditolak[d.DetailPembelianID],
+			Sisa:              d.Jumlah - diterima[d.DetailPembelianID] - ditolak[d.DetailPembelianID],
+		})
+	}
+	return details
+}
+
+func (s *Store) CreatePenerimaan(ctx context.Context, p *models.Penerimaan) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.terima(p, false)
+}
+
+// terima mencocokkan item dengan sisa pesanan lalu mencatat penerimaan, menambah stok,
+// dan memindahkan status. Jika penuh bernilai true, item diisi dengan seluruh sisa.
+// Pemanggil harus memegang s.mu.
+func (s *Store) terima(p *models.Penerimaan, penuh bool) error {
+	if err := s.periksaTransisi(p.PembelianID, models.StatusPembelianDiterima); err != nil {
+		if err == store.ErrNotFound {
+			return err
+		}
+		return fmt.Errorf("%w: pesanan berstatus %q tidak bisa menerima barang", store.ErrInvalidTransition, s.pembelian[p.PembelianID].Status)
+	}
+
+	lines := s.detailPembelianResponse(p.PembelianID)
+	if penuh {
+		p.Details = store.PenerimaanPenuh(lines)
+		if len(p.Details) == 0 {
+			s.ubahStatus(p.PembelianID, models.StatusPembelianDiterima, p.DiterimaOleh, p.Catatan.String)
+			return nil
+		}
+	}
+	ke, err := store.CocokkanPenerimaan(lines, p.Details)
+	if err != nil {
+		return err
+	}
+	daftarGudang, err := store.TentukanGudang(p, s.pembelian[p.PembelianID].GudangTujuanID)
+	if err != nil {
+		return err
+	}
+	for _, gudangID := range daftarGudang {
+		g, ok := s.gudang[gudangID]
+		if !ok {
+			return fmt.Errorf("%w: gudang %d tidak ditemukan", store.ErrInvalidReceipt, gudangID)
+		}
+		if g.DeletedAt.Valid {
+			return fmt.Errorf("%w: gudang %d sudah diarsipkan", store.ErrInvalidReceipt, gudangID)
+		}
+	}
+
+	p.PenerimaanID = s.nextID("penerimaan")
+	if p.TanggalTerima == "" {
+		p.TanggalTerima = s.timestamp()
+	}
+	for i := range p.Details {
+		d := &p.Details[i]
+		d.PenerimaanID = p.PenerimaanID
+		d.DetailPenerimaanID = s.nextID("detail_penerimaan")
+		if d.JumlahStok > 0 {
+			diterima := d.JumlahStok
+			// Penerimaan hanya menambah stok sehingga tidak mungkin gagal
+			_, _ = s.mutasiStok(&models.StokMutasi{
+				ProdukID:      d.ProdukID,
+				GudangID:      d.GudangID,
+				Tipe:          models.MutasiPenerimaan,
+				ReferensiTipe: sql.NullString{String: "penerimaan", Valid: true},
+				ReferensiID:   sql.NullInt64{Int64: p.PenerimaanID, Valid: true},
+				DibuatOleh:    p.DiterimaOleh,
+			}, func(sebelum int) int { return sebelum + diterima }, d.Batch())
+		}
+	}
+	simpan := *p
+	simpan.Details = append([]models.DetailPenerimaan(nil), p.Details...)
+	s.penerimaan[p.PenerimaanID] = simpan
+
+	s.ubahStatus(p.PembelianID, ke, p.DiterimaOleh, fmt.Sprintf("Penerimaan #%d", p.PenerimaanID))
+	return nil
+}
+
+func (s *Store) ListPenerimaan(ctx context.Context, pembelianID int64, q store.Kueri) ([]models.Penerimaan, int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if _, ok := s.pembelian[pembelianID]; !ok {
+		return nil, 0, store.ErrNotFound
+	}
+	daftar := make([]models.Penerimaan, 0)
+	for _, id := range sortedKeys(s.penerimaan) {
+		p := s.penerimaan[id]
+		p.Details = append(make([]models.DetailPenerimaan, 0, len(p.Details)), p.Details...)
+		daftar = append(daftar, p)
+	}
+	daftar, total := terapkanKueri(daftar, q.DenganLingkup("pembelian_id", []int64{pembelianID}), store.DaftarPenerimaan)
+	return daftar, total, nil
+}
@@ -0,0 +1,162 @@
+// file: internal/store/memory/pengguna.go
+
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"scm-api/internal/models"
+	"scm-api/internal/store"
+)
+
+func (s *Store) ListPengguna(ctx context.Context, q store.Kueri) ([]models.PenggunaDenganRole, int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	daftarPengguna := make([]models.PenggunaDenganRole, 0, len(s.pengguna))
+	for _, id := range sortedKeys(s.pengguna) {
+		p := models.PenggunaDenganRole{
+			Pengguna: s.pengguna[id],
+			Role:     make([]string, 0),
+			Gudang:   append(make([]int64, 0), s.penggunaGudang[id]...),
+		}
+		for _, roleID := range s.penggunaRole[id] {
+			p.Role = append(p.Role, s.role[roleID].Nama)
+		}
+		daftarPengguna = append(daftarPengguna, p)
+	}
+	daftarPengguna, total := terapkanKueri(daftarPengguna, q, store.DaftarPengguna)
+	return daftarPengguna, total, nil
+}
+
+func (s *Store) GetPengguna(ctx context.Context, id int64) (models.Pengguna, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	p, ok := s.pengguna[id]
+	if !ok {
+		return p, store.ErrNotFound
+	}
+	return p, nil
+}
+
+func (s *Store) GetPenggunaByUsername(ctx context.Context, username string) (models.Pengguna, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, p := range s.pengguna {
+		if p.Username == username {
+			return p, nil
+		}
+	}
+	return models.Pengguna{}, store.ErrNotFound
+}
+
+func (s *Store) CreatePengguna(ctx context.Context, p *models.Pengguna) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	// Tolak seperti unique key di database
+	for _, lain := range s.pengguna {
+		if lain.Username == p.Username {
+			return store.ErrDuplikat
+		}
+	}
+	p.PenggunaID = s.nextID("pengguna")
+	p.TanggalDibuat = s.timestamp()
+	s.pengguna[p.PenggunaID] = *p
+	return nil
+}
+
+func (s *Store) SimpanRefreshToken(ctx context.Context, penggunaID int64, hash string, masaBerlaku time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.refreshToken[hash]; ok {
+		return store.ErrDuplikat
+	}
+	s.refreshToken[hash] = refreshToken{penggunaID: penggunaID, kedaluwarsa: s.now().Add(masaBerlaku)}
+	return nil
+}
+
+func (s *Store) PakaiRefreshToken(ctx context.Context, hash string) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t, ok := s.refreshToken[hash]
+	if !ok || t.dicabut || !s.now().Before(t.kedaluwarsa) {
+		return 0, store.ErrNotFound
+	}
+	t.dicabut = true
+	s.refreshToken[hash] = t
+	return t.penggunaID, nil
+}
+
+func (s *Store) CabutSemuaRefreshToken(ctx context.Context, penggunaID int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for hash, t := range s.refreshToken {
+		if t.penggunaID == penggunaID && !t.dicabut {
+			t.dicabut = true
+			s.refreshToken[hash] = t
+		}
+	}
+	return nil
+}
+
+func (s *Store) NaikkanVersiToken(ctx context.Context, penggunaID int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	p, ok := s.pengguna[penggunaID]
+	if !ok {
+		return store.ErrNotFound
+	}
+	p.VersiToken++
+	s.pengguna[penggunaID] = p
+	return nil
+}
+
+func (s *Store) SetAktifPengguna(ctx context.Context, penggunaID int64, aktif bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	p, ok := s.pengguna[penggunaID]
+	if !ok {
+		return store.ErrNotFound
+	}
+	p.Aktif = aktif
+	p.VersiToken++
+	s.pengguna[penggunaID] = p
+	if !aktif {
+		for hash, t := range s.refreshToken {
+			if t.penggunaID == penggunaID && !t.dicabut {
+				t.dicabut = true
+				s.refreshToken[hash] = t
+			}
+		}
+	}
+	return nil
+}
+
+func (s *Store) GudangPengguna(ctx context.Context, penggunaID int64) ([]int64, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if _, ok := s.pengguna[penggunaID]; !ok {
+		return nil, store.ErrNotFound
+	}
+	return append(make([]int64, 0), s.penggunaGudang[penggunaID]...), nil
+}
+
+func (s *Store) SetGudangPengguna(ctx context.Context, penggunaID int64, gudangIDs []int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.pengguna[penggunaID]; !ok {
+		return store.ErrNotFound
+	}
+	// Tolak seperti foreign key di database
+	for _, id := range gudangIDs {
+		if _, ok := s.gudang[id]; !ok {
+			return fmt.Errorf("gudang %d tidak ada", id)
+		}
+	}
+	ids := append([]int64(nil), gudangIDs...)
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	s.penggunaGudang[penggunaID] = ids
+	return nil
+}
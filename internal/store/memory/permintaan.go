@@ -0,0 +1,272 @@
+// file: internal/store/memory/permintaan.go
+
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+
+	"scm-api/internal/models"
+	"scm-api/internal/store"
+)
+
+// lengkapiPermintaan mengisi nama supplier, produk, dan role seperti JOIN lalu
+// menyalin slice agar pemanggil tidak bisa mengubah data di store. Pemanggil harus memegang s.mu.
+func (s *Store) lengkapiPermintaan(p models.PermintaanDenganDetail) models.PermintaanDenganDetail {
+	p.NamaSupplier = sql.NullString{}
+	if p.SupplierID.Valid {
+		p.NamaSupplier = sql.NullString{String: s.supplier[p.SupplierID.Int64].NamaSupplier, Valid: true}
+	}
+	details := make([]models.DetailPermintaan, 0, len(p.Details))
+	for _, d := range p.Details {
+		d.NamaProduk = s.produk[d.ProdukID].NamaProduk
+		d.Kategori = s.produk[d.ProdukID].Kategori
+		details = append(details, d)
+	}
+	persetujuan := make([]models.PersetujuanPermintaan, 0, len(p.Persetujuan))
+	for _, t := range p.Persetujuan {
+		t.NamaRole = s.role[t.RoleID].Nama
+		persetujuan = append(persetujuan, t)
+	}
+	p.Details, p.Persetujuan = details, persetujuan
+	return p
+}
+
+func (s *Store) ListPermintaan(ctx context.Context, q store.Kueri) ([]models.PermintaanPembelian, int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	daftar := make([]models.PermintaanPembelian, 0, len(s.permintaan))
+	for _, id := range sortedKeys(s.permintaan) {
+		daftar = append(daftar, s.lengkapiPermintaan(s.permintaan[id]).PermintaanPembelian)
+	}
+	daftar, total := terapkanKueri(daftar, q, store.DaftarPermintaan)
+	return daftar, total, nil
+}
+
+func (s *Store) GetPermintaan(ctx context.Context, id int64) (models.PermintaanDenganDetail, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	p, ok := s.permintaan[id]
+	if !ok {
+		return models.PermintaanDenganDetail{}, store.ErrNotFound
+	}
+	return s.lengkapiPermintaan(p), nil
+}
+
+func (s *Store) CreatePermintaan(ctx context.Context, p *models.PermintaanDenganDetail) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Periksa referensi seperti foreign key di database
+	if p.SupplierID.Valid {
+		if _, ok := s.supplier[p.SupplierID.Int64]; !ok {
+			return fmt.Errorf("gagal menyimpan permintaan: supplier %d tidak ada", p.SupplierID.Int64)
+		}
+	}
+	if p.GudangTujuanID.Valid {
+		if _, ok := s.gudang[p.GudangTujuanID.Int64]; !ok {
+			return fmt.Errorf("gagal menyimpan permintaan: gudang %d tidak ada", p.GudangTujuanID.Int64)
+		}
+	}
+	for _, d := range p.Details {
+		if _, ok := s.produk[d.ProdukID]; !ok {
+			return fmt.Errorf("gagal menyimpan detail permintaan: produk %d tidak ada", d.ProdukID)
+		}
+	}
+	for _, t := range p.Persetujuan {
+		if _, ok := s.role[t.RoleID]; !ok {
+			return fmt.Errorf("gagal menyimpan tahap persetujuan: role %d tidak ada", t.RoleID)
+		}
+	}
+
+	p.PermintaanID = s.nextID("permintaan_pembelian")
+	p.Status = models.StatusPermintaanDiajukan
+	if len(p.Persetujuan) == 0 {
+		p.Status = models.StatusPermintaanDisetujui
+	}
+	p.PembelianID = sql.NullInt64{}
+	p.CreatedAt = s.timestamp()
+	p.UpdatedAt = p.CreatedAt
+	for i := range p.Details {
+		p.Details[i].PermintaanID = p.PermintaanID
+		p.Details[i].DetailPermintaanID = s.nextID("detail_permintaan")
+	}
+	// Meniru ORDER BY level, role_id saat dibaca
+	sort.Slice(p.Persetujuan, func(i, j int) bool {
+		if p.Persetujuan[i].Level != p.Persetujuan[j].Level {
+			return p.Persetujuan[i].Level < p.Persetujuan[j].Level
+		}
+		return p.Persetujuan[i].RoleID < p.Persetujuan[j].RoleID
+	})
+	for i := range p.Persetujuan {
+		p.Persetujuan[i].PermintaanID = p.PermintaanID
+		p.Persetujuan[i].PersetujuanID = s.nextID("persetujuan_permintaan")
+		p.Persetujuan[i].Status = models.PersetujuanMenunggu
+	}
+	*p = s.lengkapiPermintaan(*p)
+	s.permintaan[p.PermintaanID] = s.lengkapiPermintaan(*p)
+	return nil
+}
+
+func (s *Store) PutuskanPersetujuan(ctx context.Context, permintaanID, persetujuanID int64, keputusan, oleh, komentar string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	p, ok := s.permintaan[permintaanID]
+	if !ok {
+		return store.ErrNotFound
+	}
+	p = s.lengkapiPermintaan(p)
+	idx := -1
+	for i, t := range p.Persetujuan {
+		if t.PersetujuanID == persetujuanID {
+			idx = i
+		}
+	}
+	if idx < 0 {
+		return store.ErrNotFound
+	}
+	tahap := p.Persetujuan[idx]
+	if p.Status != models.StatusPermintaanDiajukan || tahap.Status != models.PersetujuanMenunggu {
+		return fmt.Errorf("%w: permintaan %s, tahap %s", store.ErrInvalidTransition, p.Status, tahap.Status)
+	}
+	for _, t := range p.Persetujuan {
+		if t.Level < tahap.Level && t.Status == models.PersetujuanMenunggu {
+			return fmt.Errorf("%w: level %d belum diputuskan", store.ErrInvalidTransition, t.Level)
+		}
+	}
+
+	waktu := sql.NullString{String: s.timestamp(), Valid: true}
+	p.Persetujuan[idx].Status = keputusan
+	p.Persetujuan[idx].DiputuskanOleh = sql.NullString{String: oleh, Valid: true}
+	p.Persetujuan[idx].Komentar = sql.NullString{String: komentar, Valid: komentar != ""}
+	p.Persetujuan[idx].Waktu = waktu
+
+	masihMenunggu := false
+	for i, t := range p.Persetujuan {
+		if t.Status != models.PersetujuanMenunggu {
+			continue
+		}
+		// Ditolak menghentikan seluruh tahap; disetujui cukup untuk satu level
+		if keputusan == models.PersetujuanDitolak || t.Level == tahap.Level {
+			p.Persetujuan[i].Status = models.PersetujuanDilewati
+			continue
+		}
+		masihMenunggu = true
+	}
+	switch {
+	case keputusan == models.PersetujuanDitolak:
+		p.Status = models.StatusPermintaanDitolak
+	case !masihMenunggu:
+		p.Status = models.StatusPermintaanDisetujui
+	}
+	p.UpdatedAt = waktu.String
+	s.permintaan[permintaanID] = p
+	return nil
+}
+
+func (s *Store) BatalkanPermintaan(ctx context.Context, id int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	p, ok := s.permintaan[id]
+	if !ok {
+		return store.ErrNotFound
+	}
+	if !models.BolehTransisiPermintaan(p.Status, models.StatusPermintaanDibatalkan) {
+		return fmt.Errorf("%w: dari %q ke %q", store.ErrInvalidTransition, p.Status, models.StatusPermintaanDibatalkan)
+	}
+	p = s.lengkapiPermintaan(p)
+	for i, t := range p.Persetujuan {
+		if t.Status == models.PersetujuanMenunggu {
+			p.Persetujuan[i].Status = models.PersetujuanDilewati
+		}
+	}
+	p.Status = models.StatusPermintaanDibatalkan
+	p.UpdatedAt = s.timestamp()
+	s.permintaan[id] = p
+	return nil
+}
+
+func (s *Store) KonversiPermintaan(ctx context.Context, permintaanID int64, pb *models.Pembelian, details []models.DetailPembelian, oleh string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	p, ok := s.permintaan[permintaanID]
+	if !ok {
+		return store.ErrNotFound
+	}
+	if !models.BolehTransisiPermintaan(p.Status, models.StatusPermintaanSelesai) {
+		return fmt.Errorf("%w: dari %q ke %q", store.ErrInvalidTransition, p.Status, models.StatusPermintaanSelesai)
+	}
+	if err := s.buatPembelian(pb, details, oleh); err != nil {
+		return err
+	}
+	p.Status = models.StatusPermintaanSelesai
+	p.PembelianID = sql.NullInt64{Int64: pb.PembelianID, Valid: true}
+	p.UpdatedAt = s.timestamp()
+	s.permintaan[permintaanID] = p
+	return nil
+}
+
+func (s *Store) ListAturan(ctx context.Context, q store.Kueri) ([]models.AturanPersetujuan, int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	daftar := make([]models.AturanPersetujuan, 0, len(s.aturan))
+	for _, id := range sortedKeys(s.aturan) {
+		a := s.aturan[id]
+		a.NamaRole = s.role[a.RoleID].Nama
+		daftar = append(daftar, a)
+	}
+	daftar, total := terapkanKueri(daftar, q, store.DaftarAturan)
+	return daftar, total, nil
+}
+
+func (s *Store) GetAturan(ctx context.Context, id int64) (models.AturanPersetujuan, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	a, ok := s.aturan[id]
+	if !ok {
+		return models.AturanPersetujuan{}, store.ErrNotFound
+	}
+	a.NamaRole = s.role[a.RoleID].Nama
+	return a, nil
+}
+
+func (s *Store) CreateAturan(ctx context.Context, a *models.AturanPersetujuan) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r, ok := s.role[a.RoleID]
+	if !ok {
+		return fmt.Errorf("gagal menyimpan aturan persetujuan: role %d tidak ada", a.RoleID)
+	}
+	a.AturanID = s.nextID("aturan_persetujuan")
+	a.CreatedAt = s.timestamp()
+	a.NamaRole = r.Nama
+	s.aturan[a.AturanID] = *a
+	return nil
+}
+
+func (s *Store) UpdateAturan(ctx context.Context, a models.AturanPersetujuan) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	lama, ok := s.aturan[a.AturanID]
+	if !ok {
+		return store.ErrNotFound
+	}
+	if _, ok := s.role[a.RoleID]; !ok {
+		return fmt.Errorf("gagal mengubah aturan persetujuan: role %d tidak ada", a.RoleID)
+	}
+	a.CreatedAt = lama.CreatedAt
+	s.aturan[a.AturanID] = a
+	return nil
+}
+
+func (s *Store) DeleteAturan(ctx context.Context, id int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.aturan[id]; !ok {
+		return store.ErrNotFound
+	}
+	delete(s.aturan, id)
+	return nil
+}
@@ -0,0 +1,125 @@
+// file: internal/store/memory/produk.go
+
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"scm-api/internal/models"
+	"scm-api/internal/store"
+)
+
+func (s *Store) ListProduk(ctx context.Context, q store.Kueri) ([]models.Produk, int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	daftarProduk := make([]models.Produk, 0, len(s.produk))
+	for _, id := range sortedKeys(s.produk) {
+		daftarProduk = append(daftarProduk, s.produk[id])
+	}
+	daftarProduk, total := terapkanKueri(daftarProduk, q, store.DaftarProduk)
+	return daftarProduk, total, nil
+}
+
+func (s *Store) GetProduk(ctx context.Context, id int64) (models.Produk, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	p, ok := s.produk[id]
+	if !ok {
+		return p, store.ErrNotFound
+	}
+	return p, nil
+}
+
+func (s *Store) CreateProduk(ctx context.Context, p *models.Produk) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.skuDipakai(p.SKU, 0) {
+		return store.ErrDuplikat
+	}
+	p.ProdukID = s.nextID("produk")
+	p.DeletedAt = sql.NullString{}
+	p.GambarProduk, p.GambarThumbnail = sql.NullString{}, sql.NullString{}
+	s.produk[p.ProdukID] = *p
+	return nil
+}
+
+func (s *Store) UpdateProduk(ctx context.Context, p models.Produk) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	lama, ok := s.produk[p.ProdukID]
+	if !ok {
+		return nil
+	}
+	if s.skuDipakai(p.SKU, p.ProdukID) {
+		return store.ErrDuplikat
+	}
+	// deleted_at hanya diubah lewat Delete dan Pulihkan, gambar lewat SetGambarProduk
+	p.DeletedAt = lama.DeletedAt
+	p.GambarProduk, p.GambarThumbnail = lama.GambarProduk, lama.GambarThumbnail
+	s.produk[p.ProdukID] = p
+	return nil
+}
+
+func (s *Store) SetGambarProduk(ctx context.Context, id int64, gambar, thumbnail sql.NullString) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	v, ok := s.produk[id]
+	if !ok {
+		return store.ErrNotFound
+	}
+	v.GambarProduk, v.GambarThumbnail = gambar, thumbnail
+	s.produk[id] = v
+	return nil
+}
+
+func (s *Store) DeleteProduk(ctx context.Context, id int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	v, ok := s.produk[id]
+	if !ok || v.DeletedAt.Valid {
+		return store.ErrNotFound
+	}
+	v.DeletedAt = sql.NullString{String: s.timestamp(), Valid: true}
+	s.produk[id] = v
+	return nil
+}
+
+func (s *Store) PulihkanProduk(ctx context.Context, id int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	v, ok := s.produk[id]
+	if !ok || !v.DeletedAt.Valid {
+		return store.ErrNotFound
+	}
+	if s.skuDipakai(v.SKU, id) {
+		return store.ErrDuplikat
+	}
+	v.DeletedAt = sql.NullString{}
+	s.produk[id] = v
+	return nil
+}
+
+func (s *Store) CountProduk(ctx context.Context) (int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	n := 0
+	for _, v := range s.produk {
+		if !v.DeletedAt.Valid {
+			n++
+		}
+	}
+	return n, nil
+}
+
+// skuDipakai meniru uq_produk_sku_aktif: SKU hanya bentrok dengan produk lain yang
+// belum dihapus, tanpa membedakan huruf besar dan kecil seperti collation MySQL
+func (s *Store) skuDipakai(sku string, kecuali int64) bool {
+	for id, v := range s.produk {
+		if id != kecuali && !v.DeletedAt.Valid && strings.EqualFold(v.SKU, sku) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,190 @@
+// file: internal/store/memory/replenishment.go
+
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"slices"
+	"sort"
+
+	"scm-api/internal/models"
+	"scm-api/internal/store"
+)
+
+func (s *Store) ListParameterStok(ctx context.Context, produkID, gudangID int64) ([]models.ParameterStok, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	daftar := make([]models.ParameterStok, 0)
+	for k, p := range s.parameterStok {
+		if (produkID != 0 && k.produkID != produkID) || (gudangID != 0 && k.gudangID != gudangID) {
+			continue
+		}
+		daftar = append(daftar, s.lengkapiParameter(p))
+	}
+	sort.Slice(daftar, func(i, j int) bool {
+		if daftar[i].ProdukID != daftar[j].ProdukID {
+			return daftar[i].ProdukID < daftar[j].ProdukID
+		}
+		return daftar[i].GudangID < daftar[j].GudangID
+	})
+	return daftar, nil
+}
+
+func (s *Store) KandidatSaran(ctx context.Context, gudangID int64) ([]models.KandidatSaran, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	daftar := make([]models.KandidatSaran, 0)
+	for k, prm := range s.parameterStok {
+		produk := s.produk[k.produkID]
+		if (gudangID != 0 && k.gudangID != gudangID) || produk.DeletedAt.Valid || s.gudang[k.gudangID].DeletedAt.Valid {
+			continue
+		}
+		kd := models.KandidatSaran{
+			ParameterStok: s.lengkapiParameter(prm),
+			Stok:          s.stok[k].Jumlah,
+			SatuanDasar:   produk.Satuan,
+			SupplierID:    produk.SupplierID,
+		}
+		if sp, ok := s.supplier[produk.SupplierID.Int64]; ok && produk.SupplierID.Valid && !sp.DeletedAt.Valid {
+			kd.NamaSupplier = sql.NullString{String: sp.NamaSupplier, Valid: true}
+		}
+		daftar = append(daftar, kd)
+	}
+	sort.Slice(daftar, func(i, j int) bool {
+		if daftar[i].ProdukID != daftar[j].ProdukID {
+			return daftar[i].ProdukID < daftar[j].ProdukID
+		}
+		return daftar[i].GudangID < daftar[j].GudangID
+	})
+	return daftar, nil
+}
+
+func (s *Store) GetParameterStok(ctx context.Context, produkID, gudangID int64) (models.ParameterStok, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	p, ok := s.parameterStok[stokKey{produkID, gudangID}]
+	if !ok {
+		return models.ParameterStok{}, store.ErrNotFound
+	}
+	return s.lengkapiParameter(p), nil
+}
+
+func (s *Store) SimpanParameterStok(ctx context.Context, p *models.ParameterStok) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.produk[p.ProdukID]; !ok {
+		return fmt.Errorf("gagal menyimpan parameter stok: produk %d tidak ada", p.ProdukID)
+	}
+	if _, ok := s.gudang[p.GudangID]; !ok {
+		return fmt.Errorf("gagal menyimpan parameter stok: gudang %d tidak ada", p.GudangID)
+	}
+	k := stokKey{p.ProdukID, p.GudangID}
+	if lama, ok := s.parameterStok[k]; ok {
+		p.ParameterID = lama.ParameterID
+	} else {
+		p.ParameterID = s.nextID("stok_parameter")
+	}
+	p.UpdatedAt = s.timestamp()
+	*p = s.lengkapiParameter(*p)
+	s.parameterStok[k] = *p
+	return nil
+}
+
+func (s *Store) HapusParameterStok(ctx context.Context, produkID, gudangID int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	k := stokKey{produkID, gudangID}
+	if _, ok := s.parameterStok[k]; !ok {
+		return store.ErrNotFound
+	}
+	delete(s.parameterStok, k)
+	return nil
+}
+
+// lengkapiParameter mengisi nama produk dan gudang seperti JOIN. Pemanggil harus memegang s.mu.
+func (s *Store) lengkapiParameter(p models.ParameterStok) models.ParameterStok {
+	p.NamaProduk = s.produk[p.ProdukID].NamaProduk
+	p.NamaGudang = s.gudang[p.GudangID].NamaGudang
+	return p
+}
+
+func (s *Store) PesananTerbuka(ctx context.Context) ([]models.PesananTerbuka, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	datang := make(map[int64]int)
+	for _, p := range s.penerimaan {
+		for _, d := range p.Details {
+			datang[d.DetailPembelianID] += d.JumlahDiterima + d.JumlahDitolak
+		}
+	}
+	jumlah := make(map[stokKey]int)
+	for _, id := range sortedKeys(s.pembelian) {
+		p := s.pembelian[id]
+		switch p.Status {
+		case models.StatusPembelianDraft, models.StatusPembelianDipesan, models.StatusPembelianDikirim, models.StatusPembelianDiterimaSebagian:
+		default:
+			continue
+		}
+		if !p.GudangTujuanID.Valid {
+			continue
+		}
+		for _, d := range s.detailOf(id) {
+			if sisa := d.Jumlah - datang[d.DetailPembelianID]; sisa > 0 {
+				jumlah[stokKey{d.ProdukID, p.GudangTujuanID.Int64}] += sisa * max(d.FaktorKonversi, 1)
+			}
+		}
+	}
+	daftar := make([]models.PesananTerbuka, 0, len(jumlah))
+	for k, j := range jumlah {
+		daftar = append(daftar, models.PesananTerbuka{ProdukID: k.produkID, GudangID: k.gudangID, Jumlah: j})
+	}
+	sort.Slice(daftar, func(i, j int) bool {
+		if daftar[i].ProdukID != daftar[j].ProdukID {
+			return daftar[i].ProdukID < daftar[j].ProdukID
+		}
+		return daftar[i].GudangID < daftar[j].GudangID
+	})
+	return daftar, nil
+}
+
+func (s *Store) PemakaianHarian(ctx context.Context, dari, sampai string, produkID, gudangID int64, tipe []string) ([]models.PemakaianHarian, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	type kunci struct {
+		stokKey
+		tanggal string
+	}
+	jumlah := make(map[kunci]int)
+	for _, m := range s.mutasi {
+		tanggal := m.Waktu
+		if len(tanggal) > 10 {
+			tanggal = tanggal[:10]
+		}
+		if m.Perubahan >= 0 || !slices.Contains(tipe, m.Tipe) ||
+			tanggal < dari || tanggal > sampai ||
+			(produkID != 0 && m.ProdukID != produkID) || (gudangID != 0 && m.GudangID != gudangID) {
+			continue
+		}
+		jumlah[kunci{stokKey{m.ProdukID, m.GudangID}, tanggal}] -= m.Perubahan
+	}
+	daftar := make([]models.PemakaianHarian, 0, len(jumlah))
+	for k, j := range jumlah {
+		daftar = append(daftar, models.PemakaianHarian{ProdukID: k.produkID, GudangID: k.gudangID, Tanggal: k.tanggal, Jumlah: j})
+	}
+	// Meniru ORDER BY produk_id, gudang_id, tanggal
+	sort.Slice(daftar, func(i, j int) bool {
+		a, b := daftar[i], daftar[j]
+		if a.ProdukID != b.ProdukID {
+			return a.ProdukID < b.ProdukID
+		}
+		if a.GudangID != b.GudangID {
+			return a.GudangID < b.GudangID
+		}
+		return a.Tanggal < b.Tanggal
+	})
+	return daftar, nil
+}
@@ -0,0 +1,190 @@
+// file: internal/store/memory/role.go
+
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"scm-api/internal/models"
+	"scm-api/internal/store"
+)
+
+// isiRoleAwal meniru data awal migrasi 0009_rbac, 0010_pengguna_gudang, 0011_audit_log, 0017_permintaan_pembelian, dan 0022_aturan_persetujuan_bawaan. Dipanggil dari New sebelum Store dipakai.
+func (s *Store) isiRoleAwal() {
+	s.izin = []models.Izin{
+		{Kode: models.IzinAuditLihat, Deskripsi: "Melihat jejak audit perubahan data"},
+		{Kode: models.IzinDashboardLihat, Deskripsi: "Melihat dashboard"},
+		{Kode: models.IzinGudangKelola, Deskripsi: "Menambah, mengubah, dan menghapus gudang"},
+		{Kode: models.IzinGudangLihat, Deskripsi: "Melihat gudang"},
+		{Kode: models.IzinGudangSemua, Deskripsi: "Mengakses stok dan dokumen semua gudang"},
+		{Kode: models.IzinPembelianKelola, Deskripsi: "Membuat, memesan, membatalkan, dan menghapus pesanan pembelian"},
+		{Kode: models.IzinPembelianLihat, Deskripsi: "Melihat pesanan pembelian dan penerimaannya"},
+		{Kode: models.IzinPembelianNilaiBesar, Deskripsi: "Membuat pesanan pembelian di atas batas nilai"},
+		{Kode: models.IzinPembelianTerima, Deskripsi: "Mencatat penerimaan barang pesanan"},
+		{Kode: models.IzinPenggunaKelola, Deskripsi: "Mengelola role dan izin pengguna"},
+		{Kode: models.IzinPermintaanAtur, Deskripsi: "Mengelola aturan persetujuan permintaan pembelian"},
+		{Kode: models.IzinPermintaanBuat, Deskripsi: "Mengajukan dan membatalkan permintaan pembelian sendiri"},
+		{Kode: models.IzinPermintaanLihat, Deskripsi: "Melihat permintaan pembelian"},
+		{Kode: models.IzinPermintaanSetujui, Deskripsi: "Menyetujui atau menolak permintaan pembelian sesuai role di aturan persetujuan"},
+		{Kode: models.IzinProdukKelola, Deskripsi: "Menambah, mengubah, dan menghapus produk"},
+		{Kode: models.IzinProdukLihat, Deskripsi: "Melihat produk"},
+		{Kode: models.IzinStokLihat, Deskripsi: "Melihat stok, mutasi, dan batch"},
+		{Kode: models.IzinStokSesuaikan, Deskripsi: "Menyesuaikan jumlah stok"},
+		{Kode: models.IzinSupplierKelola, Deskripsi: "Menambah, mengubah, dan menghapus supplier"},
+		{Kode: models.IzinSupplierLihat, Deskripsi: "Melihat supplier"},
+		{Kode: models.IzinTransferKelola, Deskripsi: "Membuat, mengirim, menerima, dan membatalkan transfer stok"},
+		{Kode: models.IzinTransferLihat, Deskripsi: "Melihat transfer stok"},
+	}
+
+	semua := make([]string, 0, len(s.izin))
+	for _, iz := range s.izin {
+		semua = append(semua, iz.Kode)
+	}
+	manajer := make([]string, 0, len(semua))
+	for _, kode := range semua {
+		if kode != models.IzinPenggunaKelola {
+			manajer = append(manajer, kode)
+		}
+	}
+	lihat := []string{models.IzinProdukLihat, models.IzinSupplierLihat, models.IzinPembelianLihat,
+		models.IzinGudangLihat, models.IzinStokLihat, models.IzinTransferLihat, models.IzinDashboardLihat,
+		models.IzinPermintaanLihat}
+
+	for _, r := range []models.Role{
+		{Nama: "admin", Deskripsi: "Semua izin", Izin: semua},
+		{Nama: "manajer", Deskripsi: "Semua izin kecuali mengelola pengguna", Izin: manajer},
+		{Nama: "pembelian", Deskripsi: "Staf pembelian", Izin: append([]string{
+			models.IzinProdukKelola, models.IzinSupplierKelola, models.IzinPembelianKelola, models.IzinPermintaanBuat}, lihat...)},
+		{Nama: "gudang", Deskripsi: "Staf gudang", Izin: append([]string{
+			models.IzinPembelianTerima, models.IzinStokSesuaikan, models.IzinTransferKelola, models.IzinPermintaanBuat}, lihat...)},
+	} {
+		r.RoleID = s.nextID("role")
+		sort.Strings(r.Izin)
+		s.role[r.RoleID] = r
+		if r.Nama == "manajer" {
+			a := models.AturanPersetujuan{AturanID: s.nextID("aturan_persetujuan"), Level: 1,
+				RoleID: r.RoleID, NamaRole: r.Nama, CreatedAt: s.timestamp()}
+			s.aturan[a.AturanID] = a
+		}
+	}
+}
+
+func (s *Store) ListIzin(ctx context.Context) ([]models.Izin, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return append([]models.Izin(nil), s.izin...), nil
+}
+
+func (s *Store) ListRole(ctx context.Context) ([]models.Role, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	daftarRole := make([]models.Role, 0, len(s.role))
+	for _, id := range sortedKeys(s.role) {
+		daftarRole = append(daftarRole, salinRole(s.role[id]))
+	}
+	return daftarRole, nil
+}
+
+func (s *Store) CreateRole(ctx context.Context, r *models.Role) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, lain := range s.role {
+		if lain.Nama == r.Nama {
+			return store.ErrDuplikat
+		}
+	}
+	if err := s.cekIzin(r.Izin); err != nil {
+		return err
+	}
+	r.RoleID = s.nextID("role")
+	s.role[r.RoleID] = salinRole(*r)
+	return nil
+}
+
+func (s *Store) UbahIzinRole(ctx context.Context, roleID int64, izin []string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r, ok := s.role[roleID]
+	if !ok {
+		return store.ErrNotFound
+	}
+	if err := s.cekIzin(izin); err != nil {
+		return err
+	}
+	r.Izin = izin
+	s.role[roleID] = salinRole(r)
+	return nil
+}
+
+func (s *Store) RolePengguna(ctx context.Context, penggunaID int64) ([]models.Role, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if _, ok := s.pengguna[penggunaID]; !ok {
+		return nil, store.ErrNotFound
+	}
+	daftarRole := make([]models.Role, 0)
+	for _, id := range s.penggunaRole[penggunaID] {
+		daftarRole = append(daftarRole, salinRole(s.role[id]))
+	}
+	return daftarRole, nil
+}
+
+func (s *Store) SetRolePengguna(ctx context.Context, penggunaID int64, roleIDs []int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.pengguna[penggunaID]; !ok {
+		return store.ErrNotFound
+	}
+	// Tolak seperti foreign key di database
+	for _, id := range roleIDs {
+		if _, ok := s.role[id]; !ok {
+			return fmt.Errorf("role %d tidak ada", id)
+		}
+	}
+	ids := append([]int64(nil), roleIDs...)
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	s.penggunaRole[penggunaID] = ids
+	return nil
+}
+
+func (s *Store) IzinPengguna(ctx context.Context, penggunaID int64) ([]string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	ada := make(map[string]bool)
+	izin := make([]string, 0)
+	for _, id := range s.penggunaRole[penggunaID] {
+		for _, kode := range s.role[id].Izin {
+			if !ada[kode] {
+				ada[kode] = true
+				izin = append(izin, kode)
+			}
+		}
+	}
+	sort.Strings(izin)
+	return izin, nil
+}
+
+// cekIzin meniru foreign key role_izin.kode_izin. Pemanggil harus memegang s.mu.
+func (s *Store) cekIzin(izin []string) error {
+	for _, kode := range izin {
+		ada := false
+		for _, iz := range s.izin {
+			if iz.Kode == kode {
+				ada = true
+				break
+			}
+		}
+		if !ada {
+			return fmt.Errorf("izin %s tidak ada", kode)
+		}
+	}
+	return nil
+}
+
+// salinRole menyalin slice izin agar data di map tidak ikut berubah lewat hasil yang dikembalikan
+func salinRole(r models.Role) models.Role {
+	r.Izin = append(make([]string, 0, len(r.Izin)), r.Izin...)
+	return r
+}
@@ -0,0 +1,80 @@
+// file: internal/store/memory/satuan.go
+
+package memory
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"scm-api/internal/models"
+	"scm-api/internal/store"
+)
+
+func (s *Store) ListSatuanProduk(ctx context.Context, produkID int64) ([]models.ProdukSatuan, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	daftar := make([]models.ProdukSatuan, 0)
+	for _, id := range sortedKeys(s.satuan) {
+		if ps := s.satuan[id]; ps.ProdukID == produkID {
+			daftar = append(daftar, ps)
+		}
+	}
+	sort.SliceStable(daftar, func(i, j int) bool { return daftar[i].Faktor < daftar[j].Faktor })
+	return daftar, nil
+}
+
+func (s *Store) CariSatuan(ctx context.Context, produkID int64, satuan string) (models.ProdukSatuan, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if id, ok := s.satuanOf(produkID, satuan); ok {
+		return s.satuan[id], nil
+	}
+	return models.ProdukSatuan{}, store.ErrNotFound
+}
+
+func (s *Store) CreateSatuan(ctx context.Context, ps *models.ProdukSatuan) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.satuanOf(ps.ProdukID, ps.Satuan); ok {
+		return store.ErrDuplikat
+	}
+	ps.SatuanID = s.nextID("produk_satuan")
+	ps.CreatedAt = s.timestamp()
+	s.satuan[ps.SatuanID] = *ps
+	return nil
+}
+
+func (s *Store) UpdateSatuan(ctx context.Context, ps models.ProdukSatuan) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	id, ok := s.satuanOf(ps.ProdukID, ps.Satuan)
+	if !ok {
+		return store.ErrNotFound
+	}
+	lama := s.satuan[id]
+	lama.Faktor = ps.Faktor
+	s.satuan[id] = lama
+	return nil
+}
+
+func (s *Store) DeleteSatuan(ctx context.Context, produkID int64, satuan string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	id, ok := s.satuanOf(produkID, satuan)
+	if !ok {
+		return store.ErrNotFound
+	}
+	delete(s.satuan, id)
+	return nil
+}
+
+// satuanOf mencari ID satuan milik produk tanpa membedakan huruf besar dan kecil. Pemanggil harus memegang s.mu.
+func (s *Store) satuanOf(produkID int64, satuan string) (int64, bool) {
+	for id, ps := range s.satuan {
+		if ps.ProdukID == produkID && strings.EqualFold(ps.Satuan, satuan) {
+			return id, true
+		}
+	}
+	return 0, false
+}
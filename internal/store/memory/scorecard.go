@@ -0,0 +1,54 @@
+// file: internal/store/memory/scorecard.go
+
+package memory
+
+import (
+	"context"
+	"database/sql"
+
+	"scm-api/internal/models"
+)
+
+func (s *Store) BarisScorecard(ctx context.Context, dari, sampai string) ([]models.BarisScorecard, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	diterima := make(map[int64]int)
+	ditolak := make(map[int64]int)
+	terakhir := make(map[int64]string)
+	for _, p := range s.penerimaan {
+		if p.TanggalTerima > terakhir[p.PembelianID] {
+			terakhir[p.PembelianID] = p.TanggalTerima
+		}
+		for _, d := range p.Details {
+			diterima[d.DetailPembelianID] += d.JumlahDiterima
+			ditolak[d.DetailPembelianID] += d.JumlahDitolak
+		}
+	}
+
+	daftar := make([]models.BarisScorecard, 0)
+	for _, id := range sortedKeys(s.pembelian) {
+		p := s.pembelian[id]
+		if p.Status == models.StatusPembelianDraft || p.Status == models.StatusPembelianDibatalkan ||
+			p.TanggalPesan < dari || p.TanggalPesan > sampai {
+			continue
+		}
+		for _, d := range s.detailOf(id) {
+			daftar = append(daftar, models.BarisScorecard{
+				PembelianID:     id,
+				SupplierID:      p.SupplierID,
+				Status:          p.Status,
+				TanggalPesan:    p.TanggalPesan,
+				EstimasiTiba:    p.EstimasiTiba,
+				TerimaTerakhir:  sql.NullString{String: terakhir[id], Valid: terakhir[id] != ""},
+				ProdukID:        d.ProdukID,
+				Jumlah:          d.Jumlah,
+				FaktorKonversi:  d.FaktorKonversi,
+				HargaBeliSatuan: d.HargaBeliSatuan,
+				JumlahDiterima:  diterima[d.DetailPembelianID],
+				JumlahDitolak:   ditolak[d.DetailPembelianID],
+			})
+		}
+	}
+	return daftar, nil
+}
@@ -0,0 +1,140 @@
+// file: internal/store/memory/stok.go
+
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+
+	"scm-api/internal/models"
+	"scm-api/internal/store"
+)
+
+func (s *Store) ListStok(ctx context.Context, q store.Kueri) ([]models.StokResponse, int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	daftarStok := make([]models.StokResponse, 0, len(s.stok))
+	for _, st := range s.stok {
+		daftarStok = append(daftarStok, models.StokResponse{
+			StokID:        st.StokID,
+			ProdukID:      st.ProdukID,
+			NamaProduk:    s.produk[st.ProdukID].NamaProduk,
+			GudangID:      st.GudangID,
+			NamaGudang:    s.gudang[st.GudangID].NamaGudang,
+			Jumlah:        st.Jumlah,
+			TanggalUpdate: st.TanggalUpdate,
+		})
+	}
+	sort.Slice(daftarStok, func(i, j int) bool { return daftarStok[i].StokID < daftarStok[j].StokID })
+	daftarStok, total := terapkanKueri(daftarStok, q, store.DaftarStok)
+	return daftarStok, total, nil
+}
+
+func (s *Store) AdjustStok(ctx context.Context, produkID, gudangID int64, jumlah int, oleh, catatan string) (models.StokMutasi, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	m := models.StokMutasi{
+		ProdukID:   produkID,
+		GudangID:   gudangID,
+		Tipe:       models.MutasiPenyesuaian,
+		DibuatOleh: oleh,
+		Catatan:    sql.NullString{String: catatan, Valid: catatan != ""},
+	}
+	if _, ok := s.produk[produkID]; !ok {
+		return m, fmt.Errorf("produk %d tidak ada", produkID)
+	}
+	if _, ok := s.gudang[gudangID]; !ok {
+		return m, fmt.Errorf("gudang %d tidak ada", gudangID)
+	}
+	_, err := s.mutasiStok(&m, func(int) int { return jumlah }, nil)
+	return m, err
+}
+
+func (s *Store) CatatPenjualan(ctx context.Context, produkID, gudangID int64, jumlah int, oleh, catatan string) (models.StokMutasi, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	m := models.StokMutasi{
+		ProdukID:   produkID,
+		GudangID:   gudangID,
+		Tipe:       models.MutasiPenjualan,
+		DibuatOleh: oleh,
+		Catatan:    sql.NullString{String: catatan, Valid: catatan != ""},
+	}
+	if _, ok := s.produk[produkID]; !ok {
+		return m, fmt.Errorf("produk %d tidak ada", produkID)
+	}
+	if _, ok := s.gudang[gudangID]; !ok {
+		return m, fmt.Errorf("gudang %d tidak ada", gudangID)
+	}
+	_, err := s.mutasiStok(&m, func(sebelum int) int { return sebelum - jumlah }, nil)
+	return m, err
+}
+
+// mutasiStok adalah satu-satunya jalan untuk mengubah s.stok. Jumlah baru dihitung
+// dari jumlah lama lewat jumlahBaru, lalu perubahannya dicatat di s.mutasi.
+// Jumlah baru yang negatif ditolak dengan store.ErrStokTidakCukup tanpa mengubah apa pun.
+// Stok yang bertambah dicatat sebagai batch baru jika masuk tidak nil; stok yang
+// berkurang diambil dari batch secara FEFO. Pemanggil harus memegang s.mu.
+func (s *Store) mutasiStok(m *models.StokMutasi, jumlahBaru func(sebelum int) int, masuk *models.StokBatch) ([]models.PemakaianBatch, error) {
+	key := stokKey{m.ProdukID, m.GudangID}
+	st, ok := s.stok[key]
+	m.JumlahSebelum = st.Jumlah
+	m.JumlahSesudah = jumlahBaru(st.Jumlah)
+	m.Perubahan = m.JumlahSesudah - m.JumlahSebelum
+	if m.JumlahSesudah < 0 {
+		return nil, fmt.Errorf("%w: produk %d di gudang %d hanya %d, dibutuhkan %d", store.ErrStokTidakCukup, m.ProdukID, m.GudangID, m.JumlahSebelum, -m.Perubahan)
+	}
+	if !ok {
+		st = models.Stok{StokID: s.nextID("stok"), ProdukID: m.ProdukID, GudangID: m.GudangID}
+	}
+	m.Waktu = s.timestamp()
+	m.MutasiID = s.nextID("stok_mutasi")
+
+	st.Jumlah = m.JumlahSesudah
+	st.TanggalUpdate = m.Waktu
+	s.stok[key] = st
+	s.mutasi = append(s.mutasi, *m)
+
+	var pemakaian []models.PemakaianBatch
+	if m.Perubahan < 0 {
+		pemakaian = s.pakaiBatch(m, -m.Perubahan)
+	} else if m.Perubahan > 0 && masuk != nil {
+		s.tambahBatch(m, masuk, m.Perubahan)
+	}
+	return pemakaian, nil
+}
+
+func (s *Store) ListMutasiStok(ctx context.Context, q store.Kueri) ([]models.StokMutasi, int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	daftar, total := terapkanKueri(s.mutasi, q, store.DaftarMutasi)
+	return daftar, total, nil
+}
+
+func (s *Store) StokPerProduk(ctx context.Context) (models.StokChartResponse, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	total := make(map[string]int)
+	for _, st := range s.stok {
+		total[s.produk[st.ProdukID].NamaProduk] += st.Jumlah
+	}
+	labels := make([]string, 0, len(total))
+	for nama := range total {
+		labels = append(labels, nama)
+	}
+	sort.Slice(labels, func(i, j int) bool {
+		if total[labels[i]] != total[labels[j]] {
+			return total[labels[i]] > total[labels[j]]
+		}
+		return labels[i] < labels[j]
+	})
+
+	chartData := models.StokChartResponse{Labels: labels, Data: make([]int, 0, len(labels))}
+	for _, nama := range labels {
+		chartData.Data = append(chartData.Data, total[nama])
+	}
+	return chartData, nil
+}
@@ -0,0 +1,122 @@
+// file: internal/store/memory/store.go
+
+// Package memory berisi implementasi store yang menyimpan data di memori.
+// Dipakai untuk pengujian handler dengan httptest tanpa database sungguhan.
+package memory
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"scm-api/internal/models"
+	"scm-api/internal/store"
+)
+
+// Store mengimplementasikan seluruh antarmuka di package store dengan map di memori
+type Store struct {
+	mu sync.RWMutex
+
+	produk          map[int64]models.Produk
+	supplier        map[int64]models.Supplier
+	pembelian       map[int64]models.Pembelian
+	detailPembelian map[int64]models.DetailPembelian
+	riwayatStatus   []models.RiwayatStatusPembelian
+	penerimaan      map[int64]models.Penerimaan
+	gudang          map[int64]models.Gudang
+	stok            map[stokKey]models.Stok
+	mutasi          []models.StokMutasi
+	batch           map[int64]models.StokBatch
+	transfer        map[int64]models.Transfer
+	pengguna        map[int64]models.Pengguna
+	refreshToken    map[string]refreshToken
+	izin            []models.Izin
+	role            map[int64]models.Role
+	penggunaRole    map[int64][]int64
+	penggunaGudang  map[int64][]int64
+	audit           []models.AuditLog
+	barcode         map[int64]models.ProdukBarcode
+	satuan          map[int64]models.ProdukSatuan
+	katalog         map[int64]models.SupplierProduk
+	permintaan      map[int64]models.PermintaanDenganDetail
+	aturan          map[int64]models.AturanPersetujuan
+	parameterStok   map[stokKey]models.ParameterStok
+
+	lastID map[string]int64
+
+	// now bisa diganti saat pengujian agar waktu dapat diprediksi
+	now func() time.Time
+}
+
+// refreshToken meniru satu baris tabel refresh_token, dengan hash sebagai kunci map
+type refreshToken struct {
+	penggunaID  int64
+	kedaluwarsa time.Time
+	dicabut     bool
+}
+
+type stokKey struct {
+	produkID int64
+	gudangID int64
+}
+
+var _ store.Store = (*Store)(nil)
+
+// New membuat Store kosong yang hanya berisi data awal migrasi (izin dan role bawaan)
+func New() *Store {
+	s := &Store{
+		produk:          make(map[int64]models.Produk),
+		supplier:        make(map[int64]models.Supplier),
+		pembelian:       make(map[int64]models.Pembelian),
+		detailPembelian: make(map[int64]models.DetailPembelian),
+		penerimaan:      make(map[int64]models.Penerimaan),
+		gudang:          make(map[int64]models.Gudang),
+		stok:            make(map[stokKey]models.Stok),
+		transfer:        make(map[int64]models.Transfer),
+		batch:           make(map[int64]models.StokBatch),
+		pengguna:        make(map[int64]models.Pengguna),
+		refreshToken:    make(map[string]refreshToken),
+		role:            make(map[int64]models.Role),
+		penggunaRole:    make(map[int64][]int64),
+		penggunaGudang:  make(map[int64][]int64),
+		barcode:         make(map[int64]models.ProdukBarcode),
+		satuan:          make(map[int64]models.ProdukSatuan),
+		katalog:         make(map[int64]models.SupplierProduk),
+		permintaan:      make(map[int64]models.PermintaanDenganDetail),
+		aturan:          make(map[int64]models.AturanPersetujuan),
+		parameterStok:   make(map[stokKey]models.ParameterStok),
+		lastID:          make(map[string]int64),
+		now:             time.Now,
+	}
+	s.isiRoleAwal()
+	return s
+}
+
+// AturWaktu mengganti sumber waktu store, misalnya agar pengujian bisa mencatat
+// mutasi pada tanggal yang sudah lewat
+func (s *Store) AturWaktu(now func() time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.now = now
+}
+
+// nextID meniru AUTO_INCREMENT per tabel. Pemanggil harus memegang s.mu.
+func (s *Store) nextID(table string) int64 {
+	s.lastID[table]++
+	return s.lastID[table]
+}
+
+// timestamp meniru kolom DATETIME yang dibaca sebagai string
+func (s *Store) timestamp() string {
+	return s.now().Format(time.RFC3339)
+}
+
+// sortedKeys mengembalikan kunci map terurut naik agar hasil list stabil
+func sortedKeys[V any](m map[int64]V) []int64 {
+	keys := make([]int64, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+	return keys
+}
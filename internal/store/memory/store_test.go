@@ -0,0 +1,14 @@
+package memory
+
+import (
+	"testing"
+
+	"scm-api/internal/store"
+	"scm-api/internal/store/storetest"
+)
+
+func TestStore(t *testing.T) {
+	storetest.Jalankan(t, func(t *testing.T) store.Store {
+		return New()
+	})
+}
@@ -0,0 +1,104 @@
+// file: internal/store/memory/supplier.go
+
+package memory
+
+import (
+	"context"
+	"database/sql"
+
+	"scm-api/internal/models"
+	"scm-api/internal/store"
+)
+
+func (s *Store) ListSupplier(ctx context.Context, q store.Kueri) ([]models.Supplier, int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	daftarSupplier := make([]models.Supplier, 0, len(s.supplier))
+	for _, id := range sortedKeys(s.supplier) {
+		daftarSupplier = append(daftarSupplier, s.supplier[id])
+	}
+	daftarSupplier, total := terapkanKueri(daftarSupplier, q, store.DaftarSupplier)
+	return daftarSupplier, total, nil
+}
+
+func (s *Store) GetSupplier(ctx context.Context, id int64) (models.Supplier, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	sp, ok := s.supplier[id]
+	if !ok {
+		return sp, store.ErrNotFound
+	}
+	return sp, nil
+}
+
+func (s *Store) CreateSupplier(ctx context.Context, sp *models.Supplier) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sp.SupplierID = s.nextID("supplier")
+	sp.DeletedAt = sql.NullString{}
+	sp.Rating = sql.NullFloat64{}
+	s.supplier[sp.SupplierID] = *sp
+	return nil
+}
+
+func (s *Store) UpdateSupplier(ctx context.Context, sp models.Supplier) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	lama, ok := s.supplier[sp.SupplierID]
+	if !ok {
+		return nil
+	}
+	// deleted_at hanya diubah lewat Delete dan Pulihkan, rating lewat SetRatingSupplier
+	sp.DeletedAt = lama.DeletedAt
+	sp.Rating = lama.Rating
+	s.supplier[sp.SupplierID] = sp
+	return nil
+}
+
+func (s *Store) SetRatingSupplier(ctx context.Context, id int64, rating sql.NullFloat64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	v, ok := s.supplier[id]
+	if !ok {
+		return store.ErrNotFound
+	}
+	v.Rating = rating
+	s.supplier[id] = v
+	return nil
+}
+
+func (s *Store) DeleteSupplier(ctx context.Context, id int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	v, ok := s.supplier[id]
+	if !ok || v.DeletedAt.Valid {
+		return store.ErrNotFound
+	}
+	v.DeletedAt = sql.NullString{String: s.timestamp(), Valid: true}
+	s.supplier[id] = v
+	return nil
+}
+
+func (s *Store) PulihkanSupplier(ctx context.Context, id int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	v, ok := s.supplier[id]
+	if !ok || !v.DeletedAt.Valid {
+		return store.ErrNotFound
+	}
+	v.DeletedAt = sql.NullString{}
+	s.supplier[id] = v
+	return nil
+}
+
+func (s *Store) CountSupplier(ctx context.Context) (int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	n := 0
+	for _, v := range s.supplier {
+		if !v.DeletedAt.Valid {
+			n++
+		}
+	}
+	return n, nil
+}
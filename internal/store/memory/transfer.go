@@ -0,0 +1,177 @@
+// file: internal/store/memory/transfer.go
+
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+
+	"scm-api/internal/models"
+	"scm-api/internal/store"
+)
+
+func (s *Store) ListTransfer(ctx context.Context, q store.Kueri) ([]models.Transfer, int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	daftar := make([]models.Transfer, 0, len(s.transfer))
+	for _, id := range sortedKeys(s.transfer) {
+		daftar = append(daftar, salinTransfer(s.transfer[id]))
+	}
+	daftar, total := terapkanKueri(daftar, q, store.DaftarTransfer)
+	return daftar, total, nil
+}
+
+func (s *Store) GetTransfer(ctx context.Context, id int64) (models.Transfer, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	t, ok := s.transfer[id]
+	if !ok {
+		return models.Transfer{}, store.ErrNotFound
+	}
+	return salinTransfer(t), nil
+}
+
+// salinTransfer menyalin detail agar pemanggil tidak bisa mengubah data di store
+func salinTransfer(t models.Transfer) models.Transfer {
+	details := make([]models.DetailTransfer, 0, len(t.Details))
+	for _, d := range t.Details {
+		d.Batch = append(make([]models.PemakaianBatch, 0, len(d.Batch)), d.Batch...)
+		details = append(details, d)
+	}
+	t.Details = details
+	return t
+}
+
+func (s *Store) CreateTransfer(ctx context.Context, t *models.Transfer) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Periksa referensi seperti foreign key di database
+	for _, gudangID := range []int64{t.GudangAsalID, t.GudangTujuanID} {
+		if _, ok := s.gudang[gudangID]; !ok {
+			return fmt.Errorf("gagal menyimpan data transfer: gudang %d tidak ada", gudangID)
+		}
+	}
+	for _, d := range t.Details {
+		if _, ok := s.produk[d.ProdukID]; !ok {
+			return fmt.Errorf("gagal menyimpan detail transfer: produk %d tidak ada", d.ProdukID)
+		}
+	}
+
+	t.TransferID = s.nextID("transfer_stok")
+	t.Status = models.StatusTransferDraft
+	t.TanggalDibuat = s.timestamp()
+	for i := range t.Details {
+		t.Details[i].TransferID = t.TransferID
+		t.Details[i].DetailTransferID = s.nextID("detail_transfer")
+	}
+	sort.Slice(t.Details, func(i, j int) bool { return t.Details[i].ProdukID < t.Details[j].ProdukID })
+	s.transfer[t.TransferID] = salinTransfer(*t)
+	return nil
+}
+
+func (s *Store) UbahStatusTransfer(ctx context.Context, id int64, ke, oleh string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t, ok := s.transfer[id]
+	if !ok {
+		return store.ErrNotFound
+	}
+	if !models.BolehTransisiTransfer(t.Status, ke) {
+		return fmt.Errorf("%w: dari %q ke %q", store.ErrInvalidTransition, t.Status, ke)
+	}
+
+	switch ke {
+	case models.StatusTransferDikirim:
+		// Periksa semua item lebih dulu agar tidak ada stok yang berubah sebagian
+		for _, d := range t.Details {
+			if ada := s.stok[stokKey{d.ProdukID, t.GudangAsalID}].Jumlah; ada < d.Jumlah {
+				return fmt.Errorf("%w: produk %d di gudang %d hanya %d, dibutuhkan %d", store.ErrStokTidakCukup, d.ProdukID, t.GudangAsalID, ada, d.Jumlah)
+			}
+		}
+		for i, d := range t.Details {
+			pemakaian, err := s.mutasiTransfer(t, d, t.GudangAsalID, -d.Jumlah, oleh, nil)
+			if err != nil {
+				return err
+			}
+			// Catat lot yang diambil agar dibuat kembali di gudang tujuan
+			t.Details[i].Batch = pemakaian
+		}
+		t.TanggalKirim = sql.NullString{String: s.timestamp(), Valid: true}
+	case models.StatusTransferDiterima:
+		for _, d := range t.Details {
+			// Setiap lot yang dikirim menjadi batch baru di gudang tujuan,
+			// sisanya masuk sebagai stok tanpa batch
+			sisa := d.Jumlah
+			for _, b := range d.Batch {
+				masuk := &models.StokBatch{NomorLot: b.NomorLot, TanggalProduksi: b.TanggalProduksi, TanggalKedaluwarsa: b.TanggalKedaluwarsa}
+				if _, err := s.mutasiTransfer(t, d, t.GudangTujuanID, b.Jumlah, oleh, masuk); err != nil {
+					return err
+				}
+				sisa -= b.Jumlah
+			}
+			if sisa > 0 {
+				if _, err := s.mutasiTransfer(t, d, t.GudangTujuanID, sisa, oleh, nil); err != nil {
+					return err
+				}
+			}
+		}
+		t.TanggalTerima = sql.NullString{String: s.timestamp(), Valid: true}
+	}
+	t.Status = ke
+	s.transfer[id] = t
+	return nil
+}
+
+// mutasiTransfer menambah (perubahan positif) atau mengurangi stok satu item transfer. Pemanggil harus memegang s.mu.
+func (s *Store) mutasiTransfer(t models.Transfer, d models.DetailTransfer, gudangID int64, perubahan int, oleh string, masuk *models.StokBatch) ([]models.PemakaianBatch, error) {
+	return s.mutasiStok(&models.StokMutasi{
+		ProdukID:      d.ProdukID,
+		GudangID:      gudangID,
+		Tipe:          models.MutasiTransfer,
+		ReferensiTipe: sql.NullString{String: "transfer", Valid: true},
+		ReferensiID:   sql.NullInt64{Int64: t.TransferID, Valid: true},
+		DibuatOleh:    oleh,
+	}, func(sebelum int) int { return sebelum + perubahan }, masuk)
+}
+
+func (s *Store) StokDalamPerjalanan(ctx context.Context) ([]models.StokDalamPerjalananResponse, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	type kunci struct{ produkID, asalID, tujuanID int64 }
+	total := make(map[kunci]int)
+	for _, t := range s.transfer {
+		if t.Status != models.StatusTransferDikirim {
+			continue
+		}
+		for _, d := range t.Details {
+			total[kunci{d.ProdukID, t.GudangAsalID, t.GudangTujuanID}] += d.Jumlah
+		}
+	}
+
+	daftar := make([]models.StokDalamPerjalananResponse, 0, len(total))
+	for k, jumlah := range total {
+		daftar = append(daftar, models.StokDalamPerjalananResponse{
+			ProdukID:         k.produkID,
+			NamaProduk:       s.produk[k.produkID].NamaProduk,
+			GudangAsalID:     k.asalID,
+			NamaGudangAsal:   s.gudang[k.asalID].NamaGudang,
+			GudangTujuanID:   k.tujuanID,
+			NamaGudangTujuan: s.gudang[k.tujuanID].NamaGudang,
+			Jumlah:           jumlah,
+		})
+	}
+	sort.Slice(daftar, func(i, j int) bool {
+		a, b := daftar[i], daftar[j]
+		if a.ProdukID != b.ProdukID {
+			return a.ProdukID < b.ProdukID
+		}
+		if a.GudangAsalID != b.GudangAsalID {
+			return a.GudangAsalID < b.GudangAsalID
+		}
+		return a.GudangTujuanID < b.GudangTujuanID
+	})
+	return daftar, nil
+}
@@ -0,0 +1,58 @@
+// file: internal/store/mysql/audit.go
+
+package mysql
+
+import (
+	"context"
+
+	"scm-api/internal/models"
+	"scm-api/internal/store"
+)
+
+func (s *Store) CatatAudit(ctx context.Context, a *models.AuditLog) error {
+	query := `
+        INSERT INTO audit_log (waktu, aktor, entitas, entitas_id, aksi, sebelum, sesudah, perubahan)
+        VALUES (NOW(), ?, ?, ?, ?, ?, ?, ?)
+    `
+	result, err := s.db.ExecContext(ctx, query, a.Aktor, a.Entitas, a.EntitasID, a.Aksi,
+		nullJSON(a.Sebelum), nullJSON(a.Sesudah), nullJSON(a.Perubahan))
+	if err != nil {
+		return err
+	}
+	a.AuditID, err = result.LastInsertId()
+	return err
+}
+
+var kolomAudit = kolomDaftar{
+	"audit_id": "audit_id", "waktu": "waktu", "entitas": "entitas", "entitas_id": "entitas_id",
+	"aktor": "aktor", "aksi": "aksi",
+}
+
+func (s *Store) ListAudit(ctx context.Context, q store.Kueri) ([]models.AuditLog, int, error) {
+	pilih := "audit_id, waktu, aktor, entitas, entitas_id, aksi, sebelum, sesudah, perubahan"
+	rows, total, err := s.queryDaftar(ctx, pilih, "FROM audit_log", q, store.DaftarAudit, kolomAudit)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+	daftar := make([]models.AuditLog, 0)
+	for rows.Next() {
+		var a models.AuditLog
+		var sebelum, sesudah, perubahan []byte
+		err := rows.Scan(&a.AuditID, &a.Waktu, &a.Aktor, &a.Entitas, &a.EntitasID, &a.Aksi, &sebelum, &sesudah, &perubahan)
+		if err != nil {
+			return nil, 0, err
+		}
+		a.Sebelum, a.Sesudah, a.Perubahan = sebelum, sesudah, perubahan
+		daftar = append(daftar, a)
+	}
+	return daftar, total, rows.Err()
+}
+
+// nullJSON mengubah dokumen JSON kosong menjadi NULL
+func nullJSON(doc []byte) any {
+	if len(doc) == 0 {
+		return nil
+	}
+	return string(doc)
+}
@@ -0,0 +1,62 @@
+// file: internal/store/mysql/barcode.go
+
+package mysql
+
+import (
+	"context"
+	"log"
+
+	"scm-api/internal/models"
+)
+
+const barcodeColumns = "barcode_id, produk_id, kode, gtin, internal, created_at"
+
+func scanBarcode(row interface{ Scan(...any) error }, b *models.ProdukBarcode) error {
+	return row.Scan(&b.BarcodeID, &b.ProdukID, &b.Kode, &b.GTIN, &b.Internal, &b.CreatedAt)
+}
+
+func (s *Store) ListBarcodeProduk(ctx context.Context, produkID int64) ([]models.ProdukBarcode, error) {
+	rows, err := s.db.QueryContext(ctx, "SELECT "+barcodeColumns+" FROM produk_barcode WHERE produk_id = ? ORDER BY barcode_id", produkID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	daftar := make([]models.ProdukBarcode, 0)
+	for rows.Next() {
+		var b models.ProdukBarcode
+		if err := scanBarcode(rows, &b); err != nil {
+			log.Printf("Error scanning row produk_barcode: %v", err)
+			continue
+		}
+		daftar = append(daftar, b)
+	}
+	return daftar, rows.Err()
+}
+
+func (s *Store) CariBarcode(ctx context.Context, gtin string) (models.ProdukBarcode, error) {
+	var b models.ProdukBarcode
+	err := scanBarcode(s.db.QueryRowContext(ctx, "SELECT "+barcodeColumns+" FROM produk_barcode WHERE gtin = ?", gtin), &b)
+	return b, notFound(err)
+}
+
+func (s *Store) CreateBarcode(ctx context.Context, b *models.ProdukBarcode) error {
+	result, err := s.db.ExecContext(ctx, `INSERT INTO produk_barcode (produk_id, kode, gtin, internal) VALUES (?, ?, ?, ?)`,
+		b.ProdukID, b.Kode, b.GTIN, b.Internal)
+	if err != nil {
+		return duplikat(err)
+	}
+	if b.BarcodeID, err = result.LastInsertId(); err != nil {
+		return err
+	}
+	dibuat, err := s.CariBarcode(ctx, b.GTIN)
+	b.CreatedAt = dibuat.CreatedAt
+	return err
+}
+
+func (s *Store) DeleteBarcode(ctx context.Context, produkID int64, gtin string) error {
+	result, err := s.db.ExecContext(ctx, `DELETE FROM produk_barcode WHERE produk_id = ? AND gtin = ?`, produkID, gtin)
+	if err != nil {
+		return err
+	}
+	return checkAffected(result)
+}
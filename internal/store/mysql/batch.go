@@ -0,0 +1,142 @@
+// file: internal/store/mysql/batch.go
+
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"scm-api/internal/models"
+	"scm-api/internal/store"
+)
+
+// tanggalSaja memotong nilai DATE yang terbaca sebagai "2006-01-02T15:04:05Z" agar bisa ditulis kembali ke kolom DATE
+func tanggalSaja(ns sql.NullString) sql.NullString {
+	if ns.Valid && len(ns.String) > 10 {
+		ns.String = ns.String[:10]
+	}
+	return ns
+}
+
+// tambahBatchTx mencatat batch baru sebanyak jumlah di gudang tujuan mutasi
+func tambahBatchTx(ctx context.Context, tx *sql.Tx, m *models.StokMutasi, b *models.StokBatch, jumlah int) error {
+	query := `
+        INSERT INTO stok_batch (produk_id, gudang_id, nomor_lot, tanggal_produksi, tanggal_kedaluwarsa, jumlah, penerimaan_id, tanggal_masuk)
+        VALUES (?, ?, ?, ?, ?, ?, ?, NOW())
+    `
+	result, err := tx.ExecContext(ctx, query, m.ProdukID, m.GudangID, b.NomorLot, tanggalSaja(b.TanggalProduksi), tanggalSaja(b.TanggalKedaluwarsa), jumlah, b.PenerimaanID)
+	if err != nil {
+		return fmt.Errorf("gagal mencatat batch stok: %w", err)
+	}
+	b.BatchID, _ = result.LastInsertId()
+	b.ProdukID, b.GudangID, b.Jumlah = m.ProdukID, m.GudangID, jumlah
+	return nil
+}
+
+// pakaiBatchTx mengambil sebanyak jumlah dari batch produk di gudang mutasi dengan urutan
+// FEFO: kedaluwarsa paling dekat lebih dulu, batch tanpa tanggal kedaluwarsa paling akhir.
+// Jika seluruh batch habis, sisanya diambil dari stok tanpa batch.
+func pakaiBatchTx(ctx context.Context, tx *sql.Tx, m *models.StokMutasi, jumlah int) ([]models.PemakaianBatch, error) {
+	query := `
+        SELECT batch_id, nomor_lot, tanggal_produksi, tanggal_kedaluwarsa, jumlah
+        FROM stok_batch
+        WHERE produk_id = ? AND gudang_id = ? AND jumlah > 0
+        ORDER BY tanggal_kedaluwarsa IS NULL, tanggal_kedaluwarsa, batch_id
+        FOR UPDATE`
+	rows, err := tx.QueryContext(ctx, query, m.ProdukID, m.GudangID)
+	if err != nil {
+		return nil, err
+	}
+	tersedia := make([]models.PemakaianBatch, 0)
+	for rows.Next() {
+		var b models.PemakaianBatch
+		if err := rows.Scan(&b.BatchID, &b.NomorLot, &b.TanggalProduksi, &b.TanggalKedaluwarsa, &b.Jumlah); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		tersedia = append(tersedia, b)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	pemakaian := make([]models.PemakaianBatch, 0)
+	for _, b := range tersedia {
+		if jumlah == 0 {
+			break
+		}
+		ambil := min(b.Jumlah, jumlah)
+		if _, err := tx.ExecContext(ctx, "UPDATE stok_batch SET jumlah = jumlah - ? WHERE batch_id = ?", ambil, b.BatchID); err != nil {
+			return nil, fmt.Errorf("gagal mengurangi batch stok %d: %w", b.BatchID, err)
+		}
+		b.Jumlah = ambil
+		pemakaian = append(pemakaian, b)
+		jumlah -= ambil
+	}
+	return pemakaian, nil
+}
+
+const batchColumns = `
+            b.batch_id, b.produk_id, b.gudang_id, b.nomor_lot, b.tanggal_produksi, b.tanggal_kedaluwarsa,
+            b.jumlah, b.penerimaan_id, b.tanggal_masuk, p.nama_produk, g.nama_gudang,
+            DATEDIFF(b.tanggal_kedaluwarsa, CURDATE())`
+
+const batchJoin = `
+        JOIN produk p ON b.produk_id = p.produk_id
+        JOIN gudang g ON b.gudang_id = g.gudang_id`
+
+const batchSelect = "SELECT " + batchColumns + " FROM stok_batch b" + batchJoin + " WHERE b.jumlah > 0"
+
+var kolomBatch = kolomDaftar{
+	"batch_id": "b.batch_id", "produk_id": "b.produk_id", "gudang_id": "b.gudang_id",
+	"tanggal_kedaluwarsa": "b.tanggal_kedaluwarsa", "tanggal_masuk": "b.tanggal_masuk", "jumlah": "b.jumlah",
+	"nama_produk": "p.nama_produk", "nama_gudang": "g.nama_gudang",
+}
+
+func (s *Store) ListBatchStok(ctx context.Context, q store.Kueri) ([]models.StokBatchResponse, int, error) {
+	// Batch yang sudah habis disaring di tabel turunan karena queryDaftar menyusun WHERE sendiri
+	dari := "FROM (SELECT * FROM stok_batch WHERE jumlah > 0) b" + batchJoin
+	rows, total, err := s.queryDaftar(ctx, batchColumns, dari, q, store.DaftarBatch, kolomBatch)
+	if err != nil {
+		return nil, 0, err
+	}
+	daftar, err := scanBatch(rows)
+	return daftar, total, err
+}
+
+func (s *Store) BatchKedaluwarsa(ctx context.Context, hari int, gudangID int64) ([]models.StokBatchResponse, error) {
+	query := batchSelect + " AND b.tanggal_kedaluwarsa IS NOT NULL AND b.tanggal_kedaluwarsa <= DATE_ADD(CURDATE(), INTERVAL ? DAY)"
+	args := []any{hari}
+	if gudangID != 0 {
+		query += " AND b.gudang_id = ?"
+		args = append(args, gudangID)
+	}
+	query += " ORDER BY b.tanggal_kedaluwarsa, b.gudang_id, b.produk_id, b.batch_id"
+	return s.queryBatch(ctx, query, args...)
+}
+
+func (s *Store) queryBatch(ctx context.Context, query string, args ...any) ([]models.StokBatchResponse, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	return scanBatch(rows)
+}
+
+// scanBatch membaca baris dengan kolom batchColumns lalu menutup rows
+func scanBatch(rows *sql.Rows) ([]models.StokBatchResponse, error) {
+	defer rows.Close()
+	daftar := make([]models.StokBatchResponse, 0)
+	for rows.Next() {
+		var b models.StokBatchResponse
+		err := rows.Scan(&b.BatchID, &b.ProdukID, &b.GudangID, &b.NomorLot, &b.TanggalProduksi, &b.TanggalKedaluwarsa,
+			&b.Jumlah, &b.PenerimaanID, &b.TanggalMasuk, &b.NamaProduk, &b.NamaGudang, &b.SisaHari)
+		if err != nil {
+			return nil, err
+		}
+		daftar = append(daftar, b)
+	}
+	return daftar, rows.Err()
+}
@@ -0,0 +1,82 @@
+// file: internal/store/mysql/daftar.go
+
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"maps"
+	"slices"
+	"strings"
+
+	"scm-api/internal/store"
+)
+
+// kolomDaftar memetakan nama field di store.Daftar ke ekspresi SQL-nya.
+// Entitas yang mendukung soft delete juga harus memetakan "deleted_at".
+type kolomDaftar map[string]string
+
+// queryDaftar menjalankan "SELECT pilih dari" dengan filter, rentang tanggal, urutan,
+// dan halaman dari q, lalu mengembalikan baris halaman itu beserta jumlah seluruh
+// baris yang cocok. dari berisi klausa FROM dan JOIN tanpa WHERE.
+func (s *Store) queryDaftar(ctx context.Context, pilih, dari string, q store.Kueri, d store.Daftar, kolom kolomDaftar) (*sql.Rows, int, error) {
+	where := " WHERE 1 = 1"
+	args := make([]any, 0)
+	if d.BisaDihapus && !q.TermasukDihapus {
+		where += " AND " + kolom["deleted_at"] + " IS NULL"
+	}
+	for _, field := range d.Filter {
+		if v, ok := q.Filter[field]; ok {
+			where += " AND " + kolom[field] + " = ?"
+			args = append(args, v)
+		}
+	}
+	for _, field := range slices.Sorted(maps.Keys(q.Lingkup)) {
+		ids := q.Lingkup[field]
+		if len(ids) == 0 {
+			where += " AND 1 = 0"
+			continue
+		}
+		fields, ok := d.Gabungan[field]
+		if !ok {
+			fields = []string{field}
+		}
+		syarat := make([]string, len(fields))
+		for i, f := range fields {
+			syarat[i] = kolom[f] + " IN (?" + strings.Repeat(", ?", len(ids)-1) + ")"
+			for _, id := range ids {
+				args = append(args, id)
+			}
+		}
+		where += " AND (" + strings.Join(syarat, " OR ") + ")"
+	}
+	if d.Tanggal != "" && !q.Dari.IsZero() {
+		where += " AND " + kolom[d.Tanggal] + " >= ?"
+		args = append(args, q.Dari.Format(formatDatetime))
+	}
+	if d.Tanggal != "" && !q.Sampai.IsZero() {
+		where += " AND " + kolom[d.Tanggal] + " < ?"
+		args = append(args, q.Sampai.Format(formatDatetime))
+	}
+
+	var total int
+	if err := s.db.QueryRowContext(ctx, "SELECT COUNT(*) "+dari+where, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	urut, arah := q.Urut, "ASC"
+	if urut == "" {
+		urut = d.UrutBawaan
+	}
+	if q.Turun {
+		arah = "DESC"
+	}
+	// Field pertama di d.Urut selalu ID, dipakai sebagai penentu urutan baris yang nilainya sama
+	order := " ORDER BY " + kolom[urut] + " " + arah + ", " + kolom[d.Urut[0]] + " " + arah
+	if q.Batas > 0 {
+		order += " LIMIT ? OFFSET ?"
+		args = append(args, q.Batas, q.Offset())
+	}
+	rows, err := s.db.QueryContext(ctx, "SELECT "+pilih+" "+dari+where+order, args...)
+	return rows, total, err
+}
@@ -0,0 +1,93 @@
+// file: internal/store/mysql/gudang.go
+
+package mysql
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"scm-api/internal/models"
+	"scm-api/internal/store"
+)
+
+var kolomGudang = kolomDaftar{
+	"gudang_id": "gudang_id", "nama_gudang": "nama_gudang", "lokasi": "lokasi", "deleted_at": "deleted_at",
+}
+
+func (s *Store) ListGudang(ctx context.Context, q store.Kueri) ([]models.Gudang, int, error) {
+	rows, total, err := s.queryDaftar(ctx, "gudang_id, nama_gudang, lokasi, deleted_at", "FROM gudang", q, store.DaftarGudang, kolomGudang)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+	daftarGudang := make([]models.Gudang, 0)
+	for rows.Next() {
+		var g models.Gudang
+		if err := rows.Scan(&g.GudangID, &g.NamaGudang, &g.Lokasi, &g.DeletedAt); err != nil {
+			log.Printf("Error scanning row gudang: %v", err)
+			continue
+		}
+		daftarGudang = append(daftarGudang, g)
+	}
+	return daftarGudang, total, rows.Err()
+}
+
+func (s *Store) GetGudang(ctx context.Context, id int64) (models.Gudang, error) {
+	var g models.Gudang
+	row := s.db.QueryRowContext(ctx, "SELECT gudang_id, nama_gudang, lokasi, deleted_at FROM gudang WHERE gudang_id = ?", id)
+	err := row.Scan(&g.GudangID, &g.NamaGudang, &g.Lokasi, &g.DeletedAt)
+	return g, notFound(err)
+}
+
+func (s *Store) CreateGudang(ctx context.Context, g *models.Gudang) error {
+	result, err := s.db.ExecContext(ctx, "INSERT INTO gudang (nama_gudang, lokasi) VALUES (?, ?)", g.NamaGudang, g.Lokasi)
+	if err != nil {
+		return err
+	}
+	g.GudangID, err = result.LastInsertId()
+	return err
+}
+
+func (s *Store) UpdateGudang(ctx context.Context, g models.Gudang) error {
+	_, err := s.db.ExecContext(ctx, "UPDATE gudang SET nama_gudang = ?, lokasi = ? WHERE gudang_id = ?", g.NamaGudang, g.Lokasi, g.GudangID)
+	return err
+}
+
+func (s *Store) DeleteGudang(ctx context.Context, id int64) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Baris stok dikunci agar tidak ada barang yang masuk selagi gudang diarsipkan
+	var produk, jumlah int
+	err = tx.QueryRowContext(ctx, "SELECT COUNT(*), COALESCE(SUM(jumlah), 0) FROM stok WHERE gudang_id = ? AND jumlah <> 0 FOR UPDATE", id).Scan(&produk, &jumlah)
+	if err != nil {
+		return err
+	}
+	if produk > 0 {
+		return fmt.Errorf("%w: %d produk dengan total %d unit", store.ErrStokTersisa, produk, jumlah)
+	}
+	result, err := tx.ExecContext(ctx, "UPDATE gudang SET deleted_at = NOW() WHERE gudang_id = ? AND deleted_at IS NULL", id)
+	if err != nil {
+		return err
+	}
+	if err := checkAffected(result); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (s *Store) PulihkanGudang(ctx context.Context, id int64) error {
+	result, err := s.db.ExecContext(ctx, "UPDATE gudang SET deleted_at = NULL WHERE gudang_id = ? AND deleted_at IS NOT NULL", id)
+	if err != nil {
+		return err
+	}
+	return checkAffected(result)
+}
+
+func (s *Store) CountGudang(ctx context.Context) (int, error) {
+	return s.count(ctx, "SELECT COUNT(*) FROM gudang WHERE deleted_at IS NULL")
+}
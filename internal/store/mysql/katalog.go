@@ -0,0 +1,164 @@
+// file: internal/store/mysql/katalog.go
+
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"strings"
+
+	"scm-api/internal/models"
+	"scm-api/internal/store"
+)
+
+const katalogColumns = `
+            k.supplier_produk_id, k.supplier_id, s.nama_supplier, k.produk_id, p.nama_produk, k.sku_supplier, k.satuan,
+            k.harga_beli, k.moq, k.lead_time_hari, k.berlaku_dari, k.berlaku_sampai, k.created_at, k.updated_at`
+
+const katalogFrom = `
+        FROM supplier_produk k
+        JOIN supplier s ON k.supplier_id = s.supplier_id
+        JOIN produk p ON k.produk_id = p.produk_id`
+
+var kolomKatalog = kolomDaftar{
+	"supplier_produk_id": "k.supplier_produk_id", "supplier_id": "k.supplier_id", "produk_id": "k.produk_id",
+	"nama_produk": "p.nama_produk", "satuan": "k.satuan", "harga_beli": "k.harga_beli", "moq": "k.moq",
+	"lead_time_hari": "k.lead_time_hari", "berlaku_dari": "k.berlaku_dari",
+}
+
+// kolomScanKatalog mengembalikan tujuan Scan sesuai urutan katalogColumns
+func kolomScanKatalog(k *models.SupplierProduk) []any {
+	return []any{&k.SupplierProdukID, &k.SupplierID, &k.NamaSupplier, &k.ProdukID, &k.NamaProduk, &k.SKUSupplier, &k.Satuan,
+		&k.HargaBeli, &k.MOQ, &k.LeadTimeHari, &k.BerlakuDari, &k.BerlakuSampai, &k.CreatedAt, &k.UpdatedAt}
+}
+
+func scanKatalog(rows *sql.Rows) ([]models.SupplierProduk, error) {
+	daftar := make([]models.SupplierProduk, 0)
+	for rows.Next() {
+		var k models.SupplierProduk
+		if err := rows.Scan(kolomScanKatalog(&k)...); err != nil {
+			log.Printf("Error scanning row supplier_produk: %v", err)
+			continue
+		}
+		k.BerlakuDari, k.BerlakuSampai = tanggalSaja(k.BerlakuDari), tanggalSaja(k.BerlakuSampai)
+		daftar = append(daftar, k)
+	}
+	return daftar, rows.Err()
+}
+
+func (s *Store) ListKatalog(ctx context.Context, q store.Kueri) ([]models.SupplierProduk, int, error) {
+	rows, total, err := s.queryDaftar(ctx, katalogColumns, katalogFrom, q, store.DaftarKatalog, kolomKatalog)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+	daftar, err := scanKatalog(rows)
+	return daftar, total, err
+}
+
+func (s *Store) KatalogAktif(ctx context.Context, supplierID, produkID int64, tanggal string) ([]models.SupplierProduk, error) {
+	query := "SELECT " + katalogColumns + katalogFrom + `
+        WHERE k.produk_id = ?
+          AND (k.berlaku_dari IS NULL OR k.berlaku_dari <= ?)
+          AND (k.berlaku_sampai IS NULL OR k.berlaku_sampai >= ?)`
+	args := []any{produkID, tanggal, tanggal}
+	if supplierID != 0 {
+		query += " AND k.supplier_id = ?"
+		args = append(args, supplierID)
+	} else {
+		query += " AND s.deleted_at IS NULL"
+	}
+	rows, err := s.db.QueryContext(ctx, query+" ORDER BY k.harga_beli, k.supplier_produk_id", args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanKatalog(rows)
+}
+
+func (s *Store) KatalogAktifProduk(ctx context.Context, produkIDs []int64, tanggal string) ([]models.KatalogBerfaktor, error) {
+	daftar := make([]models.KatalogBerfaktor, 0)
+	if len(produkIDs) == 0 {
+		return daftar, nil
+	}
+	// Satuan dasar produk berfaktor 1; satuan lain diambil dari produk_satuan
+	query := "SELECT " + katalogColumns + `,
+            CASE WHEN k.satuan = p.satuan THEN 1 ELSE COALESCE(ps.faktor, 0) END` + katalogFrom + `
+        LEFT JOIN produk_satuan ps ON ps.produk_id = k.produk_id AND ps.satuan = k.satuan
+        WHERE k.produk_id IN (?` + strings.Repeat(", ?", len(produkIDs)-1) + `)
+          AND (k.berlaku_dari IS NULL OR k.berlaku_dari <= ?)
+          AND (k.berlaku_sampai IS NULL OR k.berlaku_sampai >= ?)
+          AND s.deleted_at IS NULL
+        ORDER BY k.produk_id, k.harga_beli, k.supplier_produk_id`
+	args := make([]any, 0, len(produkIDs)+2)
+	for _, id := range produkIDs {
+		args = append(args, id)
+	}
+	rows, err := s.db.QueryContext(ctx, query, append(args, tanggal, tanggal)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var k models.KatalogBerfaktor
+		if err := rows.Scan(append(kolomScanKatalog(&k.SupplierProduk), &k.Faktor)...); err != nil {
+			return nil, err
+		}
+		k.BerlakuDari, k.BerlakuSampai = tanggalSaja(k.BerlakuDari), tanggalSaja(k.BerlakuSampai)
+		daftar = append(daftar, k)
+	}
+	return daftar, rows.Err()
+}
+
+func (s *Store) GetKatalog(ctx context.Context, id int64) (models.SupplierProduk, error) {
+	rows, err := s.db.QueryContext(ctx, "SELECT "+katalogColumns+katalogFrom+" WHERE k.supplier_produk_id = ?", id)
+	if err != nil {
+		return models.SupplierProduk{}, err
+	}
+	defer rows.Close()
+	daftar, err := scanKatalog(rows)
+	if err != nil {
+		return models.SupplierProduk{}, err
+	}
+	if len(daftar) == 0 {
+		return models.SupplierProduk{}, store.ErrNotFound
+	}
+	return daftar[0], nil
+}
+
+func (s *Store) CreateKatalog(ctx context.Context, k *models.SupplierProduk) error {
+	query := `
+        INSERT INTO supplier_produk (supplier_id, produk_id, sku_supplier, satuan, harga_beli, moq, lead_time_hari, berlaku_dari, berlaku_sampai)
+        VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	result, err := s.db.ExecContext(ctx, query, k.SupplierID, k.ProdukID, k.SKUSupplier, k.Satuan, k.HargaBeli, k.MOQ, k.LeadTimeHari, k.BerlakuDari, k.BerlakuSampai)
+	if err != nil {
+		return err
+	}
+	if k.SupplierProdukID, err = result.LastInsertId(); err != nil {
+		return err
+	}
+	dibuat, err := s.GetKatalog(ctx, k.SupplierProdukID)
+	if err != nil {
+		return err
+	}
+	*k = dibuat
+	return nil
+}
+
+func (s *Store) UpdateKatalog(ctx context.Context, k models.SupplierProduk) error {
+	query := `
+        UPDATE supplier_produk
+        SET produk_id = ?, sku_supplier = ?, satuan = ?, harga_beli = ?, moq = ?, lead_time_hari = ?, berlaku_dari = ?, berlaku_sampai = ?
+        WHERE supplier_produk_id = ?`
+	_, err := s.db.ExecContext(ctx, query, k.ProdukID, k.SKUSupplier, k.Satuan, k.HargaBeli, k.MOQ, k.LeadTimeHari, k.BerlakuDari, k.BerlakuSampai, k.SupplierProdukID)
+	return err
+}
+
+func (s *Store) DeleteKatalog(ctx context.Context, id int64) error {
+	result, err := s.db.ExecContext(ctx, `DELETE FROM supplier_produk WHERE supplier_produk_id = ?`, id)
+	if err != nil {
+		return err
+	}
+	return checkAffected(result)
+}
@@ -0,0 +1,247 @@
+// file: internal/store/mysql/pembelian.go
+
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+
+	"scm-api/internal/models"
+	"scm-api/internal/store"
+)
+
+const (
+	pembelianColumns = `
+            p.pembelian_id, p.supplier_id, s.nama_supplier,
+            p.tanggal_pesan, p.estimasi_tiba, p.total_biaya, p.status, p.gudang_tujuan_id`
+	pembelianFrom = `
+        FROM pembelian p
+        JOIN supplier s ON p.supplier_id = s.supplier_id`
+	pembelianSelect = "SELECT " + pembelianColumns + pembelianFrom
+)
+
+var kolomPembelian = kolomDaftar{
+	"pembelian_id": "p.pembelian_id", "supplier_id": "p.supplier_id", "nama_supplier": "s.nama_supplier",
+	"tanggal_pesan": "p.tanggal_pesan", "estimasi_tiba": "p.estimasi_tiba", "total_biaya": "p.total_biaya",
+	"status": "p.status", "gudang_tujuan_id": "p.gudang_tujuan_id",
+}
+
+func (s *Store) ListPembelian(ctx context.Context, q store.Kueri) ([]models.PembelianResponse, int, error) {
+	rows, total, err := s.queryDaftar(ctx, pembelianColumns, pembelianFrom, q, store.DaftarPembelian, kolomPembelian)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+	daftarPembelian, err := scanPembelian(rows)
+	return daftarPembelian, total, err
+}
+
+func (s *Store) RecentPembelian(ctx context.Context, limit int) ([]models.PembelianResponse, error) {
+	rows, err := s.db.QueryContext(ctx, pembelianSelect+" ORDER BY p.tanggal_pesan DESC LIMIT ?", limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanPembelian(rows)
+}
+
+func scanPembelian(rows *sql.Rows) ([]models.PembelianResponse, error) {
+	daftarPembelian := make([]models.PembelianResponse, 0)
+	for rows.Next() {
+		var p models.PembelianResponse
+		err := rows.Scan(&p.PembelianID, &p.SupplierID, &p.NamaSupplier, &p.TanggalPesan, &p.EstimasiTiba, &p.TotalBiaya, &p.Status, &p.GudangTujuanID)
+		if err != nil {
+			log.Printf("Error scanning row pembelian: %v", err)
+			continue
+		}
+		daftarPembelian = append(daftarPembelian, p)
+	}
+	return daftarPembelian, rows.Err()
+}
+
+func (s *Store) GetPembelian(ctx context.Context, id int64) (models.PembelianDenganDetailResponse, error) {
+	var response models.PembelianDenganDetailResponse
+	row := s.db.QueryRowContext(ctx, pembelianSelect+" WHERE p.pembelian_id = ?", id)
+	err := row.Scan(&response.PembelianID, &response.SupplierID, &response.NamaSupplier, &response.TanggalPesan, &response.EstimasiTiba, &response.TotalBiaya, &response.Status, &response.GudangTujuanID)
+	if err != nil {
+		return response, notFound(err)
+	}
+
+	response.Details, err = detailPembelian(ctx, s.db, id)
+	return response, err
+}
+
+func (s *Store) CreatePembelian(ctx context.Context, p *models.Pembelian, details []models.DetailPembelian, oleh string) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := createPembelianTx(ctx, tx, p, details, oleh); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (s *Store) CreatePembelianBanyak(ctx context.Context, daftar []models.PembelianBaru, oleh string) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for i := range daftar {
+		if err := createPembelianTx(ctx, tx, &daftar[i].Pembelian, daftar[i].Details, oleh); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// createPembelianTx menyimpan header, detail, dan riwayat status awal di dalam tx
+func createPembelianTx(ctx context.Context, tx *sql.Tx, p *models.Pembelian, details []models.DetailPembelian, oleh string) error {
+	queryHeader := `INSERT INTO pembelian (supplier_id, tanggal_pesan, estimasi_tiba, total_biaya, status, gudang_tujuan_id) VALUES (?, ?, ?, ?, ?, ?)`
+	result, err := tx.ExecContext(ctx, queryHeader, p.SupplierID, p.TanggalPesan, p.EstimasiTiba, p.TotalBiaya, p.Status, p.GudangTujuanID)
+	if err != nil {
+		return fmt.Errorf("gagal menyimpan data pembelian: %w", err)
+	}
+	p.PembelianID, err = result.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	queryDetail := `INSERT INTO detail_pembelian (pembelian_id, produk_id, jumlah, satuan, faktor_konversi, harga_beli_satuan, subtotal) VALUES (?, ?, ?, ?, ?, ?, ?)`
+	for i := range details {
+		details[i].PembelianID = p.PembelianID
+		result, err := tx.ExecContext(ctx, queryDetail, p.PembelianID, details[i].ProdukID, details[i].Jumlah, details[i].Satuan, details[i].FaktorKonversi, details[i].HargaBeliSatuan, details[i].Subtotal)
+		if err != nil {
+			return fmt.Errorf("gagal menyimpan detail produk pembelian: %w", err)
+		}
+		details[i].DetailPembelianID, _ = result.LastInsertId()
+	}
+
+	return catatRiwayatStatus(ctx, tx, p.PembelianID, "", p.Status, oleh, "")
+}
+
+func (s *Store) DeletePembelian(ctx context.Context, id int64) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var status string
+	if err := tx.QueryRowContext(ctx, "SELECT status FROM pembelian WHERE pembelian_id = ? FOR UPDATE", id).Scan(&status); err != nil {
+		return notFound(err)
+	}
+	if !models.BolehHapusPembelian(status) {
+		return fmt.Errorf("%w: pembelian berstatus %q tidak bisa dihapus", store.ErrInvalidTransition, status)
+	}
+
+	// Penerimaan adalah sumber stok dan mutasinya, jadi tidak ikut dihapus
+	var adaPenerimaan bool
+	if err := tx.QueryRowContext(ctx, "SELECT EXISTS (SELECT 1 FROM penerimaan WHERE pembelian_id = ?)", id).Scan(&adaPenerimaan); err != nil {
+		return err
+	}
+	if adaPenerimaan {
+		return fmt.Errorf("%w: pembelian sudah memiliki penerimaan", store.ErrInvalidTransition)
+	}
+
+	// Hapus dulu detailnya. Riwayat status sengaja disimpan sebagai jejak pesanan yang dihapus.
+	if _, err := tx.ExecContext(ctx, "DELETE FROM detail_pembelian WHERE pembelian_id = ?", id); err != nil {
+		return err
+	}
+
+	// Setelah itu, baru hapus baris di tabel header
+	result, err := tx.ExecContext(ctx, "DELETE FROM pembelian WHERE pembelian_id = ?", id)
+	if err != nil {
+		return err
+	}
+	if err := checkAffected(result); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (s *Store) TerimaPembelian(ctx context.Context, id, gudangID int64, oleh string) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	p := models.Penerimaan{PembelianID: id, DiterimaOleh: oleh, GudangID: gudangID}
+	if err := terimaTx(ctx, tx, &p, true); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (s *Store) UbahStatusPembelian(ctx context.Context, id int64, ke, oleh, catatan string) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := ubahStatusTx(ctx, tx, id, ke, oleh, catatan); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// ubahStatusTx mengunci baris pembelian, memeriksa transisi, lalu mengubah status dan mencatat riwayatnya
+func ubahStatusTx(ctx context.Context, tx *sql.Tx, id int64, ke, oleh, catatan string) error {
+	var dari string
+	err := tx.QueryRowContext(ctx, "SELECT status FROM pembelian WHERE pembelian_id = ? FOR UPDATE", id).Scan(&dari)
+	if err != nil {
+		return notFound(err)
+	}
+	if !models.BolehTransisiPembelian(dari, ke) {
+		return fmt.Errorf("%w: dari %q ke %q", store.ErrInvalidTransition, dari, ke)
+	}
+	if _, err := tx.ExecContext(ctx, "UPDATE pembelian SET status = ? WHERE pembelian_id = ?", ke, id); err != nil {
+		return err
+	}
+	return catatRiwayatStatus(ctx, tx, id, dari, ke, oleh, catatan)
+}
+
+// catatRiwayatStatus menulis satu baris pembelian_status_riwayat. dari kosong berarti status awal.
+func catatRiwayatStatus(ctx context.Context, tx *sql.Tx, id int64, dari, ke, oleh, catatan string) error {
+	query := `INSERT INTO pembelian_status_riwayat (pembelian_id, status_dari, status_ke, diubah_oleh, catatan, waktu) VALUES (?, ?, ?, ?, ?, NOW())`
+	_, err := tx.ExecContext(ctx, query, id, nullString(dari), ke, oleh, nullString(catatan))
+	if err != nil {
+		return fmt.Errorf("gagal mencatat riwayat status pembelian: %w", err)
+	}
+	return nil
+}
+
+func (s *Store) RiwayatStatusPembelian(ctx context.Context, id int64) ([]models.RiwayatStatusPembelian, error) {
+	var exists int
+	if err := s.db.QueryRowContext(ctx, "SELECT 1 FROM pembelian WHERE pembelian_id = ?", id).Scan(&exists); err != nil {
+		return nil, notFound(err)
+	}
+
+	query := `SELECT riwayat_id, pembelian_id, status_dari, status_ke, diubah_oleh, catatan, waktu FROM pembelian_status_riwayat WHERE pembelian_id = ? ORDER BY waktu, riwayat_id`
+	rows, err := s.db.QueryContext(ctx, query, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	riwayat := make([]models.RiwayatStatusPembelian, 0)
+	for rows.Next() {
+		var r models.RiwayatStatusPembelian
+		if err := rows.Scan(&r.RiwayatID, &r.PembelianID, &r.StatusDari, &r.StatusKe, &r.DiubahOleh, &r.Catatan, &r.Waktu); err != nil {
+			return nil, err
+		}
+		riwayat = append(riwayat, r)
+	}
+	return riwayat, rows.Err()
+}
+
+func (s *Store) CountPembelian(ctx context.Context) (int, error) {
+	return s.count(ctx, "SELECT COUNT(*) FROM pembelian")
+}
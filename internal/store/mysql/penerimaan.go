@@ -0,0 +1,219 @@
+// file: internal/store/mysql/penerimaan.go
+
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"scm-api/internal/models"
+	"scm-api/internal/store"
+)
+
+// querier dipenuhi oleh *sql.DB dan *sql.Tx
+type querier interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+}
+
+// detailPembelian mengambil item pembelian beserta jumlah yang sudah diterima, ditolak, dan sisanya
+func detailPembelian(ctx context.Context, q querier, pembelianID int64) ([]models.DetailPembelianResponse, error) {
+	query := `
+        SELECT
+            d.detail_pembelian_id, d.produk_id, pr.nama_produk, d.jumlah, d.satuan, d.faktor_konversi, pr.satuan, d.harga_beli_satuan, d.subtotal,
+            COALESCE(r.diterima, 0), COALESCE(r.ditolak, 0)
+        FROM detail_pembelian d
+        JOIN produk pr ON d.produk_id = pr.produk_id
+        LEFT JOIN (
+            SELECT dp.detail_pembelian_id, SUM(dp.jumlah_diterima) AS diterima, SUM(dp.jumlah_ditolak) AS ditolak
+            FROM detail_penerimaan dp
+            JOIN penerimaan pn ON pn.penerimaan_id = dp.penerimaan_id
+            WHERE pn.pembelian_id = ?
+            GROUP BY dp.detail_pembelian_id
+        ) r ON r.detail_pembelian_id = d.detail_pembelian_id
+        WHERE d.pembelian_id = ?
+        ORDER BY d.detail_pembelian_id`
+	// Agregat penerimaan dibatasi pada pembelian ini agar tidak menjumlah seluruh tabel
+	rows, err := q.QueryContext(ctx, query, pembelianID, pembelianID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	details := make([]models.DetailPembelianResponse, 0)
+	for rows.Next() {
+		var d models.DetailPembelianResponse
+		err := rows.Scan(&d.DetailPembelianID, &d.ProdukID, &d.NamaProduk, &d.Jumlah, &d.Satuan, &d.FaktorKonversi, &d.SatuanDasar, &d.HargaBeliSatuan, &d.Subtotal, &d.JumlahDiterima, &d.JumlahDitolak)
+		if err != nil {
+			return nil, err
+		}
+		d.Sisa = d.Jumlah - d.JumlahDiterima - d.JumlahDitolak
+		details = append(details, d)
+	}
+	return details, rows.Err()
+}
+
+func (s *Store) CreatePenerimaan(ctx context.Context, p *models.Penerimaan) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := terimaTx(ctx, tx, p, false); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// terimaTx mengunci pembelian, mencocokkan item dengan sisa pesanan, lalu mencatat
+// dokumen penerimaan, menambah stok, dan memindahkan status. Jika penuh bernilai true,
+// item penerimaan diisi dengan seluruh sisa setiap baris.
+func terimaTx(ctx context.Context, tx *sql.Tx, p *models.Penerimaan, penuh bool) error {
+	var dari string
+	var gudangTujuan sql.NullInt64
+	err := tx.QueryRowContext(ctx, "SELECT status, gudang_tujuan_id FROM pembelian WHERE pembelian_id = ? FOR UPDATE", p.PembelianID).Scan(&dari, &gudangTujuan)
+	if err != nil {
+		return notFound(err)
+	}
+	if !models.BolehTransisiPembelian(dari, models.StatusPembelianDiterima) {
+		return fmt.Errorf("%w: pesanan berstatus %q tidak bisa menerima barang", store.ErrInvalidTransition, dari)
+	}
+
+	lines, err := detailPembelian(ctx, tx, p.PembelianID)
+	if err != nil {
+		return err
+	}
+	if penuh {
+		p.Details = store.PenerimaanPenuh(lines)
+		if len(p.Details) == 0 {
+			// Tidak ada lagi yang ditunggu, cukup tutup pesanannya
+			return ubahStatusTx(ctx, tx, p.PembelianID, models.StatusPembelianDiterima, p.DiterimaOleh, p.Catatan.String)
+		}
+	}
+	ke, err := store.CocokkanPenerimaan(lines, p.Details)
+	if err != nil {
+		return err
+	}
+	daftarGudang, err := store.TentukanGudang(p, gudangTujuan)
+	if err != nil {
+		return err
+	}
+	for _, gudangID := range daftarGudang {
+		var dihapus sql.NullString
+		err := tx.QueryRowContext(ctx, "SELECT deleted_at FROM gudang WHERE gudang_id = ?", gudangID).Scan(&dihapus)
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("%w: gudang %d tidak ditemukan", store.ErrInvalidReceipt, gudangID)
+		}
+		if err != nil {
+			return err
+		}
+		if dihapus.Valid {
+			return fmt.Errorf("%w: gudang %d sudah diarsipkan", store.ErrInvalidReceipt, gudangID)
+		}
+	}
+
+	queryHeader := `INSERT INTO penerimaan (pembelian_id, tanggal_terima, diterima_oleh, catatan) VALUES (?, COALESCE(?, NOW()), ?, ?)`
+	result, err := tx.ExecContext(ctx, queryHeader, p.PembelianID, nullString(p.TanggalTerima), p.DiterimaOleh, p.Catatan)
+	if err != nil {
+		return fmt.Errorf("gagal menyimpan data penerimaan: %w", err)
+	}
+	p.PenerimaanID, err = result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	if err := tx.QueryRowContext(ctx, "SELECT tanggal_terima FROM penerimaan WHERE penerimaan_id = ?", p.PenerimaanID).Scan(&p.TanggalTerima); err != nil {
+		return err
+	}
+
+	queryDetail := `
+        INSERT INTO detail_penerimaan
+            (penerimaan_id, detail_pembelian_id, produk_id, gudang_id, jumlah_diterima, jumlah_ditolak, jumlah_stok, alasan_tolak, nomor_lot, tanggal_produksi, tanggal_kedaluwarsa)
+        VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+    `
+	for i := range p.Details {
+		d := &p.Details[i]
+		d.PenerimaanID = p.PenerimaanID
+		result, err := tx.ExecContext(ctx, queryDetail, p.PenerimaanID, d.DetailPembelianID, d.ProdukID, d.GudangID, d.JumlahDiterima, d.JumlahDitolak, d.JumlahStok, d.AlasanTolak,
+			d.NomorLot, tanggalSaja(d.TanggalProduksi), tanggalSaja(d.TanggalKedaluwarsa))
+		if err != nil {
+			return fmt.Errorf("gagal menyimpan detail penerimaan: %w", err)
+		}
+		d.DetailPenerimaanID, _ = result.LastInsertId()
+
+		// Hanya barang yang benar-benar diterima yang masuk stok
+		if d.JumlahStok > 0 {
+			m := models.StokMutasi{
+				ProdukID:      d.ProdukID,
+				GudangID:      d.GudangID,
+				Tipe:          models.MutasiPenerimaan,
+				ReferensiTipe: nullString("penerimaan"),
+				ReferensiID:   sql.NullInt64{Int64: p.PenerimaanID, Valid: true},
+				DibuatOleh:    p.DiterimaOleh,
+			}
+			diterima := d.JumlahStok
+			if _, err := mutasiStokTx(ctx, tx, &m, func(sebelum int) int { return sebelum + diterima }, d.Batch()); err != nil {
+				return err
+			}
+		}
+	}
+
+	return ubahStatusTx(ctx, tx, p.PembelianID, ke, p.DiterimaOleh, fmt.Sprintf("Penerimaan #%d", p.PenerimaanID))
+}
+
+var kolomPenerimaan = kolomDaftar{
+	"penerimaan_id": "penerimaan_id", "pembelian_id": "pembelian_id", "tanggal_terima": "tanggal_terima",
+}
+
+func (s *Store) ListPenerimaan(ctx context.Context, pembelianID int64, q store.Kueri) ([]models.Penerimaan, int, error) {
+	var exists int
+	if err := s.db.QueryRowContext(ctx, "SELECT 1 FROM pembelian WHERE pembelian_id = ?", pembelianID).Scan(&exists); err != nil {
+		return nil, 0, notFound(err)
+	}
+
+	q = q.DenganLingkup("pembelian_id", []int64{pembelianID})
+	pilih := "penerimaan_id, pembelian_id, tanggal_terima, diterima_oleh, catatan"
+	rows, total, err := s.queryDaftar(ctx, pilih, "FROM penerimaan", q, store.DaftarPenerimaan, kolomPenerimaan)
+	if err != nil {
+		return nil, 0, err
+	}
+	daftar := make([]models.Penerimaan, 0)
+	index := make(map[int64]int)
+	for rows.Next() {
+		var p models.Penerimaan
+		if err := rows.Scan(&p.PenerimaanID, &p.PembelianID, &p.TanggalTerima, &p.DiterimaOleh, &p.Catatan); err != nil {
+			rows.Close()
+			return nil, 0, err
+		}
+		p.Details = make([]models.DetailPenerimaan, 0)
+		index[p.PenerimaanID] = len(daftar)
+		daftar = append(daftar, p)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	queryDetail := `
+        SELECT d.detail_penerimaan_id, d.penerimaan_id, d.detail_pembelian_id, d.produk_id, COALESCE(d.gudang_id, 0), d.jumlah_diterima, d.jumlah_ditolak, d.jumlah_stok, d.alasan_tolak,
+               d.nomor_lot, d.tanggal_produksi, d.tanggal_kedaluwarsa
+        FROM detail_penerimaan d
+        JOIN penerimaan p ON d.penerimaan_id = p.penerimaan_id
+        WHERE p.pembelian_id = ?
+        ORDER BY d.detail_penerimaan_id`
+	rows, err = s.db.QueryContext(ctx, queryDetail, pembelianID)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var d models.DetailPenerimaan
+		if err := rows.Scan(&d.DetailPenerimaanID, &d.PenerimaanID, &d.DetailPembelianID, &d.ProdukID, &d.GudangID, &d.JumlahDiterima, &d.JumlahDitolak, &d.JumlahStok, &d.AlasanTolak,
+			&d.NomorLot, &d.TanggalProduksi, &d.TanggalKedaluwarsa); err != nil {
+			return nil, 0, err
+		}
+		if i, ok := index[d.PenerimaanID]; ok {
+			daftar[i].Details = append(daftar[i].Details, d)
+		}
+	}
+	return daftar, total, rows.Err()
+}
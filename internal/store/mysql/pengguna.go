@@ -0,0 +1,239 @@
+// file: internal/store/mysql/pengguna.go
+
+package mysql
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"scm-api/internal/models"
+	"scm-api/internal/store"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// kodeDuplikat adalah nomor error MySQL untuk pelanggaran kunci unik (ER_DUP_ENTRY)
+const kodeDuplikat = 1062
+
+const penggunaColumns = "pengguna_id, username, COALESCE(nama_lengkap, ''), password_hash, aktif, tanggal_dibuat, versi_token"
+
+const penggunaSelect = "SELECT " + penggunaColumns + " FROM pengguna"
+
+func scanPengguna(row interface{ Scan(...any) error }) (models.Pengguna, error) {
+	var p models.Pengguna
+	err := row.Scan(&p.PenggunaID, &p.Username, &p.NamaLengkap, &p.PasswordHash, &p.Aktif, &p.TanggalDibuat, &p.VersiToken)
+	return p, notFound(err)
+}
+
+var kolomPengguna = kolomDaftar{
+	"pengguna_id": "pengguna_id", "username": "username", "nama_lengkap": "nama_lengkap", "tanggal_dibuat": "tanggal_dibuat",
+}
+
+func (s *Store) ListPengguna(ctx context.Context, q store.Kueri) ([]models.PenggunaDenganRole, int, error) {
+	rows, total, err := s.queryDaftar(ctx, penggunaColumns, "FROM pengguna", q, store.DaftarPengguna, kolomPengguna)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+	daftarPengguna := make([]models.PenggunaDenganRole, 0)
+	indeks := make(map[int64]int)
+	ids := make([]any, 0)
+	for rows.Next() {
+		p, err := scanPengguna(rows)
+		if err != nil {
+			log.Printf("Error scanning row pengguna: %v", err)
+			continue
+		}
+		indeks[p.PenggunaID] = len(daftarPengguna)
+		ids = append(ids, p.PenggunaID)
+		daftarPengguna = append(daftarPengguna, models.PenggunaDenganRole{Pengguna: p, Role: make([]string, 0), Gudang: make([]int64, 0)})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+	if len(ids) == 0 {
+		return daftarPengguna, total, nil
+	}
+
+	// Role dan gudang hanya dibaca untuk pengguna di halaman ini
+	in := "(?" + strings.Repeat(", ?", len(ids)-1) + ")"
+	rows, err = s.db.QueryContext(ctx, `
+        SELECT pr.pengguna_id, r.nama
+        FROM pengguna_role pr
+        JOIN role r ON r.role_id = pr.role_id
+        WHERE pr.pengguna_id IN `+in+`
+        ORDER BY pr.pengguna_id, r.role_id`, ids...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var penggunaID int64
+		var nama string
+		if err := rows.Scan(&penggunaID, &nama); err != nil {
+			log.Printf("Error scanning row pengguna_role: %v", err)
+			continue
+		}
+		if i, ok := indeks[penggunaID]; ok {
+			daftarPengguna[i].Role = append(daftarPengguna[i].Role, nama)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	rows, err = s.db.QueryContext(ctx, "SELECT pengguna_id, gudang_id FROM pengguna_gudang WHERE pengguna_id IN "+in+" ORDER BY pengguna_id, gudang_id", ids...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var penggunaID, gudangID int64
+		if err := rows.Scan(&penggunaID, &gudangID); err != nil {
+			log.Printf("Error scanning row pengguna_gudang: %v", err)
+			continue
+		}
+		if i, ok := indeks[penggunaID]; ok {
+			daftarPengguna[i].Gudang = append(daftarPengguna[i].Gudang, gudangID)
+		}
+	}
+	return daftarPengguna, total, rows.Err()
+}
+
+func (s *Store) GetPengguna(ctx context.Context, id int64) (models.Pengguna, error) {
+	return scanPengguna(s.db.QueryRowContext(ctx, penggunaSelect+" WHERE pengguna_id = ?", id))
+}
+
+func (s *Store) GetPenggunaByUsername(ctx context.Context, username string) (models.Pengguna, error) {
+	return scanPengguna(s.db.QueryRowContext(ctx, penggunaSelect+" WHERE username = ?", username))
+}
+
+func (s *Store) CreatePengguna(ctx context.Context, p *models.Pengguna) error {
+	result, err := s.db.ExecContext(ctx,
+		"INSERT INTO pengguna (username, nama_lengkap, password_hash, aktif) VALUES (?, ?, ?, ?)",
+		p.Username, nullString(p.NamaLengkap), p.PasswordHash, p.Aktif)
+	if err != nil {
+		return duplikat(err)
+	}
+	if p.PenggunaID, err = result.LastInsertId(); err != nil {
+		return err
+	}
+	return s.db.QueryRowContext(ctx, "SELECT tanggal_dibuat FROM pengguna WHERE pengguna_id = ?", p.PenggunaID).Scan(&p.TanggalDibuat)
+}
+
+func (s *Store) SimpanRefreshToken(ctx context.Context, penggunaID int64, hash string, masaBerlaku time.Duration) error {
+	_, err := s.db.ExecContext(ctx,
+		"INSERT INTO refresh_token (pengguna_id, token_hash, kedaluwarsa_pada) VALUES (?, ?, DATE_ADD(NOW(), INTERVAL ? SECOND))",
+		penggunaID, hash, int64(masaBerlaku/time.Second))
+	return err
+}
+
+func (s *Store) PakaiRefreshToken(ctx context.Context, hash string) (int64, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	var tokenID, penggunaID int64
+	err = tx.QueryRowContext(ctx, `
+        SELECT token_id, pengguna_id FROM refresh_token
+        WHERE token_hash = ? AND dicabut_pada IS NULL AND kedaluwarsa_pada > NOW()
+        FOR UPDATE`, hash).Scan(&tokenID, &penggunaID)
+	if err != nil {
+		return 0, notFound(err)
+	}
+	if _, err := tx.ExecContext(ctx, "UPDATE refresh_token SET dicabut_pada = NOW() WHERE token_id = ?", tokenID); err != nil {
+		return 0, err
+	}
+	return penggunaID, tx.Commit()
+}
+
+func (s *Store) CabutSemuaRefreshToken(ctx context.Context, penggunaID int64) error {
+	_, err := s.db.ExecContext(ctx, "UPDATE refresh_token SET dicabut_pada = NOW() WHERE pengguna_id = ? AND dicabut_pada IS NULL", penggunaID)
+	return err
+}
+
+func (s *Store) NaikkanVersiToken(ctx context.Context, penggunaID int64) error {
+	result, err := s.db.ExecContext(ctx, "UPDATE pengguna SET versi_token = versi_token + 1 WHERE pengguna_id = ?", penggunaID)
+	if err != nil {
+		return err
+	}
+	return checkAffected(result)
+}
+
+func (s *Store) SetAktifPengguna(ctx context.Context, penggunaID int64, aktif bool) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, "UPDATE pengguna SET aktif = ?, versi_token = versi_token + 1 WHERE pengguna_id = ?", aktif, penggunaID)
+	if err != nil {
+		return err
+	}
+	if err := checkAffected(result); err != nil {
+		return err
+	}
+	if !aktif {
+		if _, err := tx.ExecContext(ctx, "UPDATE refresh_token SET dicabut_pada = NOW() WHERE pengguna_id = ? AND dicabut_pada IS NULL", penggunaID); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// duplikat mengubah pelanggaran kunci unik menjadi store.ErrDuplikat
+func duplikat(err error) error {
+	var myErr *mysql.MySQLError
+	if errors.As(err, &myErr) && myErr.Number == kodeDuplikat {
+		return store.ErrDuplikat
+	}
+	return err
+}
+
+func (s *Store) GudangPengguna(ctx context.Context, penggunaID int64) ([]int64, error) {
+	if _, err := s.GetPengguna(ctx, penggunaID); err != nil {
+		return nil, err
+	}
+	rows, err := s.db.QueryContext(ctx, "SELECT gudang_id FROM pengguna_gudang WHERE pengguna_id = ? ORDER BY gudang_id", penggunaID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	ids := make([]int64, 0)
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+func (s *Store) SetGudangPengguna(ctx context.Context, penggunaID int64, gudangIDs []int64) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var id int64
+	if err := tx.QueryRowContext(ctx, "SELECT pengguna_id FROM pengguna WHERE pengguna_id = ? FOR UPDATE", penggunaID).Scan(&id); err != nil {
+		return notFound(err)
+	}
+	if _, err := tx.ExecContext(ctx, "DELETE FROM pengguna_gudang WHERE pengguna_id = ?", penggunaID); err != nil {
+		return err
+	}
+	for _, gudangID := range gudangIDs {
+		if _, err := tx.ExecContext(ctx, "INSERT INTO pengguna_gudang (pengguna_id, gudang_id) VALUES (?, ?)", penggunaID, gudangID); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
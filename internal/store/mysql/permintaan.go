@@ -0,0 +1,360 @@
+// file: internal/store/mysql/permintaan.go
+
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"scm-api/internal/models"
+	"scm-api/internal/store"
+)
+
+const permintaanColumns = `
+            p.permintaan_id, p.diminta_oleh, p.supplier_id, s.nama_supplier, p.gudang_tujuan_id, p.tanggal_butuh,
+            p.catatan, p.total_biaya, p.status, p.pembelian_id, p.created_at, p.updated_at`
+
+const permintaanFrom = `
+        FROM permintaan_pembelian p
+        LEFT JOIN supplier s ON p.supplier_id = s.supplier_id`
+
+var kolomPermintaan = kolomDaftar{
+	"permintaan_id": "p.permintaan_id", "status": "p.status", "diminta_oleh": "p.diminta_oleh",
+	"supplier_id": "p.supplier_id", "created_at": "p.created_at", "tanggal_butuh": "p.tanggal_butuh",
+	"total_biaya": "p.total_biaya",
+}
+
+func scanPermintaan(row interface{ Scan(...any) error }, p *models.PermintaanPembelian) error {
+	err := row.Scan(&p.PermintaanID, &p.DimintaOleh, &p.SupplierID, &p.NamaSupplier, &p.GudangTujuanID, &p.TanggalButuh,
+		&p.Catatan, &p.TotalBiaya, &p.Status, &p.PembelianID, &p.CreatedAt, &p.UpdatedAt)
+	p.TanggalButuh = tanggalSaja(p.TanggalButuh)
+	return err
+}
+
+func (s *Store) ListPermintaan(ctx context.Context, q store.Kueri) ([]models.PermintaanPembelian, int, error) {
+	rows, total, err := s.queryDaftar(ctx, permintaanColumns, permintaanFrom, q, store.DaftarPermintaan, kolomPermintaan)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+	daftar := make([]models.PermintaanPembelian, 0)
+	for rows.Next() {
+		var p models.PermintaanPembelian
+		if err := scanPermintaan(rows, &p); err != nil {
+			return nil, 0, err
+		}
+		daftar = append(daftar, p)
+	}
+	return daftar, total, rows.Err()
+}
+
+func (s *Store) GetPermintaan(ctx context.Context, id int64) (models.PermintaanDenganDetail, error) {
+	var p models.PermintaanDenganDetail
+	row := s.db.QueryRowContext(ctx, "SELECT "+permintaanColumns+permintaanFrom+" WHERE p.permintaan_id = ?", id)
+	if err := scanPermintaan(row, &p.PermintaanPembelian); err != nil {
+		return p, notFound(err)
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+        SELECT d.detail_permintaan_id, d.permintaan_id, d.produk_id, pr.nama_produk, pr.kategori,
+               d.jumlah, d.satuan, d.faktor_konversi, d.harga_perkiraan, d.subtotal
+        FROM detail_permintaan d
+        JOIN produk pr ON d.produk_id = pr.produk_id
+        WHERE d.permintaan_id = ?
+        ORDER BY d.detail_permintaan_id`, id)
+	if err != nil {
+		return p, err
+	}
+	defer rows.Close()
+	p.Details = make([]models.DetailPermintaan, 0)
+	for rows.Next() {
+		var d models.DetailPermintaan
+		err := rows.Scan(&d.DetailPermintaanID, &d.PermintaanID, &d.ProdukID, &d.NamaProduk, &d.Kategori,
+			&d.Jumlah, &d.Satuan, &d.FaktorKonversi, &d.HargaPerkiraan, &d.Subtotal)
+		if err != nil {
+			return p, err
+		}
+		p.Details = append(p.Details, d)
+	}
+	if err := rows.Err(); err != nil {
+		return p, err
+	}
+
+	p.Persetujuan, err = persetujuanPermintaan(ctx, s.db, id)
+	return p, err
+}
+
+// persetujuanPermintaan mengambil tahap persetujuan permintaan urut level
+func persetujuanPermintaan(ctx context.Context, q querier, permintaanID int64) ([]models.PersetujuanPermintaan, error) {
+	rows, err := q.QueryContext(ctx, `
+        SELECT t.persetujuan_id, t.permintaan_id, t.level, t.role_id, r.nama, t.status, t.diputuskan_oleh, t.komentar, t.waktu
+        FROM persetujuan_permintaan t
+        JOIN role r ON t.role_id = r.role_id
+        WHERE t.permintaan_id = ?
+        ORDER BY t.level, t.role_id`, permintaanID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	daftar := make([]models.PersetujuanPermintaan, 0)
+	for rows.Next() {
+		var t models.PersetujuanPermintaan
+		err := rows.Scan(&t.PersetujuanID, &t.PermintaanID, &t.Level, &t.RoleID, &t.NamaRole, &t.Status, &t.DiputuskanOleh, &t.Komentar, &t.Waktu)
+		if err != nil {
+			return nil, err
+		}
+		daftar = append(daftar, t)
+	}
+	return daftar, rows.Err()
+}
+
+func (s *Store) CreatePermintaan(ctx context.Context, p *models.PermintaanDenganDetail) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	p.Status = models.StatusPermintaanDiajukan
+	if len(p.Persetujuan) == 0 {
+		p.Status = models.StatusPermintaanDisetujui
+	}
+	queryHeader := `
+        INSERT INTO permintaan_pembelian (diminta_oleh, supplier_id, gudang_tujuan_id, tanggal_butuh, catatan, total_biaya, status)
+        VALUES (?, ?, ?, ?, ?, ?, ?)`
+	result, err := tx.ExecContext(ctx, queryHeader, p.DimintaOleh, p.SupplierID, p.GudangTujuanID, p.TanggalButuh, p.Catatan, p.TotalBiaya, p.Status)
+	if err != nil {
+		return fmt.Errorf("gagal menyimpan permintaan: %w", err)
+	}
+	if p.PermintaanID, err = result.LastInsertId(); err != nil {
+		return err
+	}
+
+	queryDetail := `
+        INSERT INTO detail_permintaan (permintaan_id, produk_id, jumlah, satuan, faktor_konversi, harga_perkiraan, subtotal)
+        VALUES (?, ?, ?, ?, ?, ?, ?)`
+	for _, d := range p.Details {
+		_, err := tx.ExecContext(ctx, queryDetail, p.PermintaanID, d.ProdukID, d.Jumlah, d.Satuan, d.FaktorKonversi, d.HargaPerkiraan, d.Subtotal)
+		if err != nil {
+			return fmt.Errorf("gagal menyimpan detail permintaan: %w", err)
+		}
+	}
+	for _, t := range p.Persetujuan {
+		_, err := tx.ExecContext(ctx, `INSERT INTO persetujuan_permintaan (permintaan_id, level, role_id, status) VALUES (?, ?, ?, ?)`,
+			p.PermintaanID, t.Level, t.RoleID, models.PersetujuanMenunggu)
+		if err != nil {
+			return fmt.Errorf("gagal menyimpan tahap persetujuan: %w", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	simpan, err := s.GetPermintaan(ctx, p.PermintaanID)
+	if err != nil {
+		return err
+	}
+	*p = simpan
+	return nil
+}
+
+// kunciPermintaan mengunci baris permintaan sampai transaksi selesai dan mengembalikan statusnya
+func kunciPermintaan(ctx context.Context, tx *sql.Tx, id int64) (string, error) {
+	var status string
+	err := tx.QueryRowContext(ctx, `SELECT status FROM permintaan_pembelian WHERE permintaan_id = ? FOR UPDATE`, id).Scan(&status)
+	return status, notFound(err)
+}
+
+func (s *Store) PutuskanPersetujuan(ctx context.Context, permintaanID, persetujuanID int64, keputusan, oleh, komentar string) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	status, err := kunciPermintaan(ctx, tx, permintaanID)
+	if err != nil {
+		return err
+	}
+	daftar, err := persetujuanPermintaan(ctx, tx, permintaanID)
+	if err != nil {
+		return err
+	}
+	var tahap *models.PersetujuanPermintaan
+	for i := range daftar {
+		if daftar[i].PersetujuanID == persetujuanID {
+			tahap = &daftar[i]
+		}
+	}
+	if tahap == nil {
+		return store.ErrNotFound
+	}
+	if status != models.StatusPermintaanDiajukan || tahap.Status != models.PersetujuanMenunggu {
+		return fmt.Errorf("%w: permintaan %s, tahap %s", store.ErrInvalidTransition, status, tahap.Status)
+	}
+	masihMenunggu := false
+	for _, t := range daftar {
+		if t.Status != models.PersetujuanMenunggu {
+			continue
+		}
+		if t.Level < tahap.Level {
+			return fmt.Errorf("%w: level %d belum diputuskan", store.ErrInvalidTransition, t.Level)
+		}
+		if t.Level > tahap.Level {
+			masihMenunggu = true
+		}
+	}
+
+	_, err = tx.ExecContext(ctx, `
+        UPDATE persetujuan_permintaan SET status = ?, diputuskan_oleh = ?, komentar = ?, waktu = NOW()
+        WHERE persetujuan_id = ?`, keputusan, oleh, nullString(komentar), persetujuanID)
+	if err != nil {
+		return err
+	}
+	// Ditolak menghentikan seluruh tahap; disetujui cukup untuk satu level
+	lewati := `UPDATE persetujuan_permintaan SET status = ? WHERE permintaan_id = ? AND status = ?`
+	args := []any{models.PersetujuanDilewati, permintaanID, models.PersetujuanMenunggu}
+	if keputusan != models.PersetujuanDitolak {
+		lewati += " AND level = ?"
+		args = append(args, tahap.Level)
+	}
+	if _, err := tx.ExecContext(ctx, lewati, args...); err != nil {
+		return err
+	}
+
+	ke := ""
+	switch {
+	case keputusan == models.PersetujuanDitolak:
+		ke = models.StatusPermintaanDitolak
+	case !masihMenunggu:
+		ke = models.StatusPermintaanDisetujui
+	}
+	if ke != "" {
+		if _, err := tx.ExecContext(ctx, `UPDATE permintaan_pembelian SET status = ? WHERE permintaan_id = ?`, ke, permintaanID); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (s *Store) BatalkanPermintaan(ctx context.Context, id int64) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	status, err := kunciPermintaan(ctx, tx, id)
+	if err != nil {
+		return err
+	}
+	if !models.BolehTransisiPermintaan(status, models.StatusPermintaanDibatalkan) {
+		return fmt.Errorf("%w: dari %q ke %q", store.ErrInvalidTransition, status, models.StatusPermintaanDibatalkan)
+	}
+	_, err = tx.ExecContext(ctx, `UPDATE persetujuan_permintaan SET status = ? WHERE permintaan_id = ? AND status = ?`,
+		models.PersetujuanDilewati, id, models.PersetujuanMenunggu)
+	if err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, `UPDATE permintaan_pembelian SET status = ? WHERE permintaan_id = ?`, models.StatusPermintaanDibatalkan, id); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (s *Store) KonversiPermintaan(ctx context.Context, permintaanID int64, pb *models.Pembelian, details []models.DetailPembelian, oleh string) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	status, err := kunciPermintaan(ctx, tx, permintaanID)
+	if err != nil {
+		return err
+	}
+	if !models.BolehTransisiPermintaan(status, models.StatusPermintaanSelesai) {
+		return fmt.Errorf("%w: dari %q ke %q", store.ErrInvalidTransition, status, models.StatusPermintaanSelesai)
+	}
+	if err := createPembelianTx(ctx, tx, pb, details, oleh); err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, `UPDATE permintaan_pembelian SET status = ?, pembelian_id = ? WHERE permintaan_id = ?`,
+		models.StatusPermintaanSelesai, pb.PembelianID, permintaanID)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+const aturanColumns = "a.aturan_id, a.level, a.nilai_minimal, a.kategori, a.role_id, r.nama, a.created_at"
+
+const aturanFrom = `
+        FROM aturan_persetujuan a
+        JOIN role r ON a.role_id = r.role_id`
+
+const aturanSelect = "SELECT " + aturanColumns + aturanFrom
+
+func scanAturan(row interface{ Scan(...any) error }, a *models.AturanPersetujuan) error {
+	return row.Scan(&a.AturanID, &a.Level, &a.NilaiMinimal, &a.Kategori, &a.RoleID, &a.NamaRole, &a.CreatedAt)
+}
+
+var kolomAturan = kolomDaftar{
+	"aturan_id": "a.aturan_id", "level": "a.level", "nilai_minimal": "a.nilai_minimal",
+	"kategori": "a.kategori", "role_id": "a.role_id",
+}
+
+func (s *Store) ListAturan(ctx context.Context, q store.Kueri) ([]models.AturanPersetujuan, int, error) {
+	rows, total, err := s.queryDaftar(ctx, aturanColumns, aturanFrom, q, store.DaftarAturan, kolomAturan)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+	daftar := make([]models.AturanPersetujuan, 0)
+	for rows.Next() {
+		var a models.AturanPersetujuan
+		if err := scanAturan(rows, &a); err != nil {
+			return nil, 0, err
+		}
+		daftar = append(daftar, a)
+	}
+	return daftar, total, rows.Err()
+}
+
+func (s *Store) GetAturan(ctx context.Context, id int64) (models.AturanPersetujuan, error) {
+	var a models.AturanPersetujuan
+	err := scanAturan(s.db.QueryRowContext(ctx, aturanSelect+" WHERE a.aturan_id = ?", id), &a)
+	return a, notFound(err)
+}
+
+func (s *Store) CreateAturan(ctx context.Context, a *models.AturanPersetujuan) error {
+	result, err := s.db.ExecContext(ctx, `INSERT INTO aturan_persetujuan (level, nilai_minimal, kategori, role_id) VALUES (?, ?, ?, ?)`,
+		a.Level, a.NilaiMinimal, a.Kategori, a.RoleID)
+	if err != nil {
+		return err
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	*a, err = s.GetAturan(ctx, id)
+	return err
+}
+
+func (s *Store) UpdateAturan(ctx context.Context, a models.AturanPersetujuan) error {
+	result, err := s.db.ExecContext(ctx, `UPDATE aturan_persetujuan SET level = ?, nilai_minimal = ?, kategori = ?, role_id = ? WHERE aturan_id = ?`,
+		a.Level, a.NilaiMinimal, a.Kategori, a.RoleID, a.AturanID)
+	if err != nil {
+		return err
+	}
+	return checkAffected(result)
+}
+
+func (s *Store) DeleteAturan(ctx context.Context, id int64) error {
+	result, err := s.db.ExecContext(ctx, `DELETE FROM aturan_persetujuan WHERE aturan_id = ?`, id)
+	if err != nil {
+		return err
+	}
+	return checkAffected(result)
+}
@@ -0,0 +1,89 @@
+// file: internal/store/mysql/produk.go
+
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"log"
+
+	"scm-api/internal/models"
+	"scm-api/internal/store"
+)
+
+const produkColumns = "produk_id, sku, nama_produk, deskripsi, kategori, satuan, harga_jual, berat_kg, gambar_produk, gambar_thumbnail, supplier_id, deleted_at"
+
+var kolomProduk = kolomDaftar{
+	"produk_id": "produk_id", "sku": "sku", "nama_produk": "nama_produk", "kategori": "kategori",
+	"satuan": "satuan", "harga_jual": "harga_jual", "supplier_id": "supplier_id", "deleted_at": "deleted_at",
+}
+
+func (s *Store) ListProduk(ctx context.Context, q store.Kueri) ([]models.Produk, int, error) {
+	rows, total, err := s.queryDaftar(ctx, produkColumns, "FROM produk", q, store.DaftarProduk, kolomProduk)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+	daftarProduk := make([]models.Produk, 0)
+	for rows.Next() {
+		var p models.Produk
+		err := rows.Scan(&p.ProdukID, &p.SKU, &p.NamaProduk, &p.Deskripsi, &p.Kategori, &p.Satuan, &p.HargaJual, &p.BeratKg, &p.GambarProduk, &p.GambarThumbnail, &p.SupplierID, &p.DeletedAt)
+		if err != nil {
+			log.Printf("Error scanning row produk: %v", err)
+			continue
+		}
+		daftarProduk = append(daftarProduk, p)
+	}
+	return daftarProduk, total, rows.Err()
+}
+
+func (s *Store) GetProduk(ctx context.Context, id int64) (models.Produk, error) {
+	var p models.Produk
+	row := s.db.QueryRowContext(ctx, "SELECT "+produkColumns+" FROM produk WHERE produk_id = ?", id)
+	err := row.Scan(&p.ProdukID, &p.SKU, &p.NamaProduk, &p.Deskripsi, &p.Kategori, &p.Satuan, &p.HargaJual, &p.BeratKg, &p.GambarProduk, &p.GambarThumbnail, &p.SupplierID, &p.DeletedAt)
+	return p, notFound(err)
+}
+
+func (s *Store) CreateProduk(ctx context.Context, p *models.Produk) error {
+	query := `INSERT INTO produk (sku, nama_produk, deskripsi, kategori, satuan, harga_jual, berat_kg, supplier_id) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`
+	result, err := s.db.ExecContext(ctx, query, p.SKU, p.NamaProduk, p.Deskripsi, p.Kategori, p.Satuan, p.HargaJual, p.BeratKg, p.SupplierID)
+	if err != nil {
+		return duplikat(err)
+	}
+	p.ProdukID, err = result.LastInsertId()
+	return err
+}
+
+func (s *Store) UpdateProduk(ctx context.Context, p models.Produk) error {
+	query := `UPDATE produk SET sku = ?, nama_produk = ?, deskripsi = ?, kategori = ?, satuan = ?, harga_jual = ?, berat_kg = ?, supplier_id = ? WHERE produk_id = ?`
+	_, err := s.db.ExecContext(ctx, query, p.SKU, p.NamaProduk, p.Deskripsi, p.Kategori, p.Satuan, p.HargaJual, p.BeratKg, p.SupplierID, p.ProdukID)
+	return duplikat(err)
+}
+
+func (s *Store) SetGambarProduk(ctx context.Context, id int64, gambar, thumbnail sql.NullString) error {
+	result, err := s.db.ExecContext(ctx, `UPDATE produk SET gambar_produk = ?, gambar_thumbnail = ? WHERE produk_id = ?`, gambar, thumbnail, id)
+	if err != nil {
+		return err
+	}
+	return checkAffected(result)
+}
+
+func (s *Store) DeleteProduk(ctx context.Context, id int64) error {
+	result, err := s.db.ExecContext(ctx, `UPDATE produk SET deleted_at = NOW() WHERE produk_id = ? AND deleted_at IS NULL`, id)
+	if err != nil {
+		return err
+	}
+	return checkAffected(result)
+}
+
+func (s *Store) PulihkanProduk(ctx context.Context, id int64) error {
+	result, err := s.db.ExecContext(ctx, `UPDATE produk SET deleted_at = NULL WHERE produk_id = ? AND deleted_at IS NOT NULL`, id)
+	if err != nil {
+		return duplikat(err)
+	}
+	return checkAffected(result)
+}
+
+func (s *Store) CountProduk(ctx context.Context) (int, error) {
+	return s.count(ctx, "SELECT COUNT(*) FROM produk WHERE deleted_at IS NULL")
+}
@@ -0,0 +1,207 @@
+// file: internal/store/mysql/replenishment.go
+
+package mysql
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	"scm-api/internal/models"
+)
+
+const parameterSelect = `
+        SELECT sp.parameter_id, sp.produk_id, p.nama_produk, sp.gudang_id, g.nama_gudang,
+               sp.reorder_point, sp.safety_stock, sp.max_level, sp.updated_at
+        FROM stok_parameter sp
+        JOIN produk p ON sp.produk_id = p.produk_id
+        JOIN gudang g ON sp.gudang_id = g.gudang_id`
+
+func scanParameter(row interface{ Scan(...any) error }, p *models.ParameterStok) error {
+	return row.Scan(&p.ParameterID, &p.ProdukID, &p.NamaProduk, &p.GudangID, &p.NamaGudang,
+		&p.ReorderPoint, &p.SafetyStock, &p.MaxLevel, &p.UpdatedAt)
+}
+
+func (s *Store) ListParameterStok(ctx context.Context, produkID, gudangID int64) ([]models.ParameterStok, error) {
+	query := parameterSelect + " WHERE 1 = 1"
+	args := make([]any, 0)
+	if produkID != 0 {
+		query += " AND sp.produk_id = ?"
+		args = append(args, produkID)
+	}
+	if gudangID != 0 {
+		query += " AND sp.gudang_id = ?"
+		args = append(args, gudangID)
+	}
+	rows, err := s.db.QueryContext(ctx, query+" ORDER BY sp.produk_id, sp.gudang_id", args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	daftar := make([]models.ParameterStok, 0)
+	for rows.Next() {
+		var p models.ParameterStok
+		if err := scanParameter(rows, &p); err != nil {
+			return nil, err
+		}
+		daftar = append(daftar, p)
+	}
+	return daftar, rows.Err()
+}
+
+func (s *Store) KandidatSaran(ctx context.Context, gudangID int64) ([]models.KandidatSaran, error) {
+	query := `
+        SELECT sp.parameter_id, sp.produk_id, p.nama_produk, sp.gudang_id, g.nama_gudang,
+               sp.reorder_point, sp.safety_stock, sp.max_level, sp.updated_at,
+               COALESCE(st.jumlah, 0), p.satuan, p.supplier_id, IF(sup.deleted_at IS NULL, sup.nama_supplier, NULL)
+        FROM stok_parameter sp
+        JOIN produk p ON sp.produk_id = p.produk_id AND p.deleted_at IS NULL
+        JOIN gudang g ON sp.gudang_id = g.gudang_id AND g.deleted_at IS NULL
+        LEFT JOIN stok st ON st.produk_id = sp.produk_id AND st.gudang_id = sp.gudang_id
+        LEFT JOIN supplier sup ON p.supplier_id = sup.supplier_id`
+	args := make([]any, 0)
+	if gudangID != 0 {
+		query += " WHERE sp.gudang_id = ?"
+		args = append(args, gudangID)
+	}
+	rows, err := s.db.QueryContext(ctx, query+" ORDER BY sp.produk_id, sp.gudang_id", args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	daftar := make([]models.KandidatSaran, 0)
+	for rows.Next() {
+		var k models.KandidatSaran
+		p := &k.ParameterStok
+		err := rows.Scan(&p.ParameterID, &p.ProdukID, &p.NamaProduk, &p.GudangID, &p.NamaGudang,
+			&p.ReorderPoint, &p.SafetyStock, &p.MaxLevel, &p.UpdatedAt,
+			&k.Stok, &k.SatuanDasar, &k.SupplierID, &k.NamaSupplier)
+		if err != nil {
+			return nil, err
+		}
+		daftar = append(daftar, k)
+	}
+	return daftar, rows.Err()
+}
+
+func (s *Store) GetParameterStok(ctx context.Context, produkID, gudangID int64) (models.ParameterStok, error) {
+	var p models.ParameterStok
+	row := s.db.QueryRowContext(ctx, parameterSelect+" WHERE sp.produk_id = ? AND sp.gudang_id = ?", produkID, gudangID)
+	return p, notFound(scanParameter(row, &p))
+}
+
+func (s *Store) SimpanParameterStok(ctx context.Context, p *models.ParameterStok) error {
+	query := `
+        INSERT INTO stok_parameter (produk_id, gudang_id, reorder_point, safety_stock, max_level)
+        VALUES (?, ?, ?, ?, ?)
+        ON DUPLICATE KEY UPDATE reorder_point = VALUES(reorder_point), safety_stock = VALUES(safety_stock),
+            max_level = VALUES(max_level), updated_at = NOW()`
+	if _, err := s.db.ExecContext(ctx, query, p.ProdukID, p.GudangID, p.ReorderPoint, p.SafetyStock, p.MaxLevel); err != nil {
+		return err
+	}
+	simpan, err := s.GetParameterStok(ctx, p.ProdukID, p.GudangID)
+	if err != nil {
+		return err
+	}
+	*p = simpan
+	return nil
+}
+
+func (s *Store) HapusParameterStok(ctx context.Context, produkID, gudangID int64) error {
+	result, err := s.db.ExecContext(ctx, `DELETE FROM stok_parameter WHERE produk_id = ? AND gudang_id = ?`, produkID, gudangID)
+	if err != nil {
+		return err
+	}
+	return checkAffected(result)
+}
+
+func (s *Store) PesananTerbuka(ctx context.Context) ([]models.PesananTerbuka, error) {
+	query := `
+        SELECT d.produk_id, p.gudang_tujuan_id,
+               SUM((d.jumlah - COALESCE(r.datang, 0)) * d.faktor_konversi)
+        FROM pembelian p
+        JOIN detail_pembelian d ON d.pembelian_id = p.pembelian_id
+        LEFT JOIN (
+            SELECT dp.detail_pembelian_id, SUM(dp.jumlah_diterima + dp.jumlah_ditolak) AS datang
+            FROM detail_penerimaan dp
+            JOIN penerimaan pn ON pn.penerimaan_id = dp.penerimaan_id
+            JOIN pembelian pb ON pb.pembelian_id = pn.pembelian_id
+            WHERE pb.status IN (?, ?, ?, ?)
+            GROUP BY dp.detail_pembelian_id
+        ) r ON r.detail_pembelian_id = d.detail_pembelian_id
+        WHERE p.status IN (?, ?, ?, ?) AND p.gudang_tujuan_id IS NOT NULL
+          AND d.jumlah > COALESCE(r.datang, 0)
+        GROUP BY d.produk_id, p.gudang_tujuan_id
+        ORDER BY d.produk_id, p.gudang_tujuan_id`
+	// Status yang sama membatasi agregat penerimaan dan pesanannya, sehingga
+	// penerimaan pesanan yang sudah selesai tidak ikut dijumlah
+	terbuka := []any{models.StatusPembelianDraft, models.StatusPembelianDipesan,
+		models.StatusPembelianDikirim, models.StatusPembelianDiterimaSebagian}
+	rows, err := s.db.QueryContext(ctx, query, slices.Concat(terbuka, terbuka)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	daftar := make([]models.PesananTerbuka, 0)
+	for rows.Next() {
+		var t models.PesananTerbuka
+		if err := rows.Scan(&t.ProdukID, &t.GudangID, &t.Jumlah); err != nil {
+			return nil, err
+		}
+		daftar = append(daftar, t)
+	}
+	return daftar, rows.Err()
+}
+
+func (s *Store) PemakaianHarian(ctx context.Context, dari, sampai string, produkID, gudangID int64, tipe []string) ([]models.PemakaianHarian, error) {
+	if len(tipe) == 0 {
+		return make([]models.PemakaianHarian, 0), nil
+	}
+	awal, err := time.Parse("2006-01-02", dari)
+	if err != nil {
+		return nil, err
+	}
+	akhir, err := time.Parse("2006-01-02", sampai)
+	if err != nil {
+		return nil, err
+	}
+	query := `
+        SELECT produk_id, gudang_id, DATE(waktu) AS tanggal, SUM(-perubahan)
+        FROM stok_mutasi
+        WHERE perubahan < 0 AND tipe IN (?` + strings.Repeat(", ?", len(tipe)-1) + `)
+          AND waktu >= ? AND waktu < ?`
+	args := make([]any, 0, len(tipe)+4)
+	for _, t := range tipe {
+		args = append(args, t)
+	}
+	// sampai bersifat inklusif, jadi batas atasnya awal hari berikutnya
+	args = append(args, awal.Format(formatDatetime), akhir.AddDate(0, 0, 1).Format(formatDatetime))
+	if produkID != 0 {
+		query += " AND produk_id = ?"
+		args = append(args, produkID)
+	}
+	if gudangID != 0 {
+		query += " AND gudang_id = ?"
+		args = append(args, gudangID)
+	}
+	query += " GROUP BY produk_id, gudang_id, DATE(waktu) ORDER BY produk_id, gudang_id, tanggal"
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	daftar := make([]models.PemakaianHarian, 0)
+	for rows.Next() {
+		var p models.PemakaianHarian
+		if err := rows.Scan(&p.ProdukID, &p.GudangID, &p.Tanggal, &p.Jumlah); err != nil {
+			return nil, err
+		}
+		if len(p.Tanggal) > 10 {
+			p.Tanggal = p.Tanggal[:10]
+		}
+		daftar = append(daftar, p)
+	}
+	return daftar, rows.Err()
+}
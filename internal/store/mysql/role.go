@@ -0,0 +1,179 @@
+// file: internal/store/mysql/role.go
+
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"log"
+
+	"scm-api/internal/models"
+)
+
+func (s *Store) ListIzin(ctx context.Context) ([]models.Izin, error) {
+	rows, err := s.db.QueryContext(ctx, "SELECT kode, deskripsi FROM izin ORDER BY kode")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	daftarIzin := make([]models.Izin, 0)
+	for rows.Next() {
+		var iz models.Izin
+		if err := rows.Scan(&iz.Kode, &iz.Deskripsi); err != nil {
+			log.Printf("Error scanning row izin: %v", err)
+			continue
+		}
+		daftarIzin = append(daftarIzin, iz)
+	}
+	return daftarIzin, rows.Err()
+}
+
+func (s *Store) ListRole(ctx context.Context) ([]models.Role, error) {
+	return s.queryRole(ctx, "SELECT role_id, nama, COALESCE(deskripsi, '') FROM role ORDER BY role_id")
+}
+
+func (s *Store) RolePengguna(ctx context.Context, penggunaID int64) ([]models.Role, error) {
+	if _, err := s.GetPengguna(ctx, penggunaID); err != nil {
+		return nil, err
+	}
+	return s.queryRole(ctx, `
+        SELECT r.role_id, r.nama, COALESCE(r.deskripsi, '')
+        FROM role r
+        JOIN pengguna_role pr ON pr.role_id = r.role_id
+        WHERE pr.pengguna_id = ?
+        ORDER BY r.role_id`, penggunaID)
+}
+
+// queryRole menjalankan query role lalu melengkapi setiap role dengan kode izinnya
+func (s *Store) queryRole(ctx context.Context, query string, args ...any) ([]models.Role, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	daftarRole := make([]models.Role, 0)
+	indeks := make(map[int64]int)
+	for rows.Next() {
+		r := models.Role{Izin: make([]string, 0)}
+		if err := rows.Scan(&r.RoleID, &r.Nama, &r.Deskripsi); err != nil {
+			log.Printf("Error scanning row role: %v", err)
+			continue
+		}
+		indeks[r.RoleID] = len(daftarRole)
+		daftarRole = append(daftarRole, r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	rows, err = s.db.QueryContext(ctx, "SELECT role_id, kode_izin FROM role_izin ORDER BY role_id, kode_izin")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var roleID int64
+		var kode string
+		if err := rows.Scan(&roleID, &kode); err != nil {
+			log.Printf("Error scanning row role_izin: %v", err)
+			continue
+		}
+		if i, ok := indeks[roleID]; ok {
+			daftarRole[i].Izin = append(daftarRole[i].Izin, kode)
+		}
+	}
+	return daftarRole, rows.Err()
+}
+
+func (s *Store) CreateRole(ctx context.Context, r *models.Role) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, "INSERT INTO role (nama, deskripsi) VALUES (?, ?)", r.Nama, nullString(r.Deskripsi))
+	if err != nil {
+		return duplikat(err)
+	}
+	if r.RoleID, err = result.LastInsertId(); err != nil {
+		return err
+	}
+	if err := simpanIzinRoleTx(ctx, tx, r.RoleID, r.Izin); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (s *Store) UbahIzinRole(ctx context.Context, roleID int64, izin []string) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var id int64
+	if err := tx.QueryRowContext(ctx, "SELECT role_id FROM role WHERE role_id = ? FOR UPDATE", roleID).Scan(&id); err != nil {
+		return notFound(err)
+	}
+	if _, err := tx.ExecContext(ctx, "DELETE FROM role_izin WHERE role_id = ?", roleID); err != nil {
+		return err
+	}
+	if err := simpanIzinRoleTx(ctx, tx, roleID, izin); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func simpanIzinRoleTx(ctx context.Context, tx *sql.Tx, roleID int64, izin []string) error {
+	for _, kode := range izin {
+		if _, err := tx.ExecContext(ctx, "INSERT INTO role_izin (role_id, kode_izin) VALUES (?, ?)", roleID, kode); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Store) SetRolePengguna(ctx context.Context, penggunaID int64, roleIDs []int64) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var id int64
+	if err := tx.QueryRowContext(ctx, "SELECT pengguna_id FROM pengguna WHERE pengguna_id = ? FOR UPDATE", penggunaID).Scan(&id); err != nil {
+		return notFound(err)
+	}
+	if _, err := tx.ExecContext(ctx, "DELETE FROM pengguna_role WHERE pengguna_id = ?", penggunaID); err != nil {
+		return err
+	}
+	for _, roleID := range roleIDs {
+		if _, err := tx.ExecContext(ctx, "INSERT INTO pengguna_role (pengguna_id, role_id) VALUES (?, ?)", penggunaID, roleID); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (s *Store) IzinPengguna(ctx context.Context, penggunaID int64) ([]string, error) {
+	rows, err := s.db.QueryContext(ctx, `
+        SELECT DISTINCT ri.kode_izin
+        FROM pengguna_role pr
+        JOIN role_izin ri ON ri.role_id = pr.role_id
+        WHERE pr.pengguna_id = ?
+        ORDER BY ri.kode_izin`, penggunaID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	izin := make([]string, 0)
+	for rows.Next() {
+		var kode string
+		if err := rows.Scan(&kode); err != nil {
+			return nil, err
+		}
+		izin = append(izin, kode)
+	}
+	return izin, rows.Err()
+}
@@ -0,0 +1,70 @@
+// file: internal/store/mysql/satuan.go
+
+package mysql
+
+import (
+	"context"
+	"log"
+
+	"scm-api/internal/models"
+)
+
+const satuanColumns = "satuan_id, produk_id, satuan, faktor, created_at"
+
+func scanSatuan(row interface{ Scan(...any) error }, ps *models.ProdukSatuan) error {
+	return row.Scan(&ps.SatuanID, &ps.ProdukID, &ps.Satuan, &ps.Faktor, &ps.CreatedAt)
+}
+
+func (s *Store) ListSatuanProduk(ctx context.Context, produkID int64) ([]models.ProdukSatuan, error) {
+	rows, err := s.db.QueryContext(ctx, "SELECT "+satuanColumns+" FROM produk_satuan WHERE produk_id = ? ORDER BY faktor, satuan_id", produkID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	daftar := make([]models.ProdukSatuan, 0)
+	for rows.Next() {
+		var ps models.ProdukSatuan
+		if err := scanSatuan(rows, &ps); err != nil {
+			log.Printf("Error scanning row produk_satuan: %v", err)
+			continue
+		}
+		daftar = append(daftar, ps)
+	}
+	return daftar, rows.Err()
+}
+
+func (s *Store) CariSatuan(ctx context.Context, produkID int64, satuan string) (models.ProdukSatuan, error) {
+	var ps models.ProdukSatuan
+	err := scanSatuan(s.db.QueryRowContext(ctx, "SELECT "+satuanColumns+" FROM produk_satuan WHERE produk_id = ? AND satuan = ?", produkID, satuan), &ps)
+	return ps, notFound(err)
+}
+
+func (s *Store) CreateSatuan(ctx context.Context, ps *models.ProdukSatuan) error {
+	result, err := s.db.ExecContext(ctx, `INSERT INTO produk_satuan (produk_id, satuan, faktor) VALUES (?, ?, ?)`, ps.ProdukID, ps.Satuan, ps.Faktor)
+	if err != nil {
+		return duplikat(err)
+	}
+	if ps.SatuanID, err = result.LastInsertId(); err != nil {
+		return err
+	}
+	dibuat, err := s.CariSatuan(ctx, ps.ProdukID, ps.Satuan)
+	ps.CreatedAt = dibuat.CreatedAt
+	return err
+}
+
+func (s *Store) UpdateSatuan(ctx context.Context, ps models.ProdukSatuan) error {
+	// faktor yang sama tidak dihitung sebagai baris berubah, jadi keberadaannya diperiksa dulu
+	if _, err := s.CariSatuan(ctx, ps.ProdukID, ps.Satuan); err != nil {
+		return err
+	}
+	_, err := s.db.ExecContext(ctx, `UPDATE produk_satuan SET faktor = ? WHERE produk_id = ? AND satuan = ?`, ps.Faktor, ps.ProdukID, ps.Satuan)
+	return err
+}
+
+func (s *Store) DeleteSatuan(ctx context.Context, produkID int64, satuan string) error {
+	result, err := s.db.ExecContext(ctx, `DELETE FROM produk_satuan WHERE produk_id = ? AND satuan = ?`, produkID, satuan)
+	if err != nil {
+		return err
+	}
+	return checkAffected(result)
+}
@@ -0,0 +1,57 @@
+// file: internal/store/mysql/scorecard.go
+
+package mysql
+
+import (
+	"context"
+	"slices"
+
+	"scm-api/internal/models"
+)
+
+func (s *Store) BarisScorecard(ctx context.Context, dari, sampai string) ([]models.BarisScorecard, error) {
+	query := `
+        SELECT
+            p.pembelian_id, p.supplier_id, p.status, p.tanggal_pesan, p.estimasi_tiba, t.terakhir,
+            d.produk_id, d.jumlah, d.faktor_konversi, d.harga_beli_satuan,
+            COALESCE(r.diterima, 0), COALESCE(r.ditolak, 0)
+        FROM pembelian p
+        JOIN detail_pembelian d ON d.pembelian_id = p.pembelian_id
+        LEFT JOIN (
+            SELECT dp.detail_pembelian_id, SUM(dp.jumlah_diterima) AS diterima, SUM(dp.jumlah_ditolak) AS ditolak
+            FROM detail_penerimaan dp
+            JOIN penerimaan pn ON pn.penerimaan_id = dp.penerimaan_id
+            JOIN pembelian pb ON pb.pembelian_id = pn.pembelian_id
+            WHERE pb.tanggal_pesan BETWEEN ? AND ? AND pb.status NOT IN (?, ?)
+            GROUP BY dp.detail_pembelian_id
+        ) r ON r.detail_pembelian_id = d.detail_pembelian_id
+        LEFT JOIN (
+            SELECT pn.pembelian_id, MAX(pn.tanggal_terima) AS terakhir
+            FROM penerimaan pn
+            JOIN pembelian pb ON pb.pembelian_id = pn.pembelian_id
+            WHERE pb.tanggal_pesan BETWEEN ? AND ? AND pb.status NOT IN (?, ?)
+            GROUP BY pn.pembelian_id
+        ) t ON t.pembelian_id = p.pembelian_id
+        WHERE p.tanggal_pesan BETWEEN ? AND ? AND p.status NOT IN (?, ?)
+        ORDER BY p.pembelian_id, d.detail_pembelian_id`
+	// Periode dan status yang sama membatasi kedua agregat penerimaan, agar tidak
+	// menjumlah penerimaan seluruh riwayat setiap kali scorecard dihitung
+	periode := []any{dari, sampai, models.StatusPembelianDraft, models.StatusPembelianDibatalkan}
+	rows, err := s.db.QueryContext(ctx, query, slices.Concat(periode, periode, periode)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	daftar := make([]models.BarisScorecard, 0)
+	for rows.Next() {
+		var b models.BarisScorecard
+		err := rows.Scan(&b.PembelianID, &b.SupplierID, &b.Status, &b.TanggalPesan, &b.EstimasiTiba, &b.TerimaTerakhir,
+			&b.ProdukID, &b.Jumlah, &b.FaktorKonversi, &b.HargaBeliSatuan, &b.JumlahDiterima, &b.JumlahDitolak)
+		if err != nil {
+			return nil, err
+		}
+		b.EstimasiTiba = tanggalSaja(b.EstimasiTiba)
+		daftar = append(daftar, b)
+	}
+	return daftar, rows.Err()
+}
@@ -0,0 +1,205 @@
+// file: internal/store/mysql/stok.go
+
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+
+	"scm-api/internal/models"
+	"scm-api/internal/store"
+)
+
+var kolomStok = kolomDaftar{
+	"stok_id": "s.stok_id", "produk_id": "s.produk_id", "nama_produk": "p.nama_produk",
+	"gudang_id": "s.gudang_id", "nama_gudang": "g.nama_gudang", "jumlah": "s.jumlah", "tanggal_update": "s.tanggal_update",
+}
+
+func (s *Store) ListStok(ctx context.Context, q store.Kueri) ([]models.StokResponse, int, error) {
+	pilih := `
+            s.stok_id, s.produk_id, p.nama_produk,
+            s.gudang_id, g.nama_gudang, s.jumlah, s.tanggal_update`
+	dari := `
+        FROM stok s
+        JOIN produk p ON s.produk_id = p.produk_id
+        JOIN gudang g ON s.gudang_id = g.gudang_id`
+	rows, total, err := s.queryDaftar(ctx, pilih, dari, q, store.DaftarStok, kolomStok)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	daftarStok := make([]models.StokResponse, 0)
+	for rows.Next() {
+		var st models.StokResponse
+		err := rows.Scan(
+			&st.StokID, &st.ProdukID, &st.NamaProduk,
+			&st.GudangID, &st.NamaGudang, &st.Jumlah, &st.TanggalUpdate,
+		)
+		if err != nil {
+			log.Printf("Error scanning row stok: %v", err)
+			continue
+		}
+		daftarStok = append(daftarStok, st)
+	}
+	return daftarStok, total, rows.Err()
+}
+
+func (s *Store) AdjustStok(ctx context.Context, produkID, gudangID int64, jumlah int, oleh, catatan string) (models.StokMutasi, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return models.StokMutasi{}, err
+	}
+	defer tx.Rollback()
+
+	m := models.StokMutasi{
+		ProdukID:   produkID,
+		GudangID:   gudangID,
+		Tipe:       models.MutasiPenyesuaian,
+		DibuatOleh: oleh,
+		Catatan:    nullString(catatan),
+	}
+	if _, err := mutasiStokTx(ctx, tx, &m, func(int) int { return jumlah }, nil); err != nil {
+		return m, err
+	}
+	return m, tx.Commit()
+}
+
+func (s *Store) CatatPenjualan(ctx context.Context, produkID, gudangID int64, jumlah int, oleh, catatan string) (models.StokMutasi, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return models.StokMutasi{}, err
+	}
+	defer tx.Rollback()
+
+	m := models.StokMutasi{
+		ProdukID:   produkID,
+		GudangID:   gudangID,
+		Tipe:       models.MutasiPenjualan,
+		DibuatOleh: oleh,
+		Catatan:    nullString(catatan),
+	}
+	if _, err := mutasiStokTx(ctx, tx, &m, func(sebelum int) int { return sebelum - jumlah }, nil); err != nil {
+		return m, err
+	}
+	return m, tx.Commit()
+}
+
+// mutasiStokTx adalah satu-satunya jalan untuk mengubah tabel stok. Baris stok dikunci,
+// jumlah baru dihitung dari jumlah lama lewat jumlahBaru, ditulis dengan UPSERT, lalu
+// perubahannya dicatat di stok_mutasi dalam transaksi yang sama. Jumlah baru yang
+// negatif ditolak dengan store.ErrStokTidakCukup.
+//
+// Stok yang bertambah dicatat sebagai batch baru jika masuk tidak nil. Stok yang
+// berkurang diambil dari batch secara FEFO, dan batch yang terpakai dikembalikan.
+func mutasiStokTx(ctx context.Context, tx *sql.Tx, m *models.StokMutasi, jumlahBaru func(sebelum int) int, masuk *models.StokBatch) ([]models.PemakaianBatch, error) {
+	err := tx.QueryRowContext(ctx, "SELECT jumlah FROM stok WHERE produk_id = ? AND gudang_id = ? FOR UPDATE", m.ProdukID, m.GudangID).Scan(&m.JumlahSebelum)
+	if err == sql.ErrNoRows {
+		m.JumlahSebelum = 0
+	} else if err != nil {
+		return nil, err
+	}
+	m.JumlahSesudah = jumlahBaru(m.JumlahSebelum)
+	m.Perubahan = m.JumlahSesudah - m.JumlahSebelum
+	if m.JumlahSesudah < 0 {
+		return nil, fmt.Errorf("%w: produk %d di gudang %d hanya %d, dibutuhkan %d", store.ErrStokTidakCukup, m.ProdukID, m.GudangID, m.JumlahSebelum, -m.Perubahan)
+	}
+
+	// Query UPSERT: Insert data baru, tapi jika terjadi duplikasi pada unique key
+	// (produk_id, gudang_id), maka update kolom jumlah.
+	queryStok := `
+        INSERT INTO stok (produk_id, gudang_id, jumlah, tanggal_update)
+        VALUES (?, ?, ?, NOW())
+        ON DUPLICATE KEY UPDATE jumlah = VALUES(jumlah), tanggal_update = NOW()
+    `
+	if _, err := tx.ExecContext(ctx, queryStok, m.ProdukID, m.GudangID, m.JumlahSesudah); err != nil {
+		return nil, fmt.Errorf("gagal upsert stok untuk produk ID %d: %w", m.ProdukID, err)
+	}
+
+	var pemakaian []models.PemakaianBatch
+	if m.Perubahan < 0 {
+		if pemakaian, err = pakaiBatchTx(ctx, tx, m, -m.Perubahan); err != nil {
+			return nil, err
+		}
+	} else if m.Perubahan > 0 && masuk != nil {
+		if err := tambahBatchTx(ctx, tx, m, masuk, m.Perubahan); err != nil {
+			return nil, err
+		}
+	}
+
+	queryMutasi := `
+        INSERT INTO stok_mutasi (produk_id, gudang_id, tipe, jumlah_sebelum, jumlah_sesudah, perubahan, referensi_tipe, referensi_id, dibuat_oleh, catatan, waktu)
+        VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, NOW())
+    `
+	result, err := tx.ExecContext(ctx, queryMutasi, m.ProdukID, m.GudangID, m.Tipe, m.JumlahSebelum, m.JumlahSesudah, m.Perubahan, m.ReferensiTipe, m.ReferensiID, m.DibuatOleh, m.Catatan)
+	if err != nil {
+		return nil, fmt.Errorf("gagal mencatat mutasi stok: %w", err)
+	}
+	m.MutasiID, _ = result.LastInsertId()
+	return pemakaian, nil
+}
+
+// formatDatetime dipakai agar batas tanggal dibandingkan apa adanya dengan kolom
+// DATETIME, tanpa dikonversi zona waktu oleh driver
+const formatDatetime = "2006-01-02 15:04:05"
+
+var kolomMutasi = kolomDaftar{
+	"mutasi_id": "mutasi_id", "produk_id": "produk_id", "gudang_id": "gudang_id", "tipe": "tipe",
+	"referensi_tipe": "referensi_tipe", "perubahan": "perubahan", "waktu": "waktu",
+}
+
+func (s *Store) ListMutasiStok(ctx context.Context, q store.Kueri) ([]models.StokMutasi, int, error) {
+	pilih := `
+            mutasi_id, produk_id, gudang_id, tipe, jumlah_sebelum, jumlah_sesudah, perubahan,
+            referensi_tipe, referensi_id, dibuat_oleh, catatan, waktu`
+	rows, total, err := s.queryDaftar(ctx, pilih, "FROM stok_mutasi", q, store.DaftarMutasi, kolomMutasi)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+	daftar := make([]models.StokMutasi, 0)
+	for rows.Next() {
+		var m models.StokMutasi
+		err := rows.Scan(&m.MutasiID, &m.ProdukID, &m.GudangID, &m.Tipe, &m.JumlahSebelum, &m.JumlahSesudah, &m.Perubahan,
+			&m.ReferensiTipe, &m.ReferensiID, &m.DibuatOleh, &m.Catatan, &m.Waktu)
+		if err != nil {
+			return nil, 0, err
+		}
+		daftar = append(daftar, m)
+	}
+	return daftar, total, rows.Err()
+}
+
+func (s *Store) StokPerProduk(ctx context.Context) (models.StokChartResponse, error) {
+	query := `
+        SELECT p.nama_produk, SUM(s.jumlah) as total_stok
+        FROM stok s
+        JOIN produk p ON s.produk_id = p.produk_id
+        GROUP BY p.nama_produk
+        ORDER BY total_stok DESC
+    `
+	// Siapkan struct untuk diisi
+	var chartData models.StokChartResponse
+	chartData.Labels = make([]string, 0)
+	chartData.Data = make([]int, 0)
+
+	rows, err := s.db.QueryContext(ctx, query)
+	if err != nil {
+		return chartData, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var namaProduk string
+		var totalStok int
+		if err := rows.Scan(&namaProduk, &totalStok); err != nil {
+			log.Printf("Error scanning row stok chart: %v", err)
+			continue
+		}
+		chartData.Labels = append(chartData.Labels, namaProduk)
+		chartData.Data = append(chartData.Data, totalStok)
+	}
+	return chartData, rows.Err()
+}
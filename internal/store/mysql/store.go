@@ -0,0 +1,55 @@
+// file: internal/store/mysql/store.go
+
+// Package mysql berisi implementasi store yang menyimpan data di MariaDB/MySQL
+package mysql
+
+import (
+	"context"
+	"database/sql"
+
+	"scm-api/internal/store"
+)
+
+// Store mengimplementasikan seluruh antarmuka di package store di atas *sql.DB
+type Store struct {
+	db *sql.DB
+}
+
+var _ store.Store = (*Store)(nil)
+
+// New membuat Store baru dari koneksi database yang sudah terbuka
+func New(db *sql.DB) *Store {
+	return &Store{db: db}
+}
+
+// count menjalankan query COUNT(*) sederhana
+func (s *Store) count(ctx context.Context, query string) (int, error) {
+	var n int
+	err := s.db.QueryRowContext(ctx, query).Scan(&n)
+	return n, err
+}
+
+// checkAffected mengubah hasil UPDATE/DELETE yang tidak menyentuh baris apa pun menjadi store.ErrNotFound
+func checkAffected(result sql.Result) error {
+	n, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return store.ErrNotFound
+	}
+	return nil
+}
+
+// nullString mengubah string kosong menjadi NULL
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
+
+// notFound mengubah sql.ErrNoRows menjadi store.ErrNotFound
+func notFound(err error) error {
+	if err == sql.ErrNoRows {
+		return store.ErrNotFound
+	}
+	return err
+}
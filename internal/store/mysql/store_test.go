@@ -0,0 +1,83 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"testing"
+
+	"scm-api/internal/database"
+	"scm-api/internal/store"
+	"scm-api/internal/store/storetest"
+
+	_ "github.com/go-sql-driver/mysql"
+)
+
+// TestStore menjalankan skenario yang sama dengan store memory. Tes ini
+// mengosongkan database pada SCM_TEST_DATABASE_DSN, jadi jangan arahkan ke
+// database yang datanya dipakai.
+func TestStore(t *testing.T) {
+	dsn := os.Getenv("SCM_TEST_DATABASE_DSN")
+	if dsn == "" {
+		t.Skip("SCM_TEST_DATABASE_DSN tidak diisi")
+	}
+	db, err := sql.Open("mysql", dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	storetest.Jalankan(t, func(t *testing.T) store.Store {
+		t.Helper()
+		ctx := context.Background()
+		if err := kosongkan(ctx, db); err != nil {
+			t.Fatalf("mengosongkan database: %v", err)
+		}
+		m, err := database.NewMigrator(db)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := m.Up(ctx, 0); err != nil {
+			t.Fatalf("migrasi: %v", err)
+		}
+		return New(db)
+	})
+}
+
+// kosongkan menghapus semua tabel agar setiap skenario mulai dari migrasi awal
+func kosongkan(ctx context.Context, db *sql.DB) error {
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	rows, err := conn.QueryContext(ctx, `SELECT table_name FROM information_schema.tables WHERE table_schema = DATABASE()`)
+	if err != nil {
+		return err
+	}
+	var tabel []string
+	for rows.Next() {
+		var nama string
+		if err := rows.Scan(&nama); err != nil {
+			rows.Close()
+			return err
+		}
+		tabel = append(tabel, nama)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	if _, err := conn.ExecContext(ctx, `SET FOREIGN_KEY_CHECKS = 0`); err != nil {
+		return err
+	}
+	defer conn.ExecContext(ctx, `SET FOREIGN_KEY_CHECKS = 1`)
+	for _, nama := range tabel {
+		if _, err := conn.ExecContext(ctx, "DROP TABLE `"+nama+"`"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
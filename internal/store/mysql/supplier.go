@@ -0,0 +1,85 @@
+// file: internal/store/mysql/supplier.go
+
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"log"
+
+	"scm-api/internal/models"
+	"scm-api/internal/store"
+)
+
+const supplierColumns = "supplier_id, nama_supplier, alamat, kontak, contact_person, rating, deleted_at"
+
+var kolomSupplier = kolomDaftar{
+	"supplier_id": "supplier_id", "nama_supplier": "nama_supplier", "rating": "rating", "deleted_at": "deleted_at",
+}
+
+func (s *Store) ListSupplier(ctx context.Context, q store.Kueri) ([]models.Supplier, int, error) {
+	rows, total, err := s.queryDaftar(ctx, supplierColumns, "FROM supplier", q, store.DaftarSupplier, kolomSupplier)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+	daftarSupplier := make([]models.Supplier, 0)
+	for rows.Next() {
+		var sp models.Supplier
+		err := rows.Scan(&sp.SupplierID, &sp.NamaSupplier, &sp.Alamat, &sp.Kontak, &sp.ContactPerson, &sp.Rating, &sp.DeletedAt)
+		if err != nil {
+			log.Printf("Error scanning row supplier: %v", err)
+			continue
+		}
+		daftarSupplier = append(daftarSupplier, sp)
+	}
+	return daftarSupplier, total, rows.Err()
+}
+
+func (s *Store) GetSupplier(ctx context.Context, id int64) (models.Supplier, error) {
+	var sp models.Supplier
+	row := s.db.QueryRowContext(ctx, "SELECT "+supplierColumns+" FROM supplier WHERE supplier_id = ?", id)
+	err := row.Scan(&sp.SupplierID, &sp.NamaSupplier, &sp.Alamat, &sp.Kontak, &sp.ContactPerson, &sp.Rating, &sp.DeletedAt)
+	return sp, notFound(err)
+}
+
+func (s *Store) CreateSupplier(ctx context.Context, sp *models.Supplier) error {
+	query := `INSERT INTO supplier (nama_supplier, alamat, kontak, contact_person) VALUES (?, ?, ?, ?)`
+	result, err := s.db.ExecContext(ctx, query, sp.NamaSupplier, sp.Alamat, sp.Kontak, sp.ContactPerson)
+	if err != nil {
+		return err
+	}
+	sp.SupplierID, err = result.LastInsertId()
+	return err
+}
+
+func (s *Store) UpdateSupplier(ctx context.Context, sp models.Supplier) error {
+	query := `UPDATE supplier SET nama_supplier = ?, alamat = ?, kontak = ?, contact_person = ? WHERE supplier_id = ?`
+	_, err := s.db.ExecContext(ctx, query, sp.NamaSupplier, sp.Alamat, sp.Kontak, sp.ContactPerson, sp.SupplierID)
+	return err
+}
+
+func (s *Store) SetRatingSupplier(ctx context.Context, id int64, rating sql.NullFloat64) error {
+	_, err := s.db.ExecContext(ctx, `UPDATE supplier SET rating = ? WHERE supplier_id = ?`, rating, id)
+	return err
+}
+
+func (s *Store) DeleteSupplier(ctx context.Context, id int64) error {
+	result, err := s.db.ExecContext(ctx, `UPDATE supplier SET deleted_at = NOW() WHERE supplier_id = ? AND deleted_at IS NULL`, id)
+	if err != nil {
+		return err
+	}
+	return checkAffected(result)
+}
+
+func (s *Store) PulihkanSupplier(ctx context.Context, id int64) error {
+	result, err := s.db.ExecContext(ctx, `UPDATE supplier SET deleted_at = NULL WHERE supplier_id = ? AND deleted_at IS NOT NULL`, id)
+	if err != nil {
+		return err
+	}
+	return checkAffected(result)
+}
+
+func (s *Store) CountSupplier(ctx context.Context) (int, error) {
+	return s.count(ctx, "SELECT COUNT(*) FROM supplier WHERE deleted_at IS NULL")
+}
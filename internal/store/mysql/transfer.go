@@ -0,0 +1,259 @@
+// file: internal/store/mysql/transfer.go
+
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+
+	"scm-api/internal/models"
+	"scm-api/internal/store"
+)
+
+const transferColumns = `
+            transfer_id, gudang_asal_id, gudang_tujuan_id, status, catatan, dibuat_oleh,
+            tanggal_dibuat, tanggal_kirim, tanggal_terima`
+
+const transferSelect = "SELECT " + transferColumns + " FROM transfer_stok"
+
+var kolomTransfer = kolomDaftar{
+	"transfer_id": "transfer_id", "gudang_asal_id": "gudang_asal_id", "gudang_tujuan_id": "gudang_tujuan_id",
+	"status": "status", "tanggal_dibuat": "tanggal_dibuat", "tanggal_kirim": "tanggal_kirim", "tanggal_terima": "tanggal_terima",
+}
+
+func scanTransfer(row interface{ Scan(...any) error }, t *models.Transfer) error {
+	return row.Scan(&t.TransferID, &t.GudangAsalID, &t.GudangTujuanID, &t.Status, &t.Catatan, &t.DibuatOleh,
+		&t.TanggalDibuat, &t.TanggalKirim, &t.TanggalTerima)
+}
+
+func (s *Store) ListTransfer(ctx context.Context, q store.Kueri) ([]models.Transfer, int, error) {
+	rows, total, err := s.queryDaftar(ctx, transferColumns, "FROM transfer_stok", q, store.DaftarTransfer, kolomTransfer)
+	if err != nil {
+		return nil, 0, err
+	}
+	daftar := make([]models.Transfer, 0)
+	for rows.Next() {
+		var t models.Transfer
+		if err := scanTransfer(rows, &t); err != nil {
+			rows.Close()
+			return nil, 0, err
+		}
+		daftar = append(daftar, t)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	// Detail hanya dibaca untuk transfer di halaman ini, jadi paling banyak sebanyak batas halaman
+	for i := range daftar {
+		if daftar[i].Details, err = detailTransfer(ctx, s.db, daftar[i].TransferID); err != nil {
+			return nil, 0, err
+		}
+	}
+	return daftar, total, nil
+}
+
+func (s *Store) GetTransfer(ctx context.Context, id int64) (models.Transfer, error) {
+	var t models.Transfer
+	if err := scanTransfer(s.db.QueryRowContext(ctx, transferSelect+" WHERE transfer_id = ?", id), &t); err != nil {
+		return t, notFound(err)
+	}
+	var err error
+	t.Details, err = detailTransfer(ctx, s.db, id)
+	return t, err
+}
+
+func detailTransfer(ctx context.Context, q querier, transferID int64) ([]models.DetailTransfer, error) {
+	rows, err := q.QueryContext(ctx, "SELECT detail_transfer_id, transfer_id, produk_id, jumlah FROM detail_transfer WHERE transfer_id = ? ORDER BY produk_id", transferID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	details := make([]models.DetailTransfer, 0)
+	for rows.Next() {
+		var d models.DetailTransfer
+		if err := rows.Scan(&d.DetailTransferID, &d.TransferID, &d.ProdukID, &d.Jumlah); err != nil {
+			return nil, err
+		}
+		details = append(details, d)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	queryBatch := `
+        SELECT b.detail_transfer_id, b.batch_id, b.nomor_lot, b.tanggal_produksi, b.tanggal_kedaluwarsa, b.jumlah
+        FROM detail_transfer_batch b
+        JOIN detail_transfer d ON b.detail_transfer_id = d.detail_transfer_id
+        WHERE d.transfer_id = ?
+        ORDER BY b.detail_transfer_batch_id`
+	rows, err = q.QueryContext(ctx, queryBatch, transferID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	batch := make(map[int64][]models.PemakaianBatch)
+	for rows.Next() {
+		var detailID int64
+		var b models.PemakaianBatch
+		if err := rows.Scan(&detailID, &b.BatchID, &b.NomorLot, &b.TanggalProduksi, &b.TanggalKedaluwarsa, &b.Jumlah); err != nil {
+			return nil, err
+		}
+		batch[detailID] = append(batch[detailID], b)
+	}
+	for i := range details {
+		details[i].Batch = batch[details[i].DetailTransferID]
+		if details[i].Batch == nil {
+			details[i].Batch = make([]models.PemakaianBatch, 0)
+		}
+	}
+	return details, rows.Err()
+}
+
+func (s *Store) CreateTransfer(ctx context.Context, t *models.Transfer) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	t.Status = models.StatusTransferDraft
+	queryHeader := `INSERT INTO transfer_stok (gudang_asal_id, gudang_tujuan_id, status, catatan, dibuat_oleh, tanggal_dibuat) VALUES (?, ?, ?, ?, ?, NOW())`
+	result, err := tx.ExecContext(ctx, queryHeader, t.GudangAsalID, t.GudangTujuanID, t.Status, t.Catatan, t.DibuatOleh)
+	if err != nil {
+		return fmt.Errorf("gagal menyimpan data transfer: %w", err)
+	}
+	t.TransferID, err = result.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	queryDetail := `INSERT INTO detail_transfer (transfer_id, produk_id, jumlah) VALUES (?, ?, ?)`
+	for i := range t.Details {
+		t.Details[i].TransferID = t.TransferID
+		result, err := tx.ExecContext(ctx, queryDetail, t.TransferID, t.Details[i].ProdukID, t.Details[i].Jumlah)
+		if err != nil {
+			return fmt.Errorf("gagal menyimpan detail transfer: %w", err)
+		}
+		t.Details[i].DetailTransferID, _ = result.LastInsertId()
+	}
+
+	if err := tx.QueryRowContext(ctx, "SELECT tanggal_dibuat FROM transfer_stok WHERE transfer_id = ?", t.TransferID).Scan(&t.TanggalDibuat); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (s *Store) UbahStatusTransfer(ctx context.Context, id int64, ke, oleh string) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var t models.Transfer
+	if err := scanTransfer(tx.QueryRowContext(ctx, transferSelect+" WHERE transfer_id = ? FOR UPDATE", id), &t); err != nil {
+		return notFound(err)
+	}
+	if !models.BolehTransisiTransfer(t.Status, ke) {
+		return fmt.Errorf("%w: dari %q ke %q", store.ErrInvalidTransition, t.Status, ke)
+	}
+	details, err := detailTransfer(ctx, tx, id)
+	if err != nil {
+		return err
+	}
+	// Urutkan berdasarkan produk agar baris stok selalu dikunci dengan urutan yang sama
+	sort.Slice(details, func(i, j int) bool { return details[i].ProdukID < details[j].ProdukID })
+
+	var query string
+	switch ke {
+	case models.StatusTransferDikirim:
+		queryBatch := `
+            INSERT INTO detail_transfer_batch (detail_transfer_id, batch_id, nomor_lot, tanggal_produksi, tanggal_kedaluwarsa, jumlah)
+            VALUES (?, ?, ?, ?, ?, ?)
+        `
+		for _, d := range details {
+			pemakaian, err := mutasiTransferTx(ctx, tx, t, d, t.GudangAsalID, -d.Jumlah, oleh, nil)
+			if err != nil {
+				return err
+			}
+			// Catat lot yang diambil agar dibuat kembali di gudang tujuan
+			for _, b := range pemakaian {
+				_, err := tx.ExecContext(ctx, queryBatch, d.DetailTransferID, b.BatchID, b.NomorLot, tanggalSaja(b.TanggalProduksi), tanggalSaja(b.TanggalKedaluwarsa), b.Jumlah)
+				if err != nil {
+					return fmt.Errorf("gagal mencatat batch transfer: %w", err)
+				}
+			}
+		}
+		query = "UPDATE transfer_stok SET status = ?, tanggal_kirim = NOW() WHERE transfer_id = ?"
+	case models.StatusTransferDiterima:
+		for _, d := range details {
+			// Setiap lot yang dikirim menjadi batch baru di gudang tujuan,
+			// sisanya masuk sebagai stok tanpa batch
+			sisa := d.Jumlah
+			for _, b := range d.Batch {
+				masuk := &models.StokBatch{NomorLot: b.NomorLot, TanggalProduksi: b.TanggalProduksi, TanggalKedaluwarsa: b.TanggalKedaluwarsa}
+				if _, err := mutasiTransferTx(ctx, tx, t, d, t.GudangTujuanID, b.Jumlah, oleh, masuk); err != nil {
+					return err
+				}
+				sisa -= b.Jumlah
+			}
+			if sisa > 0 {
+				if _, err := mutasiTransferTx(ctx, tx, t, d, t.GudangTujuanID, sisa, oleh, nil); err != nil {
+					return err
+				}
+			}
+		}
+		query = "UPDATE transfer_stok SET status = ?, tanggal_terima = NOW() WHERE transfer_id = ?"
+	default:
+		query = "UPDATE transfer_stok SET status = ? WHERE transfer_id = ?"
+	}
+	if _, err := tx.ExecContext(ctx, query, ke, id); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// mutasiTransferTx menambah (perubahan positif) atau mengurangi stok satu item transfer di gudang
+func mutasiTransferTx(ctx context.Context, tx *sql.Tx, t models.Transfer, d models.DetailTransfer, gudangID int64, perubahan int, oleh string, masuk *models.StokBatch) ([]models.PemakaianBatch, error) {
+	m := models.StokMutasi{
+		ProdukID:      d.ProdukID,
+		GudangID:      gudangID,
+		Tipe:          models.MutasiTransfer,
+		ReferensiTipe: nullString("transfer"),
+		ReferensiID:   sql.NullInt64{Int64: t.TransferID, Valid: true},
+		DibuatOleh:    oleh,
+	}
+	return mutasiStokTx(ctx, tx, &m, func(sebelum int) int { return sebelum + perubahan }, masuk)
+}
+
+func (s *Store) StokDalamPerjalanan(ctx context.Context) ([]models.StokDalamPerjalananResponse, error) {
+	query := `
+        SELECT d.produk_id, p.nama_produk, t.gudang_asal_id, ga.nama_gudang, t.gudang_tujuan_id, gt.nama_gudang, SUM(d.jumlah)
+        FROM detail_transfer d
+        JOIN transfer_stok t ON d.transfer_id = t.transfer_id
+        JOIN produk p ON d.produk_id = p.produk_id
+        JOIN gudang ga ON t.gudang_asal_id = ga.gudang_id
+        JOIN gudang gt ON t.gudang_tujuan_id = gt.gudang_id
+        WHERE t.status = ?
+        GROUP BY d.produk_id, p.nama_produk, t.gudang_asal_id, ga.nama_gudang, t.gudang_tujuan_id, gt.nama_gudang
+        ORDER BY d.produk_id, t.gudang_asal_id, t.gudang_tujuan_id`
+	rows, err := s.db.QueryContext(ctx, query, models.StatusTransferDikirim)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	daftar := make([]models.StokDalamPerjalananResponse, 0)
+	for rows.Next() {
+		var r models.StokDalamPerjalananResponse
+		if err := rows.Scan(&r.ProdukID, &r.NamaProduk, &r.GudangAsalID, &r.NamaGudangAsal, &r.GudangTujuanID, &r.NamaGudangTujuan, &r.Jumlah); err != nil {
+			return nil, err
+		}
+		daftar = append(daftar, r)
+	}
+	return daftar, rows.Err()
+}
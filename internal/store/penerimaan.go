@@ -0,0 +1,124 @@
+// file: internal/store/penerimaan.go
+
+package store
+
+import (
+	"database/sql"
+	"fmt"
+
+	"scm-api/internal/models"
+)
+
+// CocokkanPenerimaan memasangkan setiap item penerimaan dengan baris detail pembelian
+// dan memastikan jumlah diterima + ditolak tidak melebihi sisa baris tersebut.
+// Item boleh menyebut detail_pembelian_id, atau cukup produk_id jika produk itu
+// hanya muncul di satu baris. DetailPembelianID, ProdukID, dan JumlahStok (jumlah
+// diterima dikali faktor konversi baris) pada items diisi.
+// Nilai kembaliannya adalah status pembelian setelah penerimaan dicatat.
+func CocokkanPenerimaan(lines []models.DetailPembelianResponse, items []models.DetailPenerimaan) (string, error) {
+	if len(items) == 0 {
+		return "", fmt.Errorf("%w: minimal satu item harus diterima", ErrInvalidReceipt)
+	}
+
+	sisa := make(map[int64]int, len(lines))
+	for _, l := range lines {
+		sisa[l.DetailPembelianID] = l.Sisa
+	}
+
+	for i := range items {
+		it := &items[i]
+		if it.JumlahDiterima < 0 || it.JumlahDitolak < 0 {
+			return "", fmt.Errorf("%w: jumlah tidak boleh negatif (item ke-%d)", ErrInvalidReceipt, i+1)
+		}
+		if it.JumlahDiterima+it.JumlahDitolak == 0 {
+			return "", fmt.Errorf("%w: jumlah diterima atau ditolak harus diisi (item ke-%d)", ErrInvalidReceipt, i+1)
+		}
+
+		line, err := cariBarisPembelian(lines, *it)
+		if err != nil {
+			return "", fmt.Errorf("%w: %v (item ke-%d)", ErrInvalidReceipt, err, i+1)
+		}
+		it.DetailPembelianID = line.DetailPembelianID
+		it.ProdukID = line.ProdukID
+		it.JumlahStok = it.JumlahDiterima * line.FaktorKonversi
+
+		jumlah := it.JumlahDiterima + it.JumlahDitolak
+		if jumlah > sisa[line.DetailPembelianID] {
+			return "", fmt.Errorf("%w: produk %q hanya tersisa %d, diterima+ditolak %d",
+				ErrInvalidReceipt, line.NamaProduk, sisa[line.DetailPembelianID], jumlah)
+		}
+		sisa[line.DetailPembelianID] -= jumlah
+	}
+
+	for _, n := range sisa {
+		if n > 0 {
+			return models.StatusPembelianDiterimaSebagian, nil
+		}
+	}
+	return models.StatusPembelianDiterima, nil
+}
+
+func cariBarisPembelian(lines []models.DetailPembelianResponse, it models.DetailPenerimaan) (models.DetailPembelianResponse, error) {
+	if it.DetailPembelianID != 0 {
+		for _, l := range lines {
+			if l.DetailPembelianID == it.DetailPembelianID {
+				if it.ProdukID != 0 && it.ProdukID != l.ProdukID {
+					return l, fmt.Errorf("detail_pembelian_id %d bukan untuk produk %d", it.DetailPembelianID, it.ProdukID)
+				}
+				return l, nil
+			}
+		}
+		return models.DetailPembelianResponse{}, fmt.Errorf("detail_pembelian_id %d bukan bagian dari pesanan ini", it.DetailPembelianID)
+	}
+
+	var found []models.DetailPembelianResponse
+	for _, l := range lines {
+		if l.ProdukID == it.ProdukID {
+			found = append(found, l)
+		}
+	}
+	switch len(found) {
+	case 0:
+		return models.DetailPembelianResponse{}, fmt.Errorf("produk %d tidak ada di pesanan ini", it.ProdukID)
+	case 1:
+		return found[0], nil
+	default:
+		return models.DetailPembelianResponse{}, fmt.Errorf("produk %d muncul di beberapa baris, sebutkan detail_pembelian_id", it.ProdukID)
+	}
+}
+
+// PenerimaanPenuh membuat item penerimaan untuk seluruh sisa setiap baris
+func PenerimaanPenuh(lines []models.DetailPembelianResponse) []models.DetailPenerimaan {
+	items := make([]models.DetailPenerimaan, 0, len(lines))
+	for _, l := range lines {
+		if l.Sisa > 0 {
+			items = append(items, models.DetailPenerimaan{DetailPembelianID: l.DetailPembelianID, ProdukID: l.ProdukID, JumlahDiterima: l.Sisa})
+		}
+	}
+	return items
+}
+
+// TentukanGudang mengisi GudangID setiap item dengan urutan prioritas: gudang baris,
+// gudang pada penerimaan, lalu gudang tujuan bawaan pembelian. Nilai kembaliannya
+// adalah daftar gudang unik yang dipakai agar keberadaannya bisa diperiksa.
+func TentukanGudang(p *models.Penerimaan, bawaan sql.NullInt64) ([]int64, error) {
+	dipakai := make([]int64, 0)
+	sudah := make(map[int64]bool)
+	for i := range p.Details {
+		d := &p.Details[i]
+		if d.GudangID == 0 {
+			d.GudangID = p.GudangID
+		}
+		if d.GudangID == 0 && bawaan.Valid {
+			d.GudangID = bawaan.Int64
+		}
+		if d.GudangID == 0 {
+			return nil, fmt.Errorf("%w: gudang tujuan wajib diisi karena pesanan tidak punya gudang tujuan bawaan (item ke-%d)", ErrInvalidReceipt, i+1)
+		}
+		if !sudah[d.GudangID] {
+			sudah[d.GudangID] = true
+			dipakai = append(dipakai, d.GudangID)
+		}
+	}
+	return dipakai, nil
+}
@@ -0,0 +1,329 @@
+// file: internal/store/store.go
+
+// Package store mendefinisikan antarmuka akses data untuk setiap entitas.
+// Handler hanya bergantung pada antarmuka ini, sehingga implementasi MySQL
+// bisa diganti dengan implementasi in-memory saat pengujian.
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"scm-api/internal/models"
+)
+
+// ErrNotFound dikembalikan ketika data yang diminta tidak ada
+var ErrNotFound = errors.New("data tidak ditemukan")
+
+// ErrInvalidTransition dikembalikan ketika perpindahan status tidak diizinkan
+var ErrInvalidTransition = errors.New("perubahan status tidak diizinkan")
+
+// ErrStokTidakCukup dikembalikan ketika perubahan akan membuat stok menjadi negatif
+var ErrStokTidakCukup = errors.New("stok tidak cukup")
+
+// ErrInvalidReceipt dikembalikan ketika isi penerimaan barang tidak cocok dengan pesanan
+var ErrInvalidReceipt = errors.New("penerimaan tidak valid")
+
+// ErrStokTersisa dikembalikan ketika gudang yang akan diarsipkan masih menyimpan stok
+var ErrStokTersisa = errors.New("gudang masih menyimpan stok")
+
+// ErrDuplikat dikembalikan ketika data melanggar kunci unik, misalnya username yang sudah dipakai
+var ErrDuplikat = errors.New("data sudah ada")
+
+// ProdukStore mengelola tabel produk. Produk yang dihapus hanya ditandai deleted_at
+// agar pembelian dan stok lama tetap bisa merujuknya.
+type ProdukStore interface {
+	// ListProduk mengembalikan satu halaman produk sesuai DaftarProduk beserta jumlah seluruh
+	// produk yang cocok. Produk yang sudah dihapus hanya ikut jika q.TermasukDihapus.
+	ListProduk(ctx context.Context, q Kueri) ([]models.Produk, int, error)
+	// GetProduk juga mengembalikan produk yang sudah dihapus
+	GetProduk(ctx context.Context, id int64) (models.Produk, error)
+	// CreateProduk mengembalikan ErrDuplikat jika SKU sudah dipakai produk yang belum dihapus
+	CreateProduk(ctx context.Context, p *models.Produk) error
+	// UpdateProduk tidak mengubah gambar; gambar hanya diubah lewat SetGambarProduk.
+	// Mengembalikan ErrDuplikat jika SKU sudah dipakai produk lain yang belum dihapus.
+	UpdateProduk(ctx context.Context, p models.Produk) error
+	// SetGambarProduk mengganti URL gambar dan thumbnail produk (NULL untuk menghapusnya)
+	SetGambarProduk(ctx context.Context, id int64, gambar, thumbnail sql.NullString) error
+	// DeleteProduk mengisi deleted_at. Mengembalikan ErrNotFound jika produk tidak ada atau sudah dihapus.
+	DeleteProduk(ctx context.Context, id int64) error
+	// PulihkanProduk mengosongkan deleted_at. Mengembalikan ErrNotFound jika produk tidak ada atau tidak sedang dihapus,
+	// dan ErrDuplikat jika SKU-nya sudah dipakai lagi oleh produk lain.
+	PulihkanProduk(ctx context.Context, id int64) error
+	// CountProduk hanya menghitung produk yang belum dihapus
+	CountProduk(ctx context.Context) (int, error)
+}
+
+// SupplierStore mengelola tabel supplier. Penghapusannya sama dengan produk (soft delete).
+type SupplierStore interface {
+	ListSupplier(ctx context.Context, q Kueri) ([]models.Supplier, int, error)
+	GetSupplier(ctx context.Context, id int64) (models.Supplier, error)
+	CreateSupplier(ctx context.Context, s *models.Supplier) error
+	UpdateSupplier(ctx context.Context, s models.Supplier) error
+	DeleteSupplier(ctx context.Context, id int64) error
+	PulihkanSupplier(ctx context.Context, id int64) error
+	CountSupplier(ctx context.Context) (int, error)
+	// SetRatingSupplier menyimpan rating hasil scorecard. Create dan Update tidak
+	// mengubah rating; NULL berarti belum ada data untuk dinilai.
+	SetRatingSupplier(ctx context.Context, id int64, rating sql.NullFloat64) error
+}
+
+// PembelianStore mengelola tabel pembelian dan detail_pembelian
+type PembelianStore interface {
+	ListPembelian(ctx context.Context, q Kueri) ([]models.PembelianResponse, int, error)
+	// RecentPembelian mengembalikan pembelian terbaru sebanyak limit
+	RecentPembelian(ctx context.Context, limit int) ([]models.PembelianResponse, error)
+	GetPembelian(ctx context.Context, id int64) (models.PembelianDenganDetailResponse, error)
+	// CreatePembelian menyimpan header, seluruh detail, dan riwayat status awal dalam satu transaksi
+	CreatePembelian(ctx context.Context, p *models.Pembelian, details []models.DetailPembelian, oleh string) error
+	// CreatePembelianBanyak menyimpan beberapa pesanan seperti CreatePembelian dalam satu
+	// transaksi dan mengisi PembelianID masing-masing. Jika satu gagal, tidak ada yang disimpan.
+	CreatePembelianBanyak(ctx context.Context, daftar []models.PembelianBaru, oleh string) error
+	// DeletePembelian menghapus pesanan berstatus Draft atau Dibatalkan beserta detailnya.
+	// Riwayat statusnya tetap disimpan. Mengembalikan ErrInvalidTransition untuk status lain
+	// atau jika pesanan sudah memiliki penerimaan.
+	DeletePembelian(ctx context.Context, id int64) error
+	// UbahStatusPembelian memindahkan status pembelian dan mencatat riwayatnya.
+	// Mengembalikan ErrInvalidTransition jika perpindahan tidak diizinkan.
+	UbahStatusPembelian(ctx context.Context, id int64, ke, oleh, catatan string) error
+	// TerimaPembelian mencatat penerimaan untuk seluruh sisa setiap item ke gudangID
+	// (0 berarti gudang tujuan bawaan pesanan) dan menandai pesanan "Diterima"
+	TerimaPembelian(ctx context.Context, id, gudangID int64, oleh string) error
+	// CreatePenerimaan mencatat dokumen penerimaan, menambah stok gudang tujuan sebesar
+	// jumlah yang diterima, lalu memindahkan status ke "Diterima Sebagian" atau "Diterima".
+	// Mengembalikan ErrInvalidReceipt jika item tidak cocok dengan sisa pesanan
+	// atau gudang tujuan tidak diketahui.
+	CreatePenerimaan(ctx context.Context, p *models.Penerimaan) error
+	// ListPenerimaan mengembalikan satu halaman penerimaan satu pesanan sesuai DaftarPenerimaan.
+	// Mengembalikan ErrNotFound jika pesanan tidak ada.
+	ListPenerimaan(ctx context.Context, pembelianID int64, q Kueri) ([]models.Penerimaan, int, error)
+	RiwayatStatusPembelian(ctx context.Context, id int64) ([]models.RiwayatStatusPembelian, error)
+	CountPembelian(ctx context.Context) (int, error)
+}
+
+// GudangStore mengelola tabel gudang. Penghapusannya sama dengan produk (soft delete).
+type GudangStore interface {
+	ListGudang(ctx context.Context, q Kueri) ([]models.Gudang, int, error)
+	GetGudang(ctx context.Context, id int64) (models.Gudang, error)
+	CreateGudang(ctx context.Context, g *models.Gudang) error
+	UpdateGudang(ctx context.Context, g models.Gudang) error
+	// DeleteGudang mengarsipkan gudang. Mengembalikan ErrStokTersisa jika masih ada
+	// stok dengan jumlah bukan nol di gudang tersebut.
+	DeleteGudang(ctx context.Context, id int64) error
+	PulihkanGudang(ctx context.Context, id int64) error
+	CountGudang(ctx context.Context) (int, error)
+}
+
+// StokStore mengelola tabel stok dan buku besar stok_mutasi
+type StokStore interface {
+	ListStok(ctx context.Context, q Kueri) ([]models.StokResponse, int, error)
+	// AdjustStok menetapkan jumlah stok produk di gudang (insert atau update)
+	// dan mencatatnya sebagai mutasi penyesuaian. Mutasi yang tercatat dikembalikan.
+	AdjustStok(ctx context.Context, produkID, gudangID int64, jumlah int, oleh, catatan string) (models.StokMutasi, error)
+	// CatatPenjualan mengurangi stok produk di gudang sebanyak jumlah (FEFO) dan
+	// mencatatnya sebagai mutasi penjualan. Mengembalikan ErrStokTidakCukup jika
+	// stoknya kurang.
+	CatatPenjualan(ctx context.Context, produkID, gudangID int64, jumlah int, oleh, catatan string) (models.StokMutasi, error)
+	// ListMutasiStok mengembalikan satu halaman mutasi stok sesuai DaftarMutasi
+	ListMutasiStok(ctx context.Context, q Kueri) ([]models.StokMutasi, int, error)
+	// ListBatchStok mengembalikan satu halaman batch yang masih bersisa sesuai DaftarBatch
+	ListBatchStok(ctx context.Context, q Kueri) ([]models.StokBatchResponse, int, error)
+	// BatchKedaluwarsa mengembalikan batch bersisa yang kedaluwarsa dalam hari ke depan
+	// (termasuk yang sudah lewat). gudangID 0 berarti semua gudang.
+	BatchKedaluwarsa(ctx context.Context, hari int, gudangID int64) ([]models.StokBatchResponse, error)
+	// StokPerProduk mengembalikan total stok setiap produk untuk grafik dashboard
+	StokPerProduk(ctx context.Context) (models.StokChartResponse, error)
+}
+
+// TransferStore mengelola tabel transfer_stok dan detail_transfer
+type TransferStore interface {
+	// ListTransfer mengembalikan satu halaman transfer beserta detailnya sesuai DaftarTransfer
+	ListTransfer(ctx context.Context, q Kueri) ([]models.Transfer, int, error)
+	GetTransfer(ctx context.Context, id int64) (models.Transfer, error)
+	// CreateTransfer menyimpan transfer berstatus Draft beserta seluruh detailnya
+	CreateTransfer(ctx context.Context, t *models.Transfer) error
+	// UbahStatusTransfer memindahkan status transfer. Ke "Dikirim" mengurangi stok
+	// gudang asal (ErrStokTidakCukup jika tidak cukup), ke "Diterima" menambah stok
+	// gudang tujuan. Mengembalikan ErrInvalidTransition jika perpindahan tidak diizinkan.
+	UbahStatusTransfer(ctx context.Context, id int64, ke, oleh string) error
+	// StokDalamPerjalanan menjumlahkan item transfer yang sudah dikirim tapi belum diterima
+	StokDalamPerjalanan(ctx context.Context) ([]models.StokDalamPerjalananResponse, error)
+}
+
+// PenggunaStore mengelola tabel pengguna, refresh_token, dan pengguna_gudang
+type PenggunaStore interface {
+	// ListPengguna mengembalikan satu halaman pengguna beserta nama role dan gudangnya sesuai DaftarPengguna
+	ListPengguna(ctx context.Context, q Kueri) ([]models.PenggunaDenganRole, int, error)
+	GetPengguna(ctx context.Context, id int64) (models.Pengguna, error)
+	GetPenggunaByUsername(ctx context.Context, username string) (models.Pengguna, error)
+	// CreatePengguna mengembalikan ErrDuplikat jika username sudah dipakai
+	CreatePengguna(ctx context.Context, p *models.Pengguna) error
+	// SimpanRefreshToken mencatat hash token refresh baru yang berlaku selama masaBerlaku sejak sekarang
+	SimpanRefreshToken(ctx context.Context, penggunaID int64, hash string, masaBerlaku time.Duration) error
+	// PakaiRefreshToken mencabut token refresh yang masih berlaku dan mengembalikan pemiliknya,
+	// sehingga setiap token hanya bisa dipakai sekali. Mengembalikan ErrNotFound jika token
+	// tidak ada, sudah dicabut, atau sudah kedaluwarsa.
+	PakaiRefreshToken(ctx context.Context, hash string) (int64, error)
+	// CabutSemuaRefreshToken mencabut seluruh token refresh milik pengguna yang masih berlaku
+	CabutSemuaRefreshToken(ctx context.Context, penggunaID int64) error
+	// NaikkanVersiToken membuat semua access token pengguna yang sudah terbit tidak berlaku lagi
+	NaikkanVersiToken(ctx context.Context, penggunaID int64) error
+	// SetAktifPengguna mengubah status aktif pengguna dan menaikkan versi tokennya.
+	// Saat dinonaktifkan, seluruh token refresh pengguna juga dicabut.
+	SetAktifPengguna(ctx context.Context, penggunaID int64, aktif bool) error
+	// GudangPengguna mengembalikan ID gudang tempat pengguna bekerja
+	GudangPengguna(ctx context.Context, penggunaID int64) ([]int64, error)
+	// SetGudangPengguna mengganti seluruh gudang pengguna dengan gudangIDs
+	SetGudangPengguna(ctx context.Context, penggunaID int64, gudangIDs []int64) error
+}
+
+// RoleStore mengelola tabel izin, role, role_izin, dan pengguna_role
+type RoleStore interface {
+	ListIzin(ctx context.Context) ([]models.Izin, error)
+	// ListRole mengembalikan seluruh role beserta kode izinnya
+	ListRole(ctx context.Context) ([]models.Role, error)
+	// CreateRole menyimpan role beserta izinnya. Mengembalikan ErrDuplikat jika namanya sudah dipakai.
+	CreateRole(ctx context.Context, r *models.Role) error
+	// UbahIzinRole mengganti seluruh izin role dengan daftar izin
+	UbahIzinRole(ctx context.Context, roleID int64, izin []string) error
+	// RolePengguna mengembalikan role yang dimiliki pengguna
+	RolePengguna(ctx context.Context, penggunaID int64) ([]models.Role, error)
+	// SetRolePengguna mengganti seluruh role pengguna dengan roleIDs
+	SetRolePengguna(ctx context.Context, penggunaID int64, roleIDs []int64) error
+	// IzinPengguna mengembalikan gabungan kode izin dari seluruh role pengguna
+	IzinPengguna(ctx context.Context, penggunaID int64) ([]string, error)
+}
+
+// BarcodeStore mengelola tabel produk_barcode. Barcode dicari dan dibandingkan
+// dengan GTIN 14 digit hasil barcode.Normalisasi.
+type BarcodeStore interface {
+	// ListBarcodeProduk mengembalikan barcode milik satu produk, yang paling lama lebih dulu
+	ListBarcodeProduk(ctx context.Context, produkID int64) ([]models.ProdukBarcode, error)
+	// CariBarcode mengembalikan ErrNotFound jika GTIN belum terdaftar di produk mana pun
+	CariBarcode(ctx context.Context, gtin string) (models.ProdukBarcode, error)
+	// CreateBarcode mengisi BarcodeID dan CreatedAt. Mengembalikan ErrDuplikat jika GTIN sudah dipakai.
+	CreateBarcode(ctx context.Context, b *models.ProdukBarcode) error
+	// DeleteBarcode mengembalikan ErrNotFound jika GTIN tidak terdaftar di produk tersebut
+	DeleteBarcode(ctx context.Context, produkID int64, gtin string) error
+}
+
+// SatuanStore mengelola tabel produk_satuan. Nama satuan dibandingkan tanpa
+// membedakan huruf besar dan kecil, seperti collation database.
+type SatuanStore interface {
+	// ListSatuanProduk mengembalikan satuan beli milik satu produk, yang faktornya terkecil lebih dulu
+	ListSatuanProduk(ctx context.Context, produkID int64) ([]models.ProdukSatuan, error)
+	// CariSatuan mengembalikan ErrNotFound jika satuan belum terdaftar untuk produk tersebut
+	CariSatuan(ctx context.Context, produkID int64, satuan string) (models.ProdukSatuan, error)
+	// CreateSatuan mengisi SatuanID dan CreatedAt. Mengembalikan ErrDuplikat jika satuan sudah terdaftar.
+	CreateSatuan(ctx context.Context, ps *models.ProdukSatuan) error
+	// UpdateSatuan hanya mengubah faktor. Baris pembelian lama tetap memakai faktor yang tersimpan di barisnya.
+	UpdateSatuan(ctx context.Context, ps models.ProdukSatuan) error
+	// DeleteSatuan mengembalikan ErrNotFound jika satuan tidak terdaftar untuk produk tersebut
+	DeleteSatuan(ctx context.Context, produkID int64, satuan string) error
+}
+
+// KatalogStore mengelola tabel supplier_produk
+type KatalogStore interface {
+	// ListKatalog mengembalikan satu halaman katalog sesuai DaftarKatalog
+	ListKatalog(ctx context.Context, q Kueri) ([]models.SupplierProduk, int, error)
+	// KatalogAktif mengembalikan harga yang berlaku pada tanggal (YYYY-MM-DD) untuk
+	// produk, termurah lebih dulu. supplierID 0 berarti semua supplier yang belum dihapus.
+	KatalogAktif(ctx context.Context, supplierID, produkID int64, tanggal string) ([]models.SupplierProduk, error)
+	// KatalogAktifProduk mengembalikan harga yang berlaku pada tanggal untuk beberapa produk
+	// sekaligus dari supplier yang belum dihapus, urut produk lalu harga termurah
+	KatalogAktifProduk(ctx context.Context, produkIDs []int64, tanggal string) ([]models.KatalogBerfaktor, error)
+	GetKatalog(ctx context.Context, id int64) (models.SupplierProduk, error)
+	// CreateKatalog mengisi SupplierProdukID, nama, CreatedAt, dan UpdatedAt
+	CreateKatalog(ctx context.Context, k *models.SupplierProduk) error
+	// UpdateKatalog mengganti seluruh isi baris kecuali supplier_id
+	UpdateKatalog(ctx context.Context, k models.SupplierProduk) error
+	DeleteKatalog(ctx context.Context, id int64) error
+}
+
+// PermintaanStore mengelola tabel permintaan_pembelian, detail_permintaan,
+// persetujuan_permintaan, dan aturan_persetujuan
+type PermintaanStore interface {
+	// ListPermintaan mengembalikan satu halaman header permintaan sesuai DaftarPermintaan
+	ListPermintaan(ctx context.Context, q Kueri) ([]models.PermintaanPembelian, int, error)
+	GetPermintaan(ctx context.Context, id int64) (models.PermintaanDenganDetail, error)
+	// CreatePermintaan menyimpan header, item, dan tahap persetujuan dalam satu transaksi.
+	// Status diisi Disetujui jika tidak ada tahap, selain itu Diajukan.
+	CreatePermintaan(ctx context.Context, p *models.PermintaanDenganDetail) error
+	// PutuskanPersetujuan mencatat keputusan (Disetujui atau Ditolak) untuk satu tahap.
+	// Tahap harus masih Menunggu dan berada di level terendah yang belum disetujui;
+	// jika tidak, atau permintaan tidak lagi Diajukan, ErrInvalidTransition dikembalikan.
+	// Status permintaan ikut berpindah jika tahap ini yang menentukan.
+	PutuskanPersetujuan(ctx context.Context, permintaanID, persetujuanID int64, keputusan, oleh, komentar string) error
+	// BatalkanPermintaan memindahkan permintaan ke Dibatalkan dan melewati tahap yang masih menunggu
+	BatalkanPermintaan(ctx context.Context, id int64) error
+	// KonversiPermintaan menyimpan pembelian seperti CreatePembelian lalu menandai
+	// permintaan Selesai dalam satu transaksi. Mengembalikan ErrInvalidTransition jika
+	// permintaan tidak lagi Disetujui.
+	KonversiPermintaan(ctx context.Context, permintaanID int64, p *models.Pembelian, details []models.DetailPembelian, oleh string) error
+
+	// ListAturan mengembalikan satu halaman aturan persetujuan sesuai DaftarAturan.
+	// Kueri kosong mengembalikan seluruh aturan urut level.
+	ListAturan(ctx context.Context, q Kueri) ([]models.AturanPersetujuan, int, error)
+	GetAturan(ctx context.Context, id int64) (models.AturanPersetujuan, error)
+	CreateAturan(ctx context.Context, a *models.AturanPersetujuan) error
+	UpdateAturan(ctx context.Context, a models.AturanPersetujuan) error
+	DeleteAturan(ctx context.Context, id int64) error
+}
+
+// ScorecardStore menyediakan data pembelian untuk scorecard supplier
+type ScorecardStore interface {
+	// BarisScorecard mengembalikan seluruh baris pembelian semua supplier dengan
+	// tanggal_pesan antara dari dan sampai (YYYY-MM-DD, inklusif), kecuali Draft dan Dibatalkan
+	BarisScorecard(ctx context.Context, dari, sampai string) ([]models.BarisScorecard, error)
+}
+
+// ReplenishmentStore mengelola tabel stok_parameter dan menyediakan data stok
+// keluar serta pesanan terbuka untuk saran pemesanan ulang
+type ReplenishmentStore interface {
+	// ListParameterStok mengembalikan parameter urut produk lalu gudang.
+	// produkID atau gudangID 0 berarti tidak difilter.
+	ListParameterStok(ctx context.Context, produkID, gudangID int64) ([]models.ParameterStok, error)
+	GetParameterStok(ctx context.Context, produkID, gudangID int64) (models.ParameterStok, error)
+	// SimpanParameterStok menambah atau mengganti parameter produk di gudang dan
+	// mengisi ParameterID, nama, dan UpdatedAt
+	SimpanParameterStok(ctx context.Context, p *models.ParameterStok) error
+	HapusParameterStok(ctx context.Context, produkID, gudangID int64) error
+	// KandidatSaran mengembalikan parameter stok yang perlu dihitung sarannya, urut
+	// produk lalu gudang. gudangID 0 berarti tidak difilter.
+	KandidatSaran(ctx context.Context, gudangID int64) ([]models.KandidatSaran, error)
+	// PesananTerbuka menjumlahkan sisa pesanan yang belum datang per produk dan gudang tujuan
+	PesananTerbuka(ctx context.Context) ([]models.PesananTerbuka, error)
+	// PemakaianHarian menjumlahkan stok keluar bertipe salah satu dari tipe per produk,
+	// gudang, dan tanggal antara dari dan sampai (YYYY-MM-DD, inklusif). produkID atau
+	// gudangID 0 berarti tidak difilter.
+	PemakaianHarian(ctx context.Context, dari, sampai string, produkID, gudangID int64, tipe []string) ([]models.PemakaianHarian, error)
+}
+
+// AuditStore mengelola tabel audit_log
+type AuditStore interface {
+	// CatatAudit menambah satu entri audit dengan waktu sekarang dan mengisi AuditID
+	CatatAudit(ctx context.Context, a *models.AuditLog) error
+	// ListAudit mengembalikan satu halaman entri audit sesuai DaftarAudit
+	ListAudit(ctx context.Context, q Kueri) ([]models.AuditLog, int, error)
+}
+
+// Store menggabungkan seluruh antarmuka store
+type Store interface {
+	ProdukStore
+	SupplierStore
+	PembelianStore
+	GudangStore
+	StokStore
+	TransferStore
+	PenggunaStore
+	RoleStore
+	AuditStore
+	BarcodeStore
+	SatuanStore
+	KatalogStore
+	ScorecardStore
+	PermintaanStore
+	ReplenishmentStore
+}
@@ -0,0 +1,443 @@
+// file: internal/store/storetest/storetest.go
+
+// Package storetest berisi skenario yang harus dilewati setiap implementasi
+// store.Store. Store MySQL dan memory menjalankan skenario yang sama dari tesnya
+// masing-masing, sehingga keduanya terbukti berperilaku sama.
+package storetest
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"scm-api/internal/models"
+	"scm-api/internal/store"
+)
+
+// Jalankan menjalankan setiap skenario sebagai subtes. baru harus mengembalikan
+// store kosong yang hanya berisi data awal migrasi.
+func Jalankan(t *testing.T, baru func(t *testing.T) store.Store) {
+	for _, sk := range []struct {
+		nama string
+		uji  func(t *testing.T, st store.Store)
+	}{
+		{"produk", ujiProduk},
+		{"daftar", ujiDaftar},
+		{"stok", ujiStok},
+		{"pembelian", ujiPembelian},
+		{"transfer", ujiTransfer},
+		{"saran", ujiSaran},
+		{"scorecard", ujiScorecard},
+	} {
+		t.Run(sk.nama, func(t *testing.T) {
+			sk.uji(t, baru(t))
+		})
+	}
+}
+
+// data membuat gudang, supplier, dan produk yang dipakai skenario
+type data struct {
+	gudang   []int64
+	supplier int64
+	produk   []int64
+}
+
+func isiData(t *testing.T, st store.Store, jumlahGudang, jumlahProduk int) data {
+	t.Helper()
+	ctx := context.Background()
+	var d data
+	for i := range jumlahGudang {
+		g := models.Gudang{NamaGudang: "Gudang " + strconv.Itoa(i+1), Lokasi: "Kota"}
+		wajib(t, st.CreateGudang(ctx, &g))
+		d.gudang = append(d.gudang, g.GudangID)
+	}
+	sp := models.Supplier{NamaSupplier: "Supplier"}
+	wajib(t, st.CreateSupplier(ctx, &sp))
+	d.supplier = sp.SupplierID
+	for i := range jumlahProduk {
+		p := models.Produk{SKU: "SKU-" + strconv.Itoa(i+1), NamaProduk: "Produk " + strconv.Itoa(i+1), Satuan: "pcs", HargaJual: 1000}
+		wajib(t, st.CreateProduk(ctx, &p))
+		d.produk = append(d.produk, p.ProdukID)
+	}
+	return d
+}
+
+func ujiProduk(t *testing.T, st store.Store) {
+	ctx := context.Background()
+	p := models.Produk{SKU: "ABC-1", NamaProduk: "Satu", Satuan: "pcs", HargaJual: 1000}
+	wajib(t, st.CreateProduk(ctx, &p))
+	if p.ProdukID == 0 {
+		t.Fatal("CreateProduk tidak mengisi ProdukID")
+	}
+	kembar := models.Produk{SKU: "abc-1", NamaProduk: "Kembar", Satuan: "pcs"}
+	harusGalat(t, "CreateProduk SKU kembar", st.CreateProduk(ctx, &kembar), store.ErrDuplikat)
+
+	wajib(t, st.DeleteProduk(ctx, p.ProdukID))
+	harusGalat(t, "DeleteProduk kedua kali", st.DeleteProduk(ctx, p.ProdukID), store.ErrNotFound)
+	dihapus, err := st.GetProduk(ctx, p.ProdukID)
+	wajib(t, err)
+	if !dihapus.DeletedAt.Valid {
+		t.Error("GetProduk: deleted_at kosong setelah dihapus")
+	}
+	if _, total, err := st.ListProduk(ctx, store.Kueri{}); err != nil || total != 0 {
+		t.Errorf("ListProduk = %d, %v; ingin 0 produk", total, err)
+	}
+	if _, total, err := st.ListProduk(ctx, store.Kueri{TermasukDihapus: true}); err != nil || total != 1 {
+		t.Errorf("ListProduk termasuk dihapus = %d, %v; ingin 1 produk", total, err)
+	}
+
+	// SKU produk yang dihapus boleh dipakai lagi, tetapi produk lama tidak bisa dipulihkan
+	pengganti := models.Produk{SKU: "ABC-1", NamaProduk: "Pengganti", Satuan: "pcs"}
+	wajib(t, st.CreateProduk(ctx, &pengganti))
+	harusGalat(t, "PulihkanProduk", st.PulihkanProduk(ctx, p.ProdukID), store.ErrDuplikat)
+	harusGalat(t, "GetProduk tidak ada", ambilGalat(st.GetProduk(ctx, 9999)), store.ErrNotFound)
+}
+
+func ujiDaftar(t *testing.T, st store.Store) {
+	ctx := context.Background()
+	isiData(t, st, 1, 5)
+	daftar, total, err := st.ListProduk(ctx, store.Kueri{Urut: "sku", Turun: true, Halaman: 2, Batas: 2})
+	wajib(t, err)
+	if total != 5 {
+		t.Errorf("total = %d, ingin 5", total)
+	}
+	var sku []string
+	for _, p := range daftar {
+		sku = append(sku, p.SKU)
+	}
+	if len(sku) != 2 || sku[0] != "SKU-3" || sku[1] != "SKU-2" {
+		t.Errorf("halaman 2 = %v, ingin [SKU-3 SKU-2]", sku)
+	}
+	daftar, _, err = st.ListProduk(ctx, store.Kueri{Halaman: 3, Batas: 2})
+	wajib(t, err)
+	if len(daftar) != 1 || daftar[0].SKU != "SKU-5" {
+		t.Errorf("halaman terakhir = %+v, ingin hanya SKU-5", daftar)
+	}
+}
+
+func ujiStok(t *testing.T, st store.Store) {
+	ctx := context.Background()
+	d := isiData(t, st, 1, 1)
+	produk, gudang := d.produk[0], d.gudang[0]
+
+	m, err := st.AdjustStok(ctx, produk, gudang, 10, "penguji", "stok awal")
+	wajib(t, err)
+	if m.JumlahSebelum != 0 || m.JumlahSesudah != 10 || m.Perubahan != 10 {
+		t.Errorf("mutasi pertama = %+v, ingin 0 -> 10", m)
+	}
+	_, err = st.AdjustStok(ctx, produk, gudang, -1, "penguji", "")
+	harusGalat(t, "AdjustStok negatif", err, store.ErrStokTidakCukup)
+	_, err = st.AdjustStok(ctx, produk, gudang, 4, "penguji", "opname")
+	wajib(t, err)
+
+	if n := jumlahStok(t, st, produk, gudang); n != 4 {
+		t.Errorf("stok = %d, ingin 4", n)
+	}
+	mutasi, total, err := st.ListMutasiStok(ctx, store.Kueri{Filter: map[string]string{"produk_id": strconv.FormatInt(produk, 10)}, Urut: "mutasi_id"})
+	wajib(t, err)
+	if total != 2 || len(mutasi) != 2 || mutasi[0].Perubahan != 10 || mutasi[1].Perubahan != -6 {
+		t.Errorf("mutasi = %+v, ingin perubahan 10 lalu -6", mutasi)
+	}
+	for _, m := range mutasi {
+		if m.Tipe != models.MutasiPenyesuaian || m.DibuatOleh != "penguji" {
+			t.Errorf("mutasi %d bertipe %q oleh %q, ingin penyesuaian oleh penguji", m.MutasiID, m.Tipe, m.DibuatOleh)
+		}
+	}
+	_, total, err = st.ListMutasiStok(ctx, store.Kueri{Lingkup: map[string][]int64{"gudang_id": {}}})
+	wajib(t, err)
+	if total != 0 {
+		t.Errorf("mutasi dengan lingkup kosong = %d, ingin 0", total)
+	}
+
+	m, err = st.CatatPenjualan(ctx, produk, gudang, 3, "penguji", "")
+	wajib(t, err)
+	if m.Tipe != models.MutasiPenjualan || m.JumlahSebelum != 4 || m.JumlahSesudah != 1 {
+		t.Errorf("mutasi penjualan = %+v, ingin 4 -> 1", m)
+	}
+	_, err = st.CatatPenjualan(ctx, produk, gudang, 2, "penguji", "")
+	harusGalat(t, "CatatPenjualan melebihi stok", err, store.ErrStokTidakCukup)
+
+	// Hanya penjualan yang dihitung sebagai pemakaian, bukan penyesuaian turun
+	kemarin, besok := time.Now().AddDate(0, 0, -1).Format("2006-01-02"), time.Now().AddDate(0, 0, 1).Format("2006-01-02")
+	pemakaian, err := st.PemakaianHarian(ctx, kemarin, besok, produk, 0, models.TipeMutasiPemakaian)
+	wajib(t, err)
+	jumlah := 0
+	for _, p := range pemakaian {
+		jumlah += p.Jumlah
+	}
+	if jumlah != 3 {
+		t.Errorf("pemakaian = %+v, ingin total 3", pemakaian)
+	}
+}
+
+func ujiPembelian(t *testing.T, st store.Store) {
+	ctx := context.Background()
+	d := isiData(t, st, 1, 1)
+	pb := models.Pembelian{
+		SupplierID:     d.supplier,
+		TanggalPesan:   "2024-05-01",
+		TotalBiaya:     sql.NullFloat64{Float64: 5000, Valid: true},
+		Status:         models.StatusPembelianDraft,
+		GudangTujuanID: sql.NullInt64{Int64: d.gudang[0], Valid: true},
+	}
+	details := []models.DetailPembelian{{ProdukID: d.produk[0], Jumlah: 5, Satuan: "pcs", FaktorKonversi: 1, HargaBeliSatuan: 1000, Subtotal: 5000}}
+	wajib(t, st.CreatePembelian(ctx, &pb, details, "penguji"))
+	id := pb.PembelianID
+
+	harusGalat(t, "Draft ke Diterima", st.UbahStatusPembelian(ctx, id, models.StatusPembelianDiterima, "penguji", ""), store.ErrInvalidTransition)
+	wajib(t, st.UbahStatusPembelian(ctx, id, models.StatusPembelianDipesan, "penguji", ""))
+
+	terima := func(diterima, ditolak int) error {
+		return st.CreatePenerimaan(ctx, &models.Penerimaan{
+			PembelianID: id, TanggalTerima: "2024-05-02 10:00:00", DiterimaOleh: "penguji",
+			Details: []models.DetailPenerimaan{{ProdukID: d.produk[0], JumlahDiterima: diterima, JumlahDitolak: ditolak}},
+		})
+	}
+	harusGalat(t, "penerimaan melebihi pesanan", terima(4, 2), store.ErrInvalidReceipt)
+	if n := jumlahStok(t, st, d.produk[0], d.gudang[0]); n != 0 {
+		t.Errorf("stok setelah penerimaan ditolak = %d, ingin 0", n)
+	}
+	wajib(t, terima(3, 1))
+	harusGalat(t, "DeletePembelian dengan penerimaan", st.DeletePembelian(ctx, id), store.ErrInvalidTransition)
+
+	got, err := st.GetPembelian(ctx, id)
+	wajib(t, err)
+	if got.Status != models.StatusPembelianDiterimaSebagian {
+		t.Errorf("status = %q, ingin Diterima Sebagian", got.Status)
+	}
+	if l := got.Details[0]; l.JumlahDiterima != 3 || l.JumlahDitolak != 1 || l.Sisa != 1 {
+		t.Errorf("baris = %+v, ingin diterima 3, ditolak 1, sisa 1", l)
+	}
+	if n := jumlahStok(t, st, d.produk[0], d.gudang[0]); n != 3 {
+		t.Errorf("stok = %d, ingin 3 (barang ditolak tidak masuk stok)", n)
+	}
+
+	wajib(t, terima(1, 0))
+	harusGalat(t, "penerimaan setelah Diterima", terima(1, 0), store.ErrInvalidTransition)
+
+	riwayat, err := st.RiwayatStatusPembelian(ctx, id)
+	wajib(t, err)
+	var status []string
+	for _, r := range riwayat {
+		status = append(status, r.StatusKe)
+	}
+	ingin := []string{models.StatusPembelianDraft, models.StatusPembelianDipesan, models.StatusPembelianDiterimaSebagian, models.StatusPembelianDiterima}
+	if !samaString(status, ingin) {
+		t.Errorf("riwayat status = %v, ingin %v", status, ingin)
+	}
+	if _, total, err := st.ListPenerimaan(ctx, id, store.Kueri{}); err != nil || total != 2 {
+		t.Errorf("ListPenerimaan = %d, %v; ingin 2 penerimaan", total, err)
+	}
+	harusGalat(t, "Diterima ke Ditutup", st.UbahStatusPembelian(ctx, id, models.StatusPembelianDitutup, "penguji", ""), store.ErrInvalidTransition)
+
+	// Sisa pesanan yang ditutup tidak masuk stok dan tidak lagi terhitung sebagai pesanan terbuka
+	sisa := pb
+	sisa.PembelianID, sisa.Status = 0, models.StatusPembelianDraft
+	wajib(t, st.CreatePembelian(ctx, &sisa, details, "penguji"))
+	id = sisa.PembelianID
+	wajib(t, st.UbahStatusPembelian(ctx, id, models.StatusPembelianDipesan, "penguji", ""))
+	harusGalat(t, "Dipesan ke Ditutup", st.UbahStatusPembelian(ctx, id, models.StatusPembelianDitutup, "penguji", ""), store.ErrInvalidTransition)
+	wajib(t, terima(2, 0))
+	if terbuka, err := st.PesananTerbuka(ctx); err != nil || len(terbuka) != 1 || terbuka[0].Jumlah != 3 {
+		t.Errorf("pesanan terbuka = %+v, %v; ingin sisa 3", terbuka, err)
+	}
+	wajib(t, st.UbahStatusPembelian(ctx, id, models.StatusPembelianDitutup, "penguji", "supplier kehabisan barang"))
+	if terbuka, err := st.PesananTerbuka(ctx); err != nil || len(terbuka) != 0 {
+		t.Errorf("pesanan terbuka setelah ditutup = %+v, %v; ingin kosong", terbuka, err)
+	}
+	harusGalat(t, "penerimaan setelah Ditutup", terima(1, 0), store.ErrInvalidTransition)
+	if n := jumlahStok(t, st, d.produk[0], d.gudang[0]); n != 6 {
+		t.Errorf("stok = %d, ingin 6", n)
+	}
+}
+
+func ujiTransfer(t *testing.T, st store.Store) {
+	ctx := context.Background()
+	d := isiData(t, st, 2, 1)
+	asal, tujuan, produk := d.gudang[0], d.gudang[1], d.produk[0]
+	_, err := st.AdjustStok(ctx, produk, asal, 5, "penguji", "")
+	wajib(t, err)
+
+	buat := func(jumlah int) int64 {
+		t.Helper()
+		tr := models.Transfer{GudangAsalID: asal, GudangTujuanID: tujuan, DibuatOleh: "penguji",
+			Details: []models.DetailTransfer{{ProdukID: produk, Jumlah: jumlah}}}
+		wajib(t, st.CreateTransfer(ctx, &tr))
+		return tr.TransferID
+	}
+	terlaluBanyak := buat(8)
+	harusGalat(t, "kirim melebihi stok", st.UbahStatusTransfer(ctx, terlaluBanyak, models.StatusTransferDikirim, "penguji"), store.ErrStokTidakCukup)
+	if tr, err := st.GetTransfer(ctx, terlaluBanyak); err != nil || tr.Status != models.StatusTransferDraft {
+		t.Errorf("transfer gagal berstatus %q, %v; ingin tetap Draft", tr.Status, err)
+	}
+	if n := jumlahStok(t, st, produk, asal); n != 5 {
+		t.Errorf("stok asal setelah pengiriman ditolak = %d, ingin 5", n)
+	}
+
+	id := buat(3)
+	wajib(t, st.UbahStatusTransfer(ctx, id, models.StatusTransferDikirim, "penguji"))
+	if n := jumlahStok(t, st, produk, asal); n != 2 {
+		t.Errorf("stok asal setelah dikirim = %d, ingin 2", n)
+	}
+	perjalanan, err := st.StokDalamPerjalanan(ctx)
+	wajib(t, err)
+	if len(perjalanan) != 1 || perjalanan[0].Jumlah != 3 || perjalanan[0].GudangTujuanID != tujuan {
+		t.Errorf("dalam perjalanan = %+v, ingin 3 menuju gudang %d", perjalanan, tujuan)
+	}
+
+	wajib(t, st.UbahStatusTransfer(ctx, id, models.StatusTransferDiterima, "penguji"))
+	if n := jumlahStok(t, st, produk, tujuan); n != 3 {
+		t.Errorf("stok tujuan setelah diterima = %d, ingin 3", n)
+	}
+	perjalanan, err = st.StokDalamPerjalanan(ctx)
+	wajib(t, err)
+	if len(perjalanan) != 0 {
+		t.Errorf("dalam perjalanan setelah diterima = %+v, ingin kosong", perjalanan)
+	}
+	harusGalat(t, "batal setelah diterima", st.UbahStatusTransfer(ctx, id, models.StatusTransferDibatalkan, "penguji"), store.ErrInvalidTransition)
+}
+
+func ujiSaran(t *testing.T, st store.Store) {
+	ctx := context.Background()
+	d := isiData(t, st, 1, 3)
+	gudang := d.gudang[0]
+	for _, produk := range d.produk {
+		wajib(t, st.SimpanParameterStok(ctx, &models.ParameterStok{ProdukID: produk, GudangID: gudang, SafetyStock: 5}))
+	}
+	_, err := st.AdjustStok(ctx, d.produk[0], gudang, 7, "penguji", "")
+	wajib(t, err)
+	wajib(t, st.DeleteProduk(ctx, d.produk[2]))
+
+	kandidat, err := st.KandidatSaran(ctx, 0)
+	wajib(t, err)
+	if len(kandidat) != 2 || kandidat[0].ProdukID != d.produk[0] || kandidat[0].Stok != 7 || kandidat[1].Stok != 0 {
+		t.Errorf("kandidat = %+v, ingin produk %d (stok 7) dan %d (stok 0)", kandidat, d.produk[0], d.produk[1])
+	}
+	if kandidat, err := st.KandidatSaran(ctx, gudang+1); err != nil || len(kandidat) != 0 {
+		t.Errorf("kandidat gudang lain = %+v, %v; ingin kosong", kandidat, err)
+	}
+
+	// Satuan yang belum terdaftar berfaktor 0
+	wajib(t, st.CreateSatuan(ctx, &models.ProdukSatuan{ProdukID: d.produk[0], Satuan: "dus", Faktor: 12}))
+	for _, k := range []models.SupplierProduk{
+		{SupplierID: d.supplier, ProdukID: d.produk[0], Satuan: "dus", HargaBeli: 10000, MOQ: 1},
+		{SupplierID: d.supplier, ProdukID: d.produk[0], Satuan: "pcs", HargaBeli: 900, MOQ: 1},
+		{SupplierID: d.supplier, ProdukID: d.produk[1], Satuan: "pak", HargaBeli: 500, MOQ: 1},
+		{SupplierID: d.supplier, ProdukID: d.produk[1], Satuan: "pcs", HargaBeli: 100, MOQ: 1,
+			BerlakuSampai: sql.NullString{String: "2024-01-31", Valid: true}},
+	} {
+		wajib(t, st.CreateKatalog(ctx, &k))
+	}
+	katalog, err := st.KatalogAktifProduk(ctx, []int64{d.produk[0], d.produk[1]}, "2024-05-01")
+	wajib(t, err)
+	type baris struct {
+		produk int64
+		satuan string
+		faktor int
+	}
+	var got []baris
+	for _, k := range katalog {
+		got = append(got, baris{k.ProdukID, k.Satuan, k.Faktor})
+	}
+	ingin := []baris{{d.produk[0], "pcs", 1}, {d.produk[0], "dus", 12}, {d.produk[1], "pak", 0}}
+	if len(got) != len(ingin) {
+		t.Fatalf("katalog aktif = %+v, ingin %+v", got, ingin)
+	}
+	for i := range ingin {
+		if got[i] != ingin[i] {
+			t.Errorf("katalog aktif = %+v, ingin %+v", got, ingin)
+			break
+		}
+	}
+}
+
+func ujiScorecard(t *testing.T, st store.Store) {
+	ctx := context.Background()
+	d := isiData(t, st, 1, 1)
+	buat := func(tanggal string, status ...string) int64 {
+		t.Helper()
+		pb := models.Pembelian{SupplierID: d.supplier, TanggalPesan: tanggal, Status: models.StatusPembelianDraft,
+			GudangTujuanID: sql.NullInt64{Int64: d.gudang[0], Valid: true}}
+		wajib(t, st.CreatePembelian(ctx, &pb, []models.DetailPembelian{
+			{ProdukID: d.produk[0], Jumlah: 5, Satuan: "pcs", FaktorKonversi: 1, HargaBeliSatuan: 1000, Subtotal: 5000},
+		}, "penguji"))
+		for _, ke := range status {
+			wajib(t, st.UbahStatusPembelian(ctx, pb.PembelianID, ke, "penguji", ""))
+		}
+		return pb.PembelianID
+	}
+	terima := func(id int64, tanggal string, diterima, ditolak int) {
+		t.Helper()
+		wajib(t, st.CreatePenerimaan(ctx, &models.Penerimaan{
+			PembelianID: id, TanggalTerima: tanggal, DiterimaOleh: "penguji",
+			Details: []models.DetailPenerimaan{{ProdukID: d.produk[0], JumlahDiterima: diterima, JumlahDitolak: ditolak}},
+		}))
+	}
+
+	buat("2024-05-10")
+	buat("2024-05-10", models.StatusPembelianDipesan, models.StatusPembelianDibatalkan)
+	luar := buat("2024-04-30", models.StatusPembelianDipesan)
+	terima(luar, "2024-05-02 10:00:00", 5, 0)
+	id := buat("2024-05-10", models.StatusPembelianDipesan)
+	terima(id, "2024-05-13 09:00:00", 1, 0)
+	terima(id, "2024-05-12 10:00:00", 2, 1)
+
+	baris, err := st.BarisScorecard(ctx, "2024-05-01", "2024-05-31")
+	wajib(t, err)
+	if len(baris) != 1 {
+		t.Fatalf("BarisScorecard = %+v, ingin hanya pesanan %d", baris, id)
+	}
+	b := baris[0]
+	if b.PembelianID != id || b.Status != models.StatusPembelianDiterimaSebagian || b.JumlahDiterima != 3 || b.JumlahDitolak != 1 {
+		t.Errorf("baris = %+v, ingin pesanan %d Diterima Sebagian dengan 3 diterima dan 1 ditolak", b, id)
+	}
+	if !b.TerimaTerakhir.Valid || !strings.HasPrefix(b.TerimaTerakhir.String, "2024-05-13") {
+		t.Errorf("terima terakhir = %+v, ingin 2024-05-13", b.TerimaTerakhir)
+	}
+}
+
+func jumlahStok(t *testing.T, st store.Store, produkID, gudangID int64) int {
+	t.Helper()
+	daftar, _, err := st.ListStok(context.Background(), store.Kueri{Filter: map[string]string{
+		"produk_id": strconv.FormatInt(produkID, 10), "gudang_id": strconv.FormatInt(gudangID, 10),
+	}})
+	wajib(t, err)
+	if len(daftar) == 0 {
+		return 0
+	}
+	return daftar[0].Jumlah
+}
+
+func wajib(t *testing.T, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func harusGalat(t *testing.T, apa string, err, ingin error) {
+	t.Helper()
+	if !errors.Is(err, ingin) {
+		t.Errorf("%s: galat %v, ingin %v", apa, err, ingin)
+	}
+}
+
+func ambilGalat[T any](_ T, err error) error {
+	return err
+}
+
+func samaString(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}